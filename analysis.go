@@ -11,6 +11,11 @@ import (
 
 func simpleBestDiscardTile(playerInfo *model.PlayerInfo) int {
 	shanten, results14, incShantenResults14 := util.CalculateShantenWithImproves14(playerInfo)
+	return bestAttackDiscardTile(playerInfo, shanten, results14, incShantenResults14)
+}
+
+// 根据何切分析结果选择进攻时的舍牌，没有可切的牌时返回 -1
+func bestAttackDiscardTile(playerInfo *model.PlayerInfo, shanten int, results14 util.Hand14AnalysisResultList, incShantenResults14 util.Hand14AnalysisResultList) int {
 	bestAttackDiscardTile := -1
 	if len(results14) > 0 {
 		bestAttackDiscardTile = results14[0].DiscardTile
@@ -154,12 +159,10 @@ func analysisMeld(playerInfo *model.PlayerInfo, targetTile34 int, isRedFive bool
 	return nil
 }
 
-func analysisHumanTiles(humanTilesInfo *model.HumanTilesInfo) (playerInfo *model.PlayerInfo, err error) {
-	defer func() {
-		if er := recover(); er != nil {
-			err = er.(error)
-		}
-	}()
+// 解析用户输入的手牌、副露和宝牌，不检查手牌数量
+// 没有要鸣的牌时 targetTile34 为 -1
+func parseHumanTilesInfo(humanTilesInfo *model.HumanTilesInfo) (playerInfo *model.PlayerInfo, targetTile34 int, isRedFive bool, err error) {
+	targetTile34 = -1
 
 	if err = humanTilesInfo.SelfParse(); err != nil {
 		return
@@ -170,21 +173,11 @@ func analysisHumanTiles(humanTilesInfo *model.HumanTilesInfo) (playerInfo *model
 		return
 	}
 
-	tileCount := util.CountOfTiles34(tiles34)
-	if tileCount > 14 {
-		return nil, fmt.Errorf("输入错误：%d 张牌", tileCount)
-	}
-
-	if tileCount%3 == 0 {
-		color.HiYellow("%s は %d 枚の牌です\nアシスタントがランダムで1枚追加しました", humanTilesInfo.HumanTiles, tileCount)
-		util.RandomAddTile(tiles34)
-	}
-
 	melds := []model.Meld{}
 	for _, humanMeld := range humanTilesInfo.HumanMelds {
 		tiles, _numRedFives, er := util.StrToTiles(humanMeld)
 		if er != nil {
-			return nil, er
+			return nil, -1, false, er
 		}
 		isUpper := humanMeld[len(humanMeld)-1] <= 'Z'
 		var meldType int
@@ -198,7 +191,7 @@ func analysisHumanTiles(humanTilesInfo *model.HumanTilesInfo) (playerInfo *model
 		case len(tiles) == 4 && !isUpper:
 			meldType = model.MeldTypeMinkan
 		default:
-			return nil, fmt.Errorf("输入错误: %s", humanMeld)
+			return nil, -1, false, fmt.Errorf("输入错误: %s", humanMeld)
 		}
 		containRedFive := false
 		for i, c := range _numRedFives {
@@ -220,25 +213,54 @@ func analysisHumanTiles(humanTilesInfo *model.HumanTilesInfo) (playerInfo *model
 	if humanTilesInfo.HumanDoraTiles != "" {
 		playerInfo.DoraTiles, _, err = util.StrToTiles(humanTilesInfo.HumanDoraTiles)
 		if err != nil {
-			return
+			return nil, -1, false, err
 		}
 	}
 
 	if humanTilesInfo.HumanTargetTile != "" {
+		targetTile34, isRedFive, err = util.StrToTile34(humanTilesInfo.HumanTargetTile)
+		if err != nil {
+			return nil, -1, false, err
+		}
+		return
+	}
+
+	playerInfo.IsTsumo = humanTilesInfo.IsTsumo
+	return
+}
+
+func analysisHumanTiles(humanTilesInfo *model.HumanTilesInfo) (playerInfo *model.PlayerInfo, err error) {
+	defer func() {
+		if er := recover(); er != nil {
+			err = er.(error)
+		}
+	}()
+
+	playerInfo, targetTile34, isRedFive, err := parseHumanTilesInfo(humanTilesInfo)
+	if err != nil {
+		return
+	}
+
+	tileCount := util.CountOfTiles34(playerInfo.HandTiles34)
+	if tileCount > 14 {
+		return nil, fmt.Errorf("输入错误：%d 张牌", tileCount)
+	}
+
+	if tileCount%3 == 0 {
+		color.HiYellow("%s は %d 枚の牌です\nアシスタントがランダムで1枚追加しました", humanTilesInfo.HumanTiles, tileCount)
+		util.RandomAddTile(playerInfo.HandTiles34)
+	}
+
+	if targetTile34 != -1 {
 		if tileCount%3 == 2 {
 			return nil, fmt.Errorf("输入错误: %s 是 %d 张牌", humanTilesInfo.HumanTiles, tileCount)
 		}
-		targetTile34, isRedFive, er := util.StrToTile34(humanTilesInfo.HumanTargetTile)
-		if er != nil {
-			return nil, er
-		}
 		if er := analysisMeld(playerInfo, targetTile34, isRedFive, true, nil); er != nil {
 			return nil, er
 		}
 		return
 	}
 
-	playerInfo.IsTsumo = humanTilesInfo.IsTsumo
 	err = analysisPlayerWithRisk(playerInfo, nil)
 	return
 }
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+)
+
+// JSON 分析接口
+// 供用户自行开发的悬浮窗、机器人等使用，返回的内容与控制台上的输出相对应
+// 注意：牌均为 0-33，who 均为相对自家的位置 0=自家, 1=下家, 2=对家, 3=上家
+
+// 接口版本号，字段有不兼容的变化时加一，并同时修改 analysisAPIPrefix
+const analysisAPIVersion = 1
+
+const analysisAPIPrefix = "/api/v1"
+
+// 错误码
+const (
+	apiErrorInvalidJSON      = "invalid_json"       // 请求体不是合法的 JSON
+	apiErrorInvalidTiles     = "invalid_tiles"      // 手牌格式错误
+	apiErrorInvalidTileCount = "invalid_tile_count" // 手牌数量错误
+	apiErrorInvalidDora      = "invalid_dora"       // 宝牌格式错误
+	apiErrorInvalidWinTile   = "invalid_win_tile"   // 和了牌格式错误或不在手牌中
+	apiErrorInvalidWind      = "invalid_wind"       // 场风或自风不合法
+	apiErrorInvalidEvent     = "invalid_event"      // 牌局事件与当前牌局不符
+	apiErrorUnknownPlatform  = "unknown_platform"   // 不支持的平台
+	apiErrorTooManySessions  = "too_many_sessions"  // 会话数已达上限
+	apiErrorUnauthorized     = "unauthorized"       // 缺少令牌或令牌错误
+	apiErrorForbiddenOrigin  = "forbidden_origin"   // 不允许该网页访问
+	apiErrorBodyTooLarge     = "body_too_large"     // 请求体过大
+	apiErrorRateLimited      = "rate_limited"       // 请求过于频繁
+	apiErrorInternal         = "internal_error"     // 分析时出现内部错误
+)
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// 出错的请求字段，与请求无关时为空
+	Field string `json:"field,omitempty"`
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+type apiErrorResponse struct {
+	Version int       `json:"version"`
+	Error   *apiError `json:"error"`
+}
+
+func newAPIErrorResponse(code string, field string, err error) *apiErrorResponse {
+	return &apiErrorResponse{
+		Version: analysisAPIVersion,
+		Error: &apiError{
+			Code:    code,
+			Message: err.Error(),
+			Field:   field,
+		},
+	}
+}
+
+// 3k+1 张手牌的分析结果，对应 util.Hand13AnalysisResult
+type apiHand13Result struct {
+	Shanten int `json:"shanten"`
+
+	// 进张（听牌时为和了牌）及其剩余枚数
+	Waits      util.Waits `json:"waits"`
+	WaitsCount int        `json:"waits_count"`
+
+	// 默听时能和的牌（听牌时才有）
+	DamaWaits util.Waits `json:"dama_waits,omitempty"`
+
+	// 向听前进后的平均进张数
+	AvgNextShantenWaitsCount float64 `json:"avg_next_shanten_waits_count"`
+
+	// 综合了进张与向听前进后进张的评分
+	MixedWaitsScore float64 `json:"mixed_waits_score"`
+
+	// 改良：摸到这张牌后切掉某张牌，进张变为 Waits
+	Improves             util.Improves `json:"improves,omitempty"`
+	ImproveWayCount      int           `json:"improve_way_count"`
+	AvgImproveWaitsCount float64       `json:"avg_improve_waits_count"`
+
+	// 和率（百分比）
+	AgariRate float64 `json:"agari_rate"`
+
+	// 振听率（百分比）
+	FuritenRate float64 `json:"furiten_rate"`
+
+	// 役种
+	YakuTypes []int    `json:"yaku_types"`
+	YakuNames []string `json:"yaku_names"`
+
+	IsPartWait bool `json:"part_wait"`
+	DoraCount  int  `json:"dora_count"`
+
+	// 默听、立直的荣和点数，以及局收支
+	DamaPoint       float64 `json:"dama_point"`
+	RiichiPoint     float64 `json:"riichi_point"`
+	MixedRoundPoint float64 `json:"mixed_round_point"`
+}
+
+// 役种对应的名称（含古役）
+func apiYakuNames(yakuTypes []int) []string {
+	yakuNames := []string{}
+	for _, yakuType := range yakuTypes {
+		if name, ok := util.YakuNameMap[yakuType]; ok {
+			yakuNames = append(yakuNames, name)
+		} else if name, ok := util.OldYakuNameMap[yakuType]; ok {
+			yakuNames = append(yakuNames, name)
+		}
+	}
+	return yakuNames
+}
+
+func newAPIHand13Result(r *util.Hand13AnalysisResult) *apiHand13Result {
+	yakuTypes := []int{}
+	for yakuType := range r.YakuTypes {
+		yakuTypes = append(yakuTypes, yakuType)
+	}
+	sort.Ints(yakuTypes)
+	yakuNames := apiYakuNames(yakuTypes)
+
+	waits := r.Waits
+	if waits == nil {
+		waits = util.Waits{}
+	}
+	return &apiHand13Result{
+		Shanten:                  r.Shanten,
+		Waits:                    waits,
+		WaitsCount:               waits.AllCount(),
+		DamaWaits:                r.DamaWaits,
+		AvgNextShantenWaitsCount: r.AvgNextShantenWaitsCount,
+		MixedWaitsScore:          r.MixedWaitsScore,
+		Improves:                 r.Improves,
+		ImproveWayCount:          r.ImproveWayCount,
+		AvgImproveWaitsCount:     r.AvgImproveWaitsCount,
+		AgariRate:                r.AvgAgariRate,
+		FuritenRate:              r.FuritenRate,
+		YakuTypes:                yakuTypes,
+		YakuNames:                yakuNames,
+		IsPartWait:               r.IsPartWait,
+		DoraCount:                r.DoraCount,
+		DamaPoint:                r.DamaPoint,
+		RiichiPoint:              r.RiichiPoint,
+		MixedRoundPoint:          r.MixedRoundPoint,
+	}
+}
+
+// 3k+2 张手牌的一种切法，对应 util.Hand14AnalysisResult
+type apiHand14Result struct {
+	DiscardTile       int  `json:"discard"`
+	IsDiscardDoraTile bool `json:"discard_dora"`
+
+	// 鸣牌时用于副露的手牌，比如用 23m 吃了牌就是 [1,2]
+	OpenTiles []int `json:"open_tiles,omitempty"`
+
+	// 切牌的综合危险度（百分比），没有危险度表时为 null
+	Risk *float64 `json:"risk"`
+
+	// 切牌后的手牌分析结果
+	Result13 *apiHand13Result `json:"result13"`
+}
+
+func newAPIHand14Results(results14 util.Hand14AnalysisResultList, mixedRiskTable riskTable) []*apiHand14Result {
+	results := []*apiHand14Result{}
+	for _, r := range results14 {
+		result := &apiHand14Result{
+			DiscardTile:       r.DiscardTile,
+			IsDiscardDoraTile: r.IsDiscardDoraTile,
+			OpenTiles:         r.OpenTiles,
+			Result13:          newAPIHand13Result(r.Result13),
+		}
+		if mixedRiskTable != nil {
+			risk := mixedRiskTable[r.DiscardTile]
+			result.Risk = &risk
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// 鸣他家舍牌的分析结果
+type apiMeldAnalysis struct {
+	TargetTile int  `json:"target"`
+	IsRedFive  bool `json:"red_five"`
+	AllowChi   bool `json:"allow_chi"`
+
+	// 鸣牌后的向听数
+	Shanten int `json:"shanten"`
+
+	// 鸣牌后的各种切法，按推荐顺序排列
+	Discards []*apiHand14Result `json:"discards"`
+	// 鸣牌后退向听的切法
+	BackwardDiscards []*apiHand14Result `json:"backward_discards"`
+}
+
+// 手牌分析结果
+// 3k+1 张手牌时为 Result13（有要鸣的牌时还有 Meld），3k+2 张手牌时为 Discards 和 BackwardDiscards
+type apiHandAnalysis struct {
+	// 手牌及副露，如 "24688m 34s # 6666P 234p"
+	Hand string `json:"hand"`
+
+	// 当前的向听数，-1 表示已和牌
+	Shanten int `json:"shanten"`
+
+	Result13 *apiHand13Result `json:"result13,omitempty"`
+
+	// 各种切法，按推荐顺序排列
+	Discards []*apiHand14Result `json:"discards,omitempty"`
+	// 退向听的切法
+	BackwardDiscards []*apiHand14Result `json:"backward_discards,omitempty"`
+
+	Meld *apiMeldAnalysis `json:"meld,omitempty"`
+}
+
+// 与 analysisPlayerWithRisk 相同的分析，但不输出到控制台
+// mixedRiskTable 可以为 nil
+func newAPIHandAnalysis(playerInfo *model.PlayerInfo, mixedRiskTable riskTable) (*apiHandAnalysis, error) {
+	a := &apiHandAnalysis{Hand: humanHands(playerInfo)}
+	countOfTiles := util.CountOfTiles34(playerInfo.HandTiles34)
+	switch countOfTiles % 3 {
+	case 1:
+		result := util.CalculateShantenWithImproves13(playerInfo)
+		a.Shanten = result.Shanten
+		a.Result13 = newAPIHand13Result(result)
+	case 2:
+		shanten, results14, incShantenResults14 := util.CalculateShantenWithImproves14(playerInfo)
+		a.Shanten = shanten
+		a.Discards = newAPIHand14Results(results14, mixedRiskTable)
+		a.BackwardDiscards = newAPIHand14Results(incShantenResults14, mixedRiskTable)
+	default:
+		return nil, fmt.Errorf("手牌错误：%d 张牌", countOfTiles)
+	}
+	return a, nil
+}
+
+// 与 analysisMeld 相同的分析，但不输出到控制台
+// 无法鸣这张牌时返回 nil
+func newAPIMeldAnalysis(playerInfo *model.PlayerInfo, targetTile34 int, isRedFive bool, allowChi bool, mixedRiskTable riskTable) *apiMeldAnalysis {
+	shanten, results14, incShantenResults14 := util.CalculateMeld(playerInfo, targetTile34, isRedFive, allowChi)
+	if len(results14) == 0 && len(incShantenResults14) == 0 {
+		return nil
+	}
+	return &apiMeldAnalysis{
+		TargetTile:       targetTile34,
+		IsRedFive:        isRedFive,
+		AllowChi:         allowChi,
+		Shanten:          shanten,
+		Discards:         newAPIHand14Results(results14, mixedRiskTable),
+		BackwardDiscards: newAPIHand14Results(incShantenResults14, mixedRiskTable),
+	}
+}
+
+// POST /api/v1/analysis 的请求
+type apiAnalysisRequest struct {
+	// 手牌 & 副露(暗杠用大写表示) + 要鸣的牌，格式同命令行，如 "24688m 34s # 6666P 234p + 3m"
+	Tiles string `json:"tiles"`
+	// 宝牌，如 "13m6p"
+	Dora string `json:"dora"`
+	// 是否自摸
+	Tsumo bool `json:"tsumo"`
+}
+
+type apiAnalysisResponse struct {
+	Version int `json:"version"`
+	*apiHandAnalysis
+}
+
+// 检查并分析用户输入的手牌，返回的错误均为 *apiError
+// 与命令行不同，3k 张手牌时不会随机补一张牌，而是返回错误
+func analysisAPIRequest(req *apiAnalysisRequest) (resp *apiAnalysisResponse, err error) {
+	defer func() {
+		if er := recover(); er != nil {
+			err = &apiError{Code: apiErrorInternal, Message: fmt.Sprint(er)}
+		}
+	}()
+
+	if req.Tiles == "" {
+		return nil, &apiError{Code: apiErrorInvalidTiles, Message: "缺少手牌", Field: "tiles"}
+	}
+	playerInfo, targetTile34, isRedFive, err := parseHumanTilesInfo(&model.HumanTilesInfo{HumanTiles: req.Tiles, IsTsumo: req.Tsumo})
+	if err != nil {
+		return nil, &apiError{Code: apiErrorInvalidTiles, Message: err.Error(), Field: "tiles"}
+	}
+	if req.Dora != "" {
+		doraTiles, _, er := util.StrToTiles(req.Dora)
+		if er != nil {
+			return nil, &apiError{Code: apiErrorInvalidDora, Message: er.Error(), Field: "dora"}
+		}
+		playerInfo.DoraTiles = doraTiles
+	}
+
+	tileCount := util.CountOfTiles34(playerInfo.HandTiles34)
+	if tileCount > 14 || tileCount%3 == 0 || targetTile34 != -1 && tileCount%3 == 2 {
+		message := fmt.Sprintf("输入错误：手牌为 %d 张", tileCount)
+		if targetTile34 != -1 {
+			message += "，鸣牌分析需要 3k+1 张手牌"
+		}
+		return nil, &apiError{Code: apiErrorInvalidTileCount, Message: message, Field: "tiles"}
+	}
+
+	a, err := newAPIHandAnalysis(playerInfo, nil)
+	if err != nil {
+		return nil, &apiError{Code: apiErrorInvalidTileCount, Message: err.Error(), Field: "tiles"}
+	}
+	if targetTile34 != -1 {
+		a.Meld = newAPIMeldAnalysis(playerInfo, targetTile34, isRedFive, true, nil)
+	}
+	return &apiAnalysisResponse{Version: analysisAPIVersion, apiHandAnalysis: a}, nil
+}
+
+// 他家的危险度信息，对应 riskInfo
+type apiRiskInfo struct {
+	// 听牌率（百分比），立直时为 100
+	TenpaiRate float64 `json:"tenpai_rate"`
+
+	IsTsumogiriRiichi bool `json:"tsumogiri_riichi"`
+
+	// 对该玩家的安牌
+	SafeTiles []int `json:"safe_tiles"`
+
+	// 各种牌的铳率（百分比），下标为牌
+	RiskTable []float64 `json:"risk_table"`
+
+	// 剩余无筋 123789 牌
+	LeftNoSujiTiles []int `json:"left_no_suji_tiles"`
+}
+
+func newAPIRiskInfo(ri *riskInfo) *apiRiskInfo {
+	safeTiles := []int{}
+	for tile, isSafe := range ri.safeTiles34 {
+		if isSafe {
+			safeTiles = append(safeTiles, tile)
+		}
+	}
+	leftNoSujiTiles := ri.leftNoSujiTiles
+	if leftNoSujiTiles == nil {
+		leftNoSujiTiles = []int{}
+	}
+	return &apiRiskInfo{
+		TenpaiRate:        ri.tenpaiRate,
+		IsTsumogiriRiichi: ri.isTsumogiriRiichi,
+		SafeTiles:         safeTiles,
+		RiskTable:         ri.riskTable,
+		LeftNoSujiTiles:   leftNoSujiTiles,
+	}
+}
+
+type apiDiscard struct {
+	Tile        int  `json:"tile"`
+	IsTsumogiri bool `json:"tsumogiri"`
+	// 是否为鸣牌后的舍牌
+	IsMeldDiscard bool `json:"meld_discard,omitempty"`
+}
+
+// 玩家的牌局信息，对应 playerInfo
+type apiPlayerState struct {
+	Who          int    `json:"who"`
+	Name         string `json:"name"`
+	SelfWindTile int    `json:"wind"`
+
+	Discards []*apiDiscard `json:"discards"`
+
+	// 副露（含暗杠、加杠），不含拔北
+	Melds  []*model.Meld `json:"melds"`
+	IsNaki bool          `json:"naki"`
+
+	IsRiichi   bool `json:"riichi"`
+	CanIppatsu bool `json:"ippatsu"`
+	// 立直宣言牌在 Discards 中的下标，未立直时为 -1
+	RiichiTileAt int `json:"riichi_tile_at"`
+
+	NukiDoraNum int `json:"nuki_dora,omitempty"`
+
+	// 对该玩家的危险度，自家为 null
+	Risk *apiRiskInfo `json:"risk"`
+}
+
+// GET /api/v1/:platform/state 的响应
+type apiGameState struct {
+	Version  int    `json:"version"`
+	Platform string `json:"platform"`
+
+	// 人数，3 为三麻，4 为四麻，未知时为 0
+	PlayerNumber int `json:"player_number"`
+	// 场数（如东1为0，东2为1，...，南1为4，...）
+	RoundNumber int `json:"round"`
+	BenNumber   int `json:"ben"`
+	// 场风
+	RoundWindTile int `json:"round_wind"`
+	Dealer        int `json:"dealer"`
+
+	DoraIndicators []int `json:"dora_indicators"`
+	DoraTiles      []int `json:"doras"`
+
+	// 自家手牌（不含副露）各种牌的数量，下标为牌
+	HandTiles34 []int `json:"hand"`
+	// 按照 mps 的顺序，自家赤5个数（含副露）
+	NumRedFives []int `json:"reds"`
+	// 剩余可以摸的牌数
+	LeftDrawTilesCount int `json:"left_draw_count"`
+
+	Players []*apiPlayerState `json:"players"`
+
+	// 考虑了各家听牌率的综合危险度（百分比），下标为牌
+	MixedRiskTable []float64 `json:"mixed_risk_table"`
+
+	// 当前手牌的分析结果，对局开始前为 null
+	// 3k+1 张手牌且他家刚舍牌时，还包含鸣这张牌的分析
+	Analysis *apiHandAnalysis `json:"analysis"`
+
+	// 观看牌谱时本局的舍牌推荐，其他情况为 null
+	Review *apiRoundReview `json:"review"`
+}
+
+// 牌谱中自家的一巡，对应 analysisCache
+// 没有对应的牌时为 -1
+type apiReviewTurn struct {
+	DiscardTile     int     `json:"discard"`
+	DiscardTileRisk float64 `json:"discard_risk"`
+	IsRiichi        bool    `json:"riichi"`
+
+	AttackTile      int     `json:"attack"`
+	AttackTileRisk  float64 `json:"attack_risk"`
+	DefenceTile     int     `json:"defence"`
+	DefenceTileRisk float64 `json:"defence_risk"`
+}
+
+// 牌谱中本局的舍牌推荐，对应 roundAnalysisCache.print
+type apiRoundReview struct {
+	// 是否已计算完成，未完成时 Turns 为空
+	Done  bool             `json:"done"`
+	Turns []*apiReviewTurn `json:"turns"`
+}
+
+func newAPIRoundReview(rc *roundAnalysisCache) *apiRoundReview {
+	review := &apiRoundReview{Turns: []*apiReviewTurn{}}
+	if rc == nil || !rc.isEnd {
+		return review
+	}
+	review.Done = true
+	for i, c := range rc.cache {
+		turn := &apiReviewTurn{
+			DiscardTile:     c.selfDiscardTile,
+			DiscardTileRisk: c.selfDiscardTileRisk,
+			IsRiichi:        c.isRiichiWhenDiscard,
+			AttackTile:      c.aiAttackDiscardTile,
+			AttackTileRisk:  c.aiAttackDiscardTileRisk,
+			DefenceTile:     c.aiDefenceDiscardTile,
+			DefenceTileRisk: c.aiDefenceDiscardTileRisk,
+		}
+		// 最后为自摸时没有推荐
+		if i == len(rc.cache)-1 && c.selfDiscardTile == -1 {
+			turn.AttackTile = -1
+			turn.DefenceTile = -1
+		}
+		review.Turns = append(review.Turns, turn)
+	}
+	return review
+}
+
+// 根据当前牌局生成 JSON 接口的数据，调用时需持有 mjSession.mu
+func (d *roundData) apiGameState(platform string) (state *apiGameState, err error) {
+	defer func() {
+		if er := recover(); er != nil {
+			err = fmt.Errorf("内部错误：%v", er)
+		}
+	}()
+
+	playerInfo := d.newModelPlayerInfo()
+	riskTables := d.analysisTilesRisk()
+	mixedRiskTable := riskTables.mixedRiskTable()
+
+	state = &apiGameState{
+		Version:            analysisAPIVersion,
+		Platform:           platform,
+		PlayerNumber:       d.playerNumber,
+		RoundNumber:        d.roundNumber,
+		BenNumber:          d.benNumber,
+		RoundWindTile:      d.roundWindTile,
+		Dealer:             d.dealer,
+		DoraIndicators:     append([]int{}, d.doraIndicators...),
+		DoraTiles:          append([]int{}, d.doraList()...),
+		HandTiles34:        append([]int{}, d.counts...),
+		NumRedFives:        append([]int{}, d.numRedFives...),
+		LeftDrawTilesCount: playerInfo.LeftDrawTilesCount,
+		MixedRiskTable:     mixedRiskTable,
+	}
+
+	latestDiscardWho := -1
+	for who, player := range d.players {
+		ps := &apiPlayerState{
+			Who:          who,
+			Name:         player.name,
+			SelfWindTile: player.selfWindTile,
+			Discards:     []*apiDiscard{},
+			Melds:        []*model.Meld{},
+			IsNaki:       player.isNaki,
+			IsRiichi:     player.isReached,
+			CanIppatsu:   player.canIppatsu,
+			RiichiTileAt: player.reachTileAt,
+			NukiDoraNum:  player.nukiDoraNum,
+		}
+		for i, tile := range player.discardTiles {
+			discard := &apiDiscard{Tile: tile, IsMeldDiscard: util.InInts(i, player.meldDiscardsAt)}
+			if tile < 0 {
+				discard.Tile = ^tile
+				discard.IsTsumogiri = true
+			}
+			ps.Discards = append(ps.Discards, discard)
+		}
+		for _, meld := range player.melds {
+			ps.Melds = append(ps.Melds, meld.Copy())
+		}
+		if who > 0 {
+			ps.Risk = newAPIRiskInfo(riskTables[who])
+		}
+		state.Players = append(state.Players, ps)
+
+		if len(d.globalDiscardTiles) > 0 && player.latestDiscardAtGlobal == len(d.globalDiscardTiles)-1 {
+			latestDiscardWho = who
+		}
+	}
+
+	if d.gameMode == gameModeRecord && d.parser != nil {
+		var rc *roundAnalysisCache
+		if analysisCache := d.analysisCaches.get(d.parser.GetSelfSeat()); analysisCache != nil {
+			rc = analysisCache.wholeGameCache[d.roundNumber][d.benNumber]
+		}
+		state.Review = newAPIRoundReview(rc)
+	}
+
+	// 对局开始前，或处于鸣牌后尚未舍牌等 3k 张手牌的状态
+	if util.CountOfTiles34(d.counts)%3 == 0 {
+		return
+	}
+	if state.Analysis, err = newAPIHandAnalysis(playerInfo, mixedRiskTable); err != nil {
+		return nil, err
+	}
+	// 他家刚舍牌时分析能否鸣牌
+	if state.Analysis.Result13 != nil && latestDiscardWho > 0 && playerInfo.LeftDrawTilesCount > 0 {
+		discardTile := d.globalDiscardTiles[len(d.globalDiscardTiles)-1]
+		if discardTile < 0 {
+			discardTile = ^discardTile
+		}
+		allowChi := d.playerNumber != 3 && latestDiscardWho == 3
+		state.Analysis.Meld = newAPIMeldAnalysis(d.newModelPlayerInfo(), discardTile, d.latestDiscardIsRedFive, allowChi, mixedRiskTable)
+	}
+	return
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAPIServer() (*echo.Echo, *mjHandler) {
+	h := newMjHandler(nil)
+	e := echo.New()
+	e.POST(analysisAPIPrefix+"/analysis", h.apiAnalysis)
+	e.GET(analysisAPIPrefix+"/:platform/state", h.apiGameState)
+	return e, h
+}
+
+func Test_mjHandler_apiAnalysis(t *testing.T) {
+	assert := assert.New(t)
+
+	e, _ := newTestAPIServer()
+	post := func(body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, analysisAPIPrefix+"/analysis", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		resp := map[string]interface{}{}
+		assert.NoError(json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+		return rec.Code, resp
+	}
+	errorCode := func(resp map[string]interface{}) string {
+		if apiErr, ok := resp["error"].(map[string]interface{}); ok {
+			return apiErr["code"].(string)
+		}
+		return ""
+	}
+
+	// 14 张
+	code, resp := post(`{"tiles": "123m 456p 789s 11z 349m", "dora": "1m"}`)
+	if assert.Equal(http.StatusOK, code) {
+		assert.EqualValues(analysisAPIVersion, resp["version"])
+		assert.EqualValues(0, resp["shanten"])
+		discards := resp["discards"].([]interface{})
+		if assert.NotEmpty(discards) {
+			best := discards[0].(map[string]interface{})
+			assert.EqualValues(8, best["discard"]) // 9m
+			result13 := best["result13"].(map[string]interface{})
+			assert.EqualValues(0, result13["shanten"])
+			assert.Contains(result13["waits"], "1") // 2m
+			assert.Nil(best["risk"])
+		}
+	}
+
+	// 13 张 + 要鸣的牌
+	code, resp = post(`{"tiles": "123m 456p 789s 1z 34m 6z + 5m"}`)
+	if assert.Equal(http.StatusOK, code) {
+		assert.NotNil(resp["result13"])
+		if meld, ok := resp["meld"].(map[string]interface{}); assert.True(ok) {
+			assert.EqualValues(4, meld["target"])
+			assert.NotEmpty(meld["discards"])
+		}
+	}
+
+	code, resp = post(`{"tiles": `)
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal(apiErrorInvalidJSON, errorCode(resp))
+
+	code, resp = post(`{}`)
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal(apiErrorInvalidTiles, errorCode(resp))
+
+	code, resp = post(`{"tiles": "123x"}`)
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal(apiErrorInvalidTiles, errorCode(resp))
+
+	code, resp = post(`{"tiles": "123m 456p 789s 111z"}`)
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal(apiErrorInvalidTileCount, errorCode(resp))
+
+	code, resp = post(`{"tiles": "123m 456p 789s 11z 345m + 5m"}`)
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal(apiErrorInvalidTileCount, errorCode(resp))
+
+	code, resp = post(`{"tiles": "123m 456p 789s 11z 34m", "dora": "1x"}`)
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal(apiErrorInvalidDora, errorCode(resp))
+	assert.Equal("dora", resp["error"].(map[string]interface{})["field"])
+}
+
+func Test_mjHandler_apiGameState(t *testing.T) {
+	assert := assert.New(t)
+
+	e, h := newTestAPIServer()
+	// 关闭请求时创建的会话，以免其后台任务影响其他测试
+	defer func() {
+		for _, s := range h.sessions {
+			s.close()
+		}
+	}()
+	get := func(platform string) (int, *apiGameState, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, analysisAPIPrefix+"/"+platform+"/state", nil)
+		req.Header.Set(sessionIDHeader, "api")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		state := &apiGameState{}
+		raw := map[string]interface{}{}
+		assert.NoError(json.Unmarshal(rec.Body.Bytes(), state))
+		assert.NoError(json.Unmarshal(rec.Body.Bytes(), &raw))
+		return rec.Code, state, raw
+	}
+
+	code, _, raw := get("unknown")
+	assert.Equal(http.StatusNotFound, code)
+	assert.Equal(apiErrorUnknownPlatform, raw["error"].(map[string]interface{})["code"])
+
+	// 对局开始前
+	code, state, raw := get("tenhou")
+	if assert.Equal(http.StatusOK, code) {
+		assert.Equal("tenhou", state.Platform)
+		assert.Len(state.Players, 4)
+		assert.Nil(raw["analysis"])
+	}
+
+	// 配牌后上家舍赤5m
+	s := h.sessions["api"]
+	s.mu.Lock()
+	d := s.tenhouRoundData
+	d.skipOutput = true
+	for _, ev := range []*event.Event{
+		{Type: event.TypeRoundStart, RoundStart: &event.RoundStart{
+			Dealer:         3,
+			PlayerNumber:   4,
+			DoraIndicators: []int{0},
+			HandTiles:      []int{0, 1, 2, 12, 13, 14, 24, 25, 26, 27, 27, 2, 3},
+			NumRedFives:    []int{0, 0, 0},
+		}},
+		{Type: event.TypeDiscard, Discard: &event.Discard{Who: 3, Tile: 4, IsRedFive: true, IsTsumogiri: true, CanBeMeld: true}},
+	} {
+		if !assert.NoError(d.handleEvent(ev)) {
+			s.mu.Unlock()
+			return
+		}
+	}
+	s.mu.Unlock()
+
+	code, state, _ = get("tenhou")
+	if !assert.Equal(http.StatusOK, code) {
+		return
+	}
+	assert.Equal(3, state.Dealer)
+	assert.Equal([]int{1}, state.DoraTiles)
+	assert.Equal(2, state.HandTiles34[2])
+	assert.Len(state.MixedRiskTable, 34)
+	assert.Nil(state.Players[0].Risk)
+	if assert.NotNil(state.Players[3].Risk) {
+		assert.Len(state.Players[3].Risk.RiskTable, 34)
+		assert.Contains(state.Players[3].Risk.SafeTiles, 4)
+	}
+	assert.Equal([]*apiDiscard{{Tile: 4, IsTsumogiri: true}}, state.Players[3].Discards)
+	if assert.NotNil(state.Analysis) && assert.NotNil(state.Analysis.Result13) {
+		assert.Equal(0, state.Analysis.Shanten)
+		if assert.NotNil(state.Analysis.Meld) {
+			assert.Equal(4, state.Analysis.Meld.TargetTile)
+			assert.True(state.Analysis.Meld.IsRedFive)
+			assert.True(state.Analysis.Meld.AllowChi)
+			// 吃 5m 后只能退向听
+			assert.Empty(state.Analysis.Meld.Discards)
+			if assert.NotEmpty(state.Analysis.Meld.BackwardDiscards) {
+				assert.NotNil(state.Analysis.Meld.BackwardDiscards[0].Risk)
+			}
+		}
+	}
+}
+
+func Test_newAPIRoundReview(t *testing.T) {
+	assert := assert.New(t)
+
+	review := newAPIRoundReview(nil)
+	assert.False(review.Done)
+	assert.Empty(review.Turns)
+
+	rc := &roundAnalysisCache{isEnd: true}
+	rc.cache = []*analysisCache{
+		{selfDiscardTile: 33, selfDiscardTileRisk: 1, aiAttackDiscardTile: 33, aiDefenceDiscardTile: 33},
+		{selfDiscardTile: 5, isRiichiWhenDiscard: true, aiAttackDiscardTile: 4, aiDefenceDiscardTile: 27, aiDefenceDiscardTileRisk: 0.5},
+		{selfDiscardTile: -1, aiAttackDiscardTile: 8, aiDefenceDiscardTile: 8}, // 自摸
+	}
+	review = newAPIRoundReview(rc)
+	assert.True(review.Done)
+	if assert.Len(review.Turns, 3) {
+		assert.Equal(&apiReviewTurn{DiscardTile: 5, IsRiichi: true, AttackTile: 4, DefenceTile: 27, DefenceTileRisk: 0.5}, review.Turns[1])
+		assert.Equal(-1, review.Turns[2].AttackTile)
+		assert.Equal(-1, review.Turns[2].DefenceTile)
+	}
+	// 不修改缓存
+	assert.Equal(8, rc.cache[2].aiAttackDiscardTile)
+}
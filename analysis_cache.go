@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/EndlessCheng/mahjong-helper/util"
 	"github.com/fatih/color"
+	"sync"
 )
 
 type analysisOpType int
@@ -197,50 +198,82 @@ type gameAnalysisCache struct {
 	// 局数 本场数
 	wholeGameCache [][]*roundAnalysisCache
 
+	// 所属会话，用于判断用户是否已退出该牌谱
+	session *mjSession
+
 	majsoulRecordUUID string
 
 	selfSeat int
+
+	// 是否考虑古役，与所分析的牌谱一致
+	considerOldYaku bool
 }
 
-func newGameAnalysisCache(majsoulRecordUUID string, selfSeat int) *gameAnalysisCache {
+func newGameAnalysisCache(session *mjSession, majsoulRecordUUID string, selfSeat int) *gameAnalysisCache {
 	cache := make([][]*roundAnalysisCache, 3*4) // 最多到西四
 	for i := range cache {
 		cache[i] = make([]*roundAnalysisCache, 100) // 最多连庄
 	}
 	return &gameAnalysisCache{
 		wholeGameCache:    cache,
+		session:           session,
 		majsoulRecordUUID: majsoulRecordUUID,
 		selfSeat:          selfSeat,
 	}
 }
 
+// 用户是否仍在观看该牌谱
+func (c *gameAnalysisCache) isCurrentRecord() bool {
+	return c.majsoulRecordUUID == c.session.getMajsoulCurrentRecordUUID()
+}
+
 //
 
-// TODO: 重构成 struct
-var (
-	_analysisCacheList = make([]*gameAnalysisCache, 4)
-	_currentSeat       int
-)
+// 各个座位的牌谱分析缓存
+// 会被会话的消息处理协程和牌谱分析协程同时访问
+type analysisCacheList struct {
+	mu          sync.RWMutex
+	caches      []*gameAnalysisCache
+	currentSeat int
+}
 
-func resetAnalysisCache() {
-	_analysisCacheList = make([]*gameAnalysisCache, 4)
+func newAnalysisCacheList() *analysisCacheList {
+	return &analysisCacheList{caches: make([]*gameAnalysisCache, 4)}
 }
 
-func setAnalysisCache(analysisCache *gameAnalysisCache) {
-	_analysisCacheList[analysisCache.selfSeat] = analysisCache
-	_currentSeat = analysisCache.selfSeat
+func (l *analysisCacheList) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.caches = make([]*gameAnalysisCache, 4)
 }
 
-func getAnalysisCache(seat int) *gameAnalysisCache {
-	if seat == -1 {
+func (l *analysisCacheList) set(analysisCache *gameAnalysisCache) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.caches[analysisCache.selfSeat] = analysisCache
+	l.currentSeat = analysisCache.selfSeat
+}
+
+// l 为 nil 时（例如命令行交互模式）返回 nil
+func (l *analysisCacheList) get(seat int) *gameAnalysisCache {
+	if l == nil || seat == -1 {
 		return nil
 	}
-	return _analysisCacheList[seat]
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.caches[seat]
 }
 
-func getCurrentAnalysisCache() *gameAnalysisCache {
-	return getAnalysisCache(_currentSeat)
+func (l *analysisCacheList) current() *gameAnalysisCache {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	seat := l.currentSeat
+	l.mu.RUnlock()
+	return l.get(seat)
 }
+
 func (c *gameAnalysisCache) runMajsoulRecordAnalysisTask(actions majsoulRoundActions) error {
 	// 最初のアクションから局と場を取得
 	if len(actions) == 0 {
@@ -253,7 +286,7 @@ func (c *gameAnalysisCache) runMajsoulRecordAnalysisTask(actions majsoulRoundAct
 	ben := *data.Ben
 	roundCache := c.wholeGameCache[roundNumber][ben] // TODO: アトミック操作を推奨
 	if roundCache == nil {
-		roundCache := &roundAnalysisCache{isStart: true}
+		roundCache = &roundAnalysisCache{isStart: true}
 		if debugMode {
 			fmt.Println("アシスタントは推奨打牌を計算中です... roundCacheを作成")
 		}
@@ -271,10 +304,12 @@ func (c *gameAnalysisCache) runMajsoulRecordAnalysisTask(actions majsoulRoundAct
 	// TODO: プレイヤーがスキップしたが、AIが鳴くべきと判断した場合？
 	majsoulRoundData := &majsoulRoundData{selfSeat: c.selfSeat} // 注意：新しいmajsoulRoundDataで計算するためデータ競合はない
 	majsoulRoundData.roundData = newGame(majsoulRoundData)
+	majsoulRoundData.roundData.analysisCaches = c.session.analysisCaches
 	majsoulRoundData.roundData.gameMode = gameModeRecordCache
 	majsoulRoundData.skipOutput = true
+	majsoulRoundData.considerOldYaku = c.considerOldYaku
 	for i, action := range actions[:len(actions)-1] {
-		if c.majsoulRecordUUID != getMajsoulCurrentRecordUUID() {
+		if !c.isCurrentRecord() {
 			if debugMode {
 				fmt.Println("ユーザーが牌譜を終了しました")
 			}
@@ -289,7 +324,7 @@ func (c *gameAnalysisCache) runMajsoulRecordAnalysisTask(actions majsoulRoundAct
 	}
 	roundCache.isEnd = true
 
-	if c.majsoulRecordUUID != getMajsoulCurrentRecordUUID() {
+	if !c.isCurrentRecord() {
 		if debugMode {
 			fmt.Println("ユーザーが牌譜を終了しました")
 		}
@@ -298,6 +333,7 @@ func (c *gameAnalysisCache) runMajsoulRecordAnalysisTask(actions majsoulRoundAct
 
 	clearConsole()
 	roundCache.print()
+	c.session.pushMajsoulReview()
 
 	return nil
 }
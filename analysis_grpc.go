@@ -0,0 +1,553 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/EndlessCheng/mahjong-helper/proto/analysis"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/fatih/color"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC 分析服务，定义见 proto/analysis/analysis.proto
+// 供 Discord 机器人、练习网站等其他服务调用，分析结果与 JSON 分析接口相同
+
+type analysisServer struct {
+	analysis.UnimplementedAnalysisServer
+}
+
+func newGRPCServer() *grpc.Server {
+	s := grpc.NewServer()
+	analysis.RegisterAnalysisServer(s, &analysisServer{})
+	return s
+}
+
+func runGRPCServer(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	color.HiGreen("gRPC 分析服务已启动：%s", listener.Addr())
+	return newGRPCServer().Serve(listener)
+}
+
+// 将 *apiError 转换成 gRPC 的错误
+func grpcError(err error) error {
+	apiErr, ok := err.(*apiError)
+	if !ok || apiErr.Code == apiErrorInternal {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if apiErr.Field != "" {
+		return status.Errorf(codes.InvalidArgument, "%s: %s", apiErr.Field, apiErr.Message)
+	}
+	return status.Error(codes.InvalidArgument, apiErr.Message)
+}
+
+func grpcWaits(waits util.Waits) map[int32]int32 {
+	if len(waits) == 0 {
+		return nil
+	}
+	m := make(map[int32]int32, len(waits))
+	for tile, left := range waits {
+		m[int32(tile)] = int32(left)
+	}
+	return m
+}
+
+func grpcInts(a []int) []int32 {
+	if len(a) == 0 {
+		return nil
+	}
+	b := make([]int32, len(a))
+	for i, v := range a {
+		b[i] = int32(v)
+	}
+	return b
+}
+
+func grpcHand13Result(r *apiHand13Result) *analysis.Hand13Result {
+	if r == nil {
+		return nil
+	}
+	var improves map[int32]*analysis.Waits
+	if len(r.Improves) > 0 {
+		improves = make(map[int32]*analysis.Waits, len(r.Improves))
+		for tile, waits := range r.Improves {
+			improves[int32(tile)] = &analysis.Waits{Waits: grpcWaits(waits)}
+		}
+	}
+	return &analysis.Hand13Result{
+		Shanten:                  int32(r.Shanten),
+		Waits:                    grpcWaits(r.Waits),
+		WaitsCount:               int32(r.WaitsCount),
+		DamaWaits:                grpcWaits(r.DamaWaits),
+		AvgNextShantenWaitsCount: r.AvgNextShantenWaitsCount,
+		MixedWaitsScore:          r.MixedWaitsScore,
+		Improves:                 improves,
+		ImproveWayCount:          int32(r.ImproveWayCount),
+		AvgImproveWaitsCount:     r.AvgImproveWaitsCount,
+		AgariRate:                r.AgariRate,
+		FuritenRate:              r.FuritenRate,
+		YakuTypes:                grpcInts(r.YakuTypes),
+		YakuNames:                r.YakuNames,
+		PartWait:                 r.IsPartWait,
+		DoraCount:                int32(r.DoraCount),
+		DamaPoint:                r.DamaPoint,
+		RiichiPoint:              r.RiichiPoint,
+		MixedRoundPoint:          r.MixedRoundPoint,
+	}
+}
+
+func grpcHand14Results(results []*apiHand14Result) []*analysis.Hand14Result {
+	var rs []*analysis.Hand14Result
+	for _, r := range results {
+		result := &analysis.Hand14Result{
+			Discard:     int32(r.DiscardTile),
+			DiscardDora: r.IsDiscardDoraTile,
+			OpenTiles:   grpcInts(r.OpenTiles),
+			Result13:    grpcHand13Result(r.Result13),
+		}
+		if r.Risk != nil {
+			result.HasRisk = true
+			result.Risk = *r.Risk
+		}
+		rs = append(rs, result)
+	}
+	return rs
+}
+
+func grpcHandAnalysis(a *apiHandAnalysis) *analysis.HandAnalysis {
+	if a == nil {
+		return nil
+	}
+	ha := &analysis.HandAnalysis{
+		Hand:             a.Hand,
+		Shanten:          int32(a.Shanten),
+		Result13:         grpcHand13Result(a.Result13),
+		Discards:         grpcHand14Results(a.Discards),
+		BackwardDiscards: grpcHand14Results(a.BackwardDiscards),
+	}
+	if m := a.Meld; m != nil {
+		ha.Meld = &analysis.MeldAnalysis{
+			Target:           int32(m.TargetTile),
+			RedFive:          m.IsRedFive,
+			AllowChi:         m.AllowChi,
+			Shanten:          int32(m.Shanten),
+			Discards:         grpcHand14Results(m.Discards),
+			BackwardDiscards: grpcHand14Results(m.BackwardDiscards),
+		}
+	}
+	return ha
+}
+
+func grpcRiskEvaluation(state *apiGameState) *analysis.RiskEvaluation {
+	re := &analysis.RiskEvaluation{MixedRiskTable: state.MixedRiskTable}
+	for _, ps := range state.Players {
+		if ps.Risk == nil {
+			continue
+		}
+		re.Players = append(re.Players, &analysis.PlayerRisk{
+			Who:             int32(ps.Who),
+			TenpaiRate:      ps.Risk.TenpaiRate,
+			TsumogiriRiichi: ps.Risk.IsTsumogiriRiichi,
+			SafeTiles:       grpcInts(ps.Risk.SafeTiles),
+			RiskTable:       ps.Risk.RiskTable,
+			LeftNoSujiTiles: grpcInts(ps.Risk.LeftNoSujiTiles),
+		})
+	}
+	return re
+}
+
+// 以下将 gRPC 的请求转换成 event 中的数据
+
+func checkGRPCTile(tile int32) error {
+	if tile < 0 || tile >= 34 {
+		return fmt.Errorf("牌 %d 不合法", tile)
+	}
+	return nil
+}
+
+func checkGRPCWho(who int32) error {
+	if who < 0 || who >= 4 {
+		return fmt.Errorf("who %d 不合法", who)
+	}
+	return nil
+}
+
+func checkedInts(a []int32, isTile bool) ([]int, error) {
+	b := make([]int, len(a))
+	for i, v := range a {
+		if isTile {
+			if err := checkGRPCTile(v); err != nil {
+				return nil, err
+			}
+		}
+		b[i] = int(v)
+	}
+	return b, nil
+}
+
+func meldFromGRPC(m *analysis.Meld) (*model.Meld, error) {
+	if m == nil {
+		return nil, fmt.Errorf("缺少副露")
+	}
+	if m.Type < model.MeldTypeChi || m.Type > model.MeldTypeKakan {
+		return nil, fmt.Errorf("副露类型 %d 不合法", m.Type)
+	}
+	if err := checkGRPCTile(m.Called); err != nil {
+		return nil, err
+	}
+	tiles, err := checkedInts(m.Tiles, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(tiles) < 3 || len(tiles) > 4 {
+		return nil, fmt.Errorf("副露的牌数 %d 不合法", len(tiles))
+	}
+	selfTiles, err := checkedInts(m.SelfTiles, true)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Meld{
+		MeldType:          int(m.Type),
+		Tiles:             tiles,
+		SelfTiles:         selfTiles,
+		CalledTile:        int(m.Called),
+		ContainRedFive:    m.Red,
+		RedFiveFromOthers: m.RedFromOthers,
+	}, nil
+}
+
+func roundStartFromGRPC(rs *analysis.RoundStart) (*event.RoundStart, error) {
+	if err := checkGRPCWho(rs.Dealer); err != nil {
+		return nil, err
+	}
+	playerNumber := int(rs.PlayerNumber)
+	if playerNumber == 0 {
+		playerNumber = 4
+	}
+	if playerNumber != 3 && playerNumber != 4 {
+		return nil, fmt.Errorf("人数 %d 不合法", playerNumber)
+	}
+	doraIndicators, err := checkedInts(rs.DoraIndicators, true)
+	if err != nil {
+		return nil, err
+	}
+	hand, err := checkedInts(rs.Hand, true)
+	if err != nil {
+		return nil, err
+	}
+	// 重连时手牌数会因副露而减少
+	if len(hand) > 14 || len(hand)%3 == 0 {
+		return nil, fmt.Errorf("手牌数 %d 不合法", len(hand))
+	}
+	numRedFives, _ := checkedInts(rs.RedFives, false)
+	if len(numRedFives) == 0 {
+		numRedFives = make([]int, 3)
+	}
+	if len(numRedFives) != 3 {
+		return nil, fmt.Errorf("red_fives 的长度应为 3")
+	}
+
+	e := &event.RoundStart{
+		RoundNumber:    int(rs.Round),
+		BenNumber:      int(rs.Ben),
+		Dealer:         int(rs.Dealer),
+		PlayerNumber:   playerNumber,
+		DoraIndicators: doraIndicators,
+		HandTiles:      hand,
+		NumRedFives:    numRedFives,
+	}
+	if len(rs.Snapshot) == 0 {
+		return e, nil
+	}
+	if len(rs.Snapshot) > 4 {
+		return nil, fmt.Errorf("snapshot 的长度 %d 不合法", len(rs.Snapshot))
+	}
+	e.Snapshot = &event.Snapshot{}
+	for who := range e.Snapshot.Players {
+		e.Snapshot.Players[who].ReachTileAt = -1
+	}
+	for who, ps := range rs.Snapshot {
+		snapshot := &e.Snapshot.Players[who]
+		for _, m := range ps.Melds {
+			meld, err := meldFromGRPC(m)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.Melds = append(snapshot.Melds, meld)
+		}
+		for _, tile := range ps.Discards {
+			if tile < 0 {
+				if err := checkGRPCTile(^tile); err != nil {
+					return nil, err
+				}
+			} else if err := checkGRPCTile(tile); err != nil {
+				return nil, err
+			}
+			snapshot.DiscardTiles = append(snapshot.DiscardTiles, int(tile))
+		}
+		if ps.Riichi {
+			if ps.RiichiTileAt < 0 || int(ps.RiichiTileAt) >= len(ps.Discards) {
+				return nil, fmt.Errorf("riichi_tile_at %d 不合法", ps.RiichiTileAt)
+			}
+			snapshot.ReachTileAt = int(ps.RiichiTileAt)
+		}
+		snapshot.NukiDoraNum = int(ps.Nuki)
+	}
+	return e, nil
+}
+
+// 将 gRPC 的事件转换成 event.Event，并检查数据是否合法
+func eventFromGRPC(pe *analysis.Event) (*event.Event, error) {
+	switch ev := pe.Event.(type) {
+	case *analysis.Event_GameStart:
+		if err := checkGRPCWho(ev.GameStart.Dealer); err != nil {
+			return nil, err
+		}
+		return event.NewGameStart(&event.GameStart{Dealer: int(ev.GameStart.Dealer)}), nil
+	case *analysis.Event_RoundStart:
+		rs, err := roundStartFromGRPC(ev.RoundStart)
+		if err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeRoundStart, RoundStart: rs}, nil
+	case *analysis.Event_Draw:
+		if err := checkGRPCTile(ev.Draw.Tile); err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeDraw, Draw: &event.Draw{Tile: int(ev.Draw.Tile), IsRedFive: ev.Draw.RedFive}}, nil
+	case *analysis.Event_Discard:
+		di := ev.Discard
+		if err := checkGRPCWho(di.Who); err != nil {
+			return nil, err
+		}
+		if err := checkGRPCTile(di.Tile); err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeDiscard, Discard: &event.Discard{
+			Who:         int(di.Who),
+			Tile:        int(di.Tile),
+			IsRedFive:   di.RedFive,
+			IsTsumogiri: di.Tsumogiri,
+			IsReach:     di.Riichi,
+			CanBeMeld:   di.CanBeMeld,
+		}}, nil
+	case *analysis.Event_Call:
+		if err := checkGRPCWho(ev.Call.Who); err != nil {
+			return nil, err
+		}
+		meld, err := meldFromGRPC(ev.Call.Meld)
+		if err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeCall, Call: &event.Call{Who: int(ev.Call.Who), Meld: meld}}, nil
+	case *analysis.Event_Riichi:
+		if err := checkGRPCWho(ev.Riichi.Who); err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeRiichi, Riichi: &event.Riichi{Who: int(ev.Riichi.Who)}}, nil
+	case *analysis.Event_NewDora:
+		if err := checkGRPCTile(ev.NewDora.Indicator); err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeNewDora, NewDora: &event.NewDora{Indicator: int(ev.NewDora.Indicator)}}, nil
+	case *analysis.Event_Nuki:
+		if err := checkGRPCWho(ev.Nuki.Who); err != nil {
+			return nil, err
+		}
+		return &event.Event{Type: event.TypeNuki, Nuki: &event.Nuki{Who: int(ev.Nuki.Who), IsTsumogiri: ev.Nuki.Tsumogiri}}, nil
+	case *analysis.Event_Furiten:
+		return event.NewFuriten(), nil
+	case *analysis.Event_Win:
+		whos, _ := checkedInts(ev.Win.Whos, false)
+		points, _ := checkedInts(ev.Win.Points, false)
+		return &event.Event{Type: event.TypeWin, Win: &event.Win{Whos: whos, Points: points}}, nil
+	case *analysis.Event_DrawGame:
+		whos, _ := checkedInts(ev.DrawGame.Whos, false)
+		points, _ := checkedInts(ev.DrawGame.Points, false)
+		return &event.Event{Type: event.TypeDrawGame, DrawGame: &event.DrawGame{Type: int(ev.DrawGame.Type), Whos: whos, Points: points}}, nil
+	default:
+		return nil, fmt.Errorf("缺少事件")
+	}
+}
+
+// 处理一个事件，返回处理后的分析结果，返回的错误均为 *apiError
+func (d *eventLogRoundData) grpcAnalysisEvent(e *event.Event) (result *analysis.EventAnalysis, err error) {
+	defer func() {
+		if er := recover(); er != nil {
+			err = &apiError{Code: apiErrorInternal, Message: fmt.Sprint(er)}
+		}
+	}()
+
+	if err := d.handleEvent(e); err != nil {
+		return nil, &apiError{Code: apiErrorInvalidEvent, Message: err.Error()}
+	}
+	state, err := d.apiGameState("")
+	if err != nil {
+		return nil, &apiError{Code: apiErrorInternal, Message: err.Error()}
+	}
+	return &analysis.EventAnalysis{
+		Type:     pushType(e, state),
+		Analysis: grpcHandAnalysis(state.Analysis),
+		Risk:     grpcRiskEvaluation(state),
+	}, nil
+}
+
+// 每个请求（流）使用独立的牌局数据，不输出到控制台
+func newGRPCRoundData() *eventLogRoundData {
+	d := newEventLogRoundData()
+	d.skipOutput = true
+	return d
+}
+
+func (*analysisServer) AnalyzeHand(ctx context.Context, req *analysis.AnalyzeHandRequest) (*analysis.HandAnalysis, error) {
+	resp, err := analysisAPIRequest(&apiAnalysisRequest{Tiles: req.Tiles, Dora: req.Dora, Tsumo: req.Tsumo})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return grpcHandAnalysis(resp.apiHandAnalysis), nil
+}
+
+func (*analysisServer) CalculatePoint(ctx context.Context, req *analysis.CalculatePointRequest) (*analysis.PointResult, error) {
+	result, err := calculatePointRequest(req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return result, nil
+}
+
+// 检查并计算已和牌的手牌的点数，返回的错误均为 *apiError
+func calculatePointRequest(req *analysis.CalculatePointRequest) (result *analysis.PointResult, err error) {
+	defer func() {
+		if er := recover(); er != nil {
+			err = &apiError{Code: apiErrorInternal, Message: fmt.Sprint(er)}
+		}
+	}()
+
+	if req.Tiles == "" {
+		return nil, &apiError{Code: apiErrorInvalidTiles, Message: "缺少手牌", Field: "tiles"}
+	}
+	playerInfo, targetTile34, _, err := parseHumanTilesInfo(&model.HumanTilesInfo{HumanTiles: req.Tiles})
+	if err != nil {
+		return nil, &apiError{Code: apiErrorInvalidTiles, Message: err.Error(), Field: "tiles"}
+	}
+	if targetTile34 != -1 {
+		return nil, &apiError{Code: apiErrorInvalidTiles, Message: "和了牌请用 win_tile 指定", Field: "tiles"}
+	}
+	if req.Dora != "" {
+		doraTiles, _, er := util.StrToTiles(req.Dora)
+		if er != nil {
+			return nil, &apiError{Code: apiErrorInvalidDora, Message: er.Error(), Field: "dora"}
+		}
+		playerInfo.DoraTiles = doraTiles
+	}
+
+	tileCount := util.CountOfTiles34(playerInfo.HandTiles34)
+	if tileCount > 14 || tileCount%3 != 2 {
+		return nil, &apiError{Code: apiErrorInvalidTileCount, Message: fmt.Sprintf("输入错误：手牌为 %d 张，应为 3k+2 张", tileCount), Field: "tiles"}
+	}
+	if util.CalculateShanten(playerInfo.HandTiles34) != -1 {
+		return nil, &apiError{Code: apiErrorInvalidTiles, Message: "未和牌", Field: "tiles"}
+	}
+
+	winTile, _, err := util.StrToTile34(req.WinTile)
+	if err != nil {
+		return nil, &apiError{Code: apiErrorInvalidWinTile, Message: err.Error(), Field: "win_tile"}
+	}
+	if playerInfo.HandTiles34[winTile] == 0 {
+		return nil, &apiError{Code: apiErrorInvalidWinTile, Message: "手牌中没有和了牌", Field: "win_tile"}
+	}
+
+	for _, wind := range []struct {
+		tile  *int
+		value int32
+		field string
+	}{
+		{&playerInfo.RoundWindTile, req.RoundWind, "round_wind"},
+		{&playerInfo.SelfWindTile, req.SelfWind, "self_wind"},
+	} {
+		if wind.value == 0 {
+			continue
+		}
+		if wind.value < 27 || wind.value > 30 {
+			return nil, &apiError{Code: apiErrorInvalidWind, Message: fmt.Sprintf("风牌 %d 不合法", wind.value), Field: wind.field}
+		}
+		*wind.tile = int(wind.value)
+	}
+
+	playerInfo.WinTile = winTile
+	playerInfo.IsTsumo = req.Tsumo
+	playerInfo.IsRiichi = req.Riichi || req.Daburii
+	playerInfo.IsDaburii = req.Daburii
+	playerInfo.IsParent = playerInfo.SelfWindTile == 27
+
+	pr := util.CalcPoint(playerInfo)
+	han, fu := pr.HanFu()
+	yakuTypes := pr.YakuTypes()
+	result = &analysis.PointResult{
+		Point:        int32(pr.Point),
+		Han:          int32(han),
+		Fu:           int32(fu),
+		YakumanTimes: int32(pr.YakumanTimes()),
+		YakuTypes:    grpcInts(yakuTypes),
+		YakuNames:    apiYakuNames(yakuTypes),
+		DoraCount:    int32(playerInfo.CountDora()),
+	}
+	if req.Tsumo && pr.Point > 0 {
+		childPoint, parentPoint := pr.TsumoPoints()
+		result.TsumoChildPoint = int32(childPoint)
+		result.TsumoParentPoint = int32(parentPoint)
+	}
+	return result, nil
+}
+
+func (*analysisServer) EvaluateRisk(ctx context.Context, req *analysis.EvaluateRiskRequest) (*analysis.RiskEvaluation, error) {
+	if req.Round == nil {
+		return nil, status.Error(codes.InvalidArgument, "round: 缺少一局开始时的信息")
+	}
+	rs, err := roundStartFromGRPC(req.Round)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "round: %v", err)
+	}
+	result, err := newGRPCRoundData().grpcAnalysisEvent(&event.Event{Type: event.TypeRoundStart, RoundStart: rs})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return result.Risk, nil
+}
+
+func (*analysisServer) AnalyzeEvents(stream analysis.Analysis_AnalyzeEventsServer) error {
+	d := newGRPCRoundData()
+	for i := 0; ; i++ {
+		pe, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		e, err := eventFromGRPC(pe)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "第 %d 个事件：%v", i, err)
+		}
+		result, err := d.grpcAnalysisEvent(e)
+		if err != nil {
+			apiErr := err.(*apiError)
+			apiErr.Message = fmt.Sprintf("第 %d 个事件：%s", i, apiErr.Message)
+			return grpcError(apiErr)
+		}
+		result.Index = int32(i)
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
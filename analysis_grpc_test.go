@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+	"github.com/EndlessCheng/mahjong-helper/proto/analysis"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 在本地端口上启动 gRPC 服务，返回连接到该服务的客户端
+func newTestGRPCClient(t *testing.T) (analysis.AnalysisClient, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newGRPCServer()
+	go server.Serve(listener)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, listener.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		server.Stop()
+		t.Fatal(err)
+	}
+	return analysis.NewAnalysisClient(conn), func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func Test_analysisServer(t *testing.T) {
+	assert := assert.New(t)
+
+	client, closeFunc := newTestGRPCClient(t)
+	defer closeFunc()
+	ctx := context.Background()
+
+	// 何切
+	ha, err := client.AnalyzeHand(ctx, &analysis.AnalyzeHandRequest{Tiles: "123m 456p 789s 11z 349m", Dora: "1m"})
+	if assert.NoError(err) {
+		assert.EqualValues(0, ha.Shanten)
+		if assert.NotEmpty(ha.Discards) {
+			assert.EqualValues(8, ha.Discards[0].Discard) // 9m
+			assert.Contains(ha.Discards[0].Result13.Waits, int32(1))
+			assert.False(ha.Discards[0].HasRisk)
+		}
+	}
+
+	// 鸣牌
+	ha, err = client.AnalyzeHand(ctx, &analysis.AnalyzeHandRequest{Tiles: "123m 456p 789s 1z 34m 6z + 5m"})
+	if assert.NoError(err) && assert.NotNil(ha.Meld) {
+		assert.NotNil(ha.Result13)
+		assert.EqualValues(4, ha.Meld.Target)
+		assert.NotEmpty(ha.Meld.Discards)
+	}
+
+	_, err = client.AnalyzeHand(ctx, &analysis.AnalyzeHandRequest{Tiles: "123x"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	// 点数：一气通贯 平和，子家荣和
+	pr, err := client.CalculatePoint(ctx, &analysis.CalculatePointRequest{Tiles: "123456789m 234p 55s", WinTile: "9m", SelfWind: 28})
+	if assert.NoError(err) {
+		assert.EqualValues(3900, pr.Point)
+		assert.EqualValues(3, pr.Han)
+		assert.EqualValues(30, pr.Fu)
+		assert.Len(pr.YakuNames, 2)
+	}
+	// 亲家自摸
+	pr, err = client.CalculatePoint(ctx, &analysis.CalculatePointRequest{Tiles: "123456789m 234p 55s", WinTile: "9m", Tsumo: true})
+	if assert.NoError(err) {
+		assert.EqualValues(4, pr.Han)
+		assert.EqualValues(20, pr.Fu)
+		assert.EqualValues(2600, pr.TsumoChildPoint)
+		assert.EqualValues(0, pr.TsumoParentPoint)
+	}
+
+	for _, req := range []*analysis.CalculatePointRequest{
+		{Tiles: "123456789m 234p 56s", WinTile: "9m"},  // 未和牌
+		{Tiles: "123456789m 234p 55s", WinTile: "1p"},  // 手牌中没有和了牌
+		{Tiles: "123456789m 234p 55s", WinTile: "9m", RoundWind: 31},
+	} {
+		_, err = client.CalculatePoint(ctx, req)
+		assert.Equal(codes.InvalidArgument, status.Code(err), req.String())
+	}
+
+	// 上家立直
+	re, err := client.EvaluateRisk(ctx, &analysis.EvaluateRiskRequest{Round: &analysis.RoundStart{
+		DoraIndicators: []int32{0},
+		Hand:           []int32{0, 1, 2, 12, 13, 14, 24, 25, 26, 27, 27, 2, 3},
+		Snapshot: []*analysis.PlayerSnapshot{
+			{Discards: []int32{33, 32}},
+			{Discards: []int32{31, ^30}},
+			{Discards: []int32{29, 28}},
+			{Discards: []int32{18, 8, ^9}, Riichi: true, RiichiTileAt: 1},
+		},
+	}})
+	if assert.NoError(err) {
+		assert.Len(re.MixedRiskTable, 34)
+		if assert.Len(re.Players, 3) {
+			assert.EqualValues(3, re.Players[2].Who)
+			assert.EqualValues(100, re.Players[2].TenpaiRate)
+			assert.Contains(re.Players[2].SafeTiles, int32(8))
+			assert.EqualValues(0, re.Players[2].RiskTable[8])
+		}
+	}
+
+	_, err = client.EvaluateRisk(ctx, &analysis.EvaluateRiskRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func Test_analysisServer_AnalyzeEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	client, closeFunc := newTestGRPCClient(t)
+	defer closeFunc()
+
+	stream, err := client.AnalyzeEvents(context.Background())
+	if !assert.NoError(err) {
+		return
+	}
+	events := []*analysis.Event{
+		{Event: &analysis.Event_RoundStart{RoundStart: &analysis.RoundStart{
+			Dealer:         3,
+			DoraIndicators: []int32{0},
+			Hand:           []int32{0, 1, 2, 12, 13, 14, 24, 25, 26, 27, 27, 2, 3},
+		}}},
+		{Event: &analysis.Event_Discard{Discard: &analysis.Discard{Who: 3, Tile: 4, CanBeMeld: true}}},
+		{Event: &analysis.Event_DrawGame{DrawGame: &analysis.DrawGame{}}},
+	}
+	for i, e := range events {
+		if !assert.NoError(stream.Send(e)) {
+			return
+		}
+		result, err := stream.Recv()
+		if !assert.NoError(err) {
+			return
+		}
+		assert.EqualValues(i, result.Index)
+		switch i {
+		case 0:
+			assert.Equal(pushTypeRoundStart, result.Type)
+			if assert.NotNil(result.Analysis) {
+				assert.EqualValues(0, result.Analysis.Shanten)
+			}
+		case 1:
+			assert.Equal(pushTypeCall, result.Type)
+			if assert.NotNil(result.Analysis) && assert.NotNil(result.Analysis.Meld) {
+				assert.EqualValues(4, result.Analysis.Meld.Target)
+				assert.True(result.Analysis.Meld.AllowChi)
+			}
+			if assert.Len(result.Risk.Players, 3) {
+				assert.Contains(result.Risk.Players[2].SafeTiles, int32(4))
+			}
+		case 2:
+			assert.Equal(pushTypeRoundEnd, result.Type)
+		}
+	}
+
+	// 不合法的事件会结束这个流
+	assert.NoError(stream.Send(&analysis.Event{Event: &analysis.Event_Draw{Draw: &analysis.Draw{Tile: 34}}}))
+	_, err = stream.Recv()
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
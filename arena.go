@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai/arena"
+)
+
+// 将 mjai AI 接入对战场
+type arenaPlayer struct {
+	*mjaiBot
+}
+
+func (p *arenaPlayer) React(msg *mjai.Message) *mjai.Action {
+	return p.react(msg)
+}
+
+// 解析 -arena-players，以逗号分隔的 1-4 个配置名，不足 4 个时循环补足
+func parseArenaConfigs(s string) (names [4]string, err error) {
+	splits := strings.Split(s, ",")
+	if len(splits) < 1 || len(splits) > 4 {
+		return names, fmt.Errorf("需要 1-4 个配置，输入的是 %s", s)
+	}
+	for i, name := range splits {
+		name = strings.TrimSpace(name)
+		if _, ok := mjaiBotConfigs[name]; !ok {
+			return names, fmt.Errorf("未知的配置 %s（可用的配置有 %s）", name, strings.Join(arenaConfigNames(), ","))
+		}
+		splits[i] = name
+	}
+	for i := range names {
+		names[i] = splits[i%len(splits)]
+	}
+	return
+}
+
+func arenaConfigNames() []string {
+	names := []string{}
+	for name := range mjaiBotConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var arenaWallGenerators = map[string]arena.NewWallGenerator{
+	"rand":   arena.NewRandWallGenerator,
+	"tenhou": arena.NewTenhouWallGenerator,
+}
+
+// 第 g 场对局：每 4 场使用同一个牌山种子，并轮换座位，以抵消牌运和座位的影响
+func playArenaGame(configNames [4]string, newWallGenerator arena.NewWallGenerator, seed int64, g int) (result *arena.GameResult, seatConfigNames [4]string) {
+	players := [4]arena.Player{}
+	for i := range players {
+		seatConfigNames[i] = configNames[(i+g)%4]
+		bot := newMjaiBot(seatConfigNames[i], -1, seed+int64(g)*4+int64(i))
+		bot.config = mjaiBotConfigs[seatConfigNames[i]]
+		players[i] = &arenaPlayer{bot}
+	}
+	walls := newWallGenerator(seed + int64(g/4))
+	return arena.PlayGame(players, walls, arena.Hanchan), seatConfigNames
+}
+
+// 自战 numGames 个半庄，按配置汇总结果
+// 多个对局并行进行，结果与并行度无关
+func runArena(configNames [4]string, newWallGenerator arena.NewWallGenerator, numGames int, seed int64) []*arena.Summary {
+	type gameResult struct {
+		result          *arena.GameResult
+		seatConfigNames [4]string
+	}
+	results := make([]gameResult, numGames)
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	finished := 0
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range jobs {
+				result, seatConfigNames := playArenaGame(configNames, newWallGenerator, seed, g)
+				results[g] = gameResult{result, seatConfigNames}
+
+				mu.Lock()
+				finished++
+				if finished%100 == 0 || finished == numGames {
+					fmt.Fprintf(os.Stderr, "已完成 %d/%d 场\n", finished, numGames)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for g := 0; g < numGames; g++ {
+		jobs <- g
+	}
+	close(jobs)
+	wg.Wait()
+
+	summaries := []*arena.Summary{}
+	summaryMap := map[string]*arena.Summary{}
+	for _, name := range configNames {
+		if _, ok := summaryMap[name]; !ok {
+			summaryMap[name] = &arena.Summary{Name: name}
+			summaries = append(summaries, summaryMap[name])
+		}
+	}
+	for _, r := range results {
+		for seat, name := range r.seatConfigNames {
+			summaryMap[name].Add(r.result, seat)
+		}
+	}
+	return summaries
+}
+
+func printArenaSummaries(w io.Writer, summaries []*arena.Summary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "配置\t场数\t平均顺位±95%\t一位率\t四位率\t和了率\t放铳率\t立直率\t副露率\t流局听牌率\t平均打点\t平均得点±95%\t违规\t")
+	for _, s := range summaries {
+		avgRank, rankCI := s.AvgRank()
+		avgScore, scoreCI := s.AvgScore()
+		fmt.Fprintf(tw, "%s\t%d\t%.3f±%.3f\t%.1f%%\t%.1f%%\t%.1f%%\t%.1f%%\t%.1f%%\t%.1f%%\t%.1f%%\t%.0f\t%.0f±%.0f\t%d\t\n",
+			s.Name, s.Games, avgRank, rankCI,
+			100*s.RankRate(1), 100*s.RankRate(4),
+			100*s.WinRate(), 100*s.DealInRate(), 100*s.RiichiRate(), 100*s.CallRate(), 100*s.TenpaiAtDrawRate(),
+			s.AvgWinPoint(), avgScore, scoreCI, s.Stats.InvalidActions,
+		)
+	}
+	tw.Flush()
+}
+
+// 自战模式，结果表格输出到标准输出，进度输出到标准错误
+func runArenaMode(configs string, wall string, numGames int, seed int64) error {
+	configNames, err := parseArenaConfigs(configs)
+	if err != nil {
+		return err
+	}
+	newWallGenerator, ok := arenaWallGenerators[wall]
+	if !ok {
+		return fmt.Errorf("未知的牌山生成算法 %s", wall)
+	}
+	if numGames%4 != 0 {
+		fmt.Fprintf(os.Stderr, "提示：场数为 4 的倍数时每个配置在每个座位上打的牌山相同，比较更公平\n")
+	}
+	summaries := runArena(configNames, newWallGenerator, numGames, seed)
+	printArenaSummaries(os.Stdout, summaries)
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseArenaConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	names, err := parseArenaConfigs("default")
+	assert.NoError(err)
+	assert.Equal([4]string{"default", "default", "default", "default"}, names)
+
+	names, err = parseArenaConfigs("default, push")
+	assert.NoError(err)
+	assert.Equal([4]string{"default", "push", "default", "push"}, names)
+
+	names, err = parseArenaConfigs("default,push,fold")
+	assert.NoError(err)
+	assert.Equal([4]string{"default", "push", "fold", "default"}, names)
+
+	_, err = parseArenaConfigs("default,unknown")
+	assert.Error(err)
+
+	_, err = parseArenaConfigs("default,push,fold,dama,menzen")
+	assert.Error(err)
+}
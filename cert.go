@@ -0,0 +1,253 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/fatih/color"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// HTTPS 证书
+// 默认在 cert 目录下生成一个本地 CA，并用它签发 localhost 的证书，用户信任该 CA 后浏览器便不会报错
+// 也可以用 -cert 和 -key（或 config.json 中的 tls_cert_file 和 tls_key_file）指定自己的证书
+
+const (
+	certDir = "cert"
+
+	caCertFileName   = "ca.crt"
+	caKeyFileName    = "ca.key"
+	leafCertFileName = "localhost.crt"
+	leafKeyFileName  = "localhost.key"
+
+	caValidity = 10 * 365 * 24 * time.Hour
+	// 浏览器不接受有效期超过 398 天的证书
+	leafValidity = 397 * 24 * time.Hour
+
+	// 证书在该时间内过期时提醒用户，自动生成的证书会重新生成
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+// localhost 证书中包含的域名和 IP
+var localCertHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+func newCertSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func writePEMFile(filePath string, blockType string, der []byte, perm os.FileMode) error {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	return ioutil.WriteFile(filePath, data, perm)
+}
+
+// 生成证书并保存，返回证书和私钥
+// parent 为 nil 时生成自签名的 CA 证书
+func createCertificate(template *x509.Certificate, parent *x509.Certificate, parentKey crypto.Signer, certFile string, keyFile string) (*x509.Certificate, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 私钥只允许自己读写
+	if err := writePEMFile(keyFile, "PRIVATE KEY", keyDER, 0600); err != nil {
+		return nil, nil, err
+	}
+	if err := writePEMFile(certFile, "CERTIFICATE", der, 0644); err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func createLocalCA(dir string) (*x509.Certificate, crypto.Signer, error) {
+	serialNumber, err := newCertSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"mahjong-helper"},
+			CommonName:   "mahjong-helper local CA",
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	return createCertificate(template, nil, nil, filepath.Join(dir, caCertFileName), filepath.Join(dir, caKeyFileName))
+}
+
+func createLocalLeafCertificate(dir string, ca *x509.Certificate, caKey crypto.Signer) error {
+	serialNumber, err := newCertSerialNumber()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"mahjong-helper"},
+			CommonName:   "localhost",
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(leafValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range localCertHosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	_, _, err = createCertificate(template, ca, caKey, filepath.Join(dir, leafCertFileName), filepath.Join(dir, leafKeyFileName))
+	return err
+}
+
+// 读取 PEM 格式的证书和私钥
+func loadCertificate(certFile string, keyFile string) (tls.Certificate, *x509.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	cert.Leaf = leaf
+	return cert, leaf, nil
+}
+
+// 证书是否仍可继续使用（不会在 certRenewBefore 内过期）
+func isCertUsable(cert *x509.Certificate, now time.Time) bool {
+	return now.After(cert.NotBefore) && now.Add(certRenewBefore).Before(cert.NotAfter)
+}
+
+// 读取或生成本地 CA，以及由该 CA 签发的 localhost 证书
+// 证书不存在、即将过期或与 CA 不匹配时重新生成，isNewCA 表示是否重新生成了 CA（此时需要用户重新信任）
+func localCertificate(dir string) (cert tls.Certificate, isNewCA bool, err error) {
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	now := time.Now()
+
+	caTLSCert, caCert, err := loadCertificate(filepath.Join(dir, caCertFileName), filepath.Join(dir, caKeyFileName))
+	var caKey crypto.Signer
+	if err == nil && caCert.IsCA && isCertUsable(caCert, now) {
+		caKey, _ = caTLSCert.PrivateKey.(crypto.Signer)
+	}
+	if caKey == nil {
+		if caCert, caKey, err = createLocalCA(dir); err != nil {
+			return
+		}
+		isNewCA = true
+	}
+
+	leafCertFile := filepath.Join(dir, leafCertFileName)
+	leafKeyFile := filepath.Join(dir, leafKeyFileName)
+	if !isNewCA {
+		cert, leaf, er := loadCertificate(leafCertFile, leafKeyFile)
+		if er == nil && isCertUsable(leaf, now) && leaf.CheckSignatureFrom(caCert) == nil {
+			return cert, false, nil
+		}
+	}
+	if err = createLocalLeafCertificate(dir, caCert, caKey); err != nil {
+		return
+	}
+	cert, _, err = loadCertificate(leafCertFile, leafKeyFile)
+	return
+}
+
+// 提醒用户证书即将过期或已过期
+func warnCertExpiry(cert *x509.Certificate, certFile string, now time.Time) {
+	switch {
+	case now.After(cert.NotAfter):
+		color.HiRed("证书 %s 已于 %s 过期，浏览器将无法连接到助手，请更换证书", certFile, cert.NotAfter.Local().Format("2006-01-02"))
+	case now.Add(certRenewBefore).After(cert.NotAfter):
+		color.HiYellow("证书 %s 将于 %s 过期，请及时更换证书", certFile, cert.NotAfter.Local().Format("2006-01-02"))
+	}
+}
+
+// 输出信任本地 CA 的方法
+func printTrustCAInstructions(caCertFile string) {
+	if absPath, err := filepath.Abs(caCertFile); err == nil {
+		caCertFile = absPath
+	}
+	color.HiYellow("已生成本地 CA 证书 %s", caCertFile)
+	fmt.Println("信任该证书后，浏览器便可以直接连接到助手（重新生成后需要重新信任）：")
+	switch runtime.GOOS {
+	case "windows":
+		fmt.Println("  双击该证书，点击「安装证书」，选择「当前用户」，将证书放入「受信任的根证书颁发机构」；或在命令提示符中执行")
+		fmt.Printf("  certutil -user -addstore Root \"%s\"\n", caCertFile)
+	case "darwin":
+		fmt.Println("  在终端中执行")
+		fmt.Printf("  sudo security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain \"%s\"\n", caCertFile)
+	default:
+		fmt.Println("  Chrome 内核的浏览器可在终端中执行（需要安装 libnss3-tools）")
+		fmt.Printf("  certutil -d sql:$HOME/.pki/nssdb -A -t \"C,,\" -n mahjong-helper -i \"%s\"\n", caCertFile)
+	}
+	fmt.Println("  也可以在浏览器的设置中搜索「证书」，在「管理证书」的「授权机构」中导入该证书")
+	fmt.Println("  Firefox 需要在「设置 - 隐私与安全 - 证书 - 查看证书 - 证书颁发机构」中导入该证书")
+	fmt.Println()
+}
+
+// 获取 HTTPS 使用的证书
+// certFile 和 keyFile 为空时使用 config.json 中的配置，仍为空时使用 cert 目录下自动生成的证书
+func loadTLSCertificate(certFile string, keyFile string) (cert tls.Certificate, err error) {
+	if certFile == "" && keyFile == "" {
+		certFile, keyFile = gameConf.TLSCertFile, gameConf.TLSKeyFile
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return tls.Certificate{}, fmt.Errorf("请同时指定证书和私钥")
+		}
+		cert, leaf, er := loadCertificate(certFile, keyFile)
+		if er != nil {
+			return tls.Certificate{}, fmt.Errorf("读取证书失败：%v", er)
+		}
+		warnCertExpiry(leaf, certFile, time.Now())
+		return cert, nil
+	}
+
+	cert, isNewCA, err := localCertificate(certDir)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("生成证书失败：%v", err)
+	}
+	if isNewCA {
+		printTrustCAInstructions(filepath.Join(certDir, caCertFileName))
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"crypto"
+	"crypto/x509"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_localCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "mahjong-helper-cert")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// 首次运行时生成 CA 和 localhost 证书
+	cert, isNewCA, err := localCertificate(dir)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.True(isNewCA)
+	caTLSCert, caCert, err := loadCertificate(filepath.Join(dir, caCertFileName), filepath.Join(dir, caKeyFileName))
+	if !assert.NoError(err) {
+		return
+	}
+	assert.True(caCert.IsCA)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	for _, host := range localCertHosts {
+		_, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		assert.NoError(err, host)
+	}
+	assert.True(cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore) <= 398*24*time.Hour)
+
+	// 再次运行时使用已有的证书
+	cert2, isNewCA, err := localCertificate(dir)
+	if assert.NoError(err) {
+		assert.False(isNewCA)
+		assert.Equal(cert.Certificate, cert2.Certificate)
+	}
+
+	// localhost 证书即将过期时，用原有的 CA 重新签发
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certRenewBefore / 2),
+		DNSNames:     []string{"localhost"},
+	}
+	_, _, err = createCertificate(template, caCert, caTLSCert.PrivateKey.(crypto.Signer), filepath.Join(dir, leafCertFileName), filepath.Join(dir, leafKeyFileName))
+	assert.NoError(err)
+	cert3, isNewCA, err := localCertificate(dir)
+	if assert.NoError(err) {
+		assert.False(isNewCA)
+		assert.NotEqual(cert.Certificate, cert3.Certificate)
+		assert.NoError(cert3.Leaf.CheckSignatureFrom(caCert))
+		assert.True(isCertUsable(cert3.Leaf, time.Now()))
+	}
+
+	// 指定证书
+	cert4, err := loadTLSCertificate(filepath.Join(dir, leafCertFileName), filepath.Join(dir, leafKeyFileName))
+	if assert.NoError(err) {
+		assert.Equal(cert3.Certificate, cert4.Certificate)
+	}
+	_, err = loadTLSCertificate(filepath.Join(dir, leafCertFileName), "")
+	assert.Error(err)
+	_, err = loadTLSCertificate(filepath.Join(dir, "not_exist.crt"), filepath.Join(dir, leafKeyFileName))
+	assert.Error(err)
+}
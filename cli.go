@@ -316,6 +316,52 @@ func alertBackwardToShanten2(results util.Hand14AnalysisResultList, incShantenRe
 	}
 }
 
+// 打印杠的分析结果
+func printKanResults(results []*util.KanAnalysisResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	kanNames := map[int]string{
+		meldTypeAnkan:  "暗槓",
+		meldTypeMinkan: "大明槓",
+		meldTypeKakan:  "加槓",
+	}
+	for _, r := range results {
+		fmt.Printf("%s %s: ", kanNames[r.MeldType], util.Mahjong[r.KanTile])
+		if !r.Allowed {
+			color.New(color.FgHiBlack).Printf("不可（%s）\n", r.NotAllowedReason)
+			continue
+		}
+
+		shantenInfo := util.NumberToChineseShanten(r.Result13.Shanten) + " => " + util.NumberToChineseShanten(r.KanResult13.Shanten)
+		fmt.Print(shantenInfo)
+		if r.Result13.Shanten == 0 && r.KanResult13.Shanten == 0 {
+			if r.IsWaitsChanged {
+				fmt.Printf(" 待ち %d => %d 枚", r.Result13.Waits.AllCount(), r.KanResult13.Waits.AllCount())
+			} else {
+				fmt.Print(" 待ち不変")
+			}
+		}
+		if r.RinshanAgariRate > 0 {
+			fmt.Printf(" 嶺上%.2f%%", r.RinshanAgariRate)
+		}
+		fmt.Printf(" 新ドラ+%.2f", r.KanDoraExpectation)
+		fmt.Printf(" 局収支%+d", int(math.Round(r.PointDelta())))
+
+		if r.IsRecommended() {
+			color.HiGreen(" 【槓推奨】")
+		} else {
+			fmt.Println()
+		}
+
+		if r.NumReachedOpponents > 0 {
+			color.HiYellow("注意：リーチ者 %d 人、新ドラで相手のドラも +%.2f 枚（1人あたり）", r.NumReachedOpponents, r.OtherKanDoraExpectation)
+		}
+	}
+	fmt.Println()
+}
+
 // 注意が必要な役種
 var yakuTypesToAlert = []int{
 	//util.YakuKokushi,
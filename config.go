@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"bytes"
 	"os"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
 )
 
 const (
@@ -12,15 +15,26 @@ const (
 )
 
 type gameConfig struct {
+	// 保护 MajsoulAccountIDs、APIToken 的读写和配置文件的保存，多个会话可能同时登录
+	mu sync.Mutex
+
 	MajsoulAccountIDs []int `json:"majsoul_account_ids"`
 
-	currentActiveMajsoulAccountID int    `json:"-"`
-	currentActiveTenhouUsername   string `json:"-"`
+	// HTTPS 使用的证书和私钥（PEM），为空时使用自动生成的本地证书
+	TLSCertFile string `json:"tls_cert_file,omitempty"`
+	TLSKeyFile  string `json:"tls_key_file,omitempty"`
+
+	// 访问 HTTP 接口所需的令牌，首次启动服务时随机生成
+	APIToken string `json:"api_token,omitempty"`
+	// 允许跨域访问的网页，为空时使用 defaultAllowedOrigins
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+
+	// 牌局数据日志的大小上限和保留设置，为空时使用默认值
+	GameDataLog *gameDataLogConfig `json:"gamedata_log,omitempty"`
 }
 
 var gameConf = &gameConfig{
-	MajsoulAccountIDs:             []int{},
-	currentActiveMajsoulAccountID: -1,
+	MajsoulAccountIDs: []int{},
 }
 
 func init() {
@@ -46,18 +60,27 @@ func init() {
 	//fmt.Println(*gameConf)
 }
 
+// 调用前需持有 c.mu
 func (c *gameConfig) saveConfigToFile() error {
 	data, err := json.Marshal(c)
 	if err != nil {
 		return err
 	}
-	if err := ioutil.WriteFile(configFile, data, os.ModePerm); err != nil {
+	// 配置中有访问令牌，只允许当前用户读写
+	if err := ioutil.WriteFile(configFile, data, 0600); err != nil {
 		return err
 	}
-	return nil
+	// WriteFile 不会修改已有文件的权限
+	return os.Chmod(configFile, 0600)
 }
 
 func (c *gameConfig) isIDExist(majsoulAccountID int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c._isIDExist(majsoulAccountID)
+}
+
+func (c *gameConfig) _isIDExist(majsoulAccountID int) bool {
 	for _, id := range c.MajsoulAccountIDs {
 		if id == majsoulAccountID {
 			return true
@@ -67,13 +90,36 @@ func (c *gameConfig) isIDExist(majsoulAccountID int) bool {
 }
 
 func (c *gameConfig) addMajsoulAccountID(majsoulAccountID int) error {
-	if c.isIDExist(majsoulAccountID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c._isIDExist(majsoulAccountID) {
 		return nil
 	}
-	gameConf.MajsoulAccountIDs = append(gameConf.MajsoulAccountIDs, majsoulAccountID)
+	c.MajsoulAccountIDs = append(c.MajsoulAccountIDs, majsoulAccountID)
 	return c.saveConfigToFile()
 }
 
-func (c *gameConfig) setMajsoulAccountID(majsoulAccountID int) {
-	c.currentActiveMajsoulAccountID = majsoulAccountID
-}
\ No newline at end of file
+// 保存过的雀魂账号数
+func (c *gameConfig) numMajsoulAccountIDs() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.MajsoulAccountIDs)
+}
+
+// 获取访问 HTTP 接口所需的令牌，没有时随机生成并保存
+func (c *gameConfig) apiToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.APIToken != "" {
+		return c.APIToken, nil
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	c.APIToken = hex.EncodeToString(b)
+	if err := c.saveConfigToFile(); err != nil {
+		return "", err
+	}
+	return c.APIToken, nil
+}
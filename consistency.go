@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/fatih/color"
+)
+
+// 牌局数据一致性检查
+// 每处理完一个事件后检查：
+// 1. 牌数守恒：手牌 + 牌河 + 副露 + 拔北 + 宝牌指示牌 + 牌山剩余 = 每种牌 4 张（三麻 2-8m 为 0 张）
+// 2. 手牌数：手牌数 + 3*副露数 = 13 或 14，且与事件相符
+// 3. 舍牌顺序：舍牌需按照座次轮流进行，鸣牌后轮到鸣牌者
+// 发现异常后标记为不同步，在收到下一个权威数据（新的一局、天凤 REINIT、雀魂重连时的 sync_game_actions）后恢复
+type stateChecker struct {
+	// 是否处于一局之中，和牌或流局后至下一局开始前不做检查
+	inRound bool
+
+	// 本局是否检查手牌数（观战等情况下可能没有手牌）
+	checkHand bool
+
+	// 本局第几个事件，从 1 开始
+	eventIndex int
+
+	// 下一个应该舍牌的玩家，-1 表示未知（比如重连后）
+	nextDiscardWho int
+
+	// 被鸣走的牌仍然保留在舍牌者的牌河中，统计牌数时需要减去
+	calledCounts []int
+
+	// 数据是否已不同步
+	desynced bool
+
+	// 是否刚刚恢复同步，需要提示
+	resynced bool
+}
+
+func newStateChecker() *stateChecker {
+	return &stateChecker{
+		nextDiscardWho: -1,
+		calledCounts:   make([]int, 34),
+	}
+}
+
+// 下一个舍牌的玩家，三麻时跳过空位（北家）
+func (d *roundData) nextPlayer(who int) int {
+	for i := 1; i < len(d.players); i++ {
+		next := (who + i) % len(d.players)
+		if d.playerNumber != 3 || d.players[next].selfWindTile != 30 {
+			return next
+		}
+	}
+	return who
+}
+
+// 该种牌在一局中的总数
+func (d *roundData) totalTileCount(tile int) int {
+	if d.playerNumber == 3 && tile >= 1 && tile <= 7 {
+		// 三麻没有 2-8m
+		return 0
+	}
+	return 4
+}
+
+// 根据刚处理完的事件更新检查状态
+func (c *stateChecker) update(d *roundData, e *event.Event) (err error) {
+	c.eventIndex++
+
+	// 检查舍牌顺序
+	checkTurn := func(who int) {
+		if err == nil && c.nextDiscardWho != -1 && who != c.nextDiscardWho {
+			err = fmt.Errorf("顺序异常：应轮到%s，实际为%s", d.players[c.nextDiscardWho].name, d.players[who].name)
+		}
+	}
+
+	switch e.Type {
+	case event.TypeGameStart:
+		c.inRound = false
+	case event.TypeRoundStart:
+		c.inRound = true
+		c.checkHand = util.CountOfTiles34(d.counts) > 0
+		c.eventIndex = 1
+		c.calledCounts = make([]int, 34)
+		c.nextDiscardWho = d.dealer
+		if e.RoundStart.Snapshot != nil {
+			// 重连时不知道轮到谁
+			c.nextDiscardWho = -1
+		}
+		if c.desynced {
+			c.desynced = false
+			c.resynced = true
+		}
+	case event.TypeDraw:
+		checkTurn(0)
+		c.nextDiscardWho = 0
+	case event.TypeDiscard:
+		who := e.Discard.Who
+		checkTurn(who)
+		c.nextDiscardWho = d.nextPlayer(who)
+	case event.TypeCall:
+		who, meld := e.Call.Who, e.Call.Meld
+		switch meld.MeldType {
+		case meldTypeAnkan, meldTypeKakan:
+			checkTurn(who)
+		default:
+			c.calledCounts[meld.CalledTile]++
+		}
+		c.nextDiscardWho = who
+	case event.TypeNuki:
+		who := e.Nuki.Who
+		checkTurn(who)
+		c.nextDiscardWho = who
+	case event.TypeWin, event.TypeDrawGame:
+		c.inRound = false
+		c.nextDiscardWho = -1
+	}
+	return
+}
+
+// 检查牌数守恒
+func (c *stateChecker) checkTileCounts(d *roundData) error {
+	for tile := 0; tile < 34; tile++ {
+		if d.leftCounts[tile] < 0 {
+			return fmt.Errorf("牌山中 %s 的数量为 %d", util.MahjongZH[tile], d.leftCounts[tile])
+		}
+		if d.counts[tile] < 0 {
+			return fmt.Errorf("手牌中 %s 的数量为 %d", util.MahjongZH[tile], d.counts[tile])
+		}
+	}
+
+	visibleCounts := make([]int, 34)
+	for _, tile := range d.doraIndicators {
+		visibleCounts[tile]++
+	}
+	for _, player := range d.players {
+		for _, tile := range player.discardTiles {
+			if tile < 0 {
+				tile = ^tile
+			}
+			visibleCounts[tile]++
+		}
+		for _, meld := range player.melds {
+			for _, tile := range meld.Tiles {
+				visibleCounts[tile]++
+			}
+		}
+		visibleCounts[30] += player.nukiDoraNum
+	}
+	for tile, cnt := range visibleCounts {
+		total := d.leftCounts[tile] + d.counts[tile] + cnt - c.calledCounts[tile]
+		if expected := d.totalTileCount(tile); total != expected {
+			return fmt.Errorf("%s 共有 %d 张（应为 %d 张）", util.MahjongZH[tile], total, expected)
+		}
+	}
+	return nil
+}
+
+// 检查自家手牌数
+func (c *stateChecker) checkHandCount(d *roundData, e *event.Event) error {
+	handsCount := util.CountOfTiles34(d.counts)
+	cnt := handsCount + 3*len(d.players[0].melds)
+
+	expected := -1
+	switch e.Type {
+	case event.TypeDraw:
+		expected = 14
+	case event.TypeDiscard:
+		if e.Discard.Who == 0 {
+			expected = 13
+		}
+	case event.TypeCall:
+		if e.Call.Who == 0 {
+			switch e.Call.Meld.MeldType {
+			case meldTypeChi, meldTypePon:
+				expected = 14
+			default:
+				expected = 13
+			}
+		}
+	case event.TypeNuki:
+		if e.Nuki.Who == 0 {
+			expected = 13
+		}
+	}
+
+	if expected != -1 && cnt != expected || cnt != 13 && cnt != 14 {
+		return fmt.Errorf("手牌错误：%d 张牌，%d 组副露 %v", handsCount, len(d.players[0].melds), d.counts)
+	}
+	return nil
+}
+
+// 处理完事件后检查数据是否一致
+// 调试模式下返回错误，否则提示用户数据异常，并等待之后的权威数据恢复
+func (d *roundData) checkState(e *event.Event) error {
+	c := d.stateChecker
+	if c == nil {
+		return nil
+	}
+
+	err := c.update(d, e)
+	if err == nil && c.inRound {
+		err = c.checkTileCounts(d)
+		if err == nil && c.checkHand {
+			err = c.checkHandCount(d, e)
+		}
+	}
+
+	if err != nil && !c.desynced {
+		c.desynced = true
+		if debugMode {
+			return fmt.Errorf("数据异常（本局第 %d 个事件 %s）：%v", c.eventIndex, e, err)
+		}
+		if !d.skipOutput {
+			color.HiRed("数据异常（本局第 %d 个事件 %s）：%v", c.eventIndex, e, err)
+			color.HiYellow("分析结果可能有误，将在下一局开始或重新连接（刷新网页）后恢复")
+		}
+	}
+
+	if c.resynced && !d.skipOutput {
+		c.resynced = false
+		color.HiGreen("牌局数据已重新同步")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkState(t *testing.T) {
+	assert := assert.New(t)
+
+	debugMode = true
+
+	roundStart := func() *event.Event {
+		return event.NewRoundStart(&event.RoundStart{
+			PlayerNumber:   4,
+			Dealer:         0,
+			DoraIndicators: []int{util.MustStrToTile34("1z")},
+			HandTiles:      util.MustStrToTiles("1112345678999m"),
+			NumRedFives:    []int{0, 0, 0},
+		})
+	}
+
+	newData := func() *eventLogRoundData {
+		d := newEventLogRoundData()
+		d.skipOutput = true
+		return d
+	}
+
+	d := newData()
+	for _, e := range []*event.Event{
+		roundStart(),
+		event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("2z")}),
+		event.NewDiscard(&event.Discard{Who: 0, Tile: util.MustStrToTile34("2z")}),
+		event.NewDiscard(&event.Discard{Who: 1, Tile: util.MustStrToTile34("1p"), IsTsumogiri: true}),
+		event.NewCall(&event.Call{Who: 2, Meld: &model.Meld{
+			MeldType:   model.MeldTypeChi,
+			Tiles:      util.MustStrToTiles("123p"),
+			SelfTiles:  util.MustStrToTiles("23p"),
+			CalledTile: util.MustStrToTile34("1p"),
+		}}),
+		event.NewDiscard(&event.Discard{Who: 2, Tile: util.MustStrToTile34("3z")}),
+		event.NewDiscard(&event.Discard{Who: 3, Tile: util.MustStrToTile34("3z"), IsTsumogiri: true}),
+		event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("4z")}),
+	} {
+		assert.NoError(d.analysisEvent(e), e.String())
+	}
+	assert.False(d.stateChecker.desynced)
+
+	// 漏掉了自家舍牌，下家就舍牌了
+	assert.Error(d.analysisEvent(event.NewDiscard(&event.Discard{Who: 1, Tile: util.MustStrToTile34("5z")})))
+	assert.True(d.stateChecker.desynced)
+
+	// 不同步时不再重复报告
+	assert.NoError(d.analysisEvent(event.NewDiscard(&event.Discard{Who: 3, Tile: util.MustStrToTile34("6z")})))
+
+	// 新的一局开始后恢复
+	assert.NoError(d.analysisEvent(roundStart()))
+	assert.False(d.stateChecker.desynced)
+
+	// 牌数不守恒
+	d = newData()
+	assert.NoError(d.analysisEvent(roundStart()))
+	assert.NoError(d.analysisEvent(event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("1m")})))
+	assert.NoError(d.analysisEvent(event.NewDiscard(&event.Discard{Who: 0, Tile: util.MustStrToTile34("5m")})))
+	assert.Error(d.analysisEvent(event.NewDiscard(&event.Discard{Who: 1, Tile: util.MustStrToTile34("1m")})))
+
+	// 手牌数错误
+	d = newData()
+	assert.NoError(d.analysisEvent(roundStart()))
+	assert.NoError(d.analysisEvent(event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("1z")})))
+	assert.Error(d.analysisEvent(event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("2z")})))
+}
+
+// 牌谱快速载入等不输出的模式下，和牌后也会结束本局
+func Test_handleEvent_winWithSkipOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	d := newEventLogRoundData()
+	d.skipOutput = true
+	roundStart := event.NewRoundStart(&event.RoundStart{
+		PlayerNumber:   4,
+		Dealer:         0,
+		DoraIndicators: []int{util.MustStrToTile34("1z")},
+		HandTiles:      util.MustStrToTiles("1112345678999m"),
+		NumRedFives:    []int{0, 0, 0},
+	})
+	assert.NoError(d.analysisEvent(roundStart))
+	assert.NoError(d.analysisEvent(event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("5m")})))
+	assert.True(d.stateChecker.inRound)
+
+	assert.NoError(d.analysisEvent(event.NewWin(&event.Win{Whos: []int{0}, Points: []int{48000}})))
+	assert.False(d.stateChecker.inRound)
+	assert.Equal(-1, d.stateChecker.nextDiscardWho)
+
+	// 下一局从头开始检查
+	assert.NoError(d.analysisEvent(roundStart))
+	assert.NoError(d.analysisEvent(event.NewDraw(&event.Draw{Tile: util.MustStrToTile34("2z")})))
+	assert.NoError(d.analysisEvent(event.NewDiscard(&event.Discard{Who: 0, Tile: util.MustStrToTile34("2z")})))
+	assert.False(d.stateChecker.desynced)
+}
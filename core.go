@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
 	"github.com/EndlessCheng/mahjong-helper/util/model"
 	"github.com/fatih/color"
 )
@@ -23,65 +24,10 @@ type DataParser interface {
 
 	// 尝试解析用户名
 	IsLogin() bool
-	HandleLogin()
-
-	// round 开始/重连
-	// roundNumber: 场数（如东1为0，东2为1，...，南1为4，...，南4为7，...），对于三麻来说南1也是4
-	// benNumber: 本场数
-	// dealer: 庄家 0-3
-	// doraIndicators: 宝牌指示牌
-	// handTiles: 手牌
-	// numRedFives: 按照 mps 的顺序，赤5个数
-	IsInit() bool
-	ParseInit() (roundNumber int, benNumber int, dealer int, doraIndicators []int, handTiles []int, numRedFives []int)
-
-	// 自家摸牌
-	// tile: 0-33
-	// isRedFive: 是否为赤5
-	// kanDoraIndicator: 摸牌时，若为暗杠摸的岭上牌，则可以翻出杠宝牌指示牌，否则返回 -1（目前恒为 -1，见 IsNewDora）
-	IsSelfDraw() bool
-	ParseSelfDraw() (tile int, isRedFive bool, kanDoraIndicator int)
-
-	// 舍牌
-	// who: 0=自家, 1=下家, 2=对家, 3=上家
-	// isTsumogiri: 是否为摸切（who=0 时忽略该值）
-	// isReach: 是否为立直宣言（isReach 对于天凤来说恒为 false，见 IsReach）
-	// canBeMeld: 是否可以鸣牌（who=0 时忽略该值）
-	// kanDoraIndicator: 大明杠/加杠的杠宝牌指示牌，在切牌后出现，没有则返回 -1（天凤恒为-1，见 IsNewDora）
-	IsDiscard() bool
-	ParseDiscard() (who int, discardTile int, isRedFive bool, isTsumogiri bool, isReach bool, canBeMeld bool, kanDoraIndicator int)
-
-	// 鸣牌（含暗杠、加杠）
-	// kanDoraIndicator: 暗杠的杠宝牌指示牌，在他家暗杠时出现，没有则返回 -1（天凤恒为-1，见 IsNewDora）
-	IsOpen() bool
-	ParseOpen() (who int, meld *model.Meld, kanDoraIndicator int)
-
-	// 立直声明（IsReach 对于雀魂来说恒为 false，见 ParseDiscard）
-	IsReach() bool
-	ParseReach() (who int)
-
-	// 振听
-	IsFuriten() bool
-
-	// 本局是否和牌
-	IsRoundWin() bool
-	ParseRoundWin() (whos []int, points []int)
-
-	// 是否流局
-	// 四风连打 四家立直 四杠散了 九种九牌 三家和了 | 流局听牌 流局未听牌 | 流局满贯
-	// 三家和了
-	IsRyuukyoku() bool
-	ParseRyuukyoku() (type_ int, whos []int, points []int)
-
-	// 拔北宝牌
-	IsNukiDora() bool
-	ParseNukiDora() (who int, isTsumogiri bool)
-
-	// 这一项放在末尾处理
-	// 杠宝牌（雀魂在暗杠后的摸牌时出现）
-	// kanDoraIndicator: 0-33
-	IsNewDora() bool
-	ParseNewDora() (kanDoraIndicator int)
+	HandleLogin() error
+
+	// 将当前消息解析为牌局事件，一条消息可能对应多个事件（如舍牌时翻出杠宝牌），也可能没有事件
+	ParseEvents() []*event.Event
 }
 
 type playerInfo struct {
@@ -173,6 +119,9 @@ type roundData struct {
 
 	skipOutput bool
 
+	// 是否考虑古役（如雀魂的古役模式）
+	considerOldYaku bool
+
 	// 玩家数，3 为三麻，4 为四麻
 	playerNumber int
 
@@ -207,8 +156,23 @@ type roundData struct {
 	// 可以理解成：- 表示不要/暗色，+ 表示进张/亮色
 	globalDiscardTiles []int
 
+	// globalDiscardTiles 中最后一张舍牌是否为赤5
+	latestDiscardIsRedFive bool
+
 	// 0=自家, 1=下家, 2=对家, 3=上家
 	players []*playerInfo
+
+	// 牌谱分析缓存，为 nil 时不使用
+	analysisCaches *analysisCacheList
+
+	// 事件日志，为 nil 时不记录
+	eventWriter *event.Writer
+
+	// 分析结果推送，为 nil 时不推送
+	pusher *analysisPusher
+
+	// 数据一致性检查，为 nil 时不检查
+	stateChecker *stateChecker
 }
 
 func newRoundData(parser DataParser, roundNumber int, benNumber int, dealer int) *roundData {
@@ -235,6 +199,7 @@ func newRoundData(parser DataParser, roundNumber int, benNumber int, dealer int)
 			newPlayerInfo("对家", playerWindTile[2]),
 			newPlayerInfo("上家", playerWindTile[3]),
 		},
+		stateChecker: newStateChecker(),
 	}
 }
 
@@ -245,12 +210,22 @@ func newGame(parser DataParser) *roundData {
 // 新的一局
 func (d *roundData) reset(roundNumber int, benNumber int, dealer int) {
 	skipOutput := d.skipOutput
+	considerOldYaku := d.considerOldYaku
 	gameMode := d.gameMode
 	playerNumber := d.playerNumber
+	analysisCaches := d.analysisCaches
+	eventWriter := d.eventWriter
+	pusher := d.pusher
+	stateChecker := d.stateChecker
 	newData := newRoundData(d.parser, roundNumber, benNumber, dealer)
 	newData.skipOutput = skipOutput
+	newData.considerOldYaku = considerOldYaku
 	newData.gameMode = gameMode
 	newData.playerNumber = playerNumber
+	newData.analysisCaches = analysisCaches
+	newData.eventWriter = eventWriter
+	newData.pusher = pusher
+	newData.stateChecker = stateChecker
 	if playerNumber == 3 {
 		// 三麻没有 2-8m
 		for i := 1; i <= 7; i++ {
@@ -265,16 +240,9 @@ func (d *roundData) newGame() {
 	d.reset(0, 0, 0)
 }
 
+// 剩余量为负数的情况由 checkState 报告
 func (d *roundData) descLeftCounts(tile int) {
 	d.leftCounts[tile]--
-	if d.leftCounts[tile] < 0 {
-		info := fmt.Sprintf("数据异常: %s 数量为 %d", util.MahjongZH[tile], d.leftCounts[tile])
-		if debugMode {
-			panic(info)
-		} else {
-			fmt.Println(info)
-		}
-	}
 }
 
 // 杠！
@@ -466,7 +434,19 @@ func (d *roundData) newModelPlayerInfo() *model.PlayerInfo {
 		LeftDrawTilesCount: leftDrawTilesCount,
 
 		NukiDoraNum: selfPlayer.nukiDoraNum,
+
+		ConsiderOldYaku: d.considerOldYaku,
+	}
+}
+
+// 立直的他家人数
+func (d *roundData) numReachedOpponents() (cnt int) {
+	for _, player := range d.players[1:] {
+		if player.isReached {
+			cnt++
+		}
 	}
+	return
 }
 
 func (d *roundData) analysis() error {
@@ -490,60 +470,94 @@ func (d *roundData) analysis() error {
 
 	// 先获取用户信息
 	if d.parser.IsLogin() {
-		d.parser.HandleLogin()
+		if err := d.parser.HandleLogin(); err != nil {
+			return err
+		}
 	}
 
 	if d.parser.SkipMessage() {
 		return nil
 	}
 
+	for _, e := range d.parser.ParseEvents() {
+		if err := d.handleEvent(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *roundData) handleEvent(e *event.Event) (err error) {
+	// parser 在解析时会设置人数，这里补充到事件中，便于回放
+	if e.Type == event.TypeRoundStart && e.RoundStart.PlayerNumber == 0 {
+		e.RoundStart.PlayerNumber = d.playerNumber
+	}
+
+	if d.eventWriter != nil {
+		if err := d.eventWriter.Write(e); err != nil {
+			fmt.Println("事件日志写入失败：", err)
+		}
+	}
+
 	// 若自家立直，则进入看戏模式
 	// TODO: 见逃判断
-	if !d.parser.IsInit() && !d.parser.IsRoundWin() && !d.parser.IsRyuukyoku() && d.players[0].isReached {
+	isRoundStartOrEnd := e.Type == event.TypeGameStart || e.Type == event.TypeRoundStart || e.Type == event.TypeWin || e.Type == event.TypeDrawGame
+	if !isRoundStartOrEnd && d.players[0].isReached {
 		return nil
 	}
 
+	// 处理完事件后检查数据一致性，不一致时返回的错误中会指出是哪个事件；一致时推送分析结果
+	// 这里没有 recover，panic 时也会执行检查，但 panic 会继续向上传递，由 analysis 处理
+	defer func() {
+		if checkErr := d.checkState(e); err == nil {
+			err = checkErr
+		}
+		// 推送的内容与控制台上的输出相对应，不输出时也不推送
+		if err == nil && d.pusher != nil && !d.skipOutput {
+			if pushErr := d.pusher.push(d, e); pushErr != nil {
+				fmt.Println(pushErr)
+			}
+		}
+	}()
+	return d._handleEvent(e)
+}
+
+func (d *roundData) _handleEvent(e *event.Event) error {
 	if debugMode {
 		fmt.Println("当前座位为", d.parser.GetSelfSeat())
 	}
 
 	var currentRoundCache *roundAnalysisCache
-	if analysisCache := getAnalysisCache(d.parser.GetSelfSeat()); analysisCache != nil {
+	if analysisCache := d.analysisCaches.get(d.parser.GetSelfSeat()); analysisCache != nil {
 		currentRoundCache = analysisCache.wholeGameCache[d.roundNumber][d.benNumber]
 	}
 
-	switch {
-	case d.parser.IsInit():
+	switch e.Type {
+	case event.TypeGameStart:
+		// 先就坐，还没洗牌呢~
+		// 设置第一局的 dealer
+		d.reset(0, 0, e.GameStart.Dealer)
+		d.gameMode = gameModeMatch
+		if !d.skipOutput {
+			fmt.Printf("游戏即将开始，您分配到的座位是：")
+			color.HiGreen(util.MahjongZH[d.players[0].selfWindTile])
+		}
+	case event.TypeRoundStart:
 		// round 开始/重连
 		if !debugMode && !d.skipOutput {
 			clearConsole()
 		}
 
-		roundNumber, benNumber, dealer, doraIndicators, hands, numRedFives := d.parser.ParseInit()
-		switch d.parser.GetDataSourceType() {
-		case dataSourceTypeTenhou:
-			d.reset(roundNumber, benNumber, dealer)
+		rs := e.RoundStart
+		roundNumber, benNumber, dealer, doraIndicators, hands, numRedFives := rs.RoundNumber, rs.BenNumber, rs.Dealer, append([]int(nil), rs.DoraIndicators...), rs.HandTiles, append([]int(nil), rs.NumRedFives...)
+		d.playerNumber = rs.PlayerNumber
+		d.reset(roundNumber, benNumber, dealer)
+		if d.parser.GetDataSourceType() == dataSourceTypeTenhou && d.gameMode != gameModeRecordCache {
 			d.gameMode = gameModeMatch // TODO: 牌谱模式？
-		case dataSourceTypeMajsoul:
-			if dealer != -1 { // 先就坐，还没洗牌呢~
-				// 设置第一局的 dealer
-				d.reset(0, 0, dealer)
-				d.gameMode = gameModeMatch
-				fmt.Printf("游戏即将开始，您分配到的座位是：")
-				color.HiGreen(util.MahjongZH[d.players[0].selfWindTile])
-				return nil
-			} else {
-				// 根据 selfSeat 和当前的 roundNumber 计算当前局的 dealer
-				newDealer := (4 - d.parser.GetSelfSeat() + roundNumber) % 4
-				// 新的一局
-				d.reset(roundNumber, benNumber, newDealer)
-			}
-		default:
-			panic("not impl!")
 		}
 
 		// 由于 reset 了，重新获取 currentRoundCache
-		if analysisCache := getAnalysisCache(d.parser.GetSelfSeat()); analysisCache != nil {
+		if analysisCache := d.analysisCaches.get(d.parser.GetSelfSeat()); analysisCache != nil {
 			currentRoundCache = analysisCache.wholeGameCache[d.roundNumber][d.benNumber]
 		}
 
@@ -557,6 +571,11 @@ func (d *roundData) analysis() error {
 		}
 		d.numRedFives = numRedFives
 
+		// 重连时恢复各家副露和牌河
+		if rs.Snapshot != nil {
+			d.applySnapshot(rs.Snapshot)
+		}
+
 		playerInfo := d.newModelPlayerInfo()
 
 		// 牌谱分析模式下，记录舍牌推荐
@@ -583,9 +602,10 @@ func (d *roundData) analysis() error {
 		fmt.Println()
 		// TODO: 显示地和概率
 		return analysisPlayerWithRisk(playerInfo, nil)
-	case d.parser.IsOpen():
+	case event.TypeCall:
 		// 某家鸣牌（含暗杠、加杠）
-		who, meld, kanDoraIndicator := d.parser.ParseOpen()
+		// 复制一份，避免修改事件中的数据
+		who, meld := e.Call.Who, e.Call.Meld.Copy()
 		meldType := meld.MeldType
 		meldTiles := meld.Tiles
 		calledTile := meld.CalledTile
@@ -595,11 +615,6 @@ func (d *roundData) analysis() error {
 			player.canIppatsu = false
 		}
 
-		// 杠宝牌指示牌
-		if kanDoraIndicator != -1 {
-			d.newDora(kanDoraIndicator)
-		}
-
 		player := d.players[who]
 
 		// 不是暗杠则标记该玩家鸣牌了
@@ -633,14 +648,6 @@ func (d *roundData) analysis() error {
 				}
 			}
 
-			if debugMode {
-				if who == 0 {
-					if handsCount := util.CountOfTiles34(d.counts); handsCount%3 != 1 {
-						return fmt.Errorf("手牌错误：%d 张牌 %v", handsCount, d.counts)
-					}
-				}
-			}
-
 			break
 		}
 
@@ -680,23 +687,11 @@ func (d *roundData) analysis() error {
 					currentRoundCache.addChiPonKan(meldType)
 				}
 			}
-
-			if debugMode {
-				if meldType == meldTypeMinkan || meldType == meldTypeAnkan {
-					if handsCount := util.CountOfTiles34(d.counts); handsCount%3 != 1 {
-						return fmt.Errorf("手牌错误：%d 张牌 %v", handsCount, d.counts)
-					}
-				} else {
-					if handsCount := util.CountOfTiles34(d.counts); handsCount%3 != 2 {
-						return fmt.Errorf("手牌错误：%d 张牌 %v", handsCount, d.counts)
-					}
-				}
-			}
 		}
-	case d.parser.IsReach():
+	case event.TypeRiichi:
 		// 立直宣告
 		// 如果是他家立直，进入攻守判断模式
-		who := d.parser.ParseReach()
+		who := e.Riichi.Who
 		d.players[who].isReached = true
 		d.players[who].canIppatsu = true
 		//case "AGARI", "RYUUKYOKU":
@@ -707,7 +702,7 @@ func (d *roundData) analysis() error {
 		//	// 某人退出
 		//case "REJOIN", "GO":
 		//	// 重连
-	case d.parser.IsFuriten():
+	case event.TypeFuriten:
 		// 振听
 		if d.skipOutput {
 			return nil
@@ -717,20 +712,17 @@ func (d *roundData) analysis() error {
 		//	//（下家,对家,上家 不要其上家的牌）摸牌
 		//case "HELO", "RANKING", "TAIKYOKU", "UN", "LN", "SAIKAI":
 		//	// 其他
-	case d.parser.IsSelfDraw():
+	case event.TypeDraw:
 		if !debugMode && !d.skipOutput {
 			clearConsole()
 		}
 		// 自家（从牌山 d.leftCounts）摸牌（至手牌 d.counts）
-		tile, isRedFive, kanDoraIndicator := d.parser.ParseSelfDraw()
+		tile, isRedFive := e.Draw.Tile, e.Draw.IsRedFive
 		d.descLeftCounts(tile)
 		d.counts[tile]++
 		if isRedFive {
 			d.numRedFives[tile/9]++
 		}
-		if kanDoraIndicator != -1 {
-			d.newDora(kanDoraIndicator)
-		}
 
 		playerInfo := d.newModelPlayerInfo()
 
@@ -766,15 +758,17 @@ func (d *roundData) analysis() error {
 		// 打印手牌对各家的安全度
 		riskTables.printWithHands(d.counts, d.leftCounts)
 
+		// 打印暗杠、加杠的分析
+		if playerInfo.LeftDrawTilesCount > 0 {
+			printKanResults(util.CalculateSelfKan(d.newModelPlayerInfo(), tile, d.playerNumber == 3, d.numReachedOpponents()))
+		}
+
 		// 打印何切推荐
 		// TODO: 根据是否听牌/一向听、打点、巡目、和率等进行攻守判断
 		return analysisPlayerWithRisk(playerInfo, mixedRiskTable)
-	case d.parser.IsDiscard():
-		who, discardTile, isRedFive, isTsumogiri, isReach, canBeMeld, kanDoraIndicator := d.parser.ParseDiscard()
-
-		if kanDoraIndicator != -1 {
-			d.newDora(kanDoraIndicator)
-		}
+	case event.TypeDiscard:
+		di := e.Discard
+		who, discardTile, isRedFive, isTsumogiri, isReach, canBeMeld := di.Who, di.Tile, di.IsRedFive, di.IsTsumogiri, di.IsReach, di.CanBeMeld
 
 		player := d.players[who]
 		if isReach {
@@ -793,6 +787,7 @@ func (d *roundData) analysis() error {
 			d.globalDiscardTiles = append(d.globalDiscardTiles, discardTile)
 			player.discardTiles = append(player.discardTiles, discardTile)
 			player.latestDiscardAtGlobal = len(d.globalDiscardTiles) - 1
+			d.latestDiscardIsRedFive = isRedFive
 
 			if isRedFive {
 				d.numRedFives[discardTile/9]--
@@ -803,12 +798,6 @@ func (d *roundData) analysis() error {
 				currentRoundCache.addSelfDiscardTile(discardTile, mixedRiskTable[discardTile], isReach)
 			}
 
-			if debugMode {
-				if handsCount := util.CountOfTiles34(d.counts); handsCount%3 != 1 {
-					return fmt.Errorf("手牌错误：%d 张牌 %v", handsCount, d.counts)
-				}
-			}
-
 			return nil
 		}
 
@@ -822,6 +811,7 @@ func (d *roundData) analysis() error {
 		d.globalDiscardTiles = append(d.globalDiscardTiles, _disTile)
 		player.discardTiles = append(player.discardTiles, _disTile)
 		player.latestDiscardAtGlobal = len(d.globalDiscardTiles) - 1
+		d.latestDiscardIsRedFive = isRedFive
 
 		// 标记外侧牌
 		if !player.isReached && len(player.discardTiles) <= 5 {
@@ -905,15 +895,23 @@ func (d *roundData) analysis() error {
 		// 为了方便解析牌谱，这里尽可能地解析副露
 		// TODO: 提醒: 消除海底/避免河底
 		allowChi := d.playerNumber != 3 && who == 3 && playerInfo.LeftDrawTilesCount > 0
+		if playerInfo.LeftDrawTilesCount > 0 {
+			if r := util.CalculateDaiminkan(d.newModelPlayerInfo(), discardTile, isRedFive, d.playerNumber == 3, d.numReachedOpponents()); r != nil {
+				printKanResults([]*util.KanAnalysisResult{r})
+			}
+		}
 		return analysisMeld(playerInfo, discardTile, isRedFive, allowChi, mixedRiskTable)
-	case d.parser.IsRoundWin():
-		// TODO: 解析天凤牌谱 - 注意 skipOutput
+	case event.TypeWin:
+		// 本局结束的状态在 checkState 中更新，这里只有输出
+		whos, points := e.Win.Whos, e.Win.Points
+		if d.skipOutput {
+			break
+		}
 
 		if !debugMode {
 			clearConsole()
 		}
 		fmt.Println("和牌，本局结束")
-		whos, points := d.parser.ParseRoundWin()
 		if len(whos) == 3 {
 			color.HiYellow("凤 凰 级 避 铳")
 			if d.parser.GetDataSourceType() == dataSourceTypeMajsoul {
@@ -923,11 +921,10 @@ func (d *roundData) analysis() error {
 		for i, who := range whos {
 			fmt.Println(d.players[who].name, points[i])
 		}
-	case d.parser.IsRyuukyoku():
+	case event.TypeDrawGame:
 		// TODO
-		d.parser.ParseRyuukyoku()
-	case d.parser.IsNukiDora():
-		who, isTsumogiri := d.parser.ParseNukiDora()
+	case event.TypeNuki:
+		who, isTsumogiri := e.Nuki.Who, e.Nuki.IsTsumogiri
 		player := d.players[who]
 		player.nukiDoraNum++
 		if who != 0 {
@@ -943,12 +940,11 @@ func (d *roundData) analysis() error {
 		for _, player := range d.players {
 			player.canIppatsu = false
 		}
-	case d.parser.IsNewDora():
+	case event.TypeNewDora:
 		// 杠宝牌
 		// 1. 剩余牌减少
 		// 2. 打点提高
-		kanDoraIndicator := d.parser.ParseNewDora()
-		d.newDora(kanDoraIndicator)
+		d.newDora(e.NewDora.Indicator)
 	default:
 	}
 
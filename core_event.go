@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+)
+
+// 逐条判断消息类型并解析的 parser（天凤、雀魂）
+// 通过 parseMessageEvents 转换成事件
+type messageParser interface {
+	// 获取自家初始座位，见 DataParser
+	GetSelfSeat() int
+
+	// 数据来源
+	GetDataSourceType() int
+
+	// round 开始/重连
+	// roundNumber: 场数（如东1为0，东2为1，...，南1为4，...，南4为7，...），对于三麻来说南1也是4
+	// benNumber: 本场数
+	// dealer: 庄家 0-3，雀魂在就坐时为第一局的庄家，新的一局开始时为 -1
+	// doraIndicators: 宝牌指示牌
+	// handTiles: 手牌
+	// numRedFives: 按照 mps 的顺序，赤5个数
+	IsInit() bool
+	ParseInit() (roundNumber int, benNumber int, dealer int, doraIndicators []int, handTiles []int, numRedFives []int)
+
+	// 自家摸牌
+	// tile: 0-33
+	// isRedFive: 是否为赤5
+	// kanDoraIndicator: 摸牌时，若为暗杠摸的岭上牌，则可以翻出杠宝牌指示牌，否则返回 -1（天凤恒为 -1，见 IsNewDora）
+	IsSelfDraw() bool
+	ParseSelfDraw() (tile int, isRedFive bool, kanDoraIndicator int)
+
+	// 舍牌
+	// who: 0=自家, 1=下家, 2=对家, 3=上家
+	// isTsumogiri: 是否为摸切（who=0 时忽略该值）
+	// isReach: 是否为立直宣言（isReach 对于天凤来说恒为 false，见 IsReach）
+	// canBeMeld: 是否可以鸣牌（who=0 时忽略该值）
+	// kanDoraIndicator: 大明杠/加杠的杠宝牌指示牌，在切牌后出现，没有则返回 -1（天凤恒为-1，见 IsNewDora）
+	IsDiscard() bool
+	ParseDiscard() (who int, discardTile int, isRedFive bool, isTsumogiri bool, isReach bool, canBeMeld bool, kanDoraIndicator int)
+
+	// 鸣牌（含暗杠、加杠）
+	// kanDoraIndicator: 暗杠的杠宝牌指示牌，在他家暗杠时出现，没有则返回 -1（天凤恒为-1，见 IsNewDora）
+	IsOpen() bool
+	ParseOpen() (who int, meld *model.Meld, kanDoraIndicator int)
+
+	// 立直声明（IsReach 对于雀魂来说恒为 false，见 ParseDiscard）
+	IsReach() bool
+	ParseReach() (who int)
+
+	// 振听
+	IsFuriten() bool
+
+	// 本局是否和牌
+	IsRoundWin() bool
+	ParseRoundWin() (whos []int, points []int)
+
+	// 是否流局
+	// 四风连打 四家立直 四杠散了 九种九牌 三家和了 | 流局听牌 流局未听牌 | 流局满贯
+	// 三家和了
+	IsRyuukyoku() bool
+	ParseRyuukyoku() (type_ int, whos []int, points []int)
+
+	// 拔北宝牌
+	IsNukiDora() bool
+	ParseNukiDora() (who int, isTsumogiri bool)
+
+	// 这一项放在末尾处理
+	// 杠宝牌（雀魂在暗杠后的摸牌时出现）
+	// kanDoraIndicator: 0-33
+	IsNewDora() bool
+	ParseNewDora() (kanDoraIndicator int)
+}
+
+// 重连时可以恢复整个牌局的 parser
+type reconnectDataParser interface {
+	// 是否为重连消息（此时 IsInit 也返回 true）
+	IsReconnect() bool
+	ParseReconnect() *event.Snapshot
+}
+
+// 将 parser 当前的消息转换成事件，判断顺序不能随意调整
+// 消息中附带的杠宝牌会拆分成单独的 NewDora 事件，放在该消息的其他事件之前
+func parseMessageEvents(p messageParser) []*event.Event {
+	withNewDora := func(kanDoraIndicator int, e *event.Event) []*event.Event {
+		if kanDoraIndicator == -1 {
+			return []*event.Event{e}
+		}
+		return []*event.Event{event.NewNewDora(&event.NewDora{Indicator: kanDoraIndicator}), e}
+	}
+
+	switch {
+	case p.IsInit():
+		roundNumber, benNumber, dealer, doraIndicators, hands, numRedFives := p.ParseInit()
+		if p.GetDataSourceType() == dataSourceTypeMajsoul {
+			if dealer != -1 { // 先就坐，还没洗牌呢~
+				return []*event.Event{event.NewGameStart(&event.GameStart{Dealer: dealer})}
+			}
+			// 根据 selfSeat 和当前的 roundNumber 计算当前局的 dealer
+			dealer = (4 - p.GetSelfSeat() + roundNumber) % 4
+		}
+		roundStart := &event.RoundStart{
+			RoundNumber:    roundNumber,
+			BenNumber:      benNumber,
+			Dealer:         dealer,
+			DoraIndicators: doraIndicators,
+			HandTiles:      hands,
+			NumRedFives:    numRedFives,
+		}
+		if rp, ok := p.(reconnectDataParser); ok && rp.IsReconnect() {
+			roundStart.Snapshot = rp.ParseReconnect()
+		}
+		return []*event.Event{event.NewRoundStart(roundStart)}
+	case p.IsOpen():
+		who, meld, kanDoraIndicator := p.ParseOpen()
+		return withNewDora(kanDoraIndicator, event.NewCall(&event.Call{Who: who, Meld: meld}))
+	case p.IsReach():
+		return []*event.Event{event.NewRiichi(&event.Riichi{Who: p.ParseReach()})}
+	case p.IsFuriten():
+		return []*event.Event{event.NewFuriten()}
+	case p.IsSelfDraw():
+		tile, isRedFive, kanDoraIndicator := p.ParseSelfDraw()
+		return withNewDora(kanDoraIndicator, event.NewDraw(&event.Draw{Tile: tile, IsRedFive: isRedFive}))
+	case p.IsDiscard():
+		who, discardTile, isRedFive, isTsumogiri, isReach, canBeMeld, kanDoraIndicator := p.ParseDiscard()
+		return withNewDora(kanDoraIndicator, event.NewDiscard(&event.Discard{
+			Who:         who,
+			Tile:        discardTile,
+			IsRedFive:   isRedFive,
+			IsTsumogiri: isTsumogiri,
+			IsReach:     isReach,
+			CanBeMeld:   canBeMeld,
+		}))
+	case p.IsRoundWin():
+		whos, points := p.ParseRoundWin()
+		return []*event.Event{event.NewWin(&event.Win{Whos: whos, Points: points})}
+	case p.IsRyuukyoku():
+		type_, whos, points := p.ParseRyuukyoku()
+		return []*event.Event{event.NewDrawGame(&event.DrawGame{Type: type_, Whos: whos, Points: points})}
+	case p.IsNukiDora():
+		who, isTsumogiri := p.ParseNukiDora()
+		return []*event.Event{event.NewNuki(&event.Nuki{Who: who, IsTsumogiri: isTsumogiri})}
+	case p.IsNewDora():
+		return []*event.Event{event.NewNewDora(&event.NewDora{Indicator: p.ParseNewDora()})}
+	default:
+		return nil
+	}
+}
@@ -8,13 +8,15 @@ const (
 	// TODO: 感觉有点杂乱需要重构
 	gameModeMatch       gameMode = iota // 对战 - IsInit
 	gameModeRecord                      // 解析牌谱
-	gameModeRecordCache                 // 解析牌谱 - runMajsoulRecordAnalysisTask
+	gameModeRecordCache                 // 解析牌谱 - runMajsoulRecordAnalysisTask, reviewRecordFile
 	gameModeLive                        // 解析观战
 )
 
 const (
 	dataSourceTypeTenhou = iota
 	dataSourceTypeMajsoul
+	dataSourceTypeEventLog // 事件日志
+	dataSourceTypeMjai     // mjai 协议
 )
 
 const (
@@ -3,75 +3,51 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"strings"
+	"os"
 	"testing"
 
 	"github.com/EndlessCheng/mahjong-helper/util/debug"
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_majsoul_analysis(t *testing.T) {
-	debugMode = true
-
-	logFile := "log/gamedata-x.log"
-	logData, err := ioutil.ReadFile(logFile)
+// 读取日志中的最后一局游戏
+func readLastGameDataLogGame(t *testing.T, logFile string) *gameDataLogGame {
+	f, err := os.Open(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	games, err := readGameDataLog(f)
 	if err != nil {
 		t.Fatal(err)
 	}
+	if len(games) == 0 {
+		t.Fatal("日志中没有游戏")
+	}
+	return games[len(games)-1]
+}
 
-	s := struct {
-		Level   string `json:"level"`
-		Message string `json:"message"`
-	}{}
+func Test_majsoul_analysis(t *testing.T) {
+	debugMode = true
 
-	// config
-	startLo := -1
-	endLo := -1
+	// 最新のゲームログを取得
+	game := readLastGameDataLogGame(t, "log/gamedata-x.log")
 
-	majsoulRoundData := &majsoulRoundData{}
+	majsoulRoundData := &majsoulRoundData{accountID: -1}
 	majsoulRoundData.roundData = newGame(majsoulRoundData)
 
-	lines := strings.Split(string(logData), "\n")
-	if startLo == -1 {
-		// 最新のゲームログを取得
-		for i := len(lines) - 1; i >= 0; i-- {
-			if strings.Contains(lines[i], "==============") {
-				startLo = i + 3
-				break
-			}
-		}
-	}
-	if endLo == -1 {
-		endLo = len(lines)
-	}
-
-	for lo, line := range lines[startLo-1 : endLo] {
-		debug.Lo = lo + 1
+	for _, msg := range game.messages {
+		debug.Lo = msg.lineNumber
 		fmt.Println(debug.Lo)
-		if line == "" {
-			continue
-		}
 
-		if err := json.Unmarshal([]byte(line), &s); err != nil {
-			fmt.Println(err)
-			continue
-		}
-
-		if s.Level != "INFO" {
-			fmt.Println(s.Level, s.Message)
-			continue
-		}
-
-		msg := s.Message
 		d := majsoulMessage{}
-		if err := json.Unmarshal([]byte(msg), &d); err != nil {
+		if err := json.Unmarshal([]byte(msg.message), &d); err != nil {
 			fmt.Println(err)
 			continue
 		}
 
 		majsoulRoundData.msg = &d
-		majsoulRoundData.originJSON = msg
+		majsoulRoundData.originJSON = msg.message
 		if err := majsoulRoundData.analysis(); err != nil {
 			fmt.Println("错误：", err)
 		}
@@ -81,64 +57,24 @@ func Test_majsoul_analysis(t *testing.T) {
 func Test_tenhou_analysis(t *testing.T) {
 	debugMode = true
 
-	logFile := "log/gamedata-20190715-201349.log"
-	logData, err := ioutil.ReadFile(logFile)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	s := struct {
-		Level   string `json:"level"`
-		Message string `json:"message"`
-	}{}
-
-	// config
-	startLo := -1
-	endLo := -1
+	// 取最近游戏的日志
+	game := readLastGameDataLogGame(t, "log/gamedata-20190715-201349.log")
 
 	tenhouRoundData := &tenhouRoundData{isRoundEnd: true}
 	tenhouRoundData.roundData = newGame(tenhouRoundData)
 
-	lines := strings.Split(string(logData), "\n")
-	if startLo == -1 {
-		// 取最近游戏的日志
-		for i := len(lines) - 1; i >= 0; i-- {
-			if strings.Contains(lines[i], "==============") {
-				startLo = i + 3
-				break
-			}
-		}
-	}
-	if endLo == -1 {
-		endLo = len(lines)
-	}
-
-	for lo, line := range lines[startLo-1 : endLo] {
-		debug.Lo = lo + 1
+	for _, msg := range game.messages {
+		debug.Lo = msg.lineNumber
 		fmt.Println(debug.Lo)
-		if line == "" {
-			continue
-		}
-
-		if err := json.Unmarshal([]byte(line), &s); err != nil {
-			fmt.Println(err)
-			continue
-		}
-
-		if s.Level != "INFO" {
-			fmt.Println(s.Message)
-			continue
-		}
 
-		msg := s.Message
 		d := tenhouMessage{}
-		if err := json.Unmarshal([]byte(msg), &d); err != nil {
+		if err := json.Unmarshal([]byte(msg.message), &d); err != nil {
 			fmt.Println(err)
 			continue
 		}
 
 		tenhouRoundData.msg = &d
-		tenhouRoundData.originJSON = msg
+		tenhouRoundData.originJSON = msg.message
 		if err := tenhouRoundData.analysis(); err != nil {
 			fmt.Println("错误：", err)
 		}
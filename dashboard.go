@@ -0,0 +1,279 @@
+package main
+
+// 网页版的分析界面，访问 /dashboard 即可使用
+// 通过 GET /api/v1/:platform/state 获取当前牌局，通过 /api/v1/push 接收实时的分析结果
+// 所有资源都内嵌在程序中，不需要联网
+// URL 参数：session 为会话 ID，platform 为 tenhou 或 majsoul（默认 HTTPS 时为 majsoul，否则为 tenhou），token 为助手启动时显示的接口令牌
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="zh">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>日本麻将助手</title>
+<style>
+body { margin: 0; padding: 12px; background: #1e1e1e; color: #ddd; font: 14px/1.6 Consolas, "Microsoft YaHei", monospace; }
+h2 { margin: 16px 0 6px; font-size: 15px; color: #8fbc8f; }
+table { border-collapse: collapse; }
+th, td { padding: 2px 8px; text-align: left; white-space: nowrap; }
+th { color: #999; font-weight: normal; border-bottom: 1px solid #444; }
+#status { float: right; color: #888; }
+#status.online { color: #5c5; }
+.tile { display: inline-block; min-width: 2.4em; margin: 1px; padding: 0 2px; text-align: center; border: 1px solid #555; border-radius: 3px; background: #2a2a2a; }
+.tile.dora { border-color: #e5c07b; }
+.tile.tsumogiri { color: #666; }
+.tile.meld-discard { background: #ddd; color: #111; }
+.tile.riichi { transform: rotate(-90deg); margin: 0 6px; }
+.alert-yellow { color: #ffd75f; }
+.alert-red { color: #ff5f5f; }
+.risk0 { color: #5fd7ff; }
+.risk1 { color: #ffd75f; }
+.risk2 { color: #ff5f5f; }
+.risk3 { color: #d70000; }
+.meld { margin-left: 12px; }
+.muted { color: #777; }
+.best td { color: #fff; }
+</style>
+</head>
+<body>
+<div id="status">未连接</div>
+<div id="info" class="muted">等待牌局数据...</div>
+<h2>手牌</h2>
+<div id="hand"></div>
+<h2>牌河</h2>
+<table id="rivers"></table>
+<h2>危险度</h2>
+<table id="risks"></table>
+<h2 id="analysis-title">何切</h2>
+<table id="analysis"></table>
+<div id="meld"></div>
+<div id="review"></div>
+<script>
+"use strict";
+
+var TILES = ["1万", "2万", "3万", "4万", "5万", "6万", "7万", "8万", "9万",
+	"1饼", "2饼", "3饼", "4饼", "5饼", "6饼", "7饼", "8饼", "9饼",
+	"1索", "2索", "3索", "4索", "5索", "6索", "7索", "8索", "9索",
+	"东", "南", "西", "北", "白", "发", "中"];
+var ROUND_WINDS = {27: "东", 28: "南", 29: "西", 30: "北"};
+
+var params = new URLSearchParams(location.search);
+var session = params.get("session") || "";
+var token = params.get("token") || "";
+var platform = params.get("platform") || (location.protocol === "https:" ? "majsoul" : "tenhou");
+
+function $(id) { return document.getElementById(id); }
+
+function escapeHTML(s) {
+	return String(s).replace(/[&<>"]/g, function (c) { return {"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;"}[c]; });
+}
+
+// 与 getNumRiskColor 一致
+function riskClass(risk) {
+	if (risk < 5) return "risk0";
+	if (risk < 10) return "risk1";
+	if (risk < 15) return "risk2";
+	return "risk3";
+}
+
+// 与 getOtherDiscardAlertColor 一致
+function alertClass(tile) {
+	if (tile >= 27) return "";
+	var n = tile % 9 + 1;
+	if (n === 3 || n === 7) return "alert-yellow";
+	if (n >= 4 && n <= 6) return "alert-red";
+	return "";
+}
+
+function tileHTML(tile, classes, title) {
+	return '<span class="tile ' + (classes || "") + '"' + (title ? ' title="' + escapeHTML(title) + '"' : "") + ">" + TILES[tile] + "</span>";
+}
+
+function tilesHTML(tiles, classes) {
+	return tiles.map(function (t) { return tileHTML(t, classes); }).join("");
+}
+
+function waitsHTML(waits) {
+	return Object.keys(waits).map(Number).sort(function (a, b) { return a - b; }).map(function (t) {
+		return TILES[t] + "<span class=\"muted\">" + waits[t] + "</span>";
+	}).join(" ");
+}
+
+function percent(v) { return v.toFixed(1) + "%"; }
+
+function renderInfo(state) {
+	var round = (ROUND_WINDS[state.round_wind] || "") + (state.round % 4 + 1) + "局";
+	var info = round + " " + state.ben + "本场　庄家：" + state.players[state.dealer].name +
+		"　宝牌指示牌：" + tilesHTML(state.dora_indicators) +
+		"　剩余：" + state.left_draw_count + " 张";
+	$("info").className = "";
+	$("info").innerHTML = info;
+}
+
+function renderHand(state) {
+	var doras = state.doras || [];
+	var html = "";
+	state.hand.forEach(function (count, tile) {
+		for (var i = 0; i < count; i++) {
+			var classes = doras.indexOf(tile) >= 0 ? "dora " : "";
+			var risk = state.mixed_risk_table[tile];
+			if (risk > 0) classes += riskClass(risk);
+			html += tileHTML(tile, classes, risk > 0 ? "综合危险度 " + percent(risk) : "");
+		}
+	});
+	state.players[0].melds.forEach(function (meld) {
+		html += '<span class="meld">' + tilesHTML(meld.tiles) + "</span>";
+	});
+	$("hand").innerHTML = html;
+}
+
+// 与 printDiscards 一致：摸切暗色，副露者的中张手切高亮，鸣牌后的舍牌反色
+function renderRivers(state) {
+	var rows = "";
+	for (var who = state.players.length - 1; who >= 1; who--) {
+		var p = state.players[who];
+		if (state.player_number === 3 && p.wind === 30) continue;
+		var html = p.discards.map(function (d, i) {
+			var classes = d.tsumogiri ? "tsumogiri" : (p.naki ? alertClass(d.tile) : "");
+			if (d.meld_discard) classes += " meld-discard";
+			if (i === p.riichi_tile_at) classes += " riichi";
+			return tileHTML(d.tile, classes);
+		}).join("");
+		p.melds.forEach(function (meld) {
+			html += '<span class="meld">' + tilesHTML(meld.tiles) + "</span>";
+		});
+		rows += "<tr><td>" + p.name + (p.riichi ? " <span class=\"alert-red\">立直</span>" : "") + "</td><td>" + html + "</td></tr>";
+	}
+	$("rivers").innerHTML = rows;
+}
+
+// 与 riskInfoList.printWithHands 类似：各家对手牌的危险度、听牌率和无筋数
+function renderRisks(state) {
+	var handTiles = [];
+	state.hand.forEach(function (count, tile) { if (count > 0) handTiles.push(tile); });
+	var rows = "<tr><th></th><th>听牌率</th><th>无筋</th><th>手牌危险度</th></tr>";
+	for (var who = state.players.length - 1; who >= 1; who--) {
+		var p = state.players[who];
+		if (!p.risk || state.player_number === 3 && p.wind === 30) continue;
+		var r = p.risk;
+		var tiles = handTiles.slice().sort(function (a, b) { return r.risk_table[a] - r.risk_table[b]; }).map(function (t) {
+			return tileHTML(t, riskClass(r.risk_table[t]), percent(r.risk_table[t]));
+		}).join("");
+		var noSuji = r.left_no_suji_tiles.length + (r.tsumogiri_riichi ? " 摸切立直" : "");
+		rows += "<tr><td>" + p.name + "</td><td>" + percent(r.tenpai_rate) + "</td><td>" + noSuji + "</td><td>" + tiles + "</td></tr>";
+	}
+	$("risks").innerHTML = rows;
+}
+
+function resultRow(r, best) {
+	var r13 = r.result13;
+	var point = r13.shanten === 0 ? (r13.dama_point ? r13.dama_point.toFixed(0) : "-") + " / " + (r13.riichi_point ? r13.riichi_point.toFixed(0) : "-") : "";
+	var risk = r.risk === null ? "" : '<span class="' + riskClass(r.risk) + '">' + percent(r.risk) + "</span>";
+	return "<tr" + (best ? ' class="best"' : "") + "><td>" + (r.open_tiles ? tilesHTML(r.open_tiles) + " 鸣，" : "") + "切 " + TILES[r.discard] + "</td>" +
+		"<td>" + r13.shanten + "</td><td>" + r13.waits_count + "</td><td>" + waitsHTML(r13.waits) + "</td>" +
+		"<td>" + (r13.agari_rate ? percent(r13.agari_rate) : "") + "</td><td>" + point + "</td>" +
+		"<td>" + r13.yaku_names.join(" ") + "</td><td>" + risk + "</td></tr>";
+}
+
+var RESULT_HEADER = "<tr><th></th><th>向听</th><th>进张</th><th>进张明细</th><th>和率</th><th>默听/立直</th><th>役</th><th>危险度</th></tr>";
+
+function resultsHTML(discards, backwardDiscards) {
+	var rows = RESULT_HEADER;
+	discards.forEach(function (r, i) { rows += resultRow(r, i === 0); });
+	(backwardDiscards || []).forEach(function (r) { rows += resultRow(r, false).replace("<tr>", '<tr class="muted">'); });
+	return rows;
+}
+
+function renderAnalysis(state) {
+	var a = state.analysis;
+	if (!a) {
+		$("analysis").innerHTML = "";
+		$("meld").innerHTML = "";
+		return;
+	}
+	if (a.result13) {
+		var r13 = a.result13;
+		$("analysis-title").textContent = "当前 " + a.hand;
+		$("analysis").innerHTML = "<tr><th>向听</th><th>进张</th><th>进张明细</th><th>和率</th><th>役</th></tr>" +
+			"<tr><td>" + r13.shanten + "</td><td>" + r13.waits_count + "</td><td>" + waitsHTML(r13.waits) + "</td>" +
+			"<td>" + (r13.agari_rate ? percent(r13.agari_rate) : "") + "</td><td>" + r13.yaku_names.join(" ") + "</td></tr>";
+	} else {
+		$("analysis-title").textContent = "何切 " + a.hand;
+		$("analysis").innerHTML = resultsHTML(a.discards || [], a.backward_discards);
+	}
+	if (a.meld) {
+		$("meld").innerHTML = "<h2>鸣 " + TILES[a.meld.target] + "</h2><table>" + resultsHTML(a.meld.discards, a.meld.backward_discards) + "</table>";
+	} else {
+		$("meld").innerHTML = "";
+	}
+}
+
+// 与 roundAnalysisCache.print 一致
+function renderReview(state) {
+	var review = state.review;
+	if (!review) {
+		$("review").innerHTML = "";
+		return;
+	}
+	if (!review.done) {
+		$("review").innerHTML = "<h2>牌谱</h2><div class=\"muted\">正在计算推荐舍牌...</div>";
+		return;
+	}
+	function cell(tile, risk) {
+		if (tile === -1) return "<td>--</td>";
+		return '<td class="' + (risk < 5 ? "" : riskClass(risk)) + '">' + TILES[tile] + "</td>";
+	}
+	var head = "<tr><th>巡目</th>", discards = "<tr><td>打牌</td>", attacks = "<tr><td>攻め推奨</td>", defences = "<tr><td>守り推奨</td>";
+	review.turns.forEach(function (t, i) {
+		head += "<th>" + (i + 1) + "</th>";
+		discards += cell(t.discard, t.discard_risk).replace("</td>", t.riichi ? "[リーチ]</td>" : "</td>");
+		attacks += cell(t.attack, t.attack_risk);
+		defences += cell(t.defence, t.defence_risk);
+	});
+	$("review").innerHTML = "<h2>牌谱</h2><table>" + head + "</tr>" + discards + "</tr>" + attacks + "</tr>" + defences + "</tr></table>";
+}
+
+function render(state) {
+	if (!state || !state.players) return;
+	renderInfo(state);
+	renderHand(state);
+	renderRivers(state);
+	renderRisks(state);
+	renderAnalysis(state);
+	renderReview(state);
+}
+
+function query(extra) {
+	var q = new URLSearchParams(extra || {});
+	if (session) q.set("session", session);
+	if (token) q.set("token", token);
+	return q.toString();
+}
+
+function fetchState() {
+	fetch("/api/v1/" + platform + "/state?" + query()).then(function (resp) { return resp.json(); }).then(render).catch(function () {});
+}
+
+function connect() {
+	var scheme = location.protocol === "https:" ? "wss:" : "ws:";
+	var ws = new WebSocket(scheme + "//" + location.host + "/api/v1/push?" + query({platform: platform}));
+	ws.onopen = function () {
+		$("status").textContent = platform + (session ? " / " + session : "") + " 已连接";
+		$("status").className = "online";
+		fetchState();
+	};
+	ws.onmessage = function (e) {
+		var msg = JSON.parse(e.data);
+		render(msg.state);
+	};
+	ws.onclose = function () {
+		$("status").textContent = "连接已断开，正在重连...";
+		$("status").className = "";
+		setTimeout(connect, 3000);
+	};
+}
+
+connect();
+</script>
+</body>
+</html>
+`
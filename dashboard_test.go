@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mjHandler_dashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	h := newMjHandler(nil)
+	e := echo.New()
+	e.GET("/dashboard", h.dashboard)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+	// 不依赖外部资源
+	assert.NotContains(rec.Body.String(), "http://")
+	assert.NotContains(rec.Body.String(), "https://")
+	assert.Contains(rec.Body.String(), analysisAPIPrefix+"/push")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// 回放事件日志用的 DataParser，每次处理一个事件
+type eventLogRoundData struct {
+	*roundData
+
+	event *event.Event
+
+	// 复盘牌谱时为自家的绝对座位，回放事件日志时为 -1
+	selfSeat int
+}
+
+func newEventLogRoundData() *eventLogRoundData {
+	d := &eventLogRoundData{selfSeat: -1}
+	d.roundData = newGame(d)
+	return d
+}
+
+func (d *eventLogRoundData) GetDataSourceType() int {
+	return dataSourceTypeEventLog
+}
+
+func (d *eventLogRoundData) GetSelfSeat() int {
+	return d.selfSeat
+}
+
+func (d *eventLogRoundData) GetMessage() string {
+	return ""
+}
+
+func (d *eventLogRoundData) SkipMessage() bool {
+	return false
+}
+
+func (d *eventLogRoundData) IsLogin() bool {
+	return false
+}
+
+func (d *eventLogRoundData) HandleLogin() error {
+	return nil
+}
+
+func (d *eventLogRoundData) ParseEvents() []*event.Event {
+	if d.event == nil {
+		return nil
+	}
+	return []*event.Event{d.event}
+}
+
+// 分析一个事件
+func (d *eventLogRoundData) analysisEvent(e *event.Event) error {
+	d.event = e
+	return d.analysis()
+}
+
+// 回放事件日志
+// 若 skipOutput 为 true，则只恢复牌局数据，不输出分析结果
+func replayEventLog(r io.Reader, skipOutput bool) (*eventLogRoundData, error) {
+	d := newEventLogRoundData()
+	d.skipOutput = skipOutput
+	reader := event.NewReader(r)
+	for {
+		e, err := reader.Read()
+		if err == io.EOF {
+			return d, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := d.analysisEvent(e); err != nil {
+			return nil, err
+		}
+	}
+}
+
+//
+
+var invalidFileNameCharReg = regexp.MustCompile(`[^0-9A-Za-z_-]`)
+
+// 事件日志文件路径 log/events-时间[-会话ID].jsonl
+func newEventLogFilePath(sessionID string) (filePath string, err error) {
+	if err = os.MkdirAll(logDir, os.ModePerm); err != nil {
+		return
+	}
+	fileName := "events-" + time.Now().Format("20060102-150405")
+	if sessionID != "" {
+		fileName += "-" + invalidFileNameCharReg.ReplaceAllString(sessionID, "_")
+	}
+	filePath = filepath.Join(logDir, fileName+".jsonl")
+	return filepath.Abs(filePath)
+}
+
+// 创建事件日志文件
+func newEventLogFile(sessionID string) (*os.File, *event.Writer, error) {
+	filePath, err := newEventLogFilePath(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建事件日志失败：%v", err)
+	}
+	return file, event.NewWriter(file), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_replayEventLog(t *testing.T) {
+	assert := assert.New(t)
+
+	debugMode = true
+
+	buf := &bytes.Buffer{}
+	d := &tenhouRoundData{isRoundEnd: true}
+	d.roundData = newGame(d)
+	d.eventWriter = event.NewWriter(buf)
+	d.skipOutput = true
+	for _, msg := range []*tenhouMessage{
+		{
+			Tag:    "REINIT",
+			Seed:   "0,0,0,1,2,36",
+			Ten:    "250,250,250,250",
+			Dealer: "0",
+			Hai:    "0,4,8,12,16,21,37,40,44,72,76,80,108",
+			Meld2:  "43595",
+			Meld3:  "17511",
+			Kawa0:  "132",
+			Kawa1:  "120,124,255,128",
+		},
+		{Tag: "T84"},
+		{Tag: "D84"},
+		{Tag: "E88"},
+		{Tag: "F92"},
+		{Tag: "DORA", Hai: "96"},
+	} {
+		d.msg = msg
+		if err := d.analysis(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := event.ReadAll(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	if assert.Len(events, 6) {
+		assert.Equal(event.TypeRoundStart, events[0].Type)
+		assert.NotNil(events[0].RoundStart.Snapshot)
+		assert.Equal(event.TypeNewDora, events[5].Type)
+	}
+
+	replayed, err := replayEventLog(bytes.NewReader(buf.Bytes()), true)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(d.playerNumber, replayed.playerNumber)
+	assert.Equal(d.dealer, replayed.dealer)
+	assert.Equal(d.doraIndicators, replayed.doraIndicators)
+	assert.Equal(d.counts, replayed.counts)
+	assert.Equal(d.leftCounts, replayed.leftCounts)
+	assert.Equal(d.numRedFives, replayed.numRedFives)
+	assert.Equal(d.globalDiscardTiles, replayed.globalDiscardTiles)
+	for i, player := range d.players {
+		assert.Equal(player.discardTiles, replayed.players[i].discardTiles)
+		assert.Equal(player.melds, replayed.players[i].melds)
+		assert.Equal(player.isReached, replayed.players[i].isReached)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 牌局数据日志
+// 每个会话将收到的天凤、雀魂消息记录到 log/gamedata-时间[-会话ID].log 中，每行一条 JSON（格式与 echo 的日志相同）
+// 每局游戏（半庄/东风战）单独一个文件，文件超过大小上限时也会换一个文件，超过保留数量或保留天数的旧文件会被删除
+// 可以用 -replay 回放这些文件
+
+const (
+	logDir = "log"
+
+	gameDataLogPrefix = "gamedata-"
+	serverLogPrefix   = "server-"
+
+	defaultGameDataLogMaxSizeMB  = 50
+	defaultGameDataLogMaxFiles   = 200
+	defaultGameDataLogMaxAgeDays = 30
+)
+
+// 牌局数据日志的设置，为 0 时使用默认值，小于 0 时不限制
+type gameDataLogConfig struct {
+	// 单个文件的大小上限
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+	// 最多保留的文件数
+	MaxFiles int `json:"max_files,omitempty"`
+	// 文件的保留天数
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+}
+
+func gameDataLogLimit(value int, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+// 为 0 时不限制
+func (c *gameDataLogConfig) maxSize() int64 {
+	if c == nil {
+		return defaultGameDataLogMaxSizeMB << 20
+	}
+	return int64(gameDataLogLimit(c.MaxSizeMB, defaultGameDataLogMaxSizeMB)) << 20
+}
+
+// 为 0 时不限制
+func (c *gameDataLogConfig) maxFiles() int {
+	if c == nil {
+		return defaultGameDataLogMaxFiles
+	}
+	return gameDataLogLimit(c.MaxFiles, defaultGameDataLogMaxFiles)
+}
+
+// 为 0 时不限制
+func (c *gameDataLogConfig) maxAge() time.Duration {
+	if c == nil {
+		return defaultGameDataLogMaxAgeDays * 24 * time.Hour
+	}
+	return time.Duration(gameDataLogLimit(c.MaxAgeDays, defaultGameDataLogMaxAgeDays)) * 24 * time.Hour
+}
+
+// 删除 dir 下与 pattern 匹配的旧文件，只保留最新的 maxFiles 个以及 maxAge 内修改过的文件
+func cleanLogFiles(dir string, pattern string, maxFiles int, maxAge time.Duration, now time.Time) error {
+	filePaths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return err
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	files := []logFile{}
+	for _, filePath := range filePaths {
+		info, err := os.Stat(filePath)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, logFile{filePath, info.ModTime()})
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	for i, file := range files {
+		if maxFiles > 0 && i >= maxFiles || maxAge > 0 && now.Sub(file.modTime) > maxAge {
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 日志文件路径 dir/前缀时间[-会话ID].log，同名文件已存在时加上序号
+func newLogFilePath(dir string, prefix string, sessionID string, now time.Time) (filePath string, err error) {
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return
+	}
+	fileName := prefix + now.Format("20060102-150405")
+	if sessionID != "" {
+		fileName += "-" + invalidFileNameCharReg.ReplaceAllString(sessionID, "_")
+	}
+	filePath = filepath.Join(dir, fileName+".log")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			break
+		}
+		filePath = filepath.Join(dir, fileName+"-"+strconv.Itoa(i)+".log")
+	}
+	return filepath.Abs(filePath)
+}
+
+//
+
+// 日志中的一行
+type gameDataLogEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// 用于判断消息类型的字段，天凤和雀魂的消息都可以解析
+type gameDataMessageHeader struct {
+	// 天凤
+	Tag  string `json:"tag"`
+	Seed string `json:"seed"`
+
+	// 雀魂
+	AccountID         int    `json:"account_id"`
+	CurrentRecordUUID string `json:"current_record_uuid"`
+	IsGameStart       *bool  `json:"is_game_start"`
+	SeatList          []int  `json:"seat_list"`
+	MD5               string `json:"md5"`
+	Chang             *int   `json:"chang"`
+	Ju                *int   `json:"ju"`
+	Ben               *int   `json:"ben"`
+}
+
+// 不是 JSON 对象时返回 nil
+func parseGameDataMessageHeader(message string) *gameDataMessageHeader {
+	if !strings.HasPrefix(strings.TrimSpace(message), "{") {
+		return nil
+	}
+	header := &gameDataMessageHeader{}
+	if err := json.Unmarshal([]byte(message), header); err != nil {
+		return nil
+	}
+	return header
+}
+
+func (m *gameDataMessageHeader) isTenhou() bool {
+	return m.Tag != ""
+}
+
+// 登录消息，回放时需要从中获取账号
+func (m *gameDataMessageHeader) isLogin() bool {
+	return m.Tag == "HELO" || m.AccountID > 0 && m.CurrentRecordUUID == ""
+}
+
+// 新的一局游戏（不含重连）
+func (m *gameDataMessageHeader) isGameStart() bool {
+	return m.Tag == "GO" || m.IsGameStart != nil && !*m.IsGameStart && m.SeatList != nil
+}
+
+// 一局的开始（含重连），返回场数（东1局为 0，南1局为 4）和本场数
+func (m *gameDataMessageHeader) round() (roundNumber int, benNumber int, ok bool) {
+	switch {
+	case m.Tag == "INIT" || m.Tag == "REINIT":
+		seedSplits := strings.Split(m.Seed, ",")
+		if len(seedSplits) < 2 {
+			return
+		}
+		var err error
+		if roundNumber, err = strconv.Atoi(seedSplits[0]); err != nil {
+			return
+		}
+		if benNumber, err = strconv.Atoi(seedSplits[1]); err != nil {
+			return
+		}
+		return roundNumber, benNumber, true
+	case m.MD5 != "" && m.Chang != nil && m.Ju != nil && m.Ben != nil:
+		return 4**m.Chang + *m.Ju, *m.Ben, true
+	}
+	return
+}
+
+//
+
+// 一个会话的牌局数据日志
+type gameDataLog struct {
+	mu sync.Mutex
+
+	dir       string
+	sessionID string
+	conf      *gameDataLogConfig
+
+	file *os.File
+	size int64
+
+	// 当前文件中是否已有对局数据，有则在下一局游戏开始时换一个文件
+	hasRound bool
+
+	// 最近的登录消息，换文件时写在新文件的开头，使每个文件都能单独回放
+	loginMessage string
+}
+
+func newGameDataLog(dir string, sessionID string, conf *gameDataLogConfig) *gameDataLog {
+	return &gameDataLog{
+		dir:       dir,
+		sessionID: sessionID,
+		conf:      conf,
+	}
+}
+
+// 记录一条收到的消息
+func (l *gameDataLog) info(message string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	header := parseGameDataMessageHeader(message)
+	if header != nil && header.isGameStart() && l.hasRound {
+		l.closeFile()
+	}
+	if err := l.write("INFO", message); err != nil {
+		return err
+	}
+	if header != nil {
+		if header.isLogin() {
+			l.loginMessage = message
+		}
+		if _, _, ok := header.round(); ok {
+			l.hasRound = true
+		}
+	}
+	return nil
+}
+
+// 记录处理消息时发生的错误
+func (l *gameDataLog) error(err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.write("ERROR", err.Error())
+}
+
+func (l *gameDataLog) write(level string, message string) error {
+	line, err := json.Marshal(&gameDataLogEntry{
+		Time:    time.Now().Format(time.RFC3339Nano),
+		Level:   level,
+		Message: message,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if maxSize := l.conf.maxSize(); l.file != nil && maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > maxSize {
+		l.closeFile()
+	}
+	if l.file == nil {
+		if err := l.openFile(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+func (l *gameDataLog) openFile() error {
+	filePath, err := newLogFilePath(l.dir, gameDataLogPrefix, l.sessionID, time.Now())
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return fmt.Errorf("创建牌局数据日志失败：%v", err)
+	}
+	l.file = file
+	l.size = 0
+	l.hasRound = false
+
+	if err := cleanLogFiles(l.dir, gameDataLogPrefix+"*.log", l.conf.maxFiles(), l.conf.maxAge(), time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, "清理牌局数据日志失败：", err)
+	}
+
+	if l.loginMessage != "" {
+		return l.write("INFO", l.loginMessage)
+	}
+	return nil
+}
+
+func (l *gameDataLog) closeFile() {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+func (l *gameDataLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closeFile()
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readGameDataLogFiles(t *testing.T, dir string) (fileNames []string, games [][]*gameDataLogGame) {
+	filePaths, err := filepath.Glob(filepath.Join(dir, gameDataLogPrefix+"*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filePath := range filePaths {
+		f, err := os.Open(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := readGameDataLog(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileNames = append(fileNames, filepath.Base(filePath))
+		games = append(games, g)
+	}
+	return
+}
+
+func Test_gameDataLog(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gamedata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := newGameDataLog(dir, "tab/1", nil)
+	for _, message := range []string{
+		`{"tag":"HELO","uname":"%41"}`,
+		`{"tag":"GO","type":"169"}`,
+		`{"tag":"INIT","seed":"0,0,0,1,2,36"}`,
+		`{"tag":"D12"}`,
+		`{"tag":"INIT","seed":"1,0,0,1,2,36"}`,
+		// 下一局游戏
+		`{"tag":"GO","type":"169"}`,
+		`{"tag":"INIT","seed":"0,0,0,1,2,36"}`,
+	} {
+		assert.NoError(l.info(message))
+	}
+	assert.NoError(l.error(os.ErrInvalid))
+	l.close()
+
+	fileNames, games := readGameDataLogFiles(t, dir)
+	if assert.Len(fileNames, 2) {
+		// 同一秒内创建的文件带有序号
+		if strings.HasSuffix(fileNames[0], "-2.log") {
+			fileNames[0], fileNames[1] = fileNames[1], fileNames[0]
+			games[0], games[1] = games[1], games[0]
+		}
+		for _, fileName := range fileNames {
+			assert.Contains(fileName, "-tab_1")
+		}
+
+		assert.Len(games[0], 1)
+		assert.Len(games[0][0].messages, 5)
+		assert.Len(games[0][0].rounds(), 2)
+
+		// 新文件的开头是登录消息
+		if assert.Len(games[1], 1) && assert.Len(games[1][0].messages, 3) {
+			assert.True(games[1][0].messages[0].isLogin)
+			assert.Len(games[1][0].rounds(), 1)
+		}
+	}
+}
+
+func Test_gameDataLog_maxSize(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gamedata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := newGameDataLog(dir, "", &gameDataLogConfig{MaxSizeMB: 1})
+	message := `{"tag":"D12","data":"` + strings.Repeat("x", 600<<10) + `"}`
+	for i := 0; i < 3; i++ {
+		assert.NoError(l.info(message))
+	}
+	l.close()
+
+	fileNames, _ := readGameDataLogFiles(t, dir)
+	assert.Len(fileNames, 3)
+
+	// 不限制大小
+	dir2, err := ioutil.TempDir("", "gamedata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir2)
+
+	l = newGameDataLog(dir2, "", &gameDataLogConfig{MaxSizeMB: -1})
+	for i := 0; i < 3; i++ {
+		assert.NoError(l.info(message))
+	}
+	l.close()
+
+	fileNames, _ = readGameDataLogFiles(t, dir2)
+	assert.Len(fileNames, 1)
+}
+
+func Test_cleanLogFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gamedata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	for i, name := range []string{"gamedata-1.log", "gamedata-2.log", "gamedata-3.log", "gamedata-4.log", "events-1.jsonl"} {
+		filePath := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(filePath, nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(-time.Duration(i) * 24 * time.Hour)
+		if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	assert.NoError(cleanLogFiles(dir, gameDataLogPrefix+"*.log", 3, 0, now))
+	assert.True(exists("gamedata-1.log"))
+	assert.True(exists("gamedata-3.log"))
+	assert.False(exists("gamedata-4.log"))
+
+	assert.NoError(cleanLogFiles(dir, gameDataLogPrefix+"*.log", 0, 36*time.Hour, now))
+	assert.True(exists("gamedata-1.log"))
+	assert.True(exists("gamedata-2.log"))
+	assert.False(exists("gamedata-3.log"))
+	assert.True(exists("events-1.jsonl"))
+}
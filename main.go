@@ -7,6 +7,7 @@ import (
 	"github.com/EndlessCheng/mahjong-helper/util/model"
 	"github.com/fatih/color"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
 )
@@ -27,6 +28,34 @@ var (
 	humanDoraTiles string
 
 	port int
+
+	tlsCertFile string
+	tlsKeyFile  string
+
+	grpcAddr string
+
+	reviewFilePath string
+	reviewSeat     int
+	reviewPlayer   string
+	exportFilePath string
+	whatIf         string
+
+	mjaiSource string
+	isMjaiBot  bool
+	mjaiSeed   int64
+
+	arenaGames   int
+	arenaPlayers string
+	arenaWall    string
+
+	replayHARFilePath string
+	replaySpeed       float64
+
+	replayFilePath  string
+	replayGame      int
+	replayFromRound string
+	replayToRound   string
+	replayStep      bool
 )
 
 func init() {
@@ -49,6 +78,27 @@ func init() {
 	flag.StringVar(&humanDoraTiles, "d", "", "同 -dora")
 	flag.IntVar(&port, "port", 12121, "指定服务端口")
 	flag.IntVar(&port, "p", 12121, "同 -port")
+	flag.StringVar(&tlsCertFile, "cert", "", "HTTPS 使用的证书文件（PEM），需要同时指定 -key，默认使用 cert 目录下自动生成的本地证书")
+	flag.StringVar(&tlsKeyFile, "key", "", "HTTPS 使用的私钥文件（PEM）")
+	flag.StringVar(&grpcAddr, "grpc", "", "启动 gRPC 分析服务并监听该地址（如 :12122），供其他程序调用")
+	flag.StringVar(&reviewFilePath, "review", "", "复盘牌谱（天凤 mjlog XML 文件，tenhou.net/6 或雀魂 JSON 文件，雀魂 GameDetailRecords 二进制文件），指定目录时批量复盘目录下的所有牌谱并汇总结果")
+	flag.IntVar(&reviewSeat, "seat", -1, "复盘或分析 mjai 数据时的座位（0=起家，1=起家的下家，...），复盘时默认分析所有玩家，mjai 默认使用 start_game 中的 id")
+	flag.StringVar(&reviewPlayer, "player", "", "复盘时只分析该昵称的玩家，批量复盘时可用于统计自己在各个牌谱中的结果")
+	flag.StringVar(&exportFilePath, "export", "", "将 -review 指定的牌谱转换成 tenhou.net/6 格式并保存到该文件（不做分析）")
+	flag.StringVar(&whatIf, "whatif", "", "用天凤牌谱的牌山推演改切另一张牌之后的进展，格式为 局,巡目,牌（如 东2局1本场,6,3m），需要同时指定 -review 和 -seat")
+	flag.StringVar(&mjaiSource, "mjai", "", "分析 mjai 协议的数据（- 为标准输入，tcp:地址 为监听该地址，否则为文件路径）")
+	flag.BoolVar(&isMjaiBot, "mjai-bot", false, "作为 mjai AI 在标准输入输出上对战")
+	flag.Int64Var(&mjaiSeed, "seed", 0, "mjai AI 的随机数种子，相同的种子和输入总是得到相同的输出")
+	flag.IntVar(&arenaGames, "arena", 0, "自战指定场数的半庄，统计各配置的成绩")
+	flag.StringVar(&arenaPlayers, "arena-players", "default", "自战时使用的配置，以逗号分隔 1-4 个，可用的配置有 "+strings.Join(arenaConfigNames(), ","))
+	flag.StringVar(&arenaWall, "arena-wall", "rand", "自战时的牌山生成算法（rand 或 tenhou）")
+	flag.StringVar(&replayHARFilePath, "replay-har", "", "回放浏览器导出的 HAR 文件中的雀魂 WebSocket 数据")
+	flag.Float64Var(&replaySpeed, "replay-speed", 0, "回放速度，1 为按实际时间回放，0 为不等待")
+	flag.StringVar(&replayFilePath, "replay", "", "回放牌局数据日志（log/gamedata-xxx.log）或每行一条天凤、雀魂 JSON 消息的文件")
+	flag.IntVar(&replayGame, "replay-game", 0, "回放第几局游戏（从 1 开始，-1 为最后一局），默认回放全部")
+	flag.StringVar(&replayFromRound, "replay-from", "", "从该局开始回放（如 东2局1本场），之前的消息只恢复牌局数据而不输出")
+	flag.StringVar(&replayToRound, "replay-to", "", "回放到该局为止（如 南1局，不指定本场数时含该局的所有本场）")
+	flag.BoolVar(&replayStep, "replay-step", false, "单步回放，每处理一条消息后等待输入")
 }
 
 const (
@@ -108,7 +158,7 @@ func welcome() int {
 	color.HiGreen("已选择 - %s", platformName)
 
 	if choose == platformMajsoul {
-		if len(gameConf.MajsoulAccountIDs) == 0 {
+		if gameConf.numMajsoulAccountIDs() == 0 {
 			color.HiYellow(`
 提醒：首次启用时，请开启一局人机对战，或者重登游戏。
 该步骤用于获取您的账号 ID，便于在游戏开始时获取自风，否则程序将无法解析后续数据。
@@ -124,6 +174,11 @@ func welcome() int {
 func main() {
 	flag.Parse()
 
+	var mjaiBotOutput *os.File
+	if isMjaiBot {
+		mjaiBotOutput = redirectConsoleToStderr()
+	}
+
 	color.HiGreen("日本麻将助手 %s (by EndlessCheng)", version)
 	if version != versionDev {
 		go checkNewVersion(version)
@@ -139,6 +194,20 @@ func main() {
 
 	var err error
 	switch {
+	case reviewFilePath != "": // 复盘牌谱
+		err = reviewRecordFile(reviewFilePath, reviewSeat, reviewPlayer, exportFilePath, whatIf)
+	case replayHARFilePath != "": // 回放雀魂抓包数据
+		err = replayMajsoulHARFile(replayHARFilePath, replaySpeed)
+	case replayFilePath != "": // 回放牌局数据日志
+		err = replayGameDataLogFile(replayFilePath, replayGame, replayFromRound, replayToRound, replayStep)
+	case arenaGames > 0: // 自战
+		err = runArenaMode(arenaPlayers, arenaWall, arenaGames, mjaiSeed)
+	case isMjaiBot: // mjai AI
+		err = runMjaiBot(os.Stdin, mjaiBotOutput, reviewSeat, mjaiSeed)
+	case mjaiSource != "": // mjai 协议
+		err = runMjaiAnalysis(mjaiSource, reviewSeat)
+	case grpcAddr != "": // gRPC 分析服务
+		err = runGRPCServer(grpcAddr)
 	case isMajsoul:
 		err = runServer(true, port)
 	case isTenhou || isAnalysis:
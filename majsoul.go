@@ -5,6 +5,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/EndlessCheng/mahjong-helper/util"
 	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
 	"sort"
 	"time"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
@@ -132,6 +133,8 @@ type majsoulRoundData struct {
 	msg        *majsoulMessage
 
 	selfSeat int // 自家初始座位：0-第一局的东家 1-第一局的南家 2-第一局的西家 3-第一局的北家
+
+	accountID int // 当前使用的账号 ID，-1 表示尚未获取到
 }
 
 func (d *majsoulRoundData) fatalParse(info string, msg string) {
@@ -198,6 +201,10 @@ func (d *majsoulRoundData) GetSelfSeat() int {
 	return d.selfSeat
 }
 
+func (d *majsoulRoundData) ParseEvents() []*event.Event {
+	return parseMessageEvents(d)
+}
+
 func (d *majsoulRoundData) GetMessage() string {
 	return d.originJSON
 }
@@ -206,7 +213,7 @@ func (d *majsoulRoundData) SkipMessage() bool {
 	msg := d.msg
 
 	// 没有账号 skip
-	if gameConf.currentActiveMajsoulAccountID == -1 {
+	if d.accountID == -1 {
 		return true
 	}
 
@@ -214,7 +221,7 @@ func (d *majsoulRoundData) SkipMessage() bool {
 	if msg.SeatList != nil {
 		// 特判古役模式
 		isGuyiMode := msg.GameConfig.isGuyiMode()
-		util.SetConsiderOldYaku(isGuyiMode)
+		d.considerOldYaku = isGuyiMode
 		if isGuyiMode {
 			color.HiGreen("古役模式已开启")
 			time.Sleep(2 * time.Second)
@@ -235,16 +242,16 @@ func (d *majsoulRoundData) IsLogin() bool {
 	return msg.AccountID > 0 || msg.SeatList != nil
 }
 
-func (d *majsoulRoundData) HandleLogin() {
+func (d *majsoulRoundData) HandleLogin() error {
 	msg := d.msg
 
 	if accountID := msg.AccountID; accountID > 0 {
 		gameConf.addMajsoulAccountID(accountID)
-		if accountID != gameConf.currentActiveMajsoulAccountID {
+		if accountID != d.accountID {
 			printAccountInfo(accountID)
-			gameConf.setMajsoulAccountID(accountID)
+			d.accountID = accountID
 		}
-		return
+		return nil
 	}
 
 	// 从对战 ID 列表中获取账号 ID
@@ -253,33 +260,34 @@ func (d *majsoulRoundData) HandleLogin() {
 		for _, accountID := range seatList {
 			if accountID > 0 && gameConf.isIDExist(accountID) {
 				// 找到了，更新当前使用的账号 ID
-				if gameConf.currentActiveMajsoulAccountID != accountID {
+				if d.accountID != accountID {
 					printAccountInfo(accountID)
-					gameConf.setMajsoulAccountID(accountID)
+					d.accountID = accountID
 				}
-				return
+				return nil
 			}
 		}
 
 		// 未找到缓存 ID
-		if gameConf.currentActiveMajsoulAccountID > 0 {
-			color.HiRed("尚未获取到您的账号 ID，请您刷新网页，或开启一局人机对战（错误信息：您的账号 ID %d 不在对战列表 %v 中）", gameConf.currentActiveMajsoulAccountID, msg.SeatList)
-			return
+		if d.accountID > 0 {
+			color.HiRed("尚未获取到您的账号 ID，请您刷新网页，或开启一局人机对战（错误信息：您的账号 ID %d 不在对战列表 %v 中）", d.accountID, msg.SeatList)
+			return nil
 		}
 
 		// 判断是否为人机对战，若为人机对战，则获取账号 ID
 		if !util.InInts(0, msg.SeatList) {
-			return
+			return nil
 		}
 		for _, accountID := range msg.SeatList {
 			if accountID > 0 {
 				gameConf.addMajsoulAccountID(accountID)
 				printAccountInfo(accountID)
-				gameConf.setMajsoulAccountID(accountID)
-				return
+				d.accountID = accountID
+				return nil
 			}
 		}
 	}
+	return nil
 }
 
 func (d *majsoulRoundData) IsInit() bool {
@@ -295,7 +303,7 @@ func (d *majsoulRoundData) ParseInit() (roundNumber int, benNumber int, dealer i
 		d.playerNumber = playerNumber
 		// 获取自家初始座位：0-第一局的东家 1-第一局的南家 2-第一局的西家 3-第一局的北家
 		for i, accountID := range msg.SeatList {
-			if accountID == gameConf.currentActiveMajsoulAccountID {
+			if accountID == d.accountID {
 				d.selfSeat = i
 				break
 			}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// 与浏览器脚本发送的 JSON 格式相同：字段名为 proto 中的原名，且包含默认值（如 "is_liqi":false）
+var majsoulJSONMarshaler = &jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+
+// 将雀魂 WebSocket 的原始数据（发出的请求和收到的响应、通知）转换成浏览器脚本发送的 JSON 数据
+// 这样任何抓包工具都可以将数据交给助手分析
+// 非并发安全
+type majsoulFrameConverter struct {
+	// 连接 ID -> 解析器，雀魂的大厅和对局是不同的 WebSocket 连接，各自的请求序号是独立的
+	decoders map[string]*majsoul.MessageDecoder
+
+	// 登录时获取
+	accountID uint32
+
+	// 未在抓包数据中登录时使用的账号 ID（如浏览器脚本已发送过登录信息），由会话设置
+	defaultAccountID int
+}
+
+func newMajsoulFrameConverter() *majsoulFrameConverter {
+	return &majsoulFrameConverter{decoders: map[string]*majsoul.MessageDecoder{}}
+}
+
+// 解析 POST 的数据：application/octet-stream 为一帧原始数据，否则为以空白分隔的多帧 base64 数据
+func parseMajsoulFrames(data []byte, isBinary bool) (frames [][]byte, err error) {
+	if isBinary {
+		return [][]byte{data}, nil
+	}
+	for _, field := range bytes.Fields(data) {
+		frame, err := base64.StdEncoding.DecodeString(string(field))
+		if err != nil {
+			return nil, fmt.Errorf("base64 解码失败：%v", err)
+		}
+		frames = append(frames, frame)
+	}
+	return
+}
+
+func majsoulMessageToJSON(message proto.Message) (json.RawMessage, error) {
+	data, err := majsoulJSONMarshaler.MarshalToString(message)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
+
+// 与 majsoulRecordAction 的 JSON 格式相同
+type majsoulRawRecordAction struct {
+	Name string          `json:"name"`
+	Data json.RawMessage `json:"data"`
+}
+
+func newMajsoulRawRecordAction(name string, data []byte) (*majsoulRawRecordAction, error) {
+	message, err := majsoul.UnmarshalNamedMessage(".lq."+name, data)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := majsoulMessageToJSON(message)
+	if err != nil {
+		return nil, err
+	}
+	return &majsoulRawRecordAction{Name: name, Data: jsonData}, nil
+}
+
+// 转换 connID 连接上的一帧数据，返回的每条 JSON 数据对应浏览器脚本发送的一条消息
+// 查看牌谱时返回 recordTask，其牌谱数据可能需要下载，由调用方调用 recordTask.convert 完成转换
+func (c *majsoulFrameConverter) convert(connID string, frame []byte) (results [][]byte, recordTask *majsoulGameRecordTask, err error) {
+	decoder, ok := c.decoders[connID]
+	if !ok {
+		decoder = majsoul.NewMessageDecoder()
+		c.decoders[connID] = decoder
+	}
+	message, err := decoder.Decode(frame)
+	if err != nil || message == nil {
+		return nil, nil, err
+	}
+
+	var messages []interface{}
+	if message.NotifyMessage != nil {
+		messages, err = c.convertNotify(message.NotifyMessage)
+	} else if resp, ok := message.ResponseMessage.(*lq.ResGameRecord); ok {
+		// 查看牌谱
+		recordTask, err = c.newGameRecordTask(resp)
+	} else {
+		messages, err = c.convertResponse(message.ResponseMessage)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", message.Name, err)
+	}
+
+	results, err = marshalMajsoulMessages(messages)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results, recordTask, nil
+}
+
+func marshalMajsoulMessages(messages []interface{}) ([][]byte, error) {
+	var results [][]byte
+	for _, msg := range messages {
+		var data []byte
+		var err error
+		if pm, ok := msg.(proto.Message); ok {
+			data, err = majsoulMessageToJSON(pm)
+		} else {
+			data, err = json.Marshal(msg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, data)
+	}
+	return results, nil
+}
+
+func (c *majsoulFrameConverter) convertNotify(notify proto.Message) ([]interface{}, error) {
+	switch msg := notify.(type) {
+	case *lq.ActionPrototype:
+		// 对局中的操作，如 ActionNewRound ActionDealTile ActionDiscardTile 等
+		action, err := majsoul.UnmarshalNamedMessage(".lq."+msg.Name, msg.Data)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{action}, nil
+	case *lq.NotifyPlayerLoadGameReady:
+		return []interface{}{msg}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (c *majsoulFrameConverter) convertResponse(resp proto.Message) ([]interface{}, error) {
+	switch msg := resp.(type) {
+	case *lq.ResLogin:
+		// login oauth2Login 等
+		if msg.AccountId == 0 {
+			return nil, nil
+		}
+		c.accountID = msg.AccountId
+		return []interface{}{map[string]uint32{"account_id": msg.AccountId}}, nil
+	case *lq.ResAuthGame:
+		// 进入对局
+		return []interface{}{msg}, nil
+	case *lq.ResSyncGame:
+		// 断线重连
+		return c.convertGameRestore(msg.GameRestore)
+	case *lq.ResEnterGame:
+		return c.convertGameRestore(msg.GameRestore)
+	case *lq.ResGameRecordList:
+		if len(msg.RecordList) == 0 {
+			return nil, nil
+		}
+		return []interface{}{msg}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (c *majsoulFrameConverter) convertGameRestore(gameRestore *lq.GameRestore) ([]interface{}, error) {
+	actions := []*majsoulRawRecordAction{}
+	for _, action := range gameRestore.GetActions() {
+		rawAction, err := newMajsoulRawRecordAction(action.Name, action.Data)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, rawAction)
+	}
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	return []interface{}{map[string]interface{}{"sync_game_actions": actions}}, nil
+}
+
+// 查看牌谱时的牌谱数据
+// 牌谱数据需要从 DataUrl 下载时较慢，因此不在解析数据时转换，以免下载时阻塞其他数据的解析
+type majsoulGameRecordTask struct {
+	resp *lq.ResGameRecord
+
+	// 主视角
+	accountID int
+}
+
+func (c *majsoulFrameConverter) newGameRecordTask(resp *lq.ResGameRecord) (*majsoulGameRecordTask, error) {
+	if resp.Head == nil {
+		return nil, fmt.Errorf("牌谱基本信息为空")
+	}
+	if len(resp.Data) == 0 && resp.DataUrl == "" {
+		return nil, fmt.Errorf("数据异常: dataURL 为空")
+	}
+
+	accountID := int(c.accountID)
+	if accountID == 0 {
+		accountID = c.defaultAccountID
+	}
+	if accountID <= 0 {
+		return nil, fmt.Errorf("尚未获取到您的账号 ID，请在登录雀魂前开始抓包")
+	}
+
+	return &majsoulGameRecordTask{resp: resp, accountID: accountID}, nil
+}
+
+// 牌谱数据是否需要下载
+func (t *majsoulGameRecordTask) needDownload() bool {
+	return len(t.resp.Data) == 0
+}
+
+// 依次返回牌谱基本信息、当前牌谱的 UUID 和牌谱的全部操作
+// 需要下载牌谱数据时，ctx 用于控制下载的超时
+func (t *majsoulGameRecordTask) convert(ctx context.Context) ([][]byte, error) {
+	resp := t.resp
+	data := resp.Data
+	if t.needDownload() {
+		var err error
+		if data, err = majsoul.FetchRecordData(ctx, resp.DataUrl); err != nil {
+			return nil, fmt.Errorf("下载牌谱数据失败：%v", err)
+		}
+	}
+	details, err := majsoul.ParseGameDetailRecords(data)
+	if err != nil {
+		return nil, err
+	}
+	actions := []*majsoulRawRecordAction{}
+	for _, detail := range details {
+		jsonData, err := majsoulMessageToJSON(detail.Data)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, &majsoulRawRecordAction{Name: detail.Name, Data: jsonData})
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("牌谱中没有对局数据")
+	}
+
+	head, err := majsoulMessageToJSON(resp.Head)
+	if err != nil {
+		return nil, err
+	}
+	return marshalMajsoulMessages([]interface{}{
+		map[string]interface{}{"shared_record_base_info": head},
+		map[string]interface{}{"current_record_uuid": resp.Head.Uuid, "account_id": t.accountID},
+		map[string]interface{}{"record_actions": actions},
+	})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMajsoulFrame(messageType byte, index byte, name string, message proto.Message) []byte {
+	data, err := api.WrapMessage(name, message)
+	if err != nil {
+		panic(err)
+	}
+	if messageType == api.MessageTypeNotify {
+		return append([]byte{messageType}, data...)
+	}
+	return append([]byte{messageType, index, 0}, data...)
+}
+
+func newTestMajsoulActionFrame(name string, action proto.Message) []byte {
+	data, err := proto.Marshal(action)
+	if err != nil {
+		panic(err)
+	}
+	return newTestMajsoulFrame(api.MessageTypeNotify, 0, ".lq.ActionPrototype", &lq.ActionPrototype{Name: name, Data: data})
+}
+
+func Test_majsoulFrameConverter(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newMajsoulFrameConverter()
+	convert := func(frame []byte) (messages []*majsoulMessage) {
+		results, recordTask, err := c.convert("", frame)
+		assert.NoError(err)
+		if recordTask != nil {
+			recordResults, err := recordTask.convert(context.Background())
+			assert.NoError(err)
+			results = append(results, recordResults...)
+		}
+		for _, result := range results {
+			msg := &majsoulMessage{}
+			assert.NoError(json.Unmarshal(result, msg), string(result))
+			messages = append(messages, msg)
+		}
+		return
+	}
+
+	// 登录
+	assert.Empty(convert(newTestMajsoulFrame(api.MessageTypeRequest, 1, ".lq.Lobby.login", &lq.ReqLogin{Account: "user"})))
+	messages := convert(newTestMajsoulFrame(api.MessageTypeResponse, 1, "", &lq.ResLogin{AccountId: 12345}))
+	if assert.Len(messages, 1) {
+		assert.Equal(12345, messages[0].AccountID)
+	}
+
+	// 找不到请求的响应
+	assert.Empty(convert(newTestMajsoulFrame(api.MessageTypeResponse, 2, "", &lq.ResLogin{AccountId: 12345})))
+
+	// 进入对局
+	convert(newTestMajsoulFrame(api.MessageTypeRequest, 2, ".lq.FastTest.authGame", &lq.ReqAuthGame{AccountId: 12345}))
+	messages = convert(newTestMajsoulFrame(api.MessageTypeResponse, 2, "", &lq.ResAuthGame{SeatList: []uint32{0, 12345, 0, 0}}))
+	if assert.Len(messages, 1) {
+		assert.Equal([]int{0, 12345, 0, 0}, messages[0].SeatList)
+		if assert.NotNil(messages[0].IsGameStart) {
+			assert.False(*messages[0].IsGameStart)
+		}
+	}
+
+	// 对局中的操作，默认值也需要有
+	messages = convert(newTestMajsoulActionFrame("ActionDiscardTile", &lq.ActionDiscardTile{Seat: 0, Tile: "5z", Moqie: true}))
+	if assert.Len(messages, 1) {
+		msg := messages[0]
+		if assert.NotNil(msg.Seat) && assert.NotNil(msg.IsLiqi) && assert.NotNil(msg.Moqie) {
+			assert.Equal(0, *msg.Seat)
+			assert.False(*msg.IsLiqi)
+			assert.True(*msg.Moqie)
+		}
+		assert.Equal("5z", msg.Tile)
+		assert.Nil(msg.Operation)
+	}
+	messages = convert(newTestMajsoulActionFrame("ActionAnGangAddGang", &lq.ActionAnGangAddGang{Seat: 2, Type: 3, Tiles: "3s"}))
+	if assert.Len(messages, 1) {
+		assert.Equal("3s", messages[0].Tiles)
+	}
+
+	// 断线重连
+	discardData, _ := proto.Marshal(&lq.ActionDiscardTile{Tile: "1m"})
+	convert(newTestMajsoulFrame(api.MessageTypeRequest, 3, ".lq.FastTest.syncGame", &lq.ReqSyncGame{}))
+	messages = convert(newTestMajsoulFrame(api.MessageTypeResponse, 3, "", &lq.ResSyncGame{GameRestore: &lq.GameRestore{Actions: []*lq.ActionPrototype{{Name: "ActionDiscardTile", Data: discardData}}}}))
+	if assert.Len(messages, 1) && assert.Len(messages[0].SyncGameActions, 1) {
+		assert.Equal("ActionDiscardTile", messages[0].SyncGameActions[0].Name)
+		assert.Equal("1m", messages[0].SyncGameActions[0].Action.Tile)
+	}
+
+	// 查看牌谱
+	newRound, _ := api.WrapMessage(".lq.RecordNewRound", &lq.RecordNewRound{Tiles0: []string{"1m"}, Doras: []string{"2m"}})
+	recordData, _ := api.WrapMessage("", &lq.GameDetailRecords{Records: [][]byte{newRound}})
+	head := &lq.RecordGame{Uuid: "uuid", Accounts: []*lq.RecordGame_AccountInfo{{AccountId: 12345, Seat: 2, Nickname: "A"}}}
+	convert(newTestMajsoulFrame(api.MessageTypeRequest, 4, ".lq.Lobby.fetchGameRecord", &lq.ReqGameRecord{GameUuid: "uuid"}))
+	messages = convert(newTestMajsoulFrame(api.MessageTypeResponse, 4, "", &lq.ResGameRecord{Head: head, Data: recordData}))
+	if assert.Len(messages, 3) {
+		if assert.NotNil(messages[0].SharedRecordBaseInfo) {
+			seat, _ := messages[0].SharedRecordBaseInfo.getSelfSeat(12345)
+			assert.Equal(2, seat)
+		}
+		assert.Equal("uuid", messages[1].CurrentRecordUUID)
+		assert.Equal(12345, messages[1].AccountID)
+		if assert.Len(messages[2].RecordActions, 1) {
+			assert.Equal("RecordNewRound", messages[2].RecordActions[0].Name)
+			assert.Equal([]string{"1m"}, messages[2].RecordActions[0].Action.Tiles0)
+		}
+	}
+
+	// 不同连接的请求序号是独立的
+	convert(newTestMajsoulFrame(api.MessageTypeRequest, 5, ".lq.Lobby.login", &lq.ReqLogin{}))
+	results, recordTask, err := c.convert("game", newTestMajsoulFrame(api.MessageTypeResponse, 5, "", &lq.ResLogin{AccountId: 1}))
+	assert.NoError(err)
+	assert.Empty(results)
+	assert.Nil(recordTask)
+
+	// 无法解析的数据
+	_, _, err = c.convert("", []byte{9, 9, 9})
+	assert.Error(err)
+	_, _, err = c.convert("", newTestMajsoulFrame(api.MessageTypeRequest, 5, ".lq.Lobby.notExist", &lq.ReqLogin{}))
+	assert.Error(err)
+}
+
+func Test_mjSession_putMajsoulFrames(t *testing.T) {
+	assert := assert.New(t)
+
+	frames, err := parseMajsoulFrames([]byte(base64.StdEncoding.EncodeToString(newTestMajsoulActionFrame("ActionDiscardTile", &lq.ActionDiscardTile{Tile: "1m"}))+"\n!!!"), false)
+	assert.Error(err)
+	frames, err = parseMajsoulFrames([]byte(" "+base64.StdEncoding.EncodeToString(newTestMajsoulActionFrame("ActionDiscardTile", &lq.ActionDiscardTile{Tile: "1m"}))+"\n"), false)
+	if !assert.NoError(err) || !assert.Len(frames, 1) {
+		return
+	}
+
+	s := newMjSession("", nil)
+	// 无法解析的帧会被跳过
+	assert.True(s.putMajsoulFrames("", [][]byte{{1, 2}, frames[0]}))
+	if assert.Len(s.majsoulMessageQueue, 1) {
+		msg := &majsoulMessage{}
+		assert.NoError(json.Unmarshal(<-s.majsoulMessageQueue, msg))
+		assert.Equal("1m", msg.Tile)
+	}
+
+	s.close()
+	assert.False(s.putMajsoulFrames("", frames))
+}
+
+func Test_mjSession_putMajsoulFrames_downloadRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newMjSession("", nil)
+	defer s.close()
+	assert.True(s.putMajsoulFrames("", [][]byte{
+		newTestMajsoulFrame(api.MessageTypeRequest, 1, ".lq.Lobby.login", &lq.ReqLogin{}),
+		newTestMajsoulFrame(api.MessageTypeResponse, 1, "", &lq.ResLogin{AccountId: 12345}),
+	}))
+	assert.Len(s.majsoulMessageQueue, 1)
+	<-s.majsoulMessageQueue
+
+	// 下载牌谱数据时，同一会话的其他数据仍然可以解析
+	newRound, _ := api.WrapMessage(".lq.RecordNewRound", &lq.RecordNewRound{Tiles0: []string{"1m"}, Doras: []string{"2m"}})
+	recordData, _ := api.WrapMessage("", &lq.GameDetailRecords{Records: [][]byte{newRound}})
+	putDone := make(chan bool, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go func() {
+			putDone <- s.putMajsoulFrames("game", [][]byte{newTestMajsoulActionFrame("ActionDiscardTile", &lq.ActionDiscardTile{Tile: "1m"})})
+		}()
+		select {
+		case ok := <-putDone:
+			assert.True(ok)
+		case <-time.After(5 * time.Second):
+			assert.Fail("下载牌谱数据时阻塞了其他数据的解析")
+		}
+		w.Write(recordData)
+	}))
+	defer ts.Close()
+
+	head := &lq.RecordGame{Uuid: "uuid", Accounts: []*lq.RecordGame_AccountInfo{{AccountId: 12345, Seat: 2, Nickname: "A"}}}
+	assert.True(s.putMajsoulFrames("", [][]byte{
+		newTestMajsoulFrame(api.MessageTypeRequest, 2, ".lq.Lobby.fetchGameRecord", &lq.ReqGameRecord{GameUuid: "uuid"}),
+		newTestMajsoulFrame(api.MessageTypeResponse, 2, "", &lq.ResGameRecord{Head: head, DataUrl: ts.URL}),
+	}))
+
+	messages := []*majsoulMessage{}
+	for len(s.majsoulMessageQueue) > 0 {
+		msg := &majsoulMessage{}
+		assert.NoError(json.Unmarshal(<-s.majsoulMessageQueue, msg))
+		messages = append(messages, msg)
+	}
+	if assert.Len(messages, 4) {
+		assert.Equal("1m", messages[0].Tile)
+		assert.NotNil(messages[1].SharedRecordBaseInfo)
+		assert.Equal("uuid", messages[2].CurrentRecordUUID)
+		if assert.Len(messages[3].RecordActions, 1) {
+			assert.Equal("RecordNewRound", messages[3].RecordActions[0].Name)
+		}
+	}
+}
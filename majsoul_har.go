@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 浏览器（Chrome 的开发者工具 Network 面板）导出的 HAR 文件，只解析 WebSocket 相关的字段
+type harFile struct {
+	Log struct {
+		Entries []*harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		URL string `json:"url"`
+	} `json:"request"`
+	WebSocketMessages []*harWebSocketMessage `json:"_webSocketMessages"`
+}
+
+type harWebSocketMessage struct {
+	Type   string  `json:"type"` // send 或 receive
+	Time   float64 `json:"time"` // Unix 时间戳，单位为秒
+	Opcode int     `json:"opcode"`
+	Data   string  `json:"data"` // 二进制数据为 base64 编码
+}
+
+const harOpcodeBinary = 2
+
+// 抓包得到的一帧雀魂 WebSocket 数据
+type majsoulCapturedFrame struct {
+	connID string
+	time   time.Time
+	data   []byte
+}
+
+// 从 HAR 文件中提取所有 WebSocket 连接上的二进制数据，按时间排序
+func parseMajsoulHAR(data []byte) ([]*majsoulCapturedFrame, error) {
+	har := harFile{}
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("HAR 文件解析失败：%v", err)
+	}
+
+	frames := []*majsoulCapturedFrame{}
+	for i, entry := range har.Log.Entries {
+		// 同一个 URL 可能有多次连接（如断线重连），用序号区分
+		connID := strconv.Itoa(i) + " " + entry.Request.URL
+		for _, msg := range entry.WebSocketMessages {
+			if msg.Opcode != harOpcodeBinary {
+				continue
+			}
+			frame, err := base64.StdEncoding.DecodeString(msg.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: base64 解码失败：%v", entry.Request.URL, err)
+			}
+			sec := int64(msg.Time)
+			nsec := int64((msg.Time - float64(sec)) * 1e9)
+			frames = append(frames, &majsoulCapturedFrame{
+				connID: connID,
+				time:   time.Unix(sec, nsec),
+				data:   frame,
+			})
+		}
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("HAR 文件中没有 WebSocket 二进制数据，请在导出前勾选保留 WebSocket 消息")
+	}
+
+	// 不同连接的数据按时间合并
+	sort.SliceStable(frames, func(i, j int) bool { return frames[i].time.Before(frames[j].time) })
+	return frames, nil
+}
+
+// 将抓包数据依次交给 s 分析，与实战时的处理相同
+// speed 为回放速度，1 为按实际的时间间隔回放，0 为不等待
+func (s *mjSession) replayMajsoulFrames(frames []*majsoulCapturedFrame, speed float64) {
+	if !debugMode {
+		defer func() {
+			if err := recover(); err != nil {
+				fmt.Println("内部错误：", err)
+			}
+		}()
+	}
+
+	for i, frame := range frames {
+		if speed > 0 && i > 0 {
+			time.Sleep(time.Duration(float64(frame.time.Sub(frames[i-1].time)) / speed))
+		}
+
+		s.majsoulFrameConverter.defaultAccountID = s.majsoulAccountID()
+		messages, recordTask, err := s.majsoulFrameConverter.convert(frame.connID, frame.data)
+		if err == nil && recordTask != nil {
+			var recordMessages [][]byte
+			ctx, cancel := context.WithTimeout(context.Background(), majsoulRecordDownloadTimeout)
+			recordMessages, err = recordTask.convert(ctx)
+			cancel()
+			messages = append(messages, recordMessages...)
+		}
+		if err != nil {
+			if debugMode {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			continue
+		}
+		for _, msg := range messages {
+			if debugMode {
+				fmt.Println(string(msg))
+			}
+			s.processMajsoulMessage(msg)
+		}
+	}
+}
+
+// 回放浏览器导出的 HAR 文件中的雀魂对局或牌谱
+func replayMajsoulHARFile(filePath string, speed float64) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	frames, err := parseMajsoulHAR(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("共 %d 帧数据，开始回放...\n", len(frames))
+	s := newMjSession("", nil)
+	s.replayMajsoulFrames(frames, speed)
+	return nil
+}
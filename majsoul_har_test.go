@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHAR(frames map[string][][]byte, startTime float64) []byte {
+	har := harFile{}
+	t := startTime
+	for _, url := range []string{"wss://lobby/gateway", "wss://game/game-gateway"} {
+		entry := &harEntry{}
+		entry.Request.URL = url
+		for _, frame := range frames[url] {
+			entry.WebSocketMessages = append(entry.WebSocketMessages, &harWebSocketMessage{
+				Type:   "receive",
+				Time:   t,
+				Opcode: harOpcodeBinary,
+				Data:   base64.StdEncoding.EncodeToString(frame),
+			})
+			t += 0.01
+		}
+		// 文本消息会被忽略
+		entry.WebSocketMessages = append(entry.WebSocketMessages, &harWebSocketMessage{Opcode: 1, Data: "ping"})
+		har.Log.Entries = append(har.Log.Entries, entry)
+	}
+	data, _ := json.Marshal(har)
+	return data
+}
+
+func Test_replayMajsoulHAR(t *testing.T) {
+	assert := assert.New(t)
+
+	record := &lq.RecordGame{Uuid: "record-uuid", Accounts: []*lq.RecordGame_AccountInfo{{AccountId: 1, Nickname: "A"}}}
+	data := newTestHAR(map[string][][]byte{
+		"wss://lobby/gateway": {
+			newTestMajsoulFrame(api.MessageTypeRequest, 1, ".lq.Lobby.fetchGameRecordList", &lq.ReqGameRecordList{Start: 1, Count: 10}),
+			newTestMajsoulFrame(api.MessageTypeResponse, 1, "", &lq.ResGameRecordList{RecordList: []*lq.RecordGame{record}}),
+		},
+		// 与大厅的请求序号相同
+		"wss://game/game-gateway": {
+			newTestMajsoulFrame(api.MessageTypeRequest, 1, ".lq.FastTest.authGame", &lq.ReqAuthGame{}),
+		},
+	}, 1577836800)
+
+	frames, err := parseMajsoulHAR(data)
+	if !assert.NoError(err) || !assert.Len(frames, 3) {
+		return
+	}
+	assert.True(frames[0].connID != frames[2].connID)
+	assert.Equal(int64(1577836800), frames[0].time.Unix())
+
+	s := newMjSession("", nil)
+	start := time.Now()
+	s.replayMajsoulFrames(frames, 1)
+	assert.True(time.Since(start) >= 20*time.Millisecond)
+	if assert.Contains(s.majsoulRecordMap, "record-uuid") {
+		assert.Equal("A", s.majsoulRecordMap["record-uuid"].Accounts[0].Nickname)
+	}
+
+	_, err = parseMajsoulHAR([]byte(`{"log":{"entries":[]}}`))
+	assert.Error(err)
+	_, err = parseMajsoulHAR([]byte(`not json`))
+	assert.Error(err)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/fatih/color"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// mjai 协议的 DataParser，每次处理一条消息
+// 用于对接本地的模拟器和开源 AI
+type mjaiRoundData struct {
+	*roundData
+
+	msg *mjai.Message
+
+	// 自家的绝对座位
+	selfSeat int
+	// 是否由 -seat 指定了座位，此时忽略 start_game 中的 id
+	isSeatFixed bool
+
+	// 已宣告立直但还未舍牌的玩家（绝对座位），没有时为 -1
+	reachActor int
+}
+
+// selfSeat 为 -1 时使用 start_game 中的 id
+func newMjaiRoundData(selfSeat int) *mjaiRoundData {
+	d := &mjaiRoundData{selfSeat: selfSeat, isSeatFixed: selfSeat >= 0, reachActor: -1}
+	if selfSeat < 0 {
+		d.selfSeat = 0
+	}
+	d.roundData = newGame(d)
+	return d
+}
+
+func (d *mjaiRoundData) GetDataSourceType() int {
+	return dataSourceTypeMjai
+}
+
+func (d *mjaiRoundData) GetSelfSeat() int {
+	return d.selfSeat
+}
+
+func (d *mjaiRoundData) GetMessage() string {
+	data, _ := json.Marshal(d.msg)
+	return string(data)
+}
+
+func (d *mjaiRoundData) SkipMessage() bool {
+	return d.msg == nil
+}
+
+func (d *mjaiRoundData) IsLogin() bool {
+	return d.msg != nil && d.msg.Type == mjai.TypeStartGame
+}
+
+func (d *mjaiRoundData) HandleLogin() error {
+	if !d.isSeatFixed && d.msg.ID != nil {
+		d.selfSeat = *d.msg.ID
+	}
+	return nil
+}
+
+// 与雀魂相同，三麻时也按照四个座位计算相对位置
+func (d *mjaiRoundData) relativeWho(actor int) int {
+	return (actor - d.selfSeat + 4) % 4
+}
+
+func (d *mjaiRoundData) ParseEvents() []*event.Event {
+	msg := d.msg
+	who := d.relativeWho(msg.Actor)
+	switch msg.Type {
+	case mjai.TypeStartGame:
+		// mjai 中起家固定为 0 号位
+		return []*event.Event{event.NewGameStart(&event.GameStart{Dealer: d.relativeWho(0)})}
+	case mjai.TypeStartKyoku:
+		d.reachActor = -1
+		playerNumber := len(msg.Tehais)
+		if playerNumber == 4 && len(msg.Tehais[3]) == 0 {
+			playerNumber = 3
+		}
+		hands, numRedFives := mustParseMjaiTiles(msg.Tehais[d.selfSeat])
+		doraIndicator, _ := mustParseMjaiTile(msg.DoraMarker)
+		return []*event.Event{event.NewRoundStart(&event.RoundStart{
+			RoundNumber:    4*msg.BakazeIndex() + msg.Kyoku - 1,
+			BenNumber:      msg.Honba,
+			Dealer:         d.relativeWho(msg.Oya),
+			PlayerNumber:   playerNumber,
+			DoraIndicators: []int{doraIndicator},
+			HandTiles:      hands,
+			NumRedFives:    numRedFives,
+		})}
+	case mjai.TypeTsumo:
+		// 他家的摸牌不产生事件
+		if who != 0 {
+			return nil
+		}
+		tile, isRedFive := mustParseMjaiTile(msg.Pai)
+		return []*event.Event{event.NewDraw(&event.Draw{Tile: tile, IsRedFive: isRedFive})}
+	case mjai.TypeReach:
+		// 立直宣言牌为之后的舍牌
+		d.reachActor = msg.Actor
+	case mjai.TypeDahai:
+		isReach := msg.Actor == d.reachActor
+		if isReach {
+			d.reachActor = -1
+		}
+		tile, isRedFive := mustParseMjaiTile(msg.Pai)
+		return []*event.Event{event.NewDiscard(&event.Discard{
+			Who:         who,
+			Tile:        tile,
+			IsRedFive:   isRedFive,
+			IsTsumogiri: msg.Tsumogiri,
+			IsReach:     isReach,
+			CanBeMeld:   who != 0,
+		})}
+	case mjai.TypeChi, mjai.TypePon, mjai.TypeDaiminkan, mjai.TypeAnkan, mjai.TypeKakan:
+		return []*event.Event{event.NewCall(&event.Call{Who: who, Meld: mjaiMessageToMeld(msg)})}
+	case mjai.TypeNukidora:
+		return []*event.Event{event.NewNuki(&event.Nuki{Who: who})}
+	case mjai.TypeDora:
+		doraIndicator, _ := mustParseMjaiTile(msg.DoraMarker)
+		return []*event.Event{event.NewNewDora(&event.NewDora{Indicator: doraIndicator})}
+	case mjai.TypeHora:
+		// 多家和牌时会依次收到多条 hora
+		point := msg.HoraPoints
+		if msg.Actor < len(msg.Deltas) {
+			point = msg.Deltas[msg.Actor]
+		}
+		return []*event.Event{event.NewWin(&event.Win{Whos: []int{who}, Points: []int{point}})}
+	case mjai.TypeRyukyoku:
+		return []*event.Event{event.NewDrawGame(&event.DrawGame{})}
+	}
+	return nil
+}
+
+// 分析一条 mjai 消息
+func (d *mjaiRoundData) analysisMessage(msg *mjai.Message) error {
+	d.msg = msg
+	return d.analysis()
+}
+
+//
+
+func mustParseMjaiTile(s string) (tile34 int, isRedFive bool) {
+	tile34, isRedFive, err := mjai.ParseTile(s)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+func mustParseMjaiTiles(tiles []string) (tiles34 []int, numRedFives []int) {
+	tiles34, numRedFives, err := mjai.ParseTiles(tiles)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// 将 mjai 的鸣牌消息转换成副露
+func mjaiMessageToMeld(msg *mjai.Message) *model.Meld {
+	meld := &model.Meld{}
+	switch msg.Type {
+	case mjai.TypeChi:
+		meld.MeldType = meldTypeChi
+	case mjai.TypePon:
+		meld.MeldType = meldTypePon
+	case mjai.TypeDaiminkan:
+		meld.MeldType = meldTypeMinkan
+	case mjai.TypeAnkan:
+		meld.MeldType = meldTypeAnkan
+	case mjai.TypeKakan:
+		meld.MeldType = meldTypeKakan
+	}
+
+	for _, s := range msg.Consumed {
+		tile, isRedFive := mustParseMjaiTile(s)
+		meld.SelfTiles = append(meld.SelfTiles, tile)
+		meld.Tiles = append(meld.Tiles, tile)
+		meld.ContainRedFive = meld.ContainRedFive || isRedFive
+	}
+	if msg.Type == mjai.TypeAnkan {
+		// 暗杠没有被鸣的牌，consumed 即为四张牌
+		meld.CalledTile = meld.Tiles[0]
+		return meld
+	}
+
+	calledTile, isCalledTileRedFive := mustParseMjaiTile(msg.Pai)
+	meld.CalledTile = calledTile
+	meld.Tiles = append(meld.Tiles, calledTile)
+	meld.ContainRedFive = meld.ContainRedFive || isCalledTileRedFive
+	sort.Ints(meld.Tiles)
+	if msg.Type == mjai.TypeKakan {
+		// 加杠时 consumed 为原来的碰
+		meld.SelfTiles = nil
+	} else {
+		meld.RedFiveFromOthers = isCalledTileRedFive
+	}
+	return meld
+}
+
+// 分析 mjai 消息流，直到读完为止
+// selfSeat 为 -1 时使用 start_game 中的 id
+func analysisMjaiStream(r io.Reader, selfSeat int) error {
+	d := newMjaiRoundData(selfSeat)
+	reader := mjai.NewReader(r)
+	for {
+		msg, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := d.analysisMessage(msg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+const mjaiTCPPrefix = "tcp:"
+
+// 分析 mjai 数据
+// source 为 - 时读取标准输入，为 tcp:地址 时监听该地址，依次分析每个连接发来的数据，否则读取文件
+func runMjaiAnalysis(source string, selfSeat int) error {
+	switch {
+	case source == "-":
+		return analysisMjaiStream(os.Stdin, selfSeat)
+	case strings.HasPrefix(source, mjaiTCPPrefix):
+		addr := strings.TrimPrefix(source, mjaiTCPPrefix)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		color.HiGreen("正在监听 %s，等待 mjai 数据...", listener.Addr())
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return err
+			}
+			fmt.Println("已连接", conn.RemoteAddr())
+			err = analysisMjaiStream(conn, selfSeat)
+			conn.Close()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			fmt.Println("连接已断开", conn.RemoteAddr())
+		}
+	default:
+		file, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return analysisMjaiStream(file, selfSeat)
+	}
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/fatih/color"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// mjai AI 的策略参数，用于在对战场中比较不同的策略
+type mjaiBotConfig struct {
+	// 是否吃碰
+	call bool
+	// 门清听牌时是否立直
+	riichi bool
+	// 他家立直时，自家向听数不小于该值则防守
+	defenceShanten int
+}
+
+var mjaiBotConfigs = map[string]mjaiBotConfig{
+	"default": {call: true, riichi: true, defenceShanten: 1},
+	"menzen":  {riichi: true, defenceShanten: 1},              // 不鸣牌
+	"dama":    {call: true, defenceShanten: 1},                // 不立直
+	"push":    {call: true, riichi: true, defenceShanten: 99}, // 不防守
+	"fold":    {call: true, riichi: true, defenceShanten: 0},  // 他家立直时总是防守
+}
+
+// 以 mjai 协议对战的 AI，决策使用助手的分析逻辑
+// 评价相同的舍牌有多张时用 seed 初始化的随机数选择，因此相同的 seed 和输入总是得到相同的输出
+type mjaiBot struct {
+	*mjaiRoundData
+
+	name   string
+	config mjaiBotConfig
+	rng    *rand.Rand
+
+	scores []int
+
+	// 自家最后摸到的牌，鸣牌后为空
+	lastDrawPai string
+	// 自家本局的舍牌（含立直后的摸切），用于判断振听
+	selfDiscardTiles []int
+	// 见逃后到自家下次舍牌前为同巡振听，立直后为永久振听
+	isMissedAgari bool
+	// 宣告立直后要切的牌
+	reachDiscardPai string
+}
+
+func newMjaiBot(name string, selfSeat int, seed int64) *mjaiBot {
+	b := &mjaiBot{
+		mjaiRoundData: newMjaiRoundData(selfSeat),
+		name:          name,
+		config:        mjaiBotConfigs["default"],
+		rng:           rand.New(rand.NewSource(seed)),
+	}
+	b.skipOutput = true
+	return b
+}
+
+// 处理一条消息，返回要执行的操作
+func (b *mjaiBot) react(msg *mjai.Message) *mjai.Action {
+	if msg.Type == mjai.TypeHello {
+		return mjai.NewJoin(b.name, "default")
+	}
+
+	wasReached := b.players[0].isReached
+	if err := b.analysisMessage(msg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	self := b.selfSeat
+	switch msg.Type {
+	case mjai.TypeStartKyoku:
+		b.scores = msg.Scores
+		b.lastDrawPai = ""
+		b.selfDiscardTiles = nil
+		b.isMissedAgari = false
+		b.reachDiscardPai = ""
+	case mjai.TypeReachAccepted, mjai.TypeHora, mjai.TypeRyukyoku:
+		if len(msg.Scores) > 0 {
+			b.scores = msg.Scores
+		}
+	case mjai.TypeTsumo:
+		if msg.Actor == self {
+			b.lastDrawPai = msg.Pai
+			return b.onSelfDraw(msg.Pai)
+		}
+	case mjai.TypeReach:
+		if msg.Actor == self && b.reachDiscardPai != "" {
+			pai := b.reachDiscardPai
+			b.reachDiscardPai = ""
+			return mjai.NewDahai(self, pai, pai == b.lastDrawPai)
+		}
+	case mjai.TypeChi, mjai.TypePon:
+		if msg.Actor == self {
+			b.lastDrawPai = ""
+			return b.onSelfCall(msg)
+		}
+	case mjai.TypeDahai:
+		if msg.Actor != self {
+			return b.onOtherDiscard(msg)
+		}
+		tile, _ := mustParseMjaiTile(msg.Pai)
+		b.selfDiscardTiles = append(b.selfDiscardTiles, tile)
+		if !wasReached {
+			b.isMissedAgari = false
+		}
+	}
+	return mjai.NewNone()
+}
+
+// 自家摸牌：自摸、立直或舍牌
+func (b *mjaiBot) onSelfDraw(pai string) *mjai.Action {
+	self := b.selfSeat
+	tile, isRedFive := mustParseMjaiTile(pai)
+	playerInfo := b.newModelPlayerInfo()
+	if b.players[0].isReached {
+		// 立直后不再更新手牌，这里加上摸到的牌
+		playerInfo.HandTiles34 = append([]int(nil), b.counts...)
+		playerInfo.HandTiles34[tile]++
+		playerInfo.NumRedFives = append([]int(nil), b.numRedFives...)
+		if isRedFive {
+			playerInfo.NumRedFives[tile/9]++
+		}
+	}
+
+	if b.canWin(playerInfo, tile, true) {
+		return mjai.NewHora(self, self, pai)
+	}
+	if b.players[0].isReached {
+		return mjai.NewDahai(self, pai, true)
+	}
+
+	discardTile, isTenpai := b.chooseDiscardTile(playerInfo, nil)
+	discardPai := b.discardPai(discardTile)
+	if isTenpai && b.config.riichi && b.canReach(playerInfo) {
+		b.reachDiscardPai = discardPai
+		return mjai.NewReach(self)
+	}
+	return mjai.NewDahai(self, discardPai, discardPai == pai)
+}
+
+// 自家吃碰后舍牌，不能食替
+func (b *mjaiBot) onSelfCall(msg *mjai.Message) *mjai.Action {
+	calledTile, _ := mustParseMjaiTile(msg.Pai)
+	forbiddenTiles := []int{calledTile}
+	if msg.Type == mjai.TypeChi && calledTile < 27 {
+		selfTiles, _ := mustParseMjaiTiles(msg.Consumed)
+		if selfTiles[0] < calledTile && selfTiles[1] < calledTile && calledTile%9 >= 3 {
+			forbiddenTiles = append(forbiddenTiles, calledTile-3)
+		} else if selfTiles[0] > calledTile && selfTiles[1] > calledTile && calledTile%9 <= 5 {
+			forbiddenTiles = append(forbiddenTiles, calledTile+3)
+		}
+	}
+	discardTile, _ := b.chooseDiscardTile(b.newModelPlayerInfo(), forbiddenTiles)
+	return mjai.NewDahai(b.selfSeat, b.discardPai(discardTile), false)
+}
+
+// 他家舍牌：荣和或鸣牌
+func (b *mjaiBot) onOtherDiscard(msg *mjai.Message) *mjai.Action {
+	self := b.selfSeat
+	tile, isRedFive := mustParseMjaiTile(msg.Pai)
+
+	playerInfo := b.newModelPlayerInfo()
+	playerInfo.HandTiles34 = append([]int(nil), b.counts...)
+	playerInfo.HandTiles34[tile]++
+	if util.IsAgari(playerInfo.HandTiles34) {
+		if !b.isFuriten() && b.canWin(playerInfo, tile, false) {
+			return mjai.NewHora(self, msg.Actor, msg.Pai)
+		}
+		b.isMissedAgari = true
+		return mjai.NewNone()
+	}
+
+	// 立直后、他家立直时或者海底牌不鸣牌
+	playerInfo = b.newModelPlayerInfo()
+	if !b.config.call || b.players[0].isReached || b.numReachedOpponents() > 0 || playerInfo.LeftDrawTilesCount <= 0 {
+		return mjai.NewNone()
+	}
+
+	who := b.relativeWho(msg.Actor)
+	allowChi := who == 3 && b.playerNumber != 3
+	result13 := util.CalculateShantenWithImproves13(playerInfo)
+	shanten, results14, _ := util.CalculateMeld(playerInfo, tile, isRedFive, allowChi)
+	if len(results14) == 0 || shanten >= result13.Shanten {
+		return mjai.NewNone()
+	}
+	// 鸣牌后必须有役
+	best := results14[0]
+	if len(best.Result13.YakuTypes) == 0 || len(best.OpenTiles) != 2 {
+		return mjai.NewNone()
+	}
+	callType := mjai.TypeChi
+	if best.OpenTiles[0] == tile && best.OpenTiles[1] == tile {
+		callType = mjai.TypePon
+	}
+	return mjai.NewCall(callType, self, msg.Actor, msg.Pai, b.selfPais(best.OpenTiles))
+}
+
+// 是否有役
+func (b *mjaiBot) canWin(playerInfo *model.PlayerInfo, winTile int, isTsumo bool) bool {
+	if !util.IsAgari(playerInfo.HandTiles34) {
+		return false
+	}
+	playerInfo.IsTsumo = isTsumo
+	playerInfo.WinTile = winTile
+	return util.CalcPoint(playerInfo).Point > 0
+}
+
+// 舍牌振听或见逃
+func (b *mjaiBot) isFuriten() bool {
+	if b.isMissedAgari {
+		return true
+	}
+	hands := append([]int(nil), b.counts...)
+	for _, tile := range b.selfDiscardTiles {
+		hands[tile]++
+		isAgari := util.IsAgari(hands)
+		hands[tile]--
+		if isAgari {
+			return true
+		}
+	}
+	return false
+}
+
+// 门清听牌且点数足够时立直
+func (b *mjaiBot) canReach(playerInfo *model.PlayerInfo) bool {
+	if playerInfo.IsNaki() || playerInfo.LeftDrawTilesCount < 4 {
+		return false
+	}
+	return b.selfSeat >= len(b.scores) || b.scores[b.selfSeat] >= 1000
+}
+
+// 选择舍牌，不会选择 forbiddenTiles 中的牌
+// 他家立直且自家向听数较大时防守，否则进攻
+func (b *mjaiBot) chooseDiscardTile(playerInfo *model.PlayerInfo, forbiddenTiles []int) (discardTile int, isTenpai bool) {
+	isForbidden := func(tile int) bool {
+		for _, t := range forbiddenTiles {
+			if t == tile {
+				return true
+			}
+		}
+		return false
+	}
+	filter := func(results util.Hand14AnalysisResultList) (filtered util.Hand14AnalysisResultList) {
+		for _, r := range results {
+			if !isForbidden(r.DiscardTile) {
+				filtered = append(filtered, r)
+			}
+		}
+		return
+	}
+
+	shanten, results14, incShantenResults14 := util.CalculateShantenWithImproves14(playerInfo)
+	results14, incShantenResults14 = filter(results14), filter(incShantenResults14)
+
+	if shanten >= b.config.defenceShanten && b.numReachedOpponents() > 0 {
+		tiles34 := append([]int(nil), playerInfo.HandTiles34...)
+		for _, tile := range forbiddenTiles {
+			tiles34[tile] = 0
+		}
+		if tile := b.analysisTilesRisk().mixedRiskTable().getBestDefenceTile(tiles34); tile >= 0 {
+			return tile, false
+		}
+	}
+
+	discardTile = bestAttackDiscardTile(playerInfo, shanten, results14, incShantenResults14)
+	if discardTile == -1 {
+		// 没有分析结果时，切一张能切的牌
+		for tile, c := range playerInfo.HandTiles34 {
+			if c > 0 && !isForbidden(tile) {
+				return tile, false
+			}
+		}
+		for tile, c := range playerInfo.HandTiles34 {
+			if c > 0 {
+				return tile, false
+			}
+		}
+	}
+
+	for _, results := range []util.Hand14AnalysisResultList{results14, incShantenResults14} {
+		for _, r := range results {
+			if r.DiscardTile == discardTile {
+				return b.breakTie(r, results), r.Result13.Shanten == 0 && r.Result13.Waits.AllCount() > 0
+			}
+		}
+	}
+	return discardTile, false
+}
+
+// 在评价与 best 相同的舍牌中随机选择一张
+func (b *mjaiBot) breakTie(best *util.Hand14AnalysisResult, results util.Hand14AnalysisResultList) int {
+	tiles := []int{}
+	for _, r := range results {
+		if r.IsDiscardDoraTile == best.IsDiscardDoraTile &&
+			r.DiscardTileValue == best.DiscardTileValue &&
+			r.Result13.Shanten == best.Result13.Shanten &&
+			r.Result13.Waits.AllCount() == best.Result13.Waits.AllCount() &&
+			r.Result13.MixedWaitsScore == best.Result13.MixedWaitsScore {
+			tiles = append(tiles, r.DiscardTile)
+		}
+	}
+	if len(tiles) <= 1 {
+		return best.DiscardTile
+	}
+	sort.Ints(tiles)
+	return tiles[b.rng.Intn(len(tiles))]
+}
+
+// 手牌中的赤5个数（numRedFives 包含副露中的赤5）
+func (b *mjaiBot) handRedFives() []int {
+	reds := append([]int(nil), b.numRedFives...)
+	for _, meld := range b.players[0].melds {
+		if !meld.ContainRedFive {
+			continue
+		}
+		for _, tile := range meld.Tiles {
+			if tile < 27 && tile%9 == 4 && reds[tile/9] > 0 {
+				reds[tile/9]--
+				break
+			}
+		}
+	}
+	return reds
+}
+
+// 将手牌中的牌转换成 mjai 的牌，优先使用非赤5
+func (b *mjaiBot) selfPais(tiles []int) (pais []string) {
+	counts := append([]int(nil), b.counts...)
+	reds := b.handRedFives()
+	for _, tile := range tiles {
+		isRedFive := tile < 27 && tile%9 == 4 && counts[tile] <= reds[tile/9]
+		counts[tile]--
+		if isRedFive {
+			reds[tile/9]--
+		}
+		pais = append(pais, mjai.TileString(tile, isRedFive))
+	}
+	return
+}
+
+func (b *mjaiBot) discardPai(tile int) string {
+	return b.selfPais([]int{tile})[0]
+}
+
+// 在标准输入输出上运行 mjai AI，每读入一行回复一行
+// 一行有多条消息时，只回复最后一条消息对应的操作
+func runMjaiBot(r io.Reader, w io.Writer, selfSeat int, seed int64) error {
+	b := newMjaiBot("mahjong-helper", selfSeat, seed)
+	writer := mjai.NewActionWriter(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		messages, err := mjai.Unmarshal(scanner.Bytes())
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			continue
+		}
+		action := mjai.NewNone()
+		for _, msg := range messages {
+			action = b.react(msg)
+		}
+		if err := writer.Write(action); err != nil {
+			return err
+		}
+		if messages[len(messages)-1].Type == mjai.TypeEndGame {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// mjai AI 使用标准输出通信，将控制台输出改到标准错误，返回原来的标准输出
+func redirectConsoleToStderr() (stdout *os.File) {
+	stdout = os.Stdout
+	os.Stdout = os.Stderr
+	color.Output = os.Stderr
+	return
+}
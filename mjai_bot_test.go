@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMjaiStartKyoku = `{"type":"start_kyoku","bakaze":"E","kyoku":1,"honba":0,"kyotaku":0,"oya":0,"dora_marker":"9m","scores":[25000,25000,25000,25000],"tehais":[[%s],["?","?","?","?","?","?","?","?","?","?","?","?","?"],["?","?","?","?","?","?","?","?","?","?","?","?","?"],["?","?","?","?","?","?","?","?","?","?","?","?","?"]]}`
+
+func runTestMjaiBot(t *testing.T, seed int64, hand string, lines ...string) []string {
+	input := []string{
+		`{"type":"hello","protocol":"mjsonp","protocol_version":3}`,
+		`{"type":"start_game","id":0,"names":["A","B","C","D"]}`,
+		strings.Replace(testMjaiStartKyoku, "%s", hand, 1),
+	}
+	input = append(input, lines...)
+	output := &bytes.Buffer{}
+	if err := runMjaiBot(strings.NewReader(strings.Join(input, "\n")), output, -1, seed); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(output.String()), "\n")
+}
+
+func Test_mjaiBot(t *testing.T) {
+	assert := assert.New(t)
+
+	// 自摸
+	output := runTestMjaiBot(t, 0, `"1m","2m","3m","4p","5p","6p","7s","8s","9s","2p","2p","5s","6s"`,
+		`{"type":"tsumo","actor":0,"pai":"4s"}`,
+	)
+	assert.Equal([]string{
+		`{"type":"join","name":"mahjong-helper","room":"default"}`,
+		`{"type":"none"}`,
+		`{"type":"none"}`,
+		`{"type":"hora","actor":0,"target":0,"pai":"4s"}`,
+	}, output)
+
+	// 立直后荣和
+	output = runTestMjaiBot(t, 0, `"1m","2m","3m","4p","5p","6p","7s","8s","9s","2p","2p","5s","6s"`,
+		`{"type":"tsumo","actor":0,"pai":"N"}`,
+		`{"type":"reach","actor":0}`,
+		`{"type":"dahai","actor":0,"pai":"N","tsumogiri":true}`,
+		`{"type":"reach_accepted","actor":0,"deltas":[-1000,0,0,0],"scores":[24000,25000,25000,25000]}`,
+		`{"type":"tsumo","actor":1,"pai":"?"}`,
+		`{"type":"dahai","actor":1,"pai":"7s","tsumogiri":true}`,
+	)
+	assert.Equal([]string{
+		`{"type":"reach","actor":0}`,
+		`{"type":"dahai","actor":0,"pai":"N","tsumogiri":true}`,
+		`{"type":"none"}`,
+		`{"type":"none"}`,
+		`{"type":"none"}`,
+		`{"type":"hora","actor":0,"target":1,"pai":"7s"}`,
+	}, output[3:])
+
+	// 碰役牌，之后单骑听牌
+	output = runTestMjaiBot(t, 0, `"1m","2m","3m","4p","5p","6p","7s","8s","9s","2p","E","E","S"`,
+		`{"type":"tsumo","actor":1,"pai":"?"}`,
+		`{"type":"dahai","actor":1,"pai":"E","tsumogiri":true}`,
+		`{"type":"pon","actor":0,"target":1,"pai":"E","consumed":["E","E"]}`,
+	)
+	assert.Equal([]string{
+		`{"type":"none"}`,
+		`{"type":"pon","actor":0,"target":1,"pai":"E","consumed":["E","E"]}`,
+		`{"type":"dahai","actor":0,"pai":"2p","tsumogiri":false}`,
+	}, output[3:])
+
+	// 相同的 seed 总是得到相同的结果
+	const hand = `"1m","4m","7m","2p","5p","8p","3s","6s","9s","E","S","W","N"`
+	draw := `{"type":"tsumo","actor":0,"pai":"P"}`
+	assert.Equal(runTestMjaiBot(t, 1, hand, draw), runTestMjaiBot(t, 1, hand, draw))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_mjaiRoundData(t *testing.T) {
+	assert := assert.New(t)
+
+	const data = `{"type":"start_game","id":1,"names":["A","B","C","D"]}
+{"type":"start_kyoku","bakaze":"E","kyoku":2,"honba":1,"kyotaku":0,"oya":1,"dora_marker":"3p","scores":[25000,25000,25000,25000],"tehais":[["?","?","?","?","?","?","?","?","?","?","?","?","?"],["1m","2m","4m","5m","6m","7p","8p","9p","5pr","5p","7s","8s","P"],["?","?","?","?","?","?","?","?","?","?","?","?","?"],["?","?","?","?","?","?","?","?","?","?","?","?","?"]]}
+{"type":"tsumo","actor":1,"pai":"E"}
+{"type":"dahai","actor":1,"pai":"E","tsumogiri":true}
+{"type":"tsumo","actor":2,"pai":"?"}
+{"type":"reach","actor":2}
+{"type":"dahai","actor":2,"pai":"9s","tsumogiri":false}
+{"type":"reach_accepted","actor":2,"deltas":[0,0,-1000,0],"scores":[25000,25000,24000,25000]}
+{"type":"pon","actor":3,"target":2,"pai":"9s","consumed":["9s","9s"]}
+{"type":"dahai","actor":3,"pai":"3m","tsumogiri":false}
+{"type":"tsumo","actor":0,"pai":"?"}
+{"type":"dahai","actor":0,"pai":"3m","tsumogiri":true}
+{"type":"chi","actor":1,"target":0,"pai":"3m","consumed":["1m","2m"]}
+{"type":"dahai","actor":1,"pai":"P","tsumogiri":false}
+{"type":"dora","dora_marker":"N"}
+`
+	d := newMjaiRoundData(-1)
+	d.skipOutput = true
+	reader := mjai.NewReader(strings.NewReader(data))
+	for {
+		msg, err := reader.Read()
+		if err != nil {
+			break
+		}
+		if !assert.NoError(d.analysisMessage(msg), msg.Type) {
+			return
+		}
+	}
+
+	assert.Equal(1, d.selfSeat)
+	assert.Equal(1, d.roundNumber)
+	assert.Equal(1, d.benNumber)
+	assert.Equal(0, d.dealer)
+	assert.Equal([]int{11, 30}, d.doraIndicators)
+	assert.Equal([]int{0, 1, 0}, d.numRedFives)
+	assert.Equal(0, d.counts[0]+d.counts[1]+d.counts[27]+d.counts[31])
+
+	// 下家立直
+	assert.True(d.players[1].isReached)
+	assert.Equal(1, d.players[1].reachTileAtGlobal)
+	// 对家碰了下家的立直宣言牌
+	if assert.Len(d.players[2].melds, 1) {
+		assert.Equal(&model.Meld{MeldType: meldTypePon, Tiles: []int{26, 26, 26}, SelfTiles: []int{26, 26}, CalledTile: 26}, d.players[2].melds[0])
+	}
+	if assert.Len(d.players[0].melds, 1) {
+		assert.Equal([]int{0, 1, 2}, d.players[0].melds[0].Tiles)
+	}
+	assert.Equal([]int{27, 26, 2, -3, 31}, d.globalDiscardTiles)
+
+	// 指定座位时忽略 start_game 中的 id
+	d = newMjaiRoundData(3)
+	d.skipOutput = true
+	msg, _ := mjai.Unmarshal([]byte(`{"type":"start_game","id":1}`))
+	assert.NoError(d.analysisMessage(msg[0]))
+	assert.Equal(3, d.selfSeat)
+	assert.Equal(1, d.dealer)
+}
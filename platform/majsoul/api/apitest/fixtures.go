@@ -0,0 +1,304 @@
+package apitest
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/golang/protobuf/proto"
+	"sync"
+)
+
+// 雀魂的错误码
+const (
+	ErrorCodeLoginFailed    = 1003
+	ErrorCodeRecordNotFound = 1203
+)
+
+// 模拟的账号
+type Account struct {
+	AccountID   uint32
+	Username    string
+	Nickname    string
+	AccessToken string
+}
+
+func NewTestAccount() *Account {
+	return &Account{
+		AccountID:   1,
+		Username:    "user",
+		Nickname:    "test",
+		AccessToken: "token",
+	}
+}
+
+// 处理 login oauth2Check oauth2Login logout 请求
+// 不检查密码，账号不存在或 access token 错误时返回登录失败
+func (s *Server) HandleLogin(account *Account) {
+	s.Handle(".lq.Lobby.login", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqLogin{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		if req.Account != account.Username {
+			return &lq.ResLogin{Error: &lq.Error{Code: ErrorCodeLoginFailed}}, nil
+		}
+		resp := &lq.ResLogin{AccountId: account.AccountID, Account: &lq.Account{AccountId: account.AccountID, Nickname: account.Nickname}}
+		if req.GenAccessToken {
+			resp.AccessToken = account.AccessToken
+		}
+		return resp, nil
+	})
+	s.Handle(".lq.Lobby.oauth2Check", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqOauth2Check{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		return &lq.ResOauth2Check{HasAccount: req.AccessToken == account.AccessToken}, nil
+	})
+	s.Handle(".lq.Lobby.oauth2Login", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqOauth2Login{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		if req.AccessToken != account.AccessToken {
+			return &lq.ResLogin{Error: &lq.Error{Code: ErrorCodeLoginFailed}}, nil
+		}
+		return &lq.ResLogin{AccountId: account.AccountID, Account: &lq.Account{AccountId: account.AccountID, Nickname: account.Nickname}}, nil
+	})
+	s.Handle(".lq.Lobby.logout", func(c *Conn, data []byte) (proto.Message, error) {
+		return &lq.ResLogout{}, nil
+	})
+}
+
+// 模拟的牌谱库，并发安全
+type RecordStore struct {
+	mu sync.Mutex
+
+	records   []*lq.RecordGame // 从新到旧
+	collected []string
+
+	// 获取这些牌谱时返回错误
+	failedUuids map[string]bool
+}
+
+// 生成 numRecords 个牌谱，每小时一局
+func NewRecordStore(numRecords int) *RecordStore {
+	rs := &RecordStore{failedUuids: map[string]bool{}}
+	rs.AddRecords(numRecords)
+	return rs
+}
+
+// 添加 n 个比已有牌谱更新的牌谱
+func (rs *RecordStore) AddRecords(n int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	newRecords := []*lq.RecordGame{}
+	for i := 0; i < n; i++ {
+		endTime := uint32(1577836800 + 3600*(len(rs.records)+n-i))
+		newRecords = append(newRecords, &lq.RecordGame{
+			Uuid:      fmt.Sprintf("record-%d", endTime),
+			StartTime: endTime - 1800,
+			EndTime:   endTime,
+		})
+	}
+	rs.records = append(newRecords, rs.records...)
+}
+
+// 所有牌谱，从新到旧
+func (rs *RecordStore) Records() []*lq.RecordGame {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]*lq.RecordGame(nil), rs.records...)
+}
+
+// 设置收藏的牌谱
+func (rs *RecordStore) SetCollected(uuids []string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.collected = uuids
+}
+
+// 设置获取牌谱 uuid 时是否返回错误
+func (rs *RecordStore) SetFailed(uuid string, failed bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if failed {
+		rs.failedUuids[uuid] = true
+	} else {
+		delete(rs.failedUuids, uuid)
+	}
+}
+
+func (rs *RecordStore) findRecord(uuid string) *lq.RecordGame {
+	for _, r := range rs.records {
+		if r.Uuid == uuid {
+			return r
+		}
+	}
+	return nil
+}
+
+// 生成牌谱数据（即 ResGameRecord.Data），records 为 RecordNewRound RecordDiscardTile 等
+// records 为空时只包含一个 RecordNewRound
+func NewGameDetailRecords(records ...proto.Message) ([]byte, error) {
+	if len(records) == 0 {
+		records = []proto.Message{&lq.RecordNewRound{Doras: []string{"1m"}, Scores: []int32{25000, 25000, 25000, 25000}}}
+	}
+	detail := &lq.GameDetailRecords{}
+	for _, record := range records {
+		data, err := wrap(".lq."+proto.MessageName(record)[len("lq."):], record)
+		if err != nil {
+			return nil, err
+		}
+		detail.Records = append(detail.Records, data)
+	}
+	return wrap("", detail)
+}
+
+// 处理 fetchGameRecordList fetchCollectedGameRecordList fetchGameRecordsDetail fetchGameRecord 请求
+// 一半的牌谱数据直接返回，另一半通过 data_url 获取
+func (s *Server) HandleRecords(rs *RecordStore) error {
+	detailData, err := NewGameDetailRecords()
+	if err != nil {
+		return err
+	}
+
+	s.Handle(".lq.Lobby.fetchGameRecordList", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqGameRecordList{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		resp := &lq.ResGameRecordList{TotalCount: uint32(len(rs.records))}
+		for i := int(req.Start) - 1; i < int(req.Start-1+req.Count) && i < len(rs.records); i++ {
+			resp.RecordList = append(resp.RecordList, rs.records[i])
+		}
+		return resp, nil
+	})
+	s.Handle(".lq.Lobby.fetchCollectedGameRecordList", func(c *Conn, data []byte) (proto.Message, error) {
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		resp := &lq.ResCollectedGameRecordList{}
+		for _, uuid := range rs.collected {
+			resp.RecordList = append(resp.RecordList, &lq.RecordCollectedData{Uuid: uuid})
+		}
+		return resp, nil
+	})
+	s.Handle(".lq.Lobby.fetchGameRecordsDetail", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqGameRecordsDetail{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		resp := &lq.ResGameRecordsDetail{}
+		for _, uuid := range req.UuidList {
+			if record := rs.findRecord(uuid); record != nil {
+				resp.RecordList = append(resp.RecordList, record)
+			}
+		}
+		return resp, nil
+	})
+	s.Handle(".lq.Lobby.fetchGameRecord", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqGameRecord{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		rs.mu.Lock()
+		record := rs.findRecord(req.GameUuid)
+		failed := rs.failedUuids[req.GameUuid]
+		rs.mu.Unlock()
+		if record == nil || failed {
+			return &lq.ResGameRecord{Error: &lq.Error{Code: ErrorCodeRecordNotFound}}, nil
+		}
+		if record.EndTime%2 == 0 {
+			dataURL := s.HandleFile("/record/"+record.Uuid, detailData)
+			return &lq.ResGameRecord{Head: record, DataUrl: dataURL}, nil
+		}
+		return &lq.ResGameRecord{Head: record, Data: detailData}, nil
+	})
+	return nil
+}
+
+// 模拟的对局，客户端进入对局后依次收到 Actions 的通知
+type Game struct {
+	UUID    string
+	Players []*lq.PlayerGameView // 按座位排列
+	Actions []proto.Message      // ActionNewRound ActionDealTile ActionDiscardTile 等
+}
+
+// 生成一局简单的对局：东一局配牌后东家摸切一张，然后流局
+func NewTestGame(account *Account) *Game {
+	players := []*lq.PlayerGameView{{AccountId: account.AccountID, Nickname: account.Nickname}}
+	for i := uint32(2); i <= 4; i++ {
+		players = append(players, &lq.PlayerGameView{AccountId: 100 + i, Nickname: fmt.Sprintf("player%d", i)})
+	}
+	return &Game{
+		UUID:    "game-1",
+		Players: players,
+		Actions: []proto.Message{
+			&lq.ActionNewRound{
+				Tiles:         []string{"1m", "2m", "3m", "4p", "5p", "6p", "7s", "8s", "9s", "1z", "1z", "2z", "3z", "4z"},
+				Doras:         []string{"5m"},
+				Scores:        []int32{25000, 25000, 25000, 25000},
+				LeftTileCount: 69,
+			},
+			&lq.ActionDiscardTile{Seat: 0, Tile: "4z"},
+			&lq.ActionDealTile{Seat: 1, LeftTileCount: 68},
+			&lq.ActionDiscardTile{Seat: 1, Tile: "1p", Moqie: true},
+			&lq.ActionNoTile{},
+		},
+	}
+}
+
+func (g *Game) actionPrototypes() ([]*lq.ActionPrototype, error) {
+	actions := []*lq.ActionPrototype{}
+	for i, action := range g.Actions {
+		data, err := proto.Marshal(action)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, &lq.ActionPrototype{
+			Step: uint32(i),
+			Name: proto.MessageName(action)[len("lq."):],
+			Data: data,
+		})
+	}
+	return actions, nil
+}
+
+// 处理 authGame enterGame syncGame 请求
+// enterGame 时先发送所有操作的通知，再返回响应；syncGame（断线重连）时在响应中返回所有操作
+func (s *Server) HandleGame(g *Game) error {
+	actions, err := g.actionPrototypes()
+	if err != nil {
+		return err
+	}
+
+	s.Handle(".lq.FastTest.authGame", func(c *Conn, data []byte) (proto.Message, error) {
+		req := lq.ReqAuthGame{}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		if req.GameUuid != g.UUID {
+			return &lq.ResAuthGame{Error: &lq.Error{Code: ErrorCodeLoginFailed}}, nil
+		}
+		resp := &lq.ResAuthGame{Players: g.Players, IsGameStart: true, GameConfig: &lq.GameConfig{}}
+		for _, p := range g.Players {
+			resp.SeatList = append(resp.SeatList, p.AccountId)
+		}
+		return resp, nil
+	})
+	s.Handle(".lq.FastTest.enterGame", func(c *Conn, data []byte) (proto.Message, error) {
+		for _, action := range actions {
+			if err := c.Notify(".lq.ActionPrototype", action); err != nil {
+				return nil, err
+			}
+		}
+		return &lq.ResEnterGame{IsEnd: true, Step: uint32(len(actions))}, nil
+	})
+	s.Handle(".lq.FastTest.syncGame", func(c *Conn, data []byte) (proto.Message, error) {
+		return &lq.ResSyncGame{IsEnd: true, Step: uint32(len(actions)), GameRestore: &lq.GameRestore{Actions: actions}}, nil
+	})
+	return nil
+}
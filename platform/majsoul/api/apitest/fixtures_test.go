@@ -0,0 +1,100 @@
+package apitest_test
+
+import (
+	"context"
+	"testing"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api/apitest"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleGame(t *testing.T) {
+	assert := assert.New(t)
+
+	account := apitest.NewTestAccount()
+	game := apitest.NewTestGame(account)
+	s := apitest.NewServer()
+	defer s.Close()
+	if !assert.NoError(s.HandleGame(game)) {
+		return
+	}
+
+	c := api.NewWebSocketClient()
+	if !assert.NoError(c.Connect(s.WebSocketURL, "")) {
+		return
+	}
+	defer c.Close()
+	ctx := context.Background()
+
+	_, err := c.AuthGame(ctx, &lq.ReqAuthGame{AccountId: account.AccountID, GameUuid: "unknown"})
+	assert.Error(err)
+	respAuth, err := c.AuthGame(ctx, &lq.ReqAuthGame{AccountId: account.AccountID, GameUuid: game.UUID})
+	if assert.NoError(err) {
+		assert.Len(respAuth.Players, 4)
+		assert.Equal(account.AccountID, respAuth.SeatList[0])
+	}
+
+	actions, _ := c.Subscribe("ActionPrototype")
+	respEnter, err := c.EnterGame(ctx, &lq.ReqCommon{})
+	if assert.NoError(err) {
+		assert.EqualValues(len(game.Actions), respEnter.Step)
+	}
+	names := []string{}
+	for range game.Actions {
+		notify := <-actions
+		names = append(names, notify.Message.(*lq.ActionPrototype).Name)
+	}
+	assert.Equal([]string{"ActionNewRound", "ActionDiscardTile", "ActionDealTile", "ActionDiscardTile", "ActionNoTile"}, names)
+
+	respSync, err := c.SyncGame(ctx, &lq.ReqSyncGame{})
+	if assert.NoError(err) {
+		assert.Len(respSync.GameRestore.Actions, len(game.Actions))
+	}
+}
+
+func TestHandleRecords(t *testing.T) {
+	assert := assert.New(t)
+
+	s := apitest.NewServer()
+	defer s.Close()
+	store := apitest.NewRecordStore(3)
+	if !assert.NoError(s.HandleRecords(store)) {
+		return
+	}
+
+	c := api.NewWebSocketClient()
+	if !assert.NoError(c.Connect(s.WebSocketURL, "")) {
+		return
+	}
+	defer c.Close()
+	ctx := context.Background()
+
+	respList, err := c.FetchGameRecordList(ctx, &lq.ReqGameRecordList{Start: 1, Count: 2})
+	if assert.NoError(err) {
+		assert.EqualValues(3, respList.TotalCount)
+		assert.Len(respList.RecordList, 2)
+	}
+
+	detailData, err := apitest.NewGameDetailRecords()
+	if !assert.NoError(err) {
+		return
+	}
+	for _, record := range store.Records() {
+		resp, err := c.FetchGameRecord(ctx, &lq.ReqGameRecord{GameUuid: record.Uuid})
+		if !assert.NoError(err) {
+			continue
+		}
+		data := resp.Data
+		if resp.DataUrl != "" {
+			data, err = tool.Fetch(resp.DataUrl)
+			assert.NoError(err)
+		}
+		assert.Equal(detailData, data)
+	}
+
+	store.SetFailed(store.Records()[0].Uuid, true)
+	_, err = c.FetchGameRecord(ctx, &lq.ReqGameRecord{GameUuid: store.Records()[0].Uuid})
+	assert.Error(err)
+}
@@ -0,0 +1,228 @@
+// Package apitest 提供一个本地的模拟雀魂服务器，使用与雀魂相同的 WebSocket 数据格式
+// 用于在没有网络的环境下测试 api.WebSocketClient 和牌谱下载等功能
+package apitest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// 与 api 包中的定义相同
+const (
+	messageTypeNotify   = 1
+	messageTypeRequest  = 2
+	messageTypeResponse = 3
+)
+
+// 处理一个请求，data 为请求的 protobuf 数据
+// 返回 nil, nil 时不响应（模拟请求超时），返回 error 时断开连接（模拟断线）
+type Handler func(c *Conn, data []byte) (proto.Message, error)
+
+// 模拟的雀魂服务器，大厅和对局使用同一个服务器
+type Server struct {
+	*httptest.Server
+
+	// 客户端连接的地址，如 ws://127.0.0.1:12345/
+	WebSocketURL string
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	files    map[string][]byte
+	calls    map[string]int
+	conns    []*Conn
+}
+
+// 一个客户端连接
+type Conn struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func NewServer() *Server {
+	s := &Server{
+		handlers: map[string]Handler{},
+		files:    map[string][]byte{},
+		calls:    map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.WebSocketURL = "ws" + strings.TrimPrefix(s.URL, "http") + "/"
+	return s
+}
+
+// 注册 method 的处理函数，method 为完整的方法名，如 .lq.Lobby.login
+// 未注册的方法返回空的响应（相当于 ResCommon{}），如 heatbeat
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// 通过 HTTP 提供文件，返回文件的 URL（如牌谱的 data_url）
+func (s *Server) HandleFile(path string, data []byte) (url string) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = data
+	return s.URL + path
+}
+
+// method 被调用的次数
+func (s *Server) NumCalls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = map[string]int{}
+}
+
+// 建立过的连接数，断线重连时会增加
+func (s *Server) NumConns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// 向所有连接发送通知
+func (s *Server) Notify(name string, message proto.Message) error {
+	s.mu.Lock()
+	conns := append([]*Conn(nil), s.conns...)
+	s.mu.Unlock()
+	for _, c := range conns {
+		if err := c.Notify(name, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 断开所有连接
+func (s *Server) CloseConns() {
+	s.mu.Lock()
+	conns := append([]*Conn(nil), s.conns...)
+	s.mu.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		s.mu.Lock()
+		data, ok := s.files[r.URL.Path]
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+		return
+	}
+
+	// 客户端会模拟雀魂的 origin
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &Conn{ws: ws}
+	s.mu.Lock()
+	s.conns = append(s.conns, c)
+	s.mu.Unlock()
+	defer c.Close()
+
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(data) < 3 || data[0] != messageTypeRequest {
+			continue
+		}
+		messageIndex := binary.LittleEndian.Uint16(data[1:3])
+		method, reqData, err := unwrap(data[3:])
+		if err != nil {
+			return
+		}
+		// 与雀魂服务器一样并发处理请求，响应的顺序与请求不一定相同
+		go func() {
+			if err := s.handle(c, messageIndex, method, reqData); err != nil {
+				c.Close()
+			}
+		}()
+	}
+}
+
+func (s *Server) handle(c *Conn, messageIndex uint16, method string, data []byte) error {
+	s.mu.Lock()
+	s.calls[method]++
+	handler, ok := s.handlers[method]
+	s.mu.Unlock()
+
+	var resp proto.Message = &lq.ResCommon{}
+	if ok {
+		var err error
+		if resp, err = handler(c, data); err != nil {
+			return err
+		}
+		if resp == nil {
+			return nil
+		}
+	}
+
+	respData, err := wrap("", resp)
+	if err != nil {
+		return err
+	}
+	head := []byte{messageTypeResponse, 0, 0}
+	binary.LittleEndian.PutUint16(head[1:], messageIndex)
+	return c.write(append(head, respData...))
+}
+
+func (c *Conn) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.ws.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// 发送通知，name 如 .lq.NotifyAccountUpdate
+func (c *Conn) Notify(name string, message proto.Message) error {
+	data, err := wrap(name, message)
+	if err != nil {
+		return err
+	}
+	return c.write(append([]byte{messageTypeNotify}, data...))
+}
+
+// 断开连接
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+func wrap(name string, message proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&lq.Wrapper{Name: name, Data: data})
+}
+
+func unwrap(rawData []byte) (name string, data []byte, err error) {
+	wrapper := lq.Wrapper{}
+	if err = proto.Unmarshal(rawData, &wrapper); err != nil {
+		return "", nil, fmt.Errorf("lq.Wrapper: %v", err)
+	}
+	return wrapper.Name, wrapper.Data, nil
+}
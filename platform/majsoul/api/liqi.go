@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/tool"
@@ -10,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,124 +23,473 @@ const (
 	MessageTypeResponse = 3
 )
 
+const (
+	maxMessageIndex = 60007 // from code.js
+
+	DefaultCallTimeout          = 15 * time.Second
+	DefaultHeartbeatInterval    = 6 * time.Second
+	DefaultMaxReconnectInterval = 30 * time.Second
+	minReconnectInterval        = time.Second
+
+	// 订阅通知的缓冲大小，订阅者处理不及时的通知会被丢弃
+	notifyChanSize = 100
+)
+
+var (
+	ErrClosed       = errors.New("连接已关闭")
+	ErrNotConnected = errors.New("连接已断开，正在重连")
+	ErrTooManyCalls = errors.New("等待响应的请求过多")
+)
+
+// 收到的通知，如 NotifyAccountUpdate ActionPrototype 等
+type Notify struct {
+	Name    string // 不含开头的 .lq.
+	Message proto.Message
+}
+
+// 等待响应的请求
+type pendingCall struct {
+	ws   *websocket.Conn // 断线时只让该连接上的请求失败
+	resp proto.Message
+	done chan error
+}
+
+type notifySubscriber struct {
+	name       string // 为空时订阅所有通知
+	notifyChan chan *Notify
+}
+
 type WebSocketClient struct {
-	sync.Mutex
+	// 调用时 ctx 没有设置超时的话，使用该超时时间
+	CallTimeout time.Duration
+
+	// 心跳间隔，心跳失败时会断开连接并重连
+	HeartbeatInterval time.Duration
+
+	// 重连的最大间隔，每次重连失败后间隔翻倍，为 0 时不自动重连
+	MaxReconnectInterval time.Duration
+
+	endpoint string
+	origin   string
+
+	mu sync.Mutex
+
+	ws           *websocket.Conn // 断线重连时为 nil
+	messageIndex uint16
+	pendingCalls map[uint16]*pendingCall
 
-	ws     *websocket.Conn
-	closed bool
+	// 用于断线重连后重新登录
+	accessToken       string
+	oauth2LoginConfig *lq.ReqOauth2Login
 
-	messageIndex       uint16
-	respMessageChanMap *sync.Map // messageIndex -> chan proto.Message
+	subscribers []*notifySubscriber
+
+	// 同一连接同时只能有一个写者
+	writeMu sync.Mutex
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 func NewWebSocketClient() *WebSocketClient {
 	return &WebSocketClient{
-		respMessageChanMap: &sync.Map{},
+		CallTimeout:          DefaultCallTimeout,
+		HeartbeatInterval:    DefaultHeartbeatInterval,
+		MaxReconnectInterval: DefaultMaxReconnectInterval,
+		pendingCalls:         map[uint16]*pendingCall{},
+		done:                 make(chan struct{}),
+	}
+}
+
+func (c *WebSocketClient) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
 	}
 }
 
-func (c *WebSocketClient) run() {
-	for !c.closed {
-		_, data, err := c.ws.ReadMessage()
+func (c *WebSocketClient) dial() (*websocket.Conn, error) {
+	header := http.Header{}
+	header.Set("origin", c.origin) // 模拟来源
+	ws, _, err := websocket.DefaultDialer.Dial(c.endpoint, header)
+	return ws, err
+}
+
+func (c *WebSocketClient) Connect(endpoint string, origin string) error {
+	c.endpoint = endpoint
+	c.origin = origin
+	ws, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.ws = ws
+	c.mu.Unlock()
+
+	go c.run(ws)
+	go c.heartbeat()
+
+	return nil
+}
+
+func (c *WebSocketClient) ConnectMajsoul() error {
+	endpoint, err := tool.GetMajsoulWebSocketURL()
+	if err != nil {
+		return err
+	}
+	return c.Connect(endpoint, tool.MajsoulOriginURL)
+}
+
+// 关闭连接，等待响应的请求和订阅的通知都会结束
+func (c *WebSocketClient) Close() (err error) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		c.mu.Lock()
+		ws := c.ws
+		c.ws = nil
+		subscribers := c.subscribers
+		c.subscribers = nil
+		c.mu.Unlock()
+
+		if ws != nil {
+			err = ws.Close()
+			c.failPendingCalls(ws, ErrClosed)
+		}
+		for _, s := range subscribers {
+			close(s.notifyChan)
+		}
+	})
+	return
+}
+
+// 订阅通知，name 为通知类型（如 NotifyAccountUpdate），为空时订阅所有通知
+// 取消订阅或关闭连接后，返回的 channel 会被关闭
+func (c *WebSocketClient) Subscribe(name string) (notifyChan <-chan *Notify, unsubscribe func()) {
+	s := &notifySubscriber{
+		name:       strings.TrimPrefix(strings.TrimPrefix(name, "."), "lq."),
+		notifyChan: make(chan *Notify, notifyChanSize),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isClosed() {
+		close(s.notifyChan)
+		return s.notifyChan, func() {}
+	}
+	c.subscribers = append(c.subscribers, s)
+
+	unsubscribe = func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, _s := range c.subscribers {
+			if _s == s {
+				c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+				close(s.notifyChan)
+				return
+			}
+		}
+	}
+	return s.notifyChan, unsubscribe
+}
+
+func (c *WebSocketClient) run(ws *websocket.Conn) {
+	for {
+		_, data, err := ws.ReadMessage()
 		if err != nil {
-			if c.closed {
+			if c.isClosed() {
+				c.failPendingCalls(ws, ErrClosed)
 				return
 			}
+			c.failPendingCalls(ws, ErrNotConnected)
 			fmt.Fprintln(os.Stderr, "ws.ReadMessage:", err)
-			continue
+
+			c.mu.Lock()
+			if c.ws == ws {
+				c.ws = nil
+			}
+			c.mu.Unlock()
+			ws.Close()
+
+			if c.MaxReconnectInterval > 0 {
+				c.reconnect()
+			}
+			return
 		}
 
-		if len(data) <= 3 {
+		// 空的响应（如 ResCommon{}）只有 3 字节的消息头
+		if len(data) == 0 || data[0] != MessageTypeNotify && len(data) < 3 {
 			fmt.Fprintln(os.Stderr, "数据过短", data)
 			continue
 		}
 
-		if data[0] == MessageTypeResponse {
-			messageIndex := binary.LittleEndian.Uint16(data[1:3])
-			rawRespMessageChan, ok := c.respMessageChanMap.Load(messageIndex)
-			if !ok {
-				fmt.Fprintln(os.Stderr, "未找到消息", messageIndex)
-				continue
-			}
-			c.respMessageChanMap.Delete(messageIndex)
-
-			respMessageType := reflect.TypeOf(rawRespMessageChan).Elem().Elem()
-			respMessage := reflect.New(respMessageType)
-			if err := UnwrapMessage(data[3:], respMessage.Interface().(proto.Message)); err != nil {
-				fmt.Fprintln(os.Stderr, "UnwrapData:", err)
-				reflect.ValueOf(rawRespMessageChan).Close()
-				continue
-			}
-			reflect.ValueOf(rawRespMessageChan).Send(respMessage)
+		switch data[0] {
+		case MessageTypeNotify:
+			c.handleNotify(data[1:])
+		case MessageTypeResponse:
+			c.handleResponse(ws, binary.LittleEndian.Uint16(data[1:3]), data[3:])
 		}
 	}
 }
 
-func (c *WebSocketClient) Connect(endpoint string, origin string) error {
-	header := http.Header{}
-	header.Set("origin", origin) // 模拟来源
-	ws, _, err := websocket.DefaultDialer.Dial(endpoint, header)
+func (c *WebSocketClient) handleResponse(ws *websocket.Conn, messageIndex uint16, data []byte) {
+	c.mu.Lock()
+	call, ok := c.pendingCalls[messageIndex]
+	if ok && call.ws == ws {
+		delete(c.pendingCalls, messageIndex)
+	}
+	c.mu.Unlock()
+	if !ok || call.ws != ws {
+		// 已超时的请求
+		fmt.Fprintln(os.Stderr, "未找到消息", messageIndex)
+		return
+	}
+
+	if err := UnwrapMessage(data, call.resp); err != nil {
+		call.done <- fmt.Errorf("UnwrapData: %v", err)
+		return
+	}
+	call.done <- nil
+}
+
+func (c *WebSocketClient) handleNotify(data []byte) {
+	name, data, err := UnwrapData(data)
 	if err != nil {
-		return err
+		fmt.Fprintln(os.Stderr, "UnwrapData:", err)
+		return
 	}
-	c.ws = ws
+	name = strings.TrimPrefix(name, ".")
+	mt := proto.MessageType(name)
+	if mt == nil {
+		fmt.Fprintf(os.Stderr, "未找到 %s，请检查代码！\n", name)
+		return
+	}
+	message := reflect.New(mt.Elem()).Interface().(proto.Message)
+	if err := proto.Unmarshal(data, message); err != nil {
+		fmt.Fprintln(os.Stderr, "proto.Unmarshal:", name, err)
+		return
+	}
+	notify := &Notify{Name: strings.TrimPrefix(name, "lq."), Message: message}
 
-	go c.run()
-	go c.heartbeat()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.subscribers {
+		if s.name != "" && s.name != notify.Name {
+			continue
+		}
+		select {
+		case s.notifyChan <- notify:
+		default:
+			fmt.Fprintln(os.Stderr, "通知未及时处理，已丢弃", notify.Name)
+		}
+	}
+}
 
-	return nil
+// 让 ws 上所有等待响应的请求失败
+func (c *WebSocketClient) failPendingCalls(ws *websocket.Conn, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for index, call := range c.pendingCalls {
+		if call.ws == ws {
+			delete(c.pendingCalls, index)
+			call.done <- err
+		}
+	}
 }
 
-func (c *WebSocketClient) ConnectMajsoul() error {
-	endpoint, err := tool.GetMajsoulWebSocketURL()
-	if err != nil {
-		return err
+// 断线后以指数退避的间隔重连，重连成功后用 access token 重新登录
+func (c *WebSocketClient) reconnect() {
+	interval := minReconnectInterval
+	if interval > c.MaxReconnectInterval {
+		interval = c.MaxReconnectInterval
+	}
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-time.After(interval):
+		}
+
+		ws, err := c.dial()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "重连失败:", err)
+			if interval *= 2; interval > c.MaxReconnectInterval {
+				interval = c.MaxReconnectInterval
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		if c.isClosed() {
+			c.mu.Unlock()
+			ws.Close()
+			return
+		}
+		c.ws = ws
+		c.mu.Unlock()
+
+		go c.run(ws)
+
+		if err := c.reauth(); err != nil {
+			fmt.Fprintln(os.Stderr, "重新登录失败:", err)
+		}
+		return
 	}
-	return c.Connect(endpoint, tool.MajsoulOriginURL)
 }
 
-func (c *WebSocketClient) Close() error {
-	c.closed = true
-	return c.ws.Close()
+// 记录登录信息，用于断线重连后重新登录
+func (c *WebSocketClient) recordLogin(req proto.Message, resp proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch req := req.(type) {
+	case *lq.ReqLogin:
+		c.oauth2LoginConfig = &lq.ReqOauth2Login{
+			Device:            req.Device,
+			RandomKey:         req.RandomKey,
+			ClientVersion:     req.ClientVersion,
+			CurrencyPlatforms: req.CurrencyPlatforms,
+		}
+	case *lq.ReqOauth2Login:
+		c.oauth2LoginConfig = req
+		c.accessToken = req.AccessToken
+	case *lq.ReqLogout:
+		// 登出后不再自动登录
+		c.accessToken = ""
+		c.oauth2LoginConfig = nil
+		return
+	}
+	if resp, ok := resp.(*lq.ResLogin); ok && resp.AccessToken != "" {
+		c.accessToken = resp.AccessToken
+	}
 }
 
-func (c *WebSocketClient) send(name string, reqMessage proto.Message, respMessageChan interface{}) error {
-	// 避免并发时同时读写 c.messageIndex 等变量
-	c.Lock()
-	defer c.Unlock()
+func (c *WebSocketClient) reauth() error {
+	c.mu.Lock()
+	accessToken := c.accessToken
+	config := c.oauth2LoginConfig
+	c.mu.Unlock()
+	if accessToken == "" || config == nil {
+		return nil
+	}
+
+	req := *config
+	req.Type = 0
+	req.AccessToken = accessToken
+	req.Reconnect = true
+	_, err := c.Oauth2Login(context.Background(), &req)
+	return err
+}
+
+func (c *WebSocketClient) nextMessageIndex() (uint16, error) {
+	for i := 0; i < maxMessageIndex; i++ {
+		c.messageIndex = (c.messageIndex + 1) % maxMessageIndex
+		if _, ok := c.pendingCalls[c.messageIndex]; !ok {
+			return c.messageIndex, nil
+		}
+	}
+	return 0, ErrTooManyCalls
+}
+
+// 发送请求 name 并等待响应，响应会解析到 resp 中
+func (c *WebSocketClient) call(ctx context.Context, name string, req proto.Message, resp proto.Message) error {
+	if _, ok := ctx.Deadline(); !ok && c.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.CallTimeout)
+		defer cancel()
+	}
+
+	data, err := WrapMessage(name, req)
+	if err != nil {
+		return err
+	}
 
-	data, err := WrapMessage(name, reqMessage)
+	// 先登记再发送，避免响应先于登记到达
+	c.mu.Lock()
+	if c.isClosed() {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	ws := c.ws
+	if ws == nil {
+		c.mu.Unlock()
+		return ErrNotConnected
+	}
+	messageIndex, err := c.nextMessageIndex()
 	if err != nil {
+		c.mu.Unlock()
 		return err
 	}
+	call := &pendingCall{ws: ws, resp: resp, done: make(chan error, 1)}
+	c.pendingCalls[messageIndex] = call
+	c.mu.Unlock()
 
-	c.messageIndex = (c.messageIndex + 1) % 60007 // from code.js
+	removeCall := func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.pendingCalls[messageIndex] == call {
+			delete(c.pendingCalls, messageIndex)
+		}
+	}
 
-	messageIndexBytes := make([]byte, 2)
-	binary.LittleEndian.PutUint16(messageIndexBytes, c.messageIndex)
-	messageHead := append([]byte{MessageTypeRequest}, messageIndexBytes...)
-	if err := c.ws.WriteMessage(websocket.BinaryMessage, append(messageHead, data...)); err != nil {
+	messageHead := []byte{MessageTypeRequest, 0, 0}
+	binary.LittleEndian.PutUint16(messageHead[1:], messageIndex)
+	c.writeMu.Lock()
+	err = ws.WriteMessage(websocket.BinaryMessage, append(messageHead, data...))
+	c.writeMu.Unlock()
+	if err != nil {
+		removeCall()
 		return err
 	}
 
-	c.respMessageChanMap.Store(c.messageIndex, respMessageChan)
-	return nil
+	select {
+	case err := <-call.done:
+		if err == nil {
+			c.recordLogin(req, resp)
+		}
+		return err
+	case <-ctx.Done():
+		removeCall()
+		return fmt.Errorf("%s: %v", name, ctx.Err())
+	}
 }
 
-func (c *WebSocketClient) callFastTest(methodName string, reqMessage proto.Message, respMessageChan interface{}) error {
-	return c.send(".lq.FastTest."+methodName, reqMessage, respMessageChan)
+func (c *WebSocketClient) callFastTest(ctx context.Context, methodName string, reqMessage proto.Message, respMessage proto.Message) error {
+	return c.call(ctx, ".lq.FastTest."+methodName, reqMessage, respMessage)
 }
 
-func (c *WebSocketClient) callLobby(methodName string, reqMessage proto.Message, respMessageChan interface{}) error {
-	return c.send(".lq.Lobby."+methodName, reqMessage, respMessageChan)
+func (c *WebSocketClient) callLobby(ctx context.Context, methodName string, reqMessage proto.Message, respMessage proto.Message) error {
+	return c.call(ctx, ".lq.Lobby."+methodName, reqMessage, respMessage)
 }
 
 func (c *WebSocketClient) heartbeat() {
-	for !c.closed {
+	if c.HeartbeatInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		ws := c.ws
+		c.mu.Unlock()
+		if ws == nil {
+			// 正在重连
+			continue
+		}
+
 		// 吐槽：雀魂的开发把 heart 错写成了 heat
-		if _, err := c.Heatbeat(&lq.ReqHeatBeat{}); err != nil {
+		if _, err := c.Heatbeat(context.Background(), &lq.ReqHeatBeat{}); err != nil && !c.isClosed() {
+			// 连接可能已经失效，断开后由 run 重连
 			fmt.Fprintln(os.Stderr, "heartbeat:", err)
+			ws.Close()
 		}
-		time.Sleep(6 * time.Second)
 	}
 }
@@ -1,19 +1,16 @@
-// Code generated by tool/liqi_test.go. DO NOT EDIT.
+// Code generated by tool/liqi.go. DO NOT EDIT.
 package api
 
 import (
+	"context"
 	"fmt"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
 )
 
-func (c *WebSocketClient) AuthGame(req *lq.ReqAuthGame) (resp *lq.ResAuthGame, err error) {
-	respChan := make(chan *lq.ResAuthGame)
-	if err = c.send(".lq.FastTest.authGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) AuthGame(ctx context.Context, req *lq.ReqAuthGame) (resp *lq.ResAuthGame, err error) {
+	resp = &lq.ResAuthGame{}
+	if err = c.call(ctx, ".lq.FastTest.authGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -21,14 +18,10 @@ func (c *WebSocketClient) AuthGame(req *lq.ReqAuthGame) (resp *lq.ResAuthGame, e
 	return
 }
 
-func (c *WebSocketClient) BroadcastInGame(req *lq.ReqBroadcastInGame) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.broadcastInGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BroadcastInGame(ctx context.Context, req *lq.ReqBroadcastInGame) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.broadcastInGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -36,14 +29,10 @@ func (c *WebSocketClient) BroadcastInGame(req *lq.ReqBroadcastInGame) (resp *lq.
 	return
 }
 
-func (c *WebSocketClient) CheckNetworkDelay(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.checkNetworkDelay", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CheckNetworkDelay(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.checkNetworkDelay", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -51,14 +40,10 @@ func (c *WebSocketClient) CheckNetworkDelay(req *lq.ReqCommon) (resp *lq.ResComm
 	return
 }
 
-func (c *WebSocketClient) ConfirmNewRound(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.confirmNewRound", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ConfirmNewRound(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.confirmNewRound", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -66,14 +51,10 @@ func (c *WebSocketClient) ConfirmNewRound(req *lq.ReqCommon) (resp *lq.ResCommon
 	return
 }
 
-func (c *WebSocketClient) EnterGame(req *lq.ReqCommon) (resp *lq.ResEnterGame, err error) {
-	respChan := make(chan *lq.ResEnterGame)
-	if err = c.send(".lq.FastTest.enterGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) EnterGame(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResEnterGame, err error) {
+	resp = &lq.ResEnterGame{}
+	if err = c.call(ctx, ".lq.FastTest.enterGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -81,14 +62,10 @@ func (c *WebSocketClient) EnterGame(req *lq.ReqCommon) (resp *lq.ResEnterGame, e
 	return
 }
 
-func (c *WebSocketClient) FetchGamePlayerState(req *lq.ReqCommon) (resp *lq.ResGamePlayerState, err error) {
-	respChan := make(chan *lq.ResGamePlayerState)
-	if err = c.send(".lq.FastTest.fetchGamePlayerState", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGamePlayerState(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResGamePlayerState, err error) {
+	resp = &lq.ResGamePlayerState{}
+	if err = c.call(ctx, ".lq.FastTest.fetchGamePlayerState", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -96,14 +73,10 @@ func (c *WebSocketClient) FetchGamePlayerState(req *lq.ReqCommon) (resp *lq.ResG
 	return
 }
 
-func (c *WebSocketClient) FinishSyncGame(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.finishSyncGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FinishSyncGame(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.finishSyncGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -111,14 +84,10 @@ func (c *WebSocketClient) FinishSyncGame(req *lq.ReqCommon) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) InputChiPengGang(req *lq.ReqChiPengGang) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.inputChiPengGang", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) InputChiPengGang(ctx context.Context, req *lq.ReqChiPengGang) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.inputChiPengGang", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -126,14 +95,10 @@ func (c *WebSocketClient) InputChiPengGang(req *lq.ReqChiPengGang) (resp *lq.Res
 	return
 }
 
-func (c *WebSocketClient) InputGameGMCommand(req *lq.ReqGMCommandInGaming) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.inputGameGMCommand", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) InputGameGMCommand(ctx context.Context, req *lq.ReqGMCommandInGaming) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.inputGameGMCommand", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -141,14 +106,10 @@ func (c *WebSocketClient) InputGameGMCommand(req *lq.ReqGMCommandInGaming) (resp
 	return
 }
 
-func (c *WebSocketClient) InputOperation(req *lq.ReqSelfOperation) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.inputOperation", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) InputOperation(ctx context.Context, req *lq.ReqSelfOperation) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.inputOperation", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -156,14 +117,10 @@ func (c *WebSocketClient) InputOperation(req *lq.ReqSelfOperation) (resp *lq.Res
 	return
 }
 
-func (c *WebSocketClient) SyncGame(req *lq.ReqSyncGame) (resp *lq.ResSyncGame, err error) {
-	respChan := make(chan *lq.ResSyncGame)
-	if err = c.send(".lq.FastTest.syncGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SyncGame(ctx context.Context, req *lq.ReqSyncGame) (resp *lq.ResSyncGame, err error) {
+	resp = &lq.ResSyncGame{}
+	if err = c.call(ctx, ".lq.FastTest.syncGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -171,14 +128,10 @@ func (c *WebSocketClient) SyncGame(req *lq.ReqSyncGame) (resp *lq.ResSyncGame, e
 	return
 }
 
-func (c *WebSocketClient) TerminateGame(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.FastTest.terminateGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) TerminateGame(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.FastTest.terminateGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -186,14 +139,10 @@ func (c *WebSocketClient) TerminateGame(req *lq.ReqCommon) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) AddCollectedGameRecord(req *lq.ReqAddCollectedGameRecord) (resp *lq.ResAddCollectedGameRecord, err error) {
-	respChan := make(chan *lq.ResAddCollectedGameRecord)
-	if err = c.send(".lq.Lobby.addCollectedGameRecord", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) AddCollectedGameRecord(ctx context.Context, req *lq.ReqAddCollectedGameRecord) (resp *lq.ResAddCollectedGameRecord, err error) {
+	resp = &lq.ResAddCollectedGameRecord{}
+	if err = c.call(ctx, ".lq.Lobby.addCollectedGameRecord", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -201,14 +150,10 @@ func (c *WebSocketClient) AddCollectedGameRecord(req *lq.ReqAddCollectedGameReco
 	return
 }
 
-func (c *WebSocketClient) ApplyFriend(req *lq.ReqApplyFriend) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.applyFriend", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ApplyFriend(ctx context.Context, req *lq.ReqApplyFriend) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.applyFriend", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -216,14 +161,10 @@ func (c *WebSocketClient) ApplyFriend(req *lq.ReqApplyFriend) (resp *lq.ResCommo
 	return
 }
 
-func (c *WebSocketClient) BindAccount(req *lq.ReqBindAccount) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.bindAccount", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BindAccount(ctx context.Context, req *lq.ReqBindAccount) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.bindAccount", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -231,14 +172,10 @@ func (c *WebSocketClient) BindAccount(req *lq.ReqBindAccount) (resp *lq.ResCommo
 	return
 }
 
-func (c *WebSocketClient) BindEmail(req *lq.ReqBindEmail) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.bindEmail", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BindEmail(ctx context.Context, req *lq.ReqBindEmail) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.bindEmail", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -246,14 +183,10 @@ func (c *WebSocketClient) BindEmail(req *lq.ReqBindEmail) (resp *lq.ResCommon, e
 	return
 }
 
-func (c *WebSocketClient) BindPhoneNumber(req *lq.ReqBindPhoneNumber) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.bindPhoneNumber", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BindPhoneNumber(ctx context.Context, req *lq.ReqBindPhoneNumber) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.bindPhoneNumber", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -261,14 +194,10 @@ func (c *WebSocketClient) BindPhoneNumber(req *lq.ReqBindPhoneNumber) (resp *lq.
 	return
 }
 
-func (c *WebSocketClient) BuyFromChestShop(req *lq.ReqBuyFromChestShop) (resp *lq.ResBuyFromChestShop, err error) {
-	respChan := make(chan *lq.ResBuyFromChestShop)
-	if err = c.send(".lq.Lobby.buyFromChestShop", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BuyFromChestShop(ctx context.Context, req *lq.ReqBuyFromChestShop) (resp *lq.ResBuyFromChestShop, err error) {
+	resp = &lq.ResBuyFromChestShop{}
+	if err = c.call(ctx, ".lq.Lobby.buyFromChestShop", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -276,14 +205,10 @@ func (c *WebSocketClient) BuyFromChestShop(req *lq.ReqBuyFromChestShop) (resp *l
 	return
 }
 
-func (c *WebSocketClient) BuyFromShop(req *lq.ReqBuyFromShop) (resp *lq.ResBuyFromShop, err error) {
-	respChan := make(chan *lq.ResBuyFromShop)
-	if err = c.send(".lq.Lobby.buyFromShop", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BuyFromShop(ctx context.Context, req *lq.ReqBuyFromShop) (resp *lq.ResBuyFromShop, err error) {
+	resp = &lq.ResBuyFromShop{}
+	if err = c.call(ctx, ".lq.Lobby.buyFromShop", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -291,14 +216,10 @@ func (c *WebSocketClient) BuyFromShop(req *lq.ReqBuyFromShop) (resp *lq.ResBuyFr
 	return
 }
 
-func (c *WebSocketClient) BuyFromZHP(req *lq.ReqBuyFromZHP) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.buyFromZHP", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BuyFromZHP(ctx context.Context, req *lq.ReqBuyFromZHP) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.buyFromZHP", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -306,14 +227,10 @@ func (c *WebSocketClient) BuyFromZHP(req *lq.ReqBuyFromZHP) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) BuyShiLian(req *lq.ReqBuyShiLian) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.buyShiLian", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) BuyShiLian(ctx context.Context, req *lq.ReqBuyShiLian) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.buyShiLian", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -321,14 +238,10 @@ func (c *WebSocketClient) BuyShiLian(req *lq.ReqBuyShiLian) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) CancelGooglePlayOrder(req *lq.ReqCancelGooglePlayOrder) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.cancelGooglePlayOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CancelGooglePlayOrder(ctx context.Context, req *lq.ReqCancelGooglePlayOrder) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.cancelGooglePlayOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -336,14 +249,10 @@ func (c *WebSocketClient) CancelGooglePlayOrder(req *lq.ReqCancelGooglePlayOrder
 	return
 }
 
-func (c *WebSocketClient) CancelMatch(req *lq.ReqCancelMatchQueue) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.cancelMatch", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CancelMatch(ctx context.Context, req *lq.ReqCancelMatchQueue) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.cancelMatch", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -351,14 +260,10 @@ func (c *WebSocketClient) CancelMatch(req *lq.ReqCancelMatchQueue) (resp *lq.Res
 	return
 }
 
-func (c *WebSocketClient) ChangeAvatar(req *lq.ReqChangeAvatar) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.changeAvatar", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ChangeAvatar(ctx context.Context, req *lq.ReqChangeAvatar) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.changeAvatar", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -366,14 +271,10 @@ func (c *WebSocketClient) ChangeAvatar(req *lq.ReqChangeAvatar) (resp *lq.ResCom
 	return
 }
 
-func (c *WebSocketClient) ChangeCharacterSkin(req *lq.ReqChangeCharacterSkin) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.changeCharacterSkin", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ChangeCharacterSkin(ctx context.Context, req *lq.ReqChangeCharacterSkin) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.changeCharacterSkin", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -381,14 +282,10 @@ func (c *WebSocketClient) ChangeCharacterSkin(req *lq.ReqChangeCharacterSkin) (r
 	return
 }
 
-func (c *WebSocketClient) ChangeCharacterView(req *lq.ReqChangeCharacterView) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.changeCharacterView", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ChangeCharacterView(ctx context.Context, req *lq.ReqChangeCharacterView) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.changeCharacterView", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -396,14 +293,10 @@ func (c *WebSocketClient) ChangeCharacterView(req *lq.ReqChangeCharacterView) (r
 	return
 }
 
-func (c *WebSocketClient) ChangeCollectedGameRecordRemarks(req *lq.ReqChangeCollectedGameRecordRemarks) (resp *lq.ResChangeCollectedGameRecordRemarks, err error) {
-	respChan := make(chan *lq.ResChangeCollectedGameRecordRemarks)
-	if err = c.send(".lq.Lobby.changeCollectedGameRecordRemarks", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ChangeCollectedGameRecordRemarks(ctx context.Context, req *lq.ReqChangeCollectedGameRecordRemarks) (resp *lq.ResChangeCollectedGameRecordRemarks, err error) {
+	resp = &lq.ResChangeCollectedGameRecordRemarks{}
+	if err = c.call(ctx, ".lq.Lobby.changeCollectedGameRecordRemarks", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -411,14 +304,10 @@ func (c *WebSocketClient) ChangeCollectedGameRecordRemarks(req *lq.ReqChangeColl
 	return
 }
 
-func (c *WebSocketClient) ChangeCommonView(req *lq.ReqChangeCommonView) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.changeCommonView", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ChangeCommonView(ctx context.Context, req *lq.ReqChangeCommonView) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.changeCommonView", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -426,14 +315,10 @@ func (c *WebSocketClient) ChangeCommonView(req *lq.ReqChangeCommonView) (resp *l
 	return
 }
 
-func (c *WebSocketClient) ChangeMainCharacter(req *lq.ReqChangeMainCharacter) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.changeMainCharacter", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ChangeMainCharacter(ctx context.Context, req *lq.ReqChangeMainCharacter) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.changeMainCharacter", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -441,14 +326,10 @@ func (c *WebSocketClient) ChangeMainCharacter(req *lq.ReqChangeMainCharacter) (r
 	return
 }
 
-func (c *WebSocketClient) ClientMessage(req *lq.ReqClientMessage) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.clientMessage", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ClientMessage(ctx context.Context, req *lq.ReqClientMessage) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.clientMessage", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -456,14 +337,10 @@ func (c *WebSocketClient) ClientMessage(req *lq.ReqClientMessage) (resp *lq.ResC
 	return
 }
 
-func (c *WebSocketClient) CompleteActivityFlipTask(req *lq.ReqCompleteActivityTask) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.completeActivityFlipTask", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CompleteActivityFlipTask(ctx context.Context, req *lq.ReqCompleteActivityTask) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.completeActivityFlipTask", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -471,14 +348,10 @@ func (c *WebSocketClient) CompleteActivityFlipTask(req *lq.ReqCompleteActivityTa
 	return
 }
 
-func (c *WebSocketClient) CompleteActivityTask(req *lq.ReqCompleteActivityTask) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.completeActivityTask", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CompleteActivityTask(ctx context.Context, req *lq.ReqCompleteActivityTask) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.completeActivityTask", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -486,14 +359,10 @@ func (c *WebSocketClient) CompleteActivityTask(req *lq.ReqCompleteActivityTask)
 	return
 }
 
-func (c *WebSocketClient) ComposeShard(req *lq.ReqComposeShard) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.composeShard", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ComposeShard(ctx context.Context, req *lq.ReqComposeShard) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.composeShard", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -501,14 +370,10 @@ func (c *WebSocketClient) ComposeShard(req *lq.ReqComposeShard) (resp *lq.ResCom
 	return
 }
 
-func (c *WebSocketClient) CreateAlipayAppOrder(req *lq.ReqCreateAlipayAppOrder) (resp *lq.ResCreateAlipayAppOrder, err error) {
-	respChan := make(chan *lq.ResCreateAlipayAppOrder)
-	if err = c.send(".lq.Lobby.createAlipayAppOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateAlipayAppOrder(ctx context.Context, req *lq.ReqCreateAlipayAppOrder) (resp *lq.ResCreateAlipayAppOrder, err error) {
+	resp = &lq.ResCreateAlipayAppOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createAlipayAppOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -516,14 +381,10 @@ func (c *WebSocketClient) CreateAlipayAppOrder(req *lq.ReqCreateAlipayAppOrder)
 	return
 }
 
-func (c *WebSocketClient) CreateAlipayOrder(req *lq.ReqCreateAlipayOrder) (resp *lq.ResCreateAlipayOrder, err error) {
-	respChan := make(chan *lq.ResCreateAlipayOrder)
-	if err = c.send(".lq.Lobby.createAlipayOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateAlipayOrder(ctx context.Context, req *lq.ReqCreateAlipayOrder) (resp *lq.ResCreateAlipayOrder, err error) {
+	resp = &lq.ResCreateAlipayOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createAlipayOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -531,14 +392,10 @@ func (c *WebSocketClient) CreateAlipayOrder(req *lq.ReqCreateAlipayOrder) (resp
 	return
 }
 
-func (c *WebSocketClient) CreateAlipayScanOrder(req *lq.ReqCreateAlipayScanOrder) (resp *lq.ResCreateAlipayScanOrder, err error) {
-	respChan := make(chan *lq.ResCreateAlipayScanOrder)
-	if err = c.send(".lq.Lobby.createAlipayScanOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateAlipayScanOrder(ctx context.Context, req *lq.ReqCreateAlipayScanOrder) (resp *lq.ResCreateAlipayScanOrder, err error) {
+	resp = &lq.ResCreateAlipayScanOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createAlipayScanOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -546,14 +403,10 @@ func (c *WebSocketClient) CreateAlipayScanOrder(req *lq.ReqCreateAlipayScanOrder
 	return
 }
 
-func (c *WebSocketClient) CreateBillingOrder(req *lq.ReqCreateBillingOrder) (resp *lq.ResCreateBillingOrder, err error) {
-	respChan := make(chan *lq.ResCreateBillingOrder)
-	if err = c.send(".lq.Lobby.createBillingOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateBillingOrder(ctx context.Context, req *lq.ReqCreateBillingOrder) (resp *lq.ResCreateBillingOrder, err error) {
+	resp = &lq.ResCreateBillingOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createBillingOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -561,14 +414,10 @@ func (c *WebSocketClient) CreateBillingOrder(req *lq.ReqCreateBillingOrder) (res
 	return
 }
 
-func (c *WebSocketClient) CreateENAlipayOrder(req *lq.ReqCreateENAlipayOrder) (resp *lq.ResCreateENAlipayOrder, err error) {
-	respChan := make(chan *lq.ResCreateENAlipayOrder)
-	if err = c.send(".lq.Lobby.createENAlipayOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateENAlipayOrder(ctx context.Context, req *lq.ReqCreateENAlipayOrder) (resp *lq.ResCreateENAlipayOrder, err error) {
+	resp = &lq.ResCreateENAlipayOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createENAlipayOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -576,14 +425,10 @@ func (c *WebSocketClient) CreateENAlipayOrder(req *lq.ReqCreateENAlipayOrder) (r
 	return
 }
 
-func (c *WebSocketClient) CreateENJCBOrder(req *lq.ReqCreateENJCBOrder) (resp *lq.ResCreateENJCBOrder, err error) {
-	respChan := make(chan *lq.ResCreateENJCBOrder)
-	if err = c.send(".lq.Lobby.createENJCBOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateENJCBOrder(ctx context.Context, req *lq.ReqCreateENJCBOrder) (resp *lq.ResCreateENJCBOrder, err error) {
+	resp = &lq.ResCreateENJCBOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createENJCBOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -591,14 +436,10 @@ func (c *WebSocketClient) CreateENJCBOrder(req *lq.ReqCreateENJCBOrder) (resp *l
 	return
 }
 
-func (c *WebSocketClient) CreateENMasterCardOrder(req *lq.ReqCreateENMasterCardOrder) (resp *lq.ResCreateENMasterCardOrder, err error) {
-	respChan := make(chan *lq.ResCreateENMasterCardOrder)
-	if err = c.send(".lq.Lobby.createENMasterCardOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateENMasterCardOrder(ctx context.Context, req *lq.ReqCreateENMasterCardOrder) (resp *lq.ResCreateENMasterCardOrder, err error) {
+	resp = &lq.ResCreateENMasterCardOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createENMasterCardOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -606,14 +447,10 @@ func (c *WebSocketClient) CreateENMasterCardOrder(req *lq.ReqCreateENMasterCardO
 	return
 }
 
-func (c *WebSocketClient) CreateENPaypalOrder(req *lq.ReqCreateENPaypalOrder) (resp *lq.ResCreateENPaypalOrder, err error) {
-	respChan := make(chan *lq.ResCreateENPaypalOrder)
-	if err = c.send(".lq.Lobby.createENPaypalOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateENPaypalOrder(ctx context.Context, req *lq.ReqCreateENPaypalOrder) (resp *lq.ResCreateENPaypalOrder, err error) {
+	resp = &lq.ResCreateENPaypalOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createENPaypalOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -621,14 +458,10 @@ func (c *WebSocketClient) CreateENPaypalOrder(req *lq.ReqCreateENPaypalOrder) (r
 	return
 }
 
-func (c *WebSocketClient) CreateENVisaOrder(req *lq.ReqCreateENVisaOrder) (resp *lq.ResCreateENVisaOrder, err error) {
-	respChan := make(chan *lq.ResCreateENVisaOrder)
-	if err = c.send(".lq.Lobby.createENVisaOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateENVisaOrder(ctx context.Context, req *lq.ReqCreateENVisaOrder) (resp *lq.ResCreateENVisaOrder, err error) {
+	resp = &lq.ResCreateENVisaOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createENVisaOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -636,14 +469,10 @@ func (c *WebSocketClient) CreateENVisaOrder(req *lq.ReqCreateENVisaOrder) (resp
 	return
 }
 
-func (c *WebSocketClient) CreateEmailVerifyCode(req *lq.ReqCreateEmailVerifyCode) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.createEmailVerifyCode", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateEmailVerifyCode(ctx context.Context, req *lq.ReqCreateEmailVerifyCode) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.createEmailVerifyCode", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -651,14 +480,10 @@ func (c *WebSocketClient) CreateEmailVerifyCode(req *lq.ReqCreateEmailVerifyCode
 	return
 }
 
-func (c *WebSocketClient) CreateJPAuOrder(req *lq.ReqCreateJPAuOrder) (resp *lq.ResCreateJPAuOrder, err error) {
-	respChan := make(chan *lq.ResCreateJPAuOrder)
-	if err = c.send(".lq.Lobby.createJPAuOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateJPAuOrder(ctx context.Context, req *lq.ReqCreateJPAuOrder) (resp *lq.ResCreateJPAuOrder, err error) {
+	resp = &lq.ResCreateJPAuOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createJPAuOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -666,14 +491,10 @@ func (c *WebSocketClient) CreateJPAuOrder(req *lq.ReqCreateJPAuOrder) (resp *lq.
 	return
 }
 
-func (c *WebSocketClient) CreateJPCreditCardOrder(req *lq.ReqCreateJPCreditCardOrder) (resp *lq.ResCreateJPCreditCardOrder, err error) {
-	respChan := make(chan *lq.ResCreateJPCreditCardOrder)
-	if err = c.send(".lq.Lobby.createJPCreditCardOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateJPCreditCardOrder(ctx context.Context, req *lq.ReqCreateJPCreditCardOrder) (resp *lq.ResCreateJPCreditCardOrder, err error) {
+	resp = &lq.ResCreateJPCreditCardOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createJPCreditCardOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -681,14 +502,10 @@ func (c *WebSocketClient) CreateJPCreditCardOrder(req *lq.ReqCreateJPCreditCardO
 	return
 }
 
-func (c *WebSocketClient) CreateJPDocomoOrder(req *lq.ReqCreateJPDocomoOrder) (resp *lq.ResCreateJPDocomoOrder, err error) {
-	respChan := make(chan *lq.ResCreateJPDocomoOrder)
-	if err = c.send(".lq.Lobby.createJPDocomoOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateJPDocomoOrder(ctx context.Context, req *lq.ReqCreateJPDocomoOrder) (resp *lq.ResCreateJPDocomoOrder, err error) {
+	resp = &lq.ResCreateJPDocomoOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createJPDocomoOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -696,14 +513,10 @@ func (c *WebSocketClient) CreateJPDocomoOrder(req *lq.ReqCreateJPDocomoOrder) (r
 	return
 }
 
-func (c *WebSocketClient) CreateJPPaypalOrder(req *lq.ReqCreateJPPaypalOrder) (resp *lq.ResCreateJPPaypalOrder, err error) {
-	respChan := make(chan *lq.ResCreateJPPaypalOrder)
-	if err = c.send(".lq.Lobby.createJPPaypalOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateJPPaypalOrder(ctx context.Context, req *lq.ReqCreateJPPaypalOrder) (resp *lq.ResCreateJPPaypalOrder, err error) {
+	resp = &lq.ResCreateJPPaypalOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createJPPaypalOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -711,14 +524,10 @@ func (c *WebSocketClient) CreateJPPaypalOrder(req *lq.ReqCreateJPPaypalOrder) (r
 	return
 }
 
-func (c *WebSocketClient) CreateJPSoftbankOrder(req *lq.ReqCreateJPSoftbankOrder) (resp *lq.ResCreateJPSoftbankOrder, err error) {
-	respChan := make(chan *lq.ResCreateJPSoftbankOrder)
-	if err = c.send(".lq.Lobby.createJPSoftbankOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateJPSoftbankOrder(ctx context.Context, req *lq.ReqCreateJPSoftbankOrder) (resp *lq.ResCreateJPSoftbankOrder, err error) {
+	resp = &lq.ResCreateJPSoftbankOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createJPSoftbankOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -726,14 +535,10 @@ func (c *WebSocketClient) CreateJPSoftbankOrder(req *lq.ReqCreateJPSoftbankOrder
 	return
 }
 
-func (c *WebSocketClient) CreateJPWebMoneyOrder(req *lq.ReqCreateJPWebMoneyOrder) (resp *lq.ResCreateJPWebMoneyOrder, err error) {
-	respChan := make(chan *lq.ResCreateJPWebMoneyOrder)
-	if err = c.send(".lq.Lobby.createJPWebMoneyOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateJPWebMoneyOrder(ctx context.Context, req *lq.ReqCreateJPWebMoneyOrder) (resp *lq.ResCreateJPWebMoneyOrder, err error) {
+	resp = &lq.ResCreateJPWebMoneyOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createJPWebMoneyOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -741,14 +546,10 @@ func (c *WebSocketClient) CreateJPWebMoneyOrder(req *lq.ReqCreateJPWebMoneyOrder
 	return
 }
 
-func (c *WebSocketClient) CreateNickname(req *lq.ReqCreateNickname) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.createNickname", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateNickname(ctx context.Context, req *lq.ReqCreateNickname) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.createNickname", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -756,14 +557,10 @@ func (c *WebSocketClient) CreateNickname(req *lq.ReqCreateNickname) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) CreatePhoneVerifyCode(req *lq.ReqCreatePhoneVerifyCode) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.createPhoneVerifyCode", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreatePhoneVerifyCode(ctx context.Context, req *lq.ReqCreatePhoneVerifyCode) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.createPhoneVerifyCode", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -771,14 +568,10 @@ func (c *WebSocketClient) CreatePhoneVerifyCode(req *lq.ReqCreatePhoneVerifyCode
 	return
 }
 
-func (c *WebSocketClient) CreateRoom(req *lq.ReqCreateRoom) (resp *lq.ResCreateRoom, err error) {
-	respChan := make(chan *lq.ResCreateRoom)
-	if err = c.send(".lq.Lobby.createRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateRoom(ctx context.Context, req *lq.ReqCreateRoom) (resp *lq.ResCreateRoom, err error) {
+	resp = &lq.ResCreateRoom{}
+	if err = c.call(ctx, ".lq.Lobby.createRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -786,14 +579,10 @@ func (c *WebSocketClient) CreateRoom(req *lq.ReqCreateRoom) (resp *lq.ResCreateR
 	return
 }
 
-func (c *WebSocketClient) CreateWechatAppOrder(req *lq.ReqCreateWechatAppOrder) (resp *lq.ResCreateWechatAppOrder, err error) {
-	respChan := make(chan *lq.ResCreateWechatAppOrder)
-	if err = c.send(".lq.Lobby.createWechatAppOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateWechatAppOrder(ctx context.Context, req *lq.ReqCreateWechatAppOrder) (resp *lq.ResCreateWechatAppOrder, err error) {
+	resp = &lq.ResCreateWechatAppOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createWechatAppOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -801,14 +590,10 @@ func (c *WebSocketClient) CreateWechatAppOrder(req *lq.ReqCreateWechatAppOrder)
 	return
 }
 
-func (c *WebSocketClient) CreateWechatNativeOrder(req *lq.ReqCreateWechatNativeOrder) (resp *lq.ResCreateWechatNativeOrder, err error) {
-	respChan := make(chan *lq.ResCreateWechatNativeOrder)
-	if err = c.send(".lq.Lobby.createWechatNativeOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) CreateWechatNativeOrder(ctx context.Context, req *lq.ReqCreateWechatNativeOrder) (resp *lq.ResCreateWechatNativeOrder, err error) {
+	resp = &lq.ResCreateWechatNativeOrder{}
+	if err = c.call(ctx, ".lq.Lobby.createWechatNativeOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -816,14 +601,10 @@ func (c *WebSocketClient) CreateWechatNativeOrder(req *lq.ReqCreateWechatNativeO
 	return
 }
 
-func (c *WebSocketClient) DeleteComment(req *lq.ReqDeleteComment) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.deleteComment", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) DeleteComment(ctx context.Context, req *lq.ReqDeleteComment) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.deleteComment", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -831,14 +612,10 @@ func (c *WebSocketClient) DeleteComment(req *lq.ReqDeleteComment) (resp *lq.ResC
 	return
 }
 
-func (c *WebSocketClient) DeleteMail(req *lq.ReqDeleteMail) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.deleteMail", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) DeleteMail(ctx context.Context, req *lq.ReqDeleteMail) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.deleteMail", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -846,14 +623,10 @@ func (c *WebSocketClient) DeleteMail(req *lq.ReqDeleteMail) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) DoActivitySignIn(req *lq.ReqDoActivitySignIn) (resp *lq.ResDoActivitySignIn, err error) {
-	respChan := make(chan *lq.ResDoActivitySignIn)
-	if err = c.send(".lq.Lobby.doActivitySignIn", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) DoActivitySignIn(ctx context.Context, req *lq.ReqDoActivitySignIn) (resp *lq.ResDoActivitySignIn, err error) {
+	resp = &lq.ResDoActivitySignIn{}
+	if err = c.call(ctx, ".lq.Lobby.doActivitySignIn", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -861,14 +634,10 @@ func (c *WebSocketClient) DoActivitySignIn(req *lq.ReqDoActivitySignIn) (resp *l
 	return
 }
 
-func (c *WebSocketClient) DoDailySignIn(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.doDailySignIn", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) DoDailySignIn(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.doDailySignIn", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -876,14 +645,10 @@ func (c *WebSocketClient) DoDailySignIn(req *lq.ReqCommon) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) EmailLogin(req *lq.ReqEmailLogin) (resp *lq.ResLogin, err error) {
-	respChan := make(chan *lq.ResLogin)
-	if err = c.send(".lq.Lobby.emailLogin", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) EmailLogin(ctx context.Context, req *lq.ReqEmailLogin) (resp *lq.ResLogin, err error) {
+	resp = &lq.ResLogin{}
+	if err = c.call(ctx, ".lq.Lobby.emailLogin", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -891,14 +656,10 @@ func (c *WebSocketClient) EmailLogin(req *lq.ReqEmailLogin) (resp *lq.ResLogin,
 	return
 }
 
-func (c *WebSocketClient) EnterCustomizedContest(req *lq.ReqEnterCustomizedContest) (resp *lq.ResEnterCustomizedContest, err error) {
-	respChan := make(chan *lq.ResEnterCustomizedContest)
-	if err = c.send(".lq.Lobby.enterCustomizedContest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) EnterCustomizedContest(ctx context.Context, req *lq.ReqEnterCustomizedContest) (resp *lq.ResEnterCustomizedContest, err error) {
+	resp = &lq.ResEnterCustomizedContest{}
+	if err = c.call(ctx, ".lq.Lobby.enterCustomizedContest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -906,14 +667,10 @@ func (c *WebSocketClient) EnterCustomizedContest(req *lq.ReqEnterCustomizedConte
 	return
 }
 
-func (c *WebSocketClient) ExchangeActivityItem(req *lq.ReqExchangeActivityItem) (resp *lq.ResExchangeActivityItem, err error) {
-	respChan := make(chan *lq.ResExchangeActivityItem)
-	if err = c.send(".lq.Lobby.exchangeActivityItem", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ExchangeActivityItem(ctx context.Context, req *lq.ReqExchangeActivityItem) (resp *lq.ResExchangeActivityItem, err error) {
+	resp = &lq.ResExchangeActivityItem{}
+	if err = c.call(ctx, ".lq.Lobby.exchangeActivityItem", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -921,14 +678,10 @@ func (c *WebSocketClient) ExchangeActivityItem(req *lq.ReqExchangeActivityItem)
 	return
 }
 
-func (c *WebSocketClient) ExchangeChestStone(req *lq.ReqExchangeCurrency) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.exchangeChestStone", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ExchangeChestStone(ctx context.Context, req *lq.ReqExchangeCurrency) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.exchangeChestStone", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -936,14 +689,10 @@ func (c *WebSocketClient) ExchangeChestStone(req *lq.ReqExchangeCurrency) (resp
 	return
 }
 
-func (c *WebSocketClient) ExchangeCurrency(req *lq.ReqExchangeCurrency) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.exchangeCurrency", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ExchangeCurrency(ctx context.Context, req *lq.ReqExchangeCurrency) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.exchangeCurrency", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -951,14 +700,10 @@ func (c *WebSocketClient) ExchangeCurrency(req *lq.ReqExchangeCurrency) (resp *l
 	return
 }
 
-func (c *WebSocketClient) FetchAccountActivityData(req *lq.ReqCommon) (resp *lq.ResAccountActivityData, err error) {
-	respChan := make(chan *lq.ResAccountActivityData)
-	if err = c.send(".lq.Lobby.fetchAccountActivityData", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAccountActivityData(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResAccountActivityData, err error) {
+	resp = &lq.ResAccountActivityData{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAccountActivityData", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -966,26 +711,18 @@ func (c *WebSocketClient) FetchAccountActivityData(req *lq.ReqCommon) (resp *lq.
 	return
 }
 
-func (c *WebSocketClient) FetchAccountCharacterInfo(req *lq.ReqCommon) (resp *lq.ResAccountCharacterInfo, err error) {
-	respChan := make(chan *lq.ResAccountCharacterInfo)
-	if err = c.send(".lq.Lobby.fetchAccountCharacterInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAccountCharacterInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResAccountCharacterInfo, err error) {
+	resp = &lq.ResAccountCharacterInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAccountCharacterInfo", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchAccountInfo(req *lq.ReqAccountInfo) (resp *lq.ResAccountInfo, err error) {
-	respChan := make(chan *lq.ResAccountInfo)
-	if err = c.send(".lq.Lobby.fetchAccountInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAccountInfo(ctx context.Context, req *lq.ReqAccountInfo) (resp *lq.ResAccountInfo, err error) {
+	resp = &lq.ResAccountInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAccountInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -993,14 +730,10 @@ func (c *WebSocketClient) FetchAccountInfo(req *lq.ReqAccountInfo) (resp *lq.Res
 	return
 }
 
-func (c *WebSocketClient) FetchAccountSettings(req *lq.ReqCommon) (resp *lq.ResAccountSettings, err error) {
-	respChan := make(chan *lq.ResAccountSettings)
-	if err = c.send(".lq.Lobby.fetchAccountSettings", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAccountSettings(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResAccountSettings, err error) {
+	resp = &lq.ResAccountSettings{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAccountSettings", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1008,14 +741,10 @@ func (c *WebSocketClient) FetchAccountSettings(req *lq.ReqCommon) (resp *lq.ResA
 	return
 }
 
-func (c *WebSocketClient) FetchAccountState(req *lq.ReqAccountList) (resp *lq.ResAccountStates, err error) {
-	respChan := make(chan *lq.ResAccountStates)
-	if err = c.send(".lq.Lobby.fetchAccountState", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAccountState(ctx context.Context, req *lq.ReqAccountList) (resp *lq.ResAccountStates, err error) {
+	resp = &lq.ResAccountStates{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAccountState", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1023,14 +752,10 @@ func (c *WebSocketClient) FetchAccountState(req *lq.ReqAccountList) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) FetchAccountStatisticInfo(req *lq.ReqAccountStatisticInfo) (resp *lq.ResAccountStatisticInfo, err error) {
-	respChan := make(chan *lq.ResAccountStatisticInfo)
-	if err = c.send(".lq.Lobby.fetchAccountStatisticInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAccountStatisticInfo(ctx context.Context, req *lq.ReqAccountStatisticInfo) (resp *lq.ResAccountStatisticInfo, err error) {
+	resp = &lq.ResAccountStatisticInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAccountStatisticInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1038,14 +763,10 @@ func (c *WebSocketClient) FetchAccountStatisticInfo(req *lq.ReqAccountStatisticI
 	return
 }
 
-func (c *WebSocketClient) FetchAchievement(req *lq.ReqCommon) (resp *lq.ResAchievement, err error) {
-	respChan := make(chan *lq.ResAchievement)
-	if err = c.send(".lq.Lobby.fetchAchievement", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAchievement(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResAchievement, err error) {
+	resp = &lq.ResAchievement{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAchievement", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1053,26 +774,18 @@ func (c *WebSocketClient) FetchAchievement(req *lq.ReqCommon) (resp *lq.ResAchie
 	return
 }
 
-func (c *WebSocketClient) FetchActivityFlipInfo(req *lq.ReqFetchActivityFlipInfo) (resp *lq.ResFetchActivityFlipInfo, err error) {
-	respChan := make(chan *lq.ResFetchActivityFlipInfo)
-	if err = c.send(".lq.Lobby.fetchActivityFlipInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchActivityFlipInfo(ctx context.Context, req *lq.ReqFetchActivityFlipInfo) (resp *lq.ResFetchActivityFlipInfo, err error) {
+	resp = &lq.ResFetchActivityFlipInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchActivityFlipInfo", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchActivityList(req *lq.ReqCommon) (resp *lq.ResActivityList, err error) {
-	respChan := make(chan *lq.ResActivityList)
-	if err = c.send(".lq.Lobby.fetchActivityList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchActivityList(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResActivityList, err error) {
+	resp = &lq.ResActivityList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchActivityList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1080,14 +793,10 @@ func (c *WebSocketClient) FetchActivityList(req *lq.ReqCommon) (resp *lq.ResActi
 	return
 }
 
-func (c *WebSocketClient) FetchAnnouncement(req *lq.ReqCommon) (resp *lq.ResAnnouncement, err error) {
-	respChan := make(chan *lq.ResAnnouncement)
-	if err = c.send(".lq.Lobby.fetchAnnouncement", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchAnnouncement(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResAnnouncement, err error) {
+	resp = &lq.ResAnnouncement{}
+	if err = c.call(ctx, ".lq.Lobby.fetchAnnouncement", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1095,14 +804,10 @@ func (c *WebSocketClient) FetchAnnouncement(req *lq.ReqCommon) (resp *lq.ResAnno
 	return
 }
 
-func (c *WebSocketClient) FetchBagInfo(req *lq.ReqCommon) (resp *lq.ResBagInfo, err error) {
-	respChan := make(chan *lq.ResBagInfo)
-	if err = c.send(".lq.Lobby.fetchBagInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchBagInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResBagInfo, err error) {
+	resp = &lq.ResBagInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchBagInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1110,14 +815,10 @@ func (c *WebSocketClient) FetchBagInfo(req *lq.ReqCommon) (resp *lq.ResBagInfo,
 	return
 }
 
-func (c *WebSocketClient) FetchCharacterInfo(req *lq.ReqCommon) (resp *lq.ResCharacterInfo, err error) {
-	respChan := make(chan *lq.ResCharacterInfo)
-	if err = c.send(".lq.Lobby.fetchCharacterInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCharacterInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCharacterInfo, err error) {
+	resp = &lq.ResCharacterInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCharacterInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1125,26 +826,18 @@ func (c *WebSocketClient) FetchCharacterInfo(req *lq.ReqCommon) (resp *lq.ResCha
 	return
 }
 
-func (c *WebSocketClient) FetchClientValue(req *lq.ReqCommon) (resp *lq.ResClientValue, err error) {
-	respChan := make(chan *lq.ResClientValue)
-	if err = c.send(".lq.Lobby.fetchClientValue", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchClientValue(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResClientValue, err error) {
+	resp = &lq.ResClientValue{}
+	if err = c.call(ctx, ".lq.Lobby.fetchClientValue", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchCollectedGameRecordList(req *lq.ReqCommon) (resp *lq.ResCollectedGameRecordList, err error) {
-	respChan := make(chan *lq.ResCollectedGameRecordList)
-	if err = c.send(".lq.Lobby.fetchCollectedGameRecordList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCollectedGameRecordList(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCollectedGameRecordList, err error) {
+	resp = &lq.ResCollectedGameRecordList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCollectedGameRecordList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1152,14 +845,10 @@ func (c *WebSocketClient) FetchCollectedGameRecordList(req *lq.ReqCommon) (resp
 	return
 }
 
-func (c *WebSocketClient) FetchCommentContent(req *lq.ReqFetchCommentContent) (resp *lq.ResFetchCommentContent, err error) {
-	respChan := make(chan *lq.ResFetchCommentContent)
-	if err = c.send(".lq.Lobby.fetchCommentContent", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCommentContent(ctx context.Context, req *lq.ReqFetchCommentContent) (resp *lq.ResFetchCommentContent, err error) {
+	resp = &lq.ResFetchCommentContent{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCommentContent", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1167,14 +856,10 @@ func (c *WebSocketClient) FetchCommentContent(req *lq.ReqFetchCommentContent) (r
 	return
 }
 
-func (c *WebSocketClient) FetchCommentList(req *lq.ReqFetchCommentList) (resp *lq.ResFetchCommentList, err error) {
-	respChan := make(chan *lq.ResFetchCommentList)
-	if err = c.send(".lq.Lobby.fetchCommentList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCommentList(ctx context.Context, req *lq.ReqFetchCommentList) (resp *lq.ResFetchCommentList, err error) {
+	resp = &lq.ResFetchCommentList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCommentList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1182,14 +867,10 @@ func (c *WebSocketClient) FetchCommentList(req *lq.ReqFetchCommentList) (resp *l
 	return
 }
 
-func (c *WebSocketClient) FetchCommentSetting(req *lq.ReqCommon) (resp *lq.ResCommentSetting, err error) {
-	respChan := make(chan *lq.ResCommentSetting)
-	if err = c.send(".lq.Lobby.fetchCommentSetting", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCommentSetting(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommentSetting, err error) {
+	resp = &lq.ResCommentSetting{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCommentSetting", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1197,14 +878,10 @@ func (c *WebSocketClient) FetchCommentSetting(req *lq.ReqCommon) (resp *lq.ResCo
 	return
 }
 
-func (c *WebSocketClient) FetchCommonView(req *lq.ReqCommon) (resp *lq.ResCommonView, err error) {
-	respChan := make(chan *lq.ResCommonView)
-	if err = c.send(".lq.Lobby.fetchCommonView", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCommonView(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommonView, err error) {
+	resp = &lq.ResCommonView{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCommonView", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1212,14 +889,10 @@ func (c *WebSocketClient) FetchCommonView(req *lq.ReqCommon) (resp *lq.ResCommon
 	return
 }
 
-func (c *WebSocketClient) FetchConnectionInfo(req *lq.ReqCommon) (resp *lq.ResConnectionInfo, err error) {
-	respChan := make(chan *lq.ResConnectionInfo)
-	if err = c.send(".lq.Lobby.fetchConnectionInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchConnectionInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResConnectionInfo, err error) {
+	resp = &lq.ResConnectionInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchConnectionInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1227,14 +900,10 @@ func (c *WebSocketClient) FetchConnectionInfo(req *lq.ReqCommon) (resp *lq.ResCo
 	return
 }
 
-func (c *WebSocketClient) FetchCurrentMatchInfo(req *lq.ReqCurrentMatchInfo) (resp *lq.ResCurrentMatchInfo, err error) {
-	respChan := make(chan *lq.ResCurrentMatchInfo)
-	if err = c.send(".lq.Lobby.fetchCurrentMatchInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCurrentMatchInfo(ctx context.Context, req *lq.ReqCurrentMatchInfo) (resp *lq.ResCurrentMatchInfo, err error) {
+	resp = &lq.ResCurrentMatchInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCurrentMatchInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1242,14 +911,10 @@ func (c *WebSocketClient) FetchCurrentMatchInfo(req *lq.ReqCurrentMatchInfo) (re
 	return
 }
 
-func (c *WebSocketClient) FetchCustomizedContestByContestId(req *lq.ReqFetchCustomizedContestByContestId) (resp *lq.ResFetchCustomizedContestByContestId, err error) {
-	respChan := make(chan *lq.ResFetchCustomizedContestByContestId)
-	if err = c.send(".lq.Lobby.fetchCustomizedContestByContestId", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCustomizedContestByContestId(ctx context.Context, req *lq.ReqFetchCustomizedContestByContestId) (resp *lq.ResFetchCustomizedContestByContestId, err error) {
+	resp = &lq.ResFetchCustomizedContestByContestId{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCustomizedContestByContestId", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1257,14 +922,10 @@ func (c *WebSocketClient) FetchCustomizedContestByContestId(req *lq.ReqFetchCust
 	return
 }
 
-func (c *WebSocketClient) FetchCustomizedContestExtendInfo(req *lq.ReqFetchCustomizedContestExtendInfo) (resp *lq.ResFetchCustomizedContestExtendInfo, err error) {
-	respChan := make(chan *lq.ResFetchCustomizedContestExtendInfo)
-	if err = c.send(".lq.Lobby.fetchCustomizedContestExtendInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCustomizedContestExtendInfo(ctx context.Context, req *lq.ReqFetchCustomizedContestExtendInfo) (resp *lq.ResFetchCustomizedContestExtendInfo, err error) {
+	resp = &lq.ResFetchCustomizedContestExtendInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCustomizedContestExtendInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1272,14 +933,10 @@ func (c *WebSocketClient) FetchCustomizedContestExtendInfo(req *lq.ReqFetchCusto
 	return
 }
 
-func (c *WebSocketClient) FetchCustomizedContestGameLiveList(req *lq.ReqFetchCustomizedContestGameLiveList) (resp *lq.ResFetchCustomizedContestGameLiveList, err error) {
-	respChan := make(chan *lq.ResFetchCustomizedContestGameLiveList)
-	if err = c.send(".lq.Lobby.fetchCustomizedContestGameLiveList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCustomizedContestGameLiveList(ctx context.Context, req *lq.ReqFetchCustomizedContestGameLiveList) (resp *lq.ResFetchCustomizedContestGameLiveList, err error) {
+	resp = &lq.ResFetchCustomizedContestGameLiveList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCustomizedContestGameLiveList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1287,14 +944,10 @@ func (c *WebSocketClient) FetchCustomizedContestGameLiveList(req *lq.ReqFetchCus
 	return
 }
 
-func (c *WebSocketClient) FetchCustomizedContestGameRecords(req *lq.ReqFetchCustomizedContestGameRecords) (resp *lq.ResFetchCustomizedContestGameRecords, err error) {
-	respChan := make(chan *lq.ResFetchCustomizedContestGameRecords)
-	if err = c.send(".lq.Lobby.fetchCustomizedContestGameRecords", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCustomizedContestGameRecords(ctx context.Context, req *lq.ReqFetchCustomizedContestGameRecords) (resp *lq.ResFetchCustomizedContestGameRecords, err error) {
+	resp = &lq.ResFetchCustomizedContestGameRecords{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCustomizedContestGameRecords", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1302,14 +955,10 @@ func (c *WebSocketClient) FetchCustomizedContestGameRecords(req *lq.ReqFetchCust
 	return
 }
 
-func (c *WebSocketClient) FetchCustomizedContestList(req *lq.ReqFetchCustomizedContestList) (resp *lq.ResFetchCustomizedContestList, err error) {
-	respChan := make(chan *lq.ResFetchCustomizedContestList)
-	if err = c.send(".lq.Lobby.fetchCustomizedContestList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCustomizedContestList(ctx context.Context, req *lq.ReqFetchCustomizedContestList) (resp *lq.ResFetchCustomizedContestList, err error) {
+	resp = &lq.ResFetchCustomizedContestList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCustomizedContestList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1317,14 +966,10 @@ func (c *WebSocketClient) FetchCustomizedContestList(req *lq.ReqFetchCustomizedC
 	return
 }
 
-func (c *WebSocketClient) FetchCustomizedContestOnlineInfo(req *lq.ReqFetchCustomizedContestOnlineInfo) (resp *lq.ResFetchCustomizedContestOnlineInfo, err error) {
-	respChan := make(chan *lq.ResFetchCustomizedContestOnlineInfo)
-	if err = c.send(".lq.Lobby.fetchCustomizedContestOnlineInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchCustomizedContestOnlineInfo(ctx context.Context, req *lq.ReqFetchCustomizedContestOnlineInfo) (resp *lq.ResFetchCustomizedContestOnlineInfo, err error) {
+	resp = &lq.ResFetchCustomizedContestOnlineInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchCustomizedContestOnlineInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1332,14 +977,10 @@ func (c *WebSocketClient) FetchCustomizedContestOnlineInfo(req *lq.ReqFetchCusto
 	return
 }
 
-func (c *WebSocketClient) FetchDailySignInInfo(req *lq.ReqCommon) (resp *lq.ResDailySignInInfo, err error) {
-	respChan := make(chan *lq.ResDailySignInInfo)
-	if err = c.send(".lq.Lobby.fetchDailySignInInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchDailySignInInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResDailySignInInfo, err error) {
+	resp = &lq.ResDailySignInInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchDailySignInInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1347,14 +988,10 @@ func (c *WebSocketClient) FetchDailySignInInfo(req *lq.ReqCommon) (resp *lq.ResD
 	return
 }
 
-func (c *WebSocketClient) FetchDailyTask(req *lq.ReqCommon) (resp *lq.ResDailyTask, err error) {
-	respChan := make(chan *lq.ResDailyTask)
-	if err = c.send(".lq.Lobby.fetchDailyTask", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchDailyTask(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResDailyTask, err error) {
+	resp = &lq.ResDailyTask{}
+	if err = c.call(ctx, ".lq.Lobby.fetchDailyTask", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1362,14 +999,10 @@ func (c *WebSocketClient) FetchDailyTask(req *lq.ReqCommon) (resp *lq.ResDailyTa
 	return
 }
 
-func (c *WebSocketClient) FetchFriendApplyList(req *lq.ReqCommon) (resp *lq.ResFriendApplyList, err error) {
-	respChan := make(chan *lq.ResFriendApplyList)
-	if err = c.send(".lq.Lobby.fetchFriendApplyList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchFriendApplyList(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResFriendApplyList, err error) {
+	resp = &lq.ResFriendApplyList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchFriendApplyList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1377,14 +1010,10 @@ func (c *WebSocketClient) FetchFriendApplyList(req *lq.ReqCommon) (resp *lq.ResF
 	return
 }
 
-func (c *WebSocketClient) FetchFriendList(req *lq.ReqCommon) (resp *lq.ResFriendList, err error) {
-	respChan := make(chan *lq.ResFriendList)
-	if err = c.send(".lq.Lobby.fetchFriendList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchFriendList(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResFriendList, err error) {
+	resp = &lq.ResFriendList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchFriendList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1392,14 +1021,10 @@ func (c *WebSocketClient) FetchFriendList(req *lq.ReqCommon) (resp *lq.ResFriend
 	return
 }
 
-func (c *WebSocketClient) FetchGameLiveInfo(req *lq.ReqGameLiveInfo) (resp *lq.ResGameLiveInfo, err error) {
-	respChan := make(chan *lq.ResGameLiveInfo)
-	if err = c.send(".lq.Lobby.fetchGameLiveInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGameLiveInfo(ctx context.Context, req *lq.ReqGameLiveInfo) (resp *lq.ResGameLiveInfo, err error) {
+	resp = &lq.ResGameLiveInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchGameLiveInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1407,14 +1032,10 @@ func (c *WebSocketClient) FetchGameLiveInfo(req *lq.ReqGameLiveInfo) (resp *lq.R
 	return
 }
 
-func (c *WebSocketClient) FetchGameLiveLeftSegment(req *lq.ReqGameLiveLeftSegment) (resp *lq.ResGameLiveLeftSegment, err error) {
-	respChan := make(chan *lq.ResGameLiveLeftSegment)
-	if err = c.send(".lq.Lobby.fetchGameLiveLeftSegment", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGameLiveLeftSegment(ctx context.Context, req *lq.ReqGameLiveLeftSegment) (resp *lq.ResGameLiveLeftSegment, err error) {
+	resp = &lq.ResGameLiveLeftSegment{}
+	if err = c.call(ctx, ".lq.Lobby.fetchGameLiveLeftSegment", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1422,14 +1043,10 @@ func (c *WebSocketClient) FetchGameLiveLeftSegment(req *lq.ReqGameLiveLeftSegmen
 	return
 }
 
-func (c *WebSocketClient) FetchGameLiveList(req *lq.ReqGameLiveList) (resp *lq.ResGameLiveList, err error) {
-	respChan := make(chan *lq.ResGameLiveList)
-	if err = c.send(".lq.Lobby.fetchGameLiveList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGameLiveList(ctx context.Context, req *lq.ReqGameLiveList) (resp *lq.ResGameLiveList, err error) {
+	resp = &lq.ResGameLiveList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchGameLiveList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1437,14 +1054,10 @@ func (c *WebSocketClient) FetchGameLiveList(req *lq.ReqGameLiveList) (resp *lq.R
 	return
 }
 
-func (c *WebSocketClient) FetchGameRecord(req *lq.ReqGameRecord) (resp *lq.ResGameRecord, err error) {
-	respChan := make(chan *lq.ResGameRecord)
-	if err = c.send(".lq.Lobby.fetchGameRecord", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGameRecord(ctx context.Context, req *lq.ReqGameRecord) (resp *lq.ResGameRecord, err error) {
+	resp = &lq.ResGameRecord{}
+	if err = c.call(ctx, ".lq.Lobby.fetchGameRecord", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1452,14 +1065,10 @@ func (c *WebSocketClient) FetchGameRecord(req *lq.ReqGameRecord) (resp *lq.ResGa
 	return
 }
 
-func (c *WebSocketClient) FetchGameRecordList(req *lq.ReqGameRecordList) (resp *lq.ResGameRecordList, err error) {
-	respChan := make(chan *lq.ResGameRecordList)
-	if err = c.send(".lq.Lobby.fetchGameRecordList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGameRecordList(ctx context.Context, req *lq.ReqGameRecordList) (resp *lq.ResGameRecordList, err error) {
+	resp = &lq.ResGameRecordList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchGameRecordList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1467,14 +1076,10 @@ func (c *WebSocketClient) FetchGameRecordList(req *lq.ReqGameRecordList) (resp *
 	return
 }
 
-func (c *WebSocketClient) FetchGameRecordsDetail(req *lq.ReqGameRecordsDetail) (resp *lq.ResGameRecordsDetail, err error) {
-	respChan := make(chan *lq.ResGameRecordsDetail)
-	if err = c.send(".lq.Lobby.fetchGameRecordsDetail", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchGameRecordsDetail(ctx context.Context, req *lq.ReqGameRecordsDetail) (resp *lq.ResGameRecordsDetail, err error) {
+	resp = &lq.ResGameRecordsDetail{}
+	if err = c.call(ctx, ".lq.Lobby.fetchGameRecordsDetail", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1482,14 +1087,10 @@ func (c *WebSocketClient) FetchGameRecordsDetail(req *lq.ReqGameRecordsDetail) (
 	return
 }
 
-func (c *WebSocketClient) FetchIDCardInfo(req *lq.ReqCommon) (resp *lq.ResIDCardInfo, err error) {
-	respChan := make(chan *lq.ResIDCardInfo)
-	if err = c.send(".lq.Lobby.fetchIDCardInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchIDCardInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResIDCardInfo, err error) {
+	resp = &lq.ResIDCardInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchIDCardInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1497,14 +1098,10 @@ func (c *WebSocketClient) FetchIDCardInfo(req *lq.ReqCommon) (resp *lq.ResIDCard
 	return
 }
 
-func (c *WebSocketClient) FetchLevelLeaderboard(req *lq.ReqLevelLeaderboard) (resp *lq.ResLevelLeaderboard, err error) {
-	respChan := make(chan *lq.ResLevelLeaderboard)
-	if err = c.send(".lq.Lobby.fetchLevelLeaderboard", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchLevelLeaderboard(ctx context.Context, req *lq.ReqLevelLeaderboard) (resp *lq.ResLevelLeaderboard, err error) {
+	resp = &lq.ResLevelLeaderboard{}
+	if err = c.call(ctx, ".lq.Lobby.fetchLevelLeaderboard", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1512,14 +1109,10 @@ func (c *WebSocketClient) FetchLevelLeaderboard(req *lq.ReqLevelLeaderboard) (re
 	return
 }
 
-func (c *WebSocketClient) FetchMailInfo(req *lq.ReqCommon) (resp *lq.ResMailInfo, err error) {
-	respChan := make(chan *lq.ResMailInfo)
-	if err = c.send(".lq.Lobby.fetchMailInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchMailInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResMailInfo, err error) {
+	resp = &lq.ResMailInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchMailInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1527,14 +1120,10 @@ func (c *WebSocketClient) FetchMailInfo(req *lq.ReqCommon) (resp *lq.ResMailInfo
 	return
 }
 
-func (c *WebSocketClient) FetchMisc(req *lq.ReqCommon) (resp *lq.ResMisc, err error) {
-	respChan := make(chan *lq.ResMisc)
-	if err = c.send(".lq.Lobby.fetchMisc", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchMisc(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResMisc, err error) {
+	resp = &lq.ResMisc{}
+	if err = c.call(ctx, ".lq.Lobby.fetchMisc", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1542,38 +1131,26 @@ func (c *WebSocketClient) FetchMisc(req *lq.ReqCommon) (resp *lq.ResMisc, err er
 	return
 }
 
-func (c *WebSocketClient) FetchModNicknameTime(req *lq.ReqCommon) (resp *lq.ResModNicknameTime, err error) {
-	respChan := make(chan *lq.ResModNicknameTime)
-	if err = c.send(".lq.Lobby.fetchModNicknameTime", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchModNicknameTime(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResModNicknameTime, err error) {
+	resp = &lq.ResModNicknameTime{}
+	if err = c.call(ctx, ".lq.Lobby.fetchModNicknameTime", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchMonthTicketInfo(req *lq.ReqCommon) (resp *lq.ResMonthTicketInfo, err error) {
-	respChan := make(chan *lq.ResMonthTicketInfo)
-	if err = c.send(".lq.Lobby.fetchMonthTicketInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchMonthTicketInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResMonthTicketInfo, err error) {
+	resp = &lq.ResMonthTicketInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchMonthTicketInfo", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchMultiAccountBrief(req *lq.ReqMultiAccountId) (resp *lq.ResMultiAccountBrief, err error) {
-	respChan := make(chan *lq.ResMultiAccountBrief)
-	if err = c.send(".lq.Lobby.fetchMultiAccountBrief", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchMultiAccountBrief(ctx context.Context, req *lq.ReqMultiAccountId) (resp *lq.ResMultiAccountBrief, err error) {
+	resp = &lq.ResMultiAccountBrief{}
+	if err = c.call(ctx, ".lq.Lobby.fetchMultiAccountBrief", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1581,14 +1158,10 @@ func (c *WebSocketClient) FetchMultiAccountBrief(req *lq.ReqMultiAccountId) (res
 	return
 }
 
-func (c *WebSocketClient) FetchPlatformProducts(req *lq.ReqPlatformBillingProducts) (resp *lq.ResPlatformBillingProducts, err error) {
-	respChan := make(chan *lq.ResPlatformBillingProducts)
-	if err = c.send(".lq.Lobby.fetchPlatformProducts", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchPlatformProducts(ctx context.Context, req *lq.ReqPlatformBillingProducts) (resp *lq.ResPlatformBillingProducts, err error) {
+	resp = &lq.ResPlatformBillingProducts{}
+	if err = c.call(ctx, ".lq.Lobby.fetchPlatformProducts", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1596,14 +1169,10 @@ func (c *WebSocketClient) FetchPlatformProducts(req *lq.ReqPlatformBillingProduc
 	return
 }
 
-func (c *WebSocketClient) FetchRankPointLeaderboard(req *lq.ReqFetchRankPointLeaderboard) (resp *lq.ResFetchRankPointLeaderboard, err error) {
-	respChan := make(chan *lq.ResFetchRankPointLeaderboard)
-	if err = c.send(".lq.Lobby.fetchRankPointLeaderboard", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchRankPointLeaderboard(ctx context.Context, req *lq.ReqFetchRankPointLeaderboard) (resp *lq.ResFetchRankPointLeaderboard, err error) {
+	resp = &lq.ResFetchRankPointLeaderboard{}
+	if err = c.call(ctx, ".lq.Lobby.fetchRankPointLeaderboard", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1611,14 +1180,10 @@ func (c *WebSocketClient) FetchRankPointLeaderboard(req *lq.ReqFetchRankPointLea
 	return
 }
 
-func (c *WebSocketClient) FetchReviveCoinInfo(req *lq.ReqCommon) (resp *lq.ResReviveCoinInfo, err error) {
-	respChan := make(chan *lq.ResReviveCoinInfo)
-	if err = c.send(".lq.Lobby.fetchReviveCoinInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchReviveCoinInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResReviveCoinInfo, err error) {
+	resp = &lq.ResReviveCoinInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchReviveCoinInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1626,26 +1191,18 @@ func (c *WebSocketClient) FetchReviveCoinInfo(req *lq.ReqCommon) (resp *lq.ResRe
 	return
 }
 
-func (c *WebSocketClient) FetchRollingNotice(req *lq.ReqCommon) (resp *lq.ReqRollingNotice, err error) {
-	respChan := make(chan *lq.ReqRollingNotice)
-	if err = c.send(".lq.Lobby.fetchRollingNotice", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchRollingNotice(ctx context.Context, req *lq.ReqCommon) (resp *lq.ReqRollingNotice, err error) {
+	resp = &lq.ReqRollingNotice{}
+	if err = c.call(ctx, ".lq.Lobby.fetchRollingNotice", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchRoom(req *lq.ReqCommon) (resp *lq.ResSelfRoom, err error) {
-	respChan := make(chan *lq.ResSelfRoom)
-	if err = c.send(".lq.Lobby.fetchRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchRoom(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResSelfRoom, err error) {
+	resp = &lq.ResSelfRoom{}
+	if err = c.call(ctx, ".lq.Lobby.fetchRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1653,38 +1210,26 @@ func (c *WebSocketClient) FetchRoom(req *lq.ReqCommon) (resp *lq.ResSelfRoom, er
 	return
 }
 
-func (c *WebSocketClient) FetchServerSettings(req *lq.ReqCommon) (resp *lq.ResServerSettings, err error) {
-	respChan := make(chan *lq.ResServerSettings)
-	if err = c.send(".lq.Lobby.fetchServerSettings", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchServerSettings(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResServerSettings, err error) {
+	resp = &lq.ResServerSettings{}
+	if err = c.call(ctx, ".lq.Lobby.fetchServerSettings", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchServerTime(req *lq.ReqCommon) (resp *lq.ResServerTime, err error) {
-	respChan := make(chan *lq.ResServerTime)
-	if err = c.send(".lq.Lobby.fetchServerTime", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchServerTime(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResServerTime, err error) {
+	resp = &lq.ResServerTime{}
+	if err = c.call(ctx, ".lq.Lobby.fetchServerTime", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) FetchShopInfo(req *lq.ReqCommon) (resp *lq.ResShopInfo, err error) {
-	respChan := make(chan *lq.ResShopInfo)
-	if err = c.send(".lq.Lobby.fetchShopInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchShopInfo(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResShopInfo, err error) {
+	resp = &lq.ResShopInfo{}
+	if err = c.call(ctx, ".lq.Lobby.fetchShopInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1692,14 +1237,10 @@ func (c *WebSocketClient) FetchShopInfo(req *lq.ReqCommon) (resp *lq.ResShopInfo
 	return
 }
 
-func (c *WebSocketClient) FetchTitleList(req *lq.ReqCommon) (resp *lq.ResTitleList, err error) {
-	respChan := make(chan *lq.ResTitleList)
-	if err = c.send(".lq.Lobby.fetchTitleList", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchTitleList(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResTitleList, err error) {
+	resp = &lq.ResTitleList{}
+	if err = c.call(ctx, ".lq.Lobby.fetchTitleList", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1707,14 +1248,10 @@ func (c *WebSocketClient) FetchTitleList(req *lq.ReqCommon) (resp *lq.ResTitleLi
 	return
 }
 
-func (c *WebSocketClient) FetchVipReward(req *lq.ReqCommon) (resp *lq.ResVipReward, err error) {
-	respChan := make(chan *lq.ResVipReward)
-	if err = c.send(".lq.Lobby.fetchVipReward", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FetchVipReward(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResVipReward, err error) {
+	resp = &lq.ResVipReward{}
+	if err = c.call(ctx, ".lq.Lobby.fetchVipReward", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1722,14 +1259,10 @@ func (c *WebSocketClient) FetchVipReward(req *lq.ReqCommon) (resp *lq.ResVipRewa
 	return
 }
 
-func (c *WebSocketClient) FollowCustomizedContest(req *lq.ReqTargetCustomizedContest) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.followCustomizedContest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) FollowCustomizedContest(ctx context.Context, req *lq.ReqTargetCustomizedContest) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.followCustomizedContest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1737,14 +1270,10 @@ func (c *WebSocketClient) FollowCustomizedContest(req *lq.ReqTargetCustomizedCon
 	return
 }
 
-func (c *WebSocketClient) GainAccumulatedPointActivityReward(req *lq.ReqGainAccumulatedPointActivityReward) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.gainAccumulatedPointActivityReward", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) GainAccumulatedPointActivityReward(ctx context.Context, req *lq.ReqGainAccumulatedPointActivityReward) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.gainAccumulatedPointActivityReward", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1752,14 +1281,10 @@ func (c *WebSocketClient) GainAccumulatedPointActivityReward(req *lq.ReqGainAccu
 	return
 }
 
-func (c *WebSocketClient) GainRankPointReward(req *lq.ReqGainRankPointReward) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.gainRankPointReward", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) GainRankPointReward(ctx context.Context, req *lq.ReqGainRankPointReward) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.gainRankPointReward", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1767,14 +1292,10 @@ func (c *WebSocketClient) GainRankPointReward(req *lq.ReqGainRankPointReward) (r
 	return
 }
 
-func (c *WebSocketClient) GainReviveCoin(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.gainReviveCoin", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) GainReviveCoin(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.gainReviveCoin", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1782,14 +1303,10 @@ func (c *WebSocketClient) GainReviveCoin(req *lq.ReqCommon) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) GainVipReward(req *lq.ReqGainVipReward) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.gainVipReward", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) GainVipReward(ctx context.Context, req *lq.ReqGainVipReward) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.gainVipReward", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1797,14 +1314,10 @@ func (c *WebSocketClient) GainVipReward(req *lq.ReqGainVipReward) (resp *lq.ResC
 	return
 }
 
-func (c *WebSocketClient) GameMasterCommand(req *lq.ReqGMCommand) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.gameMasterCommand", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) GameMasterCommand(ctx context.Context, req *lq.ReqGMCommand) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.gameMasterCommand", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1812,14 +1325,10 @@ func (c *WebSocketClient) GameMasterCommand(req *lq.ReqGMCommand) (resp *lq.ResC
 	return
 }
 
-func (c *WebSocketClient) GoNextShiLian(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.goNextShiLian", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) GoNextShiLian(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.goNextShiLian", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1827,14 +1336,10 @@ func (c *WebSocketClient) GoNextShiLian(req *lq.ReqCommon) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) HandleFriendApply(req *lq.ReqHandleFriendApply) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.handleFriendApply", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) HandleFriendApply(ctx context.Context, req *lq.ReqHandleFriendApply) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.handleFriendApply", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1842,14 +1347,10 @@ func (c *WebSocketClient) HandleFriendApply(req *lq.ReqHandleFriendApply) (resp
 	return
 }
 
-func (c *WebSocketClient) Heatbeat(req *lq.ReqHeatBeat) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.heatbeat", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Heatbeat(ctx context.Context, req *lq.ReqHeatBeat) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.heatbeat", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1857,14 +1358,10 @@ func (c *WebSocketClient) Heatbeat(req *lq.ReqHeatBeat) (resp *lq.ResCommon, err
 	return
 }
 
-func (c *WebSocketClient) JoinCustomizedContestChatRoom(req *lq.ReqJoinCustomizedContestChatRoom) (resp *lq.ResJoinCustomizedContestChatRoom, err error) {
-	respChan := make(chan *lq.ResJoinCustomizedContestChatRoom)
-	if err = c.send(".lq.Lobby.joinCustomizedContestChatRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) JoinCustomizedContestChatRoom(ctx context.Context, req *lq.ReqJoinCustomizedContestChatRoom) (resp *lq.ResJoinCustomizedContestChatRoom, err error) {
+	resp = &lq.ResJoinCustomizedContestChatRoom{}
+	if err = c.call(ctx, ".lq.Lobby.joinCustomizedContestChatRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1872,14 +1369,10 @@ func (c *WebSocketClient) JoinCustomizedContestChatRoom(req *lq.ReqJoinCustomize
 	return
 }
 
-func (c *WebSocketClient) JoinRoom(req *lq.ReqJoinRoom) (resp *lq.ResJoinRoom, err error) {
-	respChan := make(chan *lq.ResJoinRoom)
-	if err = c.send(".lq.Lobby.joinRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) JoinRoom(ctx context.Context, req *lq.ReqJoinRoom) (resp *lq.ResJoinRoom, err error) {
+	resp = &lq.ResJoinRoom{}
+	if err = c.call(ctx, ".lq.Lobby.joinRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1887,14 +1380,10 @@ func (c *WebSocketClient) JoinRoom(req *lq.ReqJoinRoom) (resp *lq.ResJoinRoom, e
 	return
 }
 
-func (c *WebSocketClient) KickPlayer(req *lq.ReqRoomKick) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.kickPlayer", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) KickPlayer(ctx context.Context, req *lq.ReqRoomKick) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.kickPlayer", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1902,14 +1391,10 @@ func (c *WebSocketClient) KickPlayer(req *lq.ReqRoomKick) (resp *lq.ResCommon, e
 	return
 }
 
-func (c *WebSocketClient) LeaveComment(req *lq.ReqLeaveComment) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.leaveComment", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) LeaveComment(ctx context.Context, req *lq.ReqLeaveComment) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.leaveComment", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1917,14 +1402,10 @@ func (c *WebSocketClient) LeaveComment(req *lq.ReqLeaveComment) (resp *lq.ResCom
 	return
 }
 
-func (c *WebSocketClient) LeaveCustomizedContest(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.leaveCustomizedContest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) LeaveCustomizedContest(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.leaveCustomizedContest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1932,14 +1413,10 @@ func (c *WebSocketClient) LeaveCustomizedContest(req *lq.ReqCommon) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) LeaveCustomizedContestChatRoom(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.leaveCustomizedContestChatRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) LeaveCustomizedContestChatRoom(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.leaveCustomizedContestChatRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1947,14 +1424,10 @@ func (c *WebSocketClient) LeaveCustomizedContestChatRoom(req *lq.ReqCommon) (res
 	return
 }
 
-func (c *WebSocketClient) LeaveRoom(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.leaveRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) LeaveRoom(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.leaveRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1962,14 +1435,10 @@ func (c *WebSocketClient) LeaveRoom(req *lq.ReqCommon) (resp *lq.ResCommon, err
 	return
 }
 
-func (c *WebSocketClient) Login(req *lq.ReqLogin) (resp *lq.ResLogin, err error) {
-	respChan := make(chan *lq.ResLogin)
-	if err = c.send(".lq.Lobby.login", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Login(ctx context.Context, req *lq.ReqLogin) (resp *lq.ResLogin, err error) {
+	resp = &lq.ResLogin{}
+	if err = c.call(ctx, ".lq.Lobby.login", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1977,14 +1446,10 @@ func (c *WebSocketClient) Login(req *lq.ReqLogin) (resp *lq.ResLogin, err error)
 	return
 }
 
-func (c *WebSocketClient) LoginBeat(req *lq.ReqLoginBeat) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.loginBeat", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) LoginBeat(ctx context.Context, req *lq.ReqLoginBeat) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.loginBeat", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -1992,14 +1457,10 @@ func (c *WebSocketClient) LoginBeat(req *lq.ReqLoginBeat) (resp *lq.ResCommon, e
 	return
 }
 
-func (c *WebSocketClient) Logout(req *lq.ReqLogout) (resp *lq.ResLogout, err error) {
-	respChan := make(chan *lq.ResLogout)
-	if err = c.send(".lq.Lobby.logout", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Logout(ctx context.Context, req *lq.ReqLogout) (resp *lq.ResLogout, err error) {
+	resp = &lq.ResLogout{}
+	if err = c.call(ctx, ".lq.Lobby.logout", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2007,14 +1468,10 @@ func (c *WebSocketClient) Logout(req *lq.ReqLogout) (resp *lq.ResLogout, err err
 	return
 }
 
-func (c *WebSocketClient) MatchGame(req *lq.ReqJoinMatchQueue) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.matchGame", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) MatchGame(ctx context.Context, req *lq.ReqJoinMatchQueue) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.matchGame", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2022,14 +1479,10 @@ func (c *WebSocketClient) MatchGame(req *lq.ReqJoinMatchQueue) (resp *lq.ResComm
 	return
 }
 
-func (c *WebSocketClient) MatchShiLian(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.matchShiLian", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) MatchShiLian(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.matchShiLian", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2037,14 +1490,10 @@ func (c *WebSocketClient) MatchShiLian(req *lq.ReqCommon) (resp *lq.ResCommon, e
 	return
 }
 
-func (c *WebSocketClient) ModifyBirthday(req *lq.ReqModifyBirthday) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.modifyBirthday", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ModifyBirthday(ctx context.Context, req *lq.ReqModifyBirthday) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.modifyBirthday", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2052,14 +1501,10 @@ func (c *WebSocketClient) ModifyBirthday(req *lq.ReqModifyBirthday) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) ModifyNickname(req *lq.ReqModifyNickname) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.modifyNickname", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ModifyNickname(ctx context.Context, req *lq.ReqModifyNickname) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.modifyNickname", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2067,14 +1512,10 @@ func (c *WebSocketClient) ModifyNickname(req *lq.ReqModifyNickname) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) ModifyPassword(req *lq.ReqModifyPassword) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.modifyPassword", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ModifyPassword(ctx context.Context, req *lq.ReqModifyPassword) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.modifyPassword", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2082,14 +1523,10 @@ func (c *WebSocketClient) ModifyPassword(req *lq.ReqModifyPassword) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) ModifyRoom(req *lq.ReqModifyRoom) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.modifyRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ModifyRoom(ctx context.Context, req *lq.ReqModifyRoom) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.modifyRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2097,14 +1534,10 @@ func (c *WebSocketClient) ModifyRoom(req *lq.ReqModifyRoom) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) ModifySignature(req *lq.ReqModifySignature) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.modifySignature", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ModifySignature(ctx context.Context, req *lq.ReqModifySignature) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.modifySignature", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2112,14 +1545,10 @@ func (c *WebSocketClient) ModifySignature(req *lq.ReqModifySignature) (resp *lq.
 	return
 }
 
-func (c *WebSocketClient) Oauth2Auth(req *lq.ReqOauth2Auth) (resp *lq.ResOauth2Auth, err error) {
-	respChan := make(chan *lq.ResOauth2Auth)
-	if err = c.send(".lq.Lobby.oauth2Auth", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Oauth2Auth(ctx context.Context, req *lq.ReqOauth2Auth) (resp *lq.ResOauth2Auth, err error) {
+	resp = &lq.ResOauth2Auth{}
+	if err = c.call(ctx, ".lq.Lobby.oauth2Auth", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2127,14 +1556,10 @@ func (c *WebSocketClient) Oauth2Auth(req *lq.ReqOauth2Auth) (resp *lq.ResOauth2A
 	return
 }
 
-func (c *WebSocketClient) Oauth2Check(req *lq.ReqOauth2Check) (resp *lq.ResOauth2Check, err error) {
-	respChan := make(chan *lq.ResOauth2Check)
-	if err = c.send(".lq.Lobby.oauth2Check", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Oauth2Check(ctx context.Context, req *lq.ReqOauth2Check) (resp *lq.ResOauth2Check, err error) {
+	resp = &lq.ResOauth2Check{}
+	if err = c.call(ctx, ".lq.Lobby.oauth2Check", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2142,14 +1567,10 @@ func (c *WebSocketClient) Oauth2Check(req *lq.ReqOauth2Check) (resp *lq.ResOauth
 	return
 }
 
-func (c *WebSocketClient) Oauth2Login(req *lq.ReqOauth2Login) (resp *lq.ResLogin, err error) {
-	respChan := make(chan *lq.ResLogin)
-	if err = c.send(".lq.Lobby.oauth2Login", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Oauth2Login(ctx context.Context, req *lq.ReqOauth2Login) (resp *lq.ResLogin, err error) {
+	resp = &lq.ResLogin{}
+	if err = c.call(ctx, ".lq.Lobby.oauth2Login", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2157,14 +1578,10 @@ func (c *WebSocketClient) Oauth2Login(req *lq.ReqOauth2Login) (resp *lq.ResLogin
 	return
 }
 
-func (c *WebSocketClient) Oauth2Signup(req *lq.ReqOauth2Signup) (resp *lq.ResOauth2Signup, err error) {
-	respChan := make(chan *lq.ResOauth2Signup)
-	if err = c.send(".lq.Lobby.oauth2Signup", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Oauth2Signup(ctx context.Context, req *lq.ReqOauth2Signup) (resp *lq.ResOauth2Signup, err error) {
+	resp = &lq.ResOauth2Signup{}
+	if err = c.call(ctx, ".lq.Lobby.oauth2Signup", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2172,14 +1589,10 @@ func (c *WebSocketClient) Oauth2Signup(req *lq.ReqOauth2Signup) (resp *lq.ResOau
 	return
 }
 
-func (c *WebSocketClient) OpenChest(req *lq.ReqOpenChest) (resp *lq.ResOpenChest, err error) {
-	respChan := make(chan *lq.ResOpenChest)
-	if err = c.send(".lq.Lobby.openChest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) OpenChest(ctx context.Context, req *lq.ReqOpenChest) (resp *lq.ResOpenChest, err error) {
+	resp = &lq.ResOpenChest{}
+	if err = c.call(ctx, ".lq.Lobby.openChest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2187,14 +1600,10 @@ func (c *WebSocketClient) OpenChest(req *lq.ReqOpenChest) (resp *lq.ResOpenChest
 	return
 }
 
-func (c *WebSocketClient) OpenManualItem(req *lq.ReqOpenManualItem) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.openManualItem", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) OpenManualItem(ctx context.Context, req *lq.ReqOpenManualItem) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.openManualItem", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2202,14 +1611,10 @@ func (c *WebSocketClient) OpenManualItem(req *lq.ReqOpenManualItem) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) OpenRandomRewardItem(req *lq.ReqOpenRandomRewardItem) (resp *lq.ResOpenRandomRewardItem, err error) {
-	respChan := make(chan *lq.ResOpenRandomRewardItem)
-	if err = c.send(".lq.Lobby.openRandomRewardItem", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) OpenRandomRewardItem(ctx context.Context, req *lq.ReqOpenRandomRewardItem) (resp *lq.ResOpenRandomRewardItem, err error) {
+	resp = &lq.ResOpenRandomRewardItem{}
+	if err = c.call(ctx, ".lq.Lobby.openRandomRewardItem", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2217,14 +1622,10 @@ func (c *WebSocketClient) OpenRandomRewardItem(req *lq.ReqOpenRandomRewardItem)
 	return
 }
 
-func (c *WebSocketClient) PayMonthTicket(req *lq.ReqPayMonthTicket) (resp *lq.ResPayMonthTicket, err error) {
-	respChan := make(chan *lq.ResPayMonthTicket)
-	if err = c.send(".lq.Lobby.payMonthTicket", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) PayMonthTicket(ctx context.Context, req *lq.ReqPayMonthTicket) (resp *lq.ResPayMonthTicket, err error) {
+	resp = &lq.ResPayMonthTicket{}
+	if err = c.call(ctx, ".lq.Lobby.payMonthTicket", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2232,14 +1633,10 @@ func (c *WebSocketClient) PayMonthTicket(req *lq.ReqPayMonthTicket) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) ReadAnnouncement(req *lq.ReqReadAnnouncement) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.readAnnouncement", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ReadAnnouncement(ctx context.Context, req *lq.ReqReadAnnouncement) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.readAnnouncement", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2247,14 +1644,10 @@ func (c *WebSocketClient) ReadAnnouncement(req *lq.ReqReadAnnouncement) (resp *l
 	return
 }
 
-func (c *WebSocketClient) ReadMail(req *lq.ReqReadMail) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.readMail", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ReadMail(ctx context.Context, req *lq.ReqReadMail) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.readMail", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2262,14 +1655,10 @@ func (c *WebSocketClient) ReadMail(req *lq.ReqReadMail) (resp *lq.ResCommon, err
 	return
 }
 
-func (c *WebSocketClient) ReadyPlay(req *lq.ReqRoomReady) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.readyPlay", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ReadyPlay(ctx context.Context, req *lq.ReqRoomReady) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.readyPlay", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2277,26 +1666,18 @@ func (c *WebSocketClient) ReadyPlay(req *lq.ReqRoomReady) (resp *lq.ResCommon, e
 	return
 }
 
-func (c *WebSocketClient) RecieveActivityFlipTask(req *lq.ReqRecieveActivityFlipTask) (resp *lq.ResRecieveActivityFlipTask, err error) {
-	respChan := make(chan *lq.ResRecieveActivityFlipTask)
-	if err = c.send(".lq.Lobby.recieveActivityFlipTask", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) RecieveActivityFlipTask(ctx context.Context, req *lq.ReqRecieveActivityFlipTask) (resp *lq.ResRecieveActivityFlipTask, err error) {
+	resp = &lq.ResRecieveActivityFlipTask{}
+	if err = c.call(ctx, ".lq.Lobby.recieveActivityFlipTask", req, resp); err != nil {
+		return nil, err
 	}
 	return
 }
 
-func (c *WebSocketClient) RefreshDailyTask(req *lq.ReqRefreshDailyTask) (resp *lq.ResRefreshDailyTask, err error) {
-	respChan := make(chan *lq.ResRefreshDailyTask)
-	if err = c.send(".lq.Lobby.refreshDailyTask", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) RefreshDailyTask(ctx context.Context, req *lq.ReqRefreshDailyTask) (resp *lq.ResRefreshDailyTask, err error) {
+	resp = &lq.ResRefreshDailyTask{}
+	if err = c.call(ctx, ".lq.Lobby.refreshDailyTask", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2304,14 +1685,10 @@ func (c *WebSocketClient) RefreshDailyTask(req *lq.ReqRefreshDailyTask) (resp *l
 	return
 }
 
-func (c *WebSocketClient) RefreshZHPShop(req *lq.ReqCommon) (resp *lq.ResRefreshZHPShop, err error) {
-	respChan := make(chan *lq.ResRefreshZHPShop)
-	if err = c.send(".lq.Lobby.refreshZHPShop", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) RefreshZHPShop(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResRefreshZHPShop, err error) {
+	resp = &lq.ResRefreshZHPShop{}
+	if err = c.call(ctx, ".lq.Lobby.refreshZHPShop", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2319,14 +1696,10 @@ func (c *WebSocketClient) RefreshZHPShop(req *lq.ReqCommon) (resp *lq.ResRefresh
 	return
 }
 
-func (c *WebSocketClient) RemoveCollectedGameRecord(req *lq.ReqRemoveCollectedGameRecord) (resp *lq.ResRemoveCollectedGameRecord, err error) {
-	respChan := make(chan *lq.ResRemoveCollectedGameRecord)
-	if err = c.send(".lq.Lobby.removeCollectedGameRecord", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) RemoveCollectedGameRecord(ctx context.Context, req *lq.ReqRemoveCollectedGameRecord) (resp *lq.ResRemoveCollectedGameRecord, err error) {
+	resp = &lq.ResRemoveCollectedGameRecord{}
+	if err = c.call(ctx, ".lq.Lobby.removeCollectedGameRecord", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2334,14 +1707,10 @@ func (c *WebSocketClient) RemoveCollectedGameRecord(req *lq.ReqRemoveCollectedGa
 	return
 }
 
-func (c *WebSocketClient) RemoveFriend(req *lq.ReqRemoveFriend) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.removeFriend", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) RemoveFriend(ctx context.Context, req *lq.ReqRemoveFriend) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.removeFriend", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2349,14 +1718,10 @@ func (c *WebSocketClient) RemoveFriend(req *lq.ReqRemoveFriend) (resp *lq.ResCom
 	return
 }
 
-func (c *WebSocketClient) SayChatMessage(req *lq.ReqSayChatMessage) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.sayChatMessage", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SayChatMessage(ctx context.Context, req *lq.ReqSayChatMessage) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.sayChatMessage", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2364,14 +1729,10 @@ func (c *WebSocketClient) SayChatMessage(req *lq.ReqSayChatMessage) (resp *lq.Re
 	return
 }
 
-func (c *WebSocketClient) SearchAccountById(req *lq.ReqSearchAccountById) (resp *lq.ResSearchAccountById, err error) {
-	respChan := make(chan *lq.ResSearchAccountById)
-	if err = c.send(".lq.Lobby.searchAccountById", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SearchAccountById(ctx context.Context, req *lq.ReqSearchAccountById) (resp *lq.ResSearchAccountById, err error) {
+	resp = &lq.ResSearchAccountById{}
+	if err = c.call(ctx, ".lq.Lobby.searchAccountById", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2379,14 +1740,10 @@ func (c *WebSocketClient) SearchAccountById(req *lq.ReqSearchAccountById) (resp
 	return
 }
 
-func (c *WebSocketClient) SearchAccountByPattern(req *lq.ReqSearchAccountByPattern) (resp *lq.ResSearchAccountByPattern, err error) {
-	respChan := make(chan *lq.ResSearchAccountByPattern)
-	if err = c.send(".lq.Lobby.searchAccountByPattern", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SearchAccountByPattern(ctx context.Context, req *lq.ReqSearchAccountByPattern) (resp *lq.ResSearchAccountByPattern, err error) {
+	resp = &lq.ResSearchAccountByPattern{}
+	if err = c.call(ctx, ".lq.Lobby.searchAccountByPattern", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2394,14 +1751,10 @@ func (c *WebSocketClient) SearchAccountByPattern(req *lq.ReqSearchAccountByPatte
 	return
 }
 
-func (c *WebSocketClient) SellItem(req *lq.ReqSellItem) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.sellItem", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SellItem(ctx context.Context, req *lq.ReqSellItem) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.sellItem", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2409,14 +1762,10 @@ func (c *WebSocketClient) SellItem(req *lq.ReqSellItem) (resp *lq.ResCommon, err
 	return
 }
 
-func (c *WebSocketClient) SendClientMessage(req *lq.ReqSendClientMessage) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.sendClientMessage", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SendClientMessage(ctx context.Context, req *lq.ReqSendClientMessage) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.sendClientMessage", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2424,14 +1773,10 @@ func (c *WebSocketClient) SendClientMessage(req *lq.ReqSendClientMessage) (resp
 	return
 }
 
-func (c *WebSocketClient) SendGiftToCharacter(req *lq.ReqSendGiftToCharacter) (resp *lq.ResSendGiftToCharacter, err error) {
-	respChan := make(chan *lq.ResSendGiftToCharacter)
-	if err = c.send(".lq.Lobby.sendGiftToCharacter", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SendGiftToCharacter(ctx context.Context, req *lq.ReqSendGiftToCharacter) (resp *lq.ResSendGiftToCharacter, err error) {
+	resp = &lq.ResSendGiftToCharacter{}
+	if err = c.call(ctx, ".lq.Lobby.sendGiftToCharacter", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2439,14 +1784,10 @@ func (c *WebSocketClient) SendGiftToCharacter(req *lq.ReqSendGiftToCharacter) (r
 	return
 }
 
-func (c *WebSocketClient) ShopPurchase(req *lq.ReqShopPurchase) (resp *lq.ResShopPurchase, err error) {
-	respChan := make(chan *lq.ResShopPurchase)
-	if err = c.send(".lq.Lobby.shopPurchase", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) ShopPurchase(ctx context.Context, req *lq.ReqShopPurchase) (resp *lq.ResShopPurchase, err error) {
+	resp = &lq.ResShopPurchase{}
+	if err = c.call(ctx, ".lq.Lobby.shopPurchase", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2454,14 +1795,10 @@ func (c *WebSocketClient) ShopPurchase(req *lq.ReqShopPurchase) (resp *lq.ResSho
 	return
 }
 
-func (c *WebSocketClient) Signup(req *lq.ReqSignupAccount) (resp *lq.ResSignupAccount, err error) {
-	respChan := make(chan *lq.ResSignupAccount)
-	if err = c.send(".lq.Lobby.signup", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) Signup(ctx context.Context, req *lq.ReqSignupAccount) (resp *lq.ResSignupAccount, err error) {
+	resp = &lq.ResSignupAccount{}
+	if err = c.call(ctx, ".lq.Lobby.signup", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2469,14 +1806,10 @@ func (c *WebSocketClient) Signup(req *lq.ReqSignupAccount) (resp *lq.ResSignupAc
 	return
 }
 
-func (c *WebSocketClient) SolveGooglePlayOrder(req *lq.ReqSolveGooglePlayOrder) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.solveGooglePlayOrder", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) SolveGooglePlayOrder(ctx context.Context, req *lq.ReqSolveGooglePlayOrder) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.solveGooglePlayOrder", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2484,14 +1817,10 @@ func (c *WebSocketClient) SolveGooglePlayOrder(req *lq.ReqSolveGooglePlayOrder)
 	return
 }
 
-func (c *WebSocketClient) StartCustomizedContest(req *lq.ReqStartCustomizedContest) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.startCustomizedContest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) StartCustomizedContest(ctx context.Context, req *lq.ReqStartCustomizedContest) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.startCustomizedContest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2499,14 +1828,10 @@ func (c *WebSocketClient) StartCustomizedContest(req *lq.ReqStartCustomizedConte
 	return
 }
 
-func (c *WebSocketClient) StartRoom(req *lq.ReqRoomStart) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.startRoom", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) StartRoom(ctx context.Context, req *lq.ReqRoomStart) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.startRoom", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2514,14 +1839,10 @@ func (c *WebSocketClient) StartRoom(req *lq.ReqRoomStart) (resp *lq.ResCommon, e
 	return
 }
 
-func (c *WebSocketClient) StopCustomizedContest(req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.stopCustomizedContest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) StopCustomizedContest(ctx context.Context, req *lq.ReqCommon) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.stopCustomizedContest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2529,14 +1850,10 @@ func (c *WebSocketClient) StopCustomizedContest(req *lq.ReqCommon) (resp *lq.Res
 	return
 }
 
-func (c *WebSocketClient) TakeAttachmentFromMail(req *lq.ReqTakeAttachment) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.takeAttachmentFromMail", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) TakeAttachmentFromMail(ctx context.Context, req *lq.ReqTakeAttachment) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.takeAttachmentFromMail", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2544,14 +1861,10 @@ func (c *WebSocketClient) TakeAttachmentFromMail(req *lq.ReqTakeAttachment) (res
 	return
 }
 
-func (c *WebSocketClient) UnfollowCustomizedContest(req *lq.ReqTargetCustomizedContest) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.unfollowCustomizedContest", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UnfollowCustomizedContest(ctx context.Context, req *lq.ReqTargetCustomizedContest) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.unfollowCustomizedContest", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2559,14 +1872,10 @@ func (c *WebSocketClient) UnfollowCustomizedContest(req *lq.ReqTargetCustomizedC
 	return
 }
 
-func (c *WebSocketClient) UpdateAccountSettings(req *lq.ReqUpdateAccountSettings) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.updateAccountSettings", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UpdateAccountSettings(ctx context.Context, req *lq.ReqUpdateAccountSettings) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.updateAccountSettings", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2574,14 +1883,10 @@ func (c *WebSocketClient) UpdateAccountSettings(req *lq.ReqUpdateAccountSettings
 	return
 }
 
-func (c *WebSocketClient) UpdateClientValue(req *lq.ReqUpdateClientValue) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.updateClientValue", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UpdateClientValue(ctx context.Context, req *lq.ReqUpdateClientValue) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.updateClientValue", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2589,14 +1894,10 @@ func (c *WebSocketClient) UpdateClientValue(req *lq.ReqUpdateClientValue) (resp
 	return
 }
 
-func (c *WebSocketClient) UpdateCommentSetting(req *lq.ReqUpdateCommentSetting) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.updateCommentSetting", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UpdateCommentSetting(ctx context.Context, req *lq.ReqUpdateCommentSetting) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.updateCommentSetting", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2604,14 +1905,10 @@ func (c *WebSocketClient) UpdateCommentSetting(req *lq.ReqUpdateCommentSetting)
 	return
 }
 
-func (c *WebSocketClient) UpdateIDCardInfo(req *lq.ReqUpdateIDCardInfo) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.updateIDCardInfo", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UpdateIDCardInfo(ctx context.Context, req *lq.ReqUpdateIDCardInfo) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.updateIDCardInfo", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2619,14 +1916,10 @@ func (c *WebSocketClient) UpdateIDCardInfo(req *lq.ReqUpdateIDCardInfo) (resp *l
 	return
 }
 
-func (c *WebSocketClient) UpdateReadComment(req *lq.ReqUpdateReadComment) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.updateReadComment", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UpdateReadComment(ctx context.Context, req *lq.ReqUpdateReadComment) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.updateReadComment", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2634,14 +1927,10 @@ func (c *WebSocketClient) UpdateReadComment(req *lq.ReqUpdateReadComment) (resp
 	return
 }
 
-func (c *WebSocketClient) UpgradeCharacter(req *lq.ReqUpgradeCharacter) (resp *lq.ResUpgradeCharacter, err error) {
-	respChan := make(chan *lq.ResUpgradeCharacter)
-	if err = c.send(".lq.Lobby.upgradeCharacter", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UpgradeCharacter(ctx context.Context, req *lq.ReqUpgradeCharacter) (resp *lq.ResUpgradeCharacter, err error) {
+	resp = &lq.ResUpgradeCharacter{}
+	if err = c.call(ctx, ".lq.Lobby.upgradeCharacter", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2649,14 +1938,10 @@ func (c *WebSocketClient) UpgradeCharacter(req *lq.ReqUpgradeCharacter) (resp *l
 	return
 }
 
-func (c *WebSocketClient) UseBagItem(req *lq.ReqUseBagItem) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.useBagItem", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UseBagItem(ctx context.Context, req *lq.ReqUseBagItem) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.useBagItem", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2664,14 +1949,10 @@ func (c *WebSocketClient) UseBagItem(req *lq.ReqUseBagItem) (resp *lq.ResCommon,
 	return
 }
 
-func (c *WebSocketClient) UseGiftCode(req *lq.ReqUseGiftCode) (resp *lq.ResUseGiftCode, err error) {
-	respChan := make(chan *lq.ResUseGiftCode)
-	if err = c.send(".lq.Lobby.useGiftCode", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UseGiftCode(ctx context.Context, req *lq.ReqUseGiftCode) (resp *lq.ResUseGiftCode, err error) {
+	resp = &lq.ResUseGiftCode{}
+	if err = c.call(ctx, ".lq.Lobby.useGiftCode", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2679,14 +1960,10 @@ func (c *WebSocketClient) UseGiftCode(req *lq.ReqUseGiftCode) (resp *lq.ResUseGi
 	return
 }
 
-func (c *WebSocketClient) UseTitle(req *lq.ReqUseTitle) (resp *lq.ResCommon, err error) {
-	respChan := make(chan *lq.ResCommon)
-	if err = c.send(".lq.Lobby.useTitle", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) UseTitle(ctx context.Context, req *lq.ReqUseTitle) (resp *lq.ResCommon, err error) {
+	resp = &lq.ResCommon{}
+	if err = c.call(ctx, ".lq.Lobby.useTitle", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -2694,14 +1971,10 @@ func (c *WebSocketClient) UseTitle(req *lq.ReqUseTitle) (resp *lq.ResCommon, err
 	return
 }
 
-func (c *WebSocketClient) VerfifyCodeForSecure(req *lq.ReqVerifyCodeForSecure) (resp *lq.ResVerfiyCodeForSecure, err error) {
-	respChan := make(chan *lq.ResVerfiyCodeForSecure)
-	if err = c.send(".lq.Lobby.verfifyCodeForSecure", req, respChan); err != nil {
-		return
-	}
-	resp = <-respChan
-	if resp == nil {
-		return nil, fmt.Errorf("empty response")
+func (c *WebSocketClient) VerfifyCodeForSecure(ctx context.Context, req *lq.ReqVerifyCodeForSecure) (resp *lq.ResVerfiyCodeForSecure, err error) {
+	resp = &lq.ResVerfiyCodeForSecure{}
+	if err = c.call(ctx, ".lq.Lobby.verfifyCodeForSecure", req, resp); err != nil {
+		return nil, err
 	}
 	if resp.Error != nil {
 		err = fmt.Errorf("majsoul error: %s", resp.Error.String())
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api/apitest"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, s *apitest.Server) *WebSocketClient {
+	c := NewWebSocketClient()
+	c.HeartbeatInterval = 0
+	c.MaxReconnectInterval = 10 * time.Millisecond
+	if err := c.Connect(s.WebSocketURL, ""); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestWebSocketClient_call(t *testing.T) {
+	assert := assert.New(t)
+
+	s := apitest.NewServer()
+	defer s.Close()
+	s.Handle(".lq.Lobby.fetchServerTime", func(c *apitest.Conn, data []byte) (proto.Message, error) {
+		return &lq.ResServerTime{ServerTime: 1234}, nil
+	})
+	s.Handle(".lq.Lobby.fetchAccountInfo", func(c *apitest.Conn, data []byte) (proto.Message, error) {
+		// 不响应
+		return nil, nil
+	})
+	c := newTestClient(t, s)
+	defer c.Close()
+
+	// 并发调用
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.FetchServerTime(context.Background(), &lq.ReqCommon{})
+			if assert.NoError(err) {
+				assert.EqualValues(1234, resp.ServerTime)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.FetchAccountInfo(ctx, &lq.ReqAccountInfo{})
+	assert.Error(err)
+
+	c.CallTimeout = 50 * time.Millisecond
+	_, err = c.FetchAccountInfo(context.Background(), &lq.ReqAccountInfo{})
+	assert.Error(err)
+
+	// 超时的请求不会残留
+	c.mu.Lock()
+	assert.Empty(c.pendingCalls)
+	c.mu.Unlock()
+
+	assert.NoError(c.Close())
+	_, err = c.FetchServerTime(context.Background(), &lq.ReqCommon{})
+	assert.Equal(ErrClosed, err)
+}
+
+func TestWebSocketClient_reconnect(t *testing.T) {
+	assert := assert.New(t)
+
+	account := apitest.NewTestAccount()
+	s := apitest.NewServer()
+	defer s.Close()
+	s.HandleLogin(account)
+	reauthTokens := make(chan string, 1)
+	s.Handle(".lq.Lobby.oauth2Login", func(c *apitest.Conn, data []byte) (proto.Message, error) {
+		req := &lq.ReqOauth2Login{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			return nil, err
+		}
+		if req.Reconnect {
+			reauthTokens <- req.AccessToken
+		}
+		return &lq.ResLogin{AccountId: account.AccountID}, nil
+	})
+	s.Handle(".lq.Lobby.fetchServerTime", func(c *apitest.Conn, data []byte) (proto.Message, error) {
+		return &lq.ResServerTime{ServerTime: 1234}, nil
+	})
+	s.Handle(".lq.Lobby.fetchAccountInfo", func(c *apitest.Conn, data []byte) (proto.Message, error) {
+		// 模拟断线
+		return nil, errors.New("disconnect")
+	})
+	c := newTestClient(t, s)
+	defer c.Close()
+
+	_, err := c.Login(context.Background(), &lq.ReqLogin{Account: account.Username, GenAccessToken: true})
+	assert.NoError(err)
+
+	// 断线时等待响应的请求立即失败
+	_, err = c.FetchAccountInfo(context.Background(), &lq.ReqAccountInfo{})
+	assert.Equal(ErrNotConnected, err)
+
+	// 重连后用 access token 重新登录
+	select {
+	case token := <-reauthTokens:
+		assert.Equal(account.AccessToken, token)
+	case <-time.After(5 * time.Second):
+		t.Fatal("重连超时")
+	}
+	assert.Equal(2, s.NumConns())
+
+	resp, err := c.FetchServerTime(context.Background(), &lq.ReqCommon{})
+	if assert.NoError(err) {
+		assert.EqualValues(1234, resp.ServerTime)
+	}
+
+	// 登出后断线重连不再重新登录
+	_, err = c.Logout(context.Background(), &lq.ReqLogout{})
+	assert.NoError(err)
+	assert.NoError(c.reauth())
+	assert.Empty(reauthTokens)
+}
+
+func TestWebSocketClient_Subscribe(t *testing.T) {
+	assert := assert.New(t)
+
+	s := apitest.NewServer()
+	defer s.Close()
+	s.Handle(".lq.Lobby.fetchServerTime", func(c *apitest.Conn, data []byte) (proto.Message, error) {
+		// 先发通知再响应，响应到达时通知已经送达
+		c.Notify(".lq.NotifyAnotherLogin", &lq.NotifyAnotherLogin{})
+		c.Notify(".lq.NotifyAccountUpdate", &lq.NotifyAccountUpdate{Update: &lq.AccountUpdate{}})
+		return &lq.ResServerTime{}, nil
+	})
+	c := newTestClient(t, s)
+	defer c.Close()
+
+	accountUpdates, unsubscribe := c.Subscribe("NotifyAccountUpdate")
+	allNotifies, _ := c.Subscribe("")
+
+	_, err := c.FetchServerTime(context.Background(), &lq.ReqCommon{})
+	assert.NoError(err)
+
+	notify := <-accountUpdates
+	assert.Equal("NotifyAccountUpdate", notify.Name)
+	assert.IsType(&lq.NotifyAccountUpdate{}, notify.Message)
+	assert.Equal("NotifyAnotherLogin", (<-allNotifies).Name)
+	assert.Equal("NotifyAccountUpdate", (<-allNotifies).Name)
+
+	// 取消订阅和关闭连接后 channel 被关闭
+	unsubscribe()
+	_, ok := <-accountUpdates
+	assert.False(ok)
+	c.Close()
+	_, ok = <-allNotifies
+	assert.False(ok)
+}
+
+func TestWebSocketClient_nextMessageIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewWebSocketClient()
+	c.messageIndex = maxMessageIndex - 2
+	c.pendingCalls[maxMessageIndex-1] = &pendingCall{}
+	c.pendingCalls[0] = &pendingCall{}
+	index, err := c.nextMessageIndex()
+	assert.NoError(err)
+	assert.EqualValues(1, index)
+
+	for i := uint16(0); i < maxMessageIndex; i++ {
+		c.pendingCalls[i] = &pendingCall{}
+	}
+	_, err = c.nextMessageIndex()
+	assert.Equal(ErrTooManyCalls, err)
+}
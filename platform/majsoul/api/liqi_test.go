@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api/apitest"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/tool"
 	"github.com/satori/go.uuid"
@@ -12,7 +14,42 @@ import (
 	"time"
 )
 
+var _testAccount = apitest.NewTestAccount()
+
+// 设置环境变量 MAJSOUL_ONLINE_TEST=1 时连接雀魂服务器测试（需配置 USERNAME、PASSWORD 等），否则使用本地的模拟服务器测试
+// 不能用 USERNAME 判断，Windows 总是设置了该变量
+func _isOnline() bool {
+	return os.Getenv("MAJSOUL_ONLINE_TEST") == "1"
+}
+
+func _connect(t *testing.T) (c *WebSocketClient, closeFunc func()) {
+	c = NewWebSocketClient()
+	if !_isOnline() {
+		s := apitest.NewServer()
+		s.HandleLogin(_testAccount)
+		if err := c.Connect(s.WebSocketURL, ""); err != nil {
+			s.Close()
+			t.Fatal(err)
+		}
+		return c, func() { c.Close(); s.Close() }
+	}
+
+	endpoint, err := tool.GetMajsoulWebSocketURL() // wss://mj-srv-7.majsoul.com:4131/
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log("连接 endpoint: " + endpoint)
+	if err := c.Connect(endpoint, tool.MajsoulOriginURL); err != nil {
+		t.Fatal(err)
+	}
+	return c, func() { c.Close() }
+}
+
 func _genReqLogin(t *testing.T) *lq.ReqLogin {
+	if !_isOnline() {
+		return &lq.ReqLogin{Account: _testAccount.Username, ClientVersion: "0.0.0.w", GenAccessToken: true}
+	}
+
 	username, ok := os.LookupEnv("USERNAME")
 	if !ok {
 		t.Skip("未配置环境变量 USERNAME，退出")
@@ -30,8 +67,7 @@ func _genReqLogin(t *testing.T) *lq.ReqLogin {
 	// randomKey 最好是个固定值
 	randomKey, ok := os.LookupEnv("RANDOM_KEY")
 	if !ok {
-		rawRandomKey, _ := uuid.NewV4()
-		randomKey = rawRandomKey.String()
+		randomKey = uuid.NewV4().String()
 	}
 
 	version, err := tool.GetMajsoulVersion(tool.ApiGetVersionZH)
@@ -56,10 +92,13 @@ func _genReqLogin(t *testing.T) *lq.ReqLogin {
 }
 
 func _genReqOauth2Login(t *testing.T, accessToken string) *lq.ReqOauth2Login {
+	if !_isOnline() {
+		return &lq.ReqOauth2Login{AccessToken: accessToken, ClientVersion: "0.0.0.w"}
+	}
+
 	randomKey, ok := os.LookupEnv("RANDOM_KEY")
 	if !ok {
-		rawRandomKey, _ := uuid.NewV4()
-		randomKey = rawRandomKey.String()
+		randomKey = uuid.NewV4().String()
 	}
 
 	version, err := tool.GetMajsoulVersion(tool.ApiGetVersionZH)
@@ -83,19 +122,11 @@ func _genReqOauth2Login(t *testing.T, accessToken string) *lq.ReqOauth2Login {
 }
 
 func TestLogin(t *testing.T) {
-	endpoint, err := tool.GetMajsoulWebSocketURL() // wss://mj-srv-7.majsoul.com:4131/
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Log("连接 endpoint: " + endpoint)
-	c := NewWebSocketClient()
-	if err := c.Connect(endpoint, tool.MajsoulOriginURL); err != nil {
-		t.Fatal(err)
-	}
-	defer c.Close()
+	c, closeFunc := _connect(t)
+	defer closeFunc()
 
 	reqLogin := _genReqLogin(t)
-	respLogin, err := c.Login(reqLogin)
+	respLogin, err := c.Login(context.Background(), reqLogin)
 	if err != nil {
 		t.Skip("登录失败:", err)
 	}
@@ -104,7 +135,7 @@ func TestLogin(t *testing.T) {
 
 	time.Sleep(time.Second)
 
-	respLogout, err := c.Logout(&lq.ReqLogout{})
+	respLogout, err := c.Logout(context.Background(), &lq.ReqLogout{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -112,27 +143,22 @@ func TestLogin(t *testing.T) {
 }
 
 func TestReLogin(t *testing.T) {
-	endpoint, err := tool.GetMajsoulWebSocketURL() // wss://mj-srv-7.majsoul.com:4131/
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Log("连接 endpoint: " + endpoint)
-	c := NewWebSocketClient()
-	if err := c.Connect(endpoint, tool.MajsoulOriginURL); err != nil {
-		t.Fatal(err)
-	}
-	defer c.Close()
+	c, closeFunc := _connect(t)
+	defer closeFunc()
 
-	accessToken, ok := os.LookupEnv("TOKEN")
-	if !ok {
-		t.Skip("未配置环境变量 TOKEN，退出")
+	accessToken := _testAccount.AccessToken
+	if _isOnline() {
+		var ok bool
+		if accessToken, ok = os.LookupEnv("TOKEN"); !ok {
+			t.Skip("未配置环境变量 TOKEN，退出")
+		}
 	}
 	reqOauth2Check := lq.ReqOauth2Check{
 		// Type = 3 为 QQ
 		Type:        0, // 账号/QQ/微信/微博/ 海外的……?
 		AccessToken: accessToken,
 	}
-	respOauth2Check, err := c.Oauth2Check(&reqOauth2Check)
+	respOauth2Check, err := c.Oauth2Check(context.Background(), &reqOauth2Check)
 	if err != nil {
 		t.Skip("token 验证失败:", err)
 	}
@@ -143,7 +169,7 @@ func TestReLogin(t *testing.T) {
 	}
 
 	reqOauth2Login := _genReqOauth2Login(t, accessToken)
-	respLogin, err := c.Oauth2Login(reqOauth2Login)
+	respLogin, err := c.Oauth2Login(context.Background(), reqOauth2Login)
 	if err != nil {
 		t.Skip("登录失败:", err)
 	}
@@ -152,7 +178,7 @@ func TestReLogin(t *testing.T) {
 
 	time.Sleep(time.Second)
 
-	respLogout, err := c.Logout(&lq.ReqLogout{})
+	respLogout, err := c.Logout(context.Background(), &lq.ReqLogout{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -20,11 +20,117 @@ type Message struct {
 	NotifyMessage   proto.Message `json:"notify_message,omitempty"`
 }
 
+// 根据消息名（如 .lq.ActionPrototype）创建对应的消息并解析 data
+func UnmarshalNamedMessage(name string, data []byte) (proto.Message, error) {
+	mt := proto.MessageType(strings.TrimPrefix(name, "."))
+	if mt == nil {
+		return nil, fmt.Errorf("未找到 %s，请检查代码！", name)
+	}
+	message := reflect.New(mt.Elem()).Interface().(proto.Message)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// 解析 WebSocket 发出和收到的原始数据
+// 请求会被记录下来，收到对应的响应时一并返回
+// 非并发安全
+type MessageDecoder struct {
+	indexToMessageMap map[uint16]*Message
+}
+
+func NewMessageDecoder() *MessageDecoder {
+	return &MessageDecoder{
+		indexToMessageMap: map[uint16]*Message{},
+	}
+}
+
+// 解析一帧数据
+// 通知和请求响应会返回解析后的消息，请求或找不到对应请求的响应返回 nil
+func (d *MessageDecoder) Decode(data []byte) (*Message, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("数据为空")
+	}
+
+	messageType := data[0]
+	switch messageType {
+	case api.MessageTypeNotify:
+		notifyName, data, err := api.UnwrapData(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		notifyMessage, err := UnmarshalNamedMessage(notifyName, data)
+		if err != nil {
+			return nil, err
+		}
+		return &Message{
+			Name:          strings.TrimPrefix(notifyName, "."), // 移除开头的 .
+			NotifyMessage: notifyMessage,
+		}, nil
+	case api.MessageTypeRequest:
+		if len(data) < 3 {
+			return nil, fmt.Errorf("数据过短 %v", data)
+		}
+		messageIndex := binary.LittleEndian.Uint16(data[1:3])
+
+		rawMethodName, data, err := api.UnwrapData(data[3:])
+		if err != nil {
+			return nil, err
+		}
+		rawMethodName = strings.TrimPrefix(rawMethodName, ".") // 移除开头的 .
+
+		// 通过 rawMethodName 找到请求类型和请求响应类型
+		splits := strings.Split(rawMethodName, ".")
+		if len(splits) != 3 {
+			return nil, fmt.Errorf("无法识别的请求 %s", rawMethodName)
+		}
+		clientName, methodName := splits[1], splits[2]
+		methodType := lq.FindMethod(clientName, methodName)
+		if methodType == nil {
+			return nil, fmt.Errorf("未找到 %s，请检查代码！", rawMethodName)
+		}
+		reqType := methodType.In(1)
+		respType := methodType.Out(0)
+
+		reqMessage := reflect.New(reqType.Elem()).Interface().(proto.Message)
+		if err := proto.Unmarshal(data, reqMessage); err != nil {
+			return nil, fmt.Errorf("%s: %v", rawMethodName, err)
+		}
+		respMessage := reflect.New(respType.Elem()).Interface().(proto.Message)
+
+		d.indexToMessageMap[messageIndex] = &Message{
+			Name:            rawMethodName,
+			RequestMessage:  reqMessage,
+			ResponseMessage: respMessage,
+		}
+		return nil, nil
+	case api.MessageTypeResponse:
+		if len(data) < 3 {
+			return nil, fmt.Errorf("数据过短 %v", data)
+		}
+		// 似乎是有序返回的……
+		messageIndex := binary.LittleEndian.Uint16(data[1:3])
+		message, ok := d.indexToMessageMap[messageIndex]
+		if !ok {
+			// 用户在启动助手前就启动了雀魂
+			return nil, nil
+		}
+		delete(d.indexToMessageMap, messageIndex)
+		if err := api.UnwrapMessage(data[3:], message.ResponseMessage); err != nil {
+			return nil, fmt.Errorf("%s: %v", message.Name, err)
+		}
+		return message, nil
+	default:
+		return nil, fmt.Errorf("数据有误 %d", messageType)
+	}
+}
+
 type MessageReceiver struct {
 	originMessageQueue  chan []byte   // 包含所有 WebSocket 发出的消息和收到的消息
 	orderedMessageQueue chan *Message // 整理后的 WebSocket 收到的消息（包含请求响应和通知）
 
-	indexToMessageMap map[uint16]*Message
+	decoder *MessageDecoder
 }
 
 func NewMessageReceiver() *MessageReceiver {
@@ -32,7 +138,7 @@ func NewMessageReceiver() *MessageReceiver {
 	mr := &MessageReceiver{
 		originMessageQueue:  make(chan []byte, maxQueueSize),
 		orderedMessageQueue: make(chan *Message, maxQueueSize),
-		indexToMessageMap:   map[uint16]*Message{},
+		decoder:             NewMessageDecoder(),
 	}
 	go mr.run()
 	return mr
@@ -40,79 +146,13 @@ func NewMessageReceiver() *MessageReceiver {
 
 func (mr *MessageReceiver) run() {
 	for data := range mr.originMessageQueue {
-		messageType := data[0]
-		switch messageType {
-		case api.MessageTypeNotify:
-			notifyName, data, err := api.UnwrapData(data[1:])
-			if err != nil {
-				fmt.Fprintln(os.Stderr, "MessageReceiver.run.api.UnwrapData.NOTIFY", err)
-				continue
-			}
-			notifyName = notifyName[1:] // 移除开头的 .
-
-			mt := proto.MessageType(notifyName)
-			if mt == nil {
-				fmt.Fprintf(os.Stderr, "MessageReceiver.run 未找到 %s，请检查！\n", notifyName)
-				continue
-			}
-			messagePtr := reflect.New(mt.Elem())
-			if err := proto.Unmarshal(data, messagePtr.Interface().(proto.Message)); err != nil {
-				fmt.Fprintln(os.Stderr, "MessageReceiver.run.proto.Unmarshal.NOTIFY", notifyName, err)
-				continue
-			}
-
-			mr.orderedMessageQueue <- &Message{
-				Name:          notifyName,
-				NotifyMessage: messagePtr.Interface().(proto.Message),
-			}
-		case api.MessageTypeRequest:
-			messageIndex := binary.LittleEndian.Uint16(data[1:3])
-
-			rawMethodName, data, err := api.UnwrapData(data[3:])
-			if err != nil {
-				fmt.Fprintln(os.Stderr, "MessageReceiver.run.api.UnwrapData.REQUEST", err)
-				continue
-			}
-			rawMethodName = rawMethodName[1:] // 移除开头的 .
-
-			// 通过 rawMethodName 找到请求类型和请求响应类型
-			splits := strings.Split(rawMethodName, ".")
-			clientName, methodName := splits[1], splits[2]
-			methodType := lq.FindMethod(clientName, methodName)
-			reqType := methodType.In(1)
-			respType := methodType.Out(0)
-
-			messagePtr := reflect.New(reqType.Elem())
-			if err := proto.Unmarshal(data, messagePtr.Interface().(proto.Message)); err != nil {
-				fmt.Fprintln(os.Stderr, "MessageReceiver.run.proto.Unmarshal.REQUEST", rawMethodName, err)
-				continue
-			}
-			reqMessage := messagePtr.Interface().(proto.Message)
-
-			messagePtr = reflect.New(respType.Elem())
-			respMessage := messagePtr.Interface().(proto.Message)
-
-			mr.indexToMessageMap[messageIndex] = &Message{
-				Name:            rawMethodName,
-				RequestMessage:  reqMessage,
-				ResponseMessage: respMessage,
-			}
-		case api.MessageTypeResponse:
-			// 似乎是有序返回的……
-			messageIndex := binary.LittleEndian.Uint16(data[1:3])
-			message, ok := mr.indexToMessageMap[messageIndex]
-			if !ok {
-				// 用户在启动助手前就启动了雀魂
-				continue
-			}
-			delete(mr.indexToMessageMap, messageIndex)
-			if err := api.UnwrapMessage(data[3:], message.ResponseMessage); err != nil {
-				fmt.Fprintln(os.Stderr, "MessageReceiver.run.proto.Unmarshal.RESPONSE", message.Name, err)
-				continue
-			}
+		message, err := mr.decoder.Decode(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "MessageReceiver.run", err)
+			continue
+		}
+		if message != nil {
 			mr.orderedMessageQueue <- message
-		default:
-			panic(fmt.Sprintln("[MessageReceiver] 数据有误", messageType))
 		}
 	}
 }
@@ -1,6 +1,7 @@
 package majsoul
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/json"
@@ -8,11 +9,14 @@ import (
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
 	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/tool"
-	"github.com/golang/protobuf/proto"
 	"github.com/satori/go.uuid"
 	"io/ioutil"
+	"net/http"
 	"os"
-	"reflect"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 )
 
 const (
@@ -24,152 +28,457 @@ const (
 	// 该接口传入的 UUID 在登录后调用 FetchCollectedGameRecordList 获得
 )
 
-func genReqLogin(username string, password string) (*lq.ReqLogin, error) {
-	const key = "lailai" // from code.js
-	mac := hmac.New(sha256.New, []byte(key))
-	mac.Write([]byte(password))
-	password = fmt.Sprintf("%x", mac.Sum(nil))
+// 下载牌谱的配置
+type DownloadConfig struct {
+	// 用户名和密码登录
+	Username string
+	Password string
+
+	// 不为空时用 access token 登录，此时无需用户名和密码
+	// 用密码登录成功后会输出 access token
+	AccessToken string
+
+	RecordType uint32
+
+	// 下载收藏的牌谱，此时忽略 RecordType
+	Collected bool
+
+	// 牌谱和索引文件的保存目录，为空时为当前目录
+	Dir string
+
+	// 同时下载的牌谱数，不大于 0 时为 defaultConcurrency
+	Concurrency int
+
+	// 以下用于连接其他服务器（如测试时使用的本地服务器），为空时连接雀魂服务器
+	Endpoint      string
+	Origin        string
+	ClientVersion string
+}
+
+const (
+	defaultConcurrency = 4
+
+	recordListPageSize = 10
+
+	// 一次 FetchGameRecordsDetail 获取的牌谱数
+	recordsDetailPageSize = 10
+
+	// 下载目录中的索引文件，不是牌谱
+	RecordIndexFileName = "majsoul-records.json"
+)
+
+func (c *DownloadConfig) clientVersion() (string, error) {
+	if c.ClientVersion != "" {
+		return c.ClientVersion, nil
+	}
+	version, err := tool.GetMajsoulVersion(tool.ApiGetVersionZH)
+	if err != nil {
+		return "", err
+	}
+	return version.ResVersion, nil
+}
 
+func randomKey() string {
 	// randomKey 最好是个固定值
 	randomKey, ok := os.LookupEnv("RANDOM_KEY")
 	if !ok {
 		randomKey = uuid.NewV4().String()
 	}
+	return randomKey
+}
 
-	version, err := tool.GetMajsoulVersion(tool.ApiGetVersionZH)
-	if err != nil {
-		return nil, err
+func newClientDeviceInfo() *lq.ClientDeviceInfo {
+	return &lq.ClientDeviceInfo{
+		DeviceType: "pc",
+		Os:         "",
+		OsVersion:  "",
+		Browser:    "safari",
 	}
+}
+
+func genReqLogin(username string, password string, clientVersion string) *lq.ReqLogin {
+	const key = "lailai" // from code.js
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(password))
+	password = fmt.Sprintf("%x", mac.Sum(nil))
+
 	return &lq.ReqLogin{
-		Account:   username,
-		Password:  password,
-		Reconnect: false,
-		Device: &lq.ClientDeviceInfo{
-			DeviceType: "pc",
-			Os:         "",
-			OsVersion:  "",
-			Browser:    "safari",
-		},
-		RandomKey:         randomKey,          // 例如 aa566cfc-547e-4cc0-a36f-2ebe6269109b
-		ClientVersion:     version.ResVersion, // 0.5.162.w
+		Account:           username,
+		Password:          password,
+		Reconnect:         false,
+		Device:            newClientDeviceInfo(),
+		RandomKey:         randomKey(),   // 例如 aa566cfc-547e-4cc0-a36f-2ebe6269109b
+		ClientVersion:     clientVersion, // 0.5.162.w
 		GenAccessToken:    true,
 		CurrencyPlatforms: []uint32{2}, // 1-inGooglePlay, 2-inChina
-	}, nil
+	}
 }
 
-// TODO: add token
-func DownloadRecords(username string, password string, recordType uint32) error {
-	c := api.NewWebSocketClient()
-	if err := c.ConnectMajsoul(); err != nil {
+func genReqOauth2Login(accessToken string, clientVersion string) *lq.ReqOauth2Login {
+	return &lq.ReqOauth2Login{
+		Type:              0, // 雀魂账号的 access token
+		AccessToken:       accessToken,
+		Reconnect:         false,
+		Device:            newClientDeviceInfo(),
+		RandomKey:         randomKey(),
+		ClientVersion:     clientVersion,
+		CurrencyPlatforms: []uint32{2},
+	}
+}
+
+func (c *DownloadConfig) connect() (*api.WebSocketClient, error) {
+	client := api.NewWebSocketClient()
+	if c.Endpoint == "" {
+		if err := client.ConnectMajsoul(); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	origin := c.Origin
+	if origin == "" {
+		origin = tool.MajsoulOriginURL
+	}
+	if err := client.Connect(c.Endpoint, origin); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *DownloadConfig) login(ctx context.Context, client *api.WebSocketClient) error {
+	clientVersion, err := c.clientVersion()
+	if err != nil {
+		return err
+	}
+	if c.AccessToken != "" {
+		_, err := client.Oauth2Login(ctx, genReqOauth2Login(c.AccessToken, clientVersion))
 		return err
 	}
-	defer c.Close()
+	respLogin, err := client.Login(ctx, genReqLogin(c.Username, c.Password, clientVersion))
+	if err != nil {
+		return err
+	}
+	if respLogin.AccessToken != "" {
+		fmt.Println("access token:", respLogin.AccessToken)
+	}
+	return nil
+}
 
-	// 登录
-	reqLogin, err := genReqLogin(username, password)
+// 牌谱列表的名称，用于在索引中区分不同的列表
+func (c *DownloadConfig) listName() string {
+	if c.Collected {
+		return "collected"
+	}
+	return fmt.Sprintf("type-%d", c.RecordType)
+}
+
+//
+
+// 已下载牌谱的索引，保存在牌谱目录下
+// 每下载完一个牌谱就更新一次，下载中断后再次下载时会跳过已下载的牌谱
+type recordIndex struct {
+	// 已下载的牌谱 uuid -> 对局结束时间
+	Downloaded map[string]uint32 `json:"downloaded"`
+
+	// 已获取到但还未下载的牌谱
+	Pending map[string]*lq.RecordGame `json:"pending"`
+
+	// 是否完整获取过该牌谱列表
+	// 牌谱列表按时间从新到旧排列，完整获取过的列表在遇到已下载的牌谱后就无需继续获取
+	ListComplete map[string]bool `json:"list_complete"`
+}
+
+func loadRecordIndex(dir string) (*recordIndex, error) {
+	index := &recordIndex{}
+	data, err := ioutil.ReadFile(filepath.Join(dir, RecordIndexFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, index); err != nil {
+			return nil, fmt.Errorf("牌谱索引解析失败：%v", err)
+		}
+	}
+	if index.Downloaded == nil {
+		index.Downloaded = map[string]uint32{}
+	}
+	if index.Pending == nil {
+		index.Pending = map[string]*lq.RecordGame{}
+	}
+	if index.ListComplete == nil {
+		index.ListComplete = map[string]bool{}
+	}
+	return index, nil
+}
+
+// 先写入临时文件再重命名，避免中断时损坏索引
+func (index *recordIndex) save(dir string) error {
+	data, err := json.MarshalIndent(index, "", "  ")
 	if err != nil {
 		return err
 	}
-	if _, err := c.Login(reqLogin); err != nil {
+	path := filepath.Join(dir, RecordIndexFileName)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
 		return err
 	}
-	defer c.Logout(&lq.ReqLogout{})
+	return os.Rename(path+".tmp", path)
+}
+
+// 牌谱文件存在才算已下载
+func (index *recordIndex) isDownloaded(dir string, uuid string) bool {
+	if _, ok := index.Downloaded[uuid]; !ok {
+		return false
+	}
+	_, err := os.Stat(recordFilePath(dir, uuid))
+	return err == nil
+}
+
+func recordFilePath(dir string, uuid string) string {
+	return filepath.Join(dir, uuid+".json")
+}
 
-	// 分页获取牌谱列表
-	// TODO: 若之前下载过，可以判断：上次是否下载完成->只下载本地最新文件之后的牌谱
+//
+
+// 分页获取牌谱列表
+// 若之前完整获取过该列表，遇到已下载的牌谱后停止获取
+func fetchRecordList(ctx context.Context, client *api.WebSocketClient, recordType uint32, index *recordIndex, dir string, isListComplete bool) ([]*lq.RecordGame, error) {
 	recordList := []*lq.RecordGame{}
-	const pageSize = 10
-	for i := uint32(1); ; i += pageSize {
+	for i := uint32(1); ; i += recordListPageSize {
 		reqGameRecordList := lq.ReqGameRecordList{
 			Start: i,
-			Count: pageSize,
+			Count: recordListPageSize,
 			Type:  recordType,
 		}
-		respGameRecordList, err := c.FetchGameRecordList(&reqGameRecordList)
+		respGameRecordList, err := client.FetchGameRecordList(ctx, &reqGameRecordList)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		foundDownloaded := false
+		for _, gameRecord := range respGameRecordList.RecordList {
+			if index.isDownloaded(dir, gameRecord.Uuid) {
+				foundDownloaded = true
+			} else {
+				recordList = append(recordList, gameRecord)
+			}
 		}
-		recordList = append(recordList, respGameRecordList.RecordList...)
-		if len(respGameRecordList.RecordList) < pageSize {
+		if len(respGameRecordList.RecordList) < recordListPageSize || isListComplete && foundDownloaded {
 			break
 		}
 	}
+	return recordList, nil
+}
 
-	// TODO: 若牌谱数量巨大，可以使用协程增加下载速度
-	for i, gameRecord := range recordList {
-		fmt.Printf("%d/%d %s\n", i+1, len(recordList), gameRecord.Uuid)
-
-		// 获取具体牌谱内容
-		reqGameRecord := lq.ReqGameRecord{
-			GameUuid: gameRecord.Uuid,
-		}
-		respGameRecord, err := c.FetchGameRecord(&reqGameRecord)
-		if err != nil {
-			return err
+// 获取收藏的牌谱列表
+func fetchCollectedRecordList(ctx context.Context, client *api.WebSocketClient, index *recordIndex, dir string) ([]*lq.RecordGame, error) {
+	respCollected, err := client.FetchCollectedGameRecordList(ctx, &lq.ReqCommon{})
+	if err != nil {
+		return nil, err
+	}
+	uuids := []string{}
+	for _, collected := range respCollected.RecordList {
+		if !index.isDownloaded(dir, collected.Uuid) {
+			uuids = append(uuids, collected.Uuid)
 		}
+	}
 
-		// 解析
-		data := respGameRecord.Data
-		if len(data) == 0 {
-			dataURL := respGameRecord.DataUrl
-			if dataURL == "" {
-				fmt.Fprintln(os.Stderr, "数据异常: dataURL 为空")
-				continue
-			}
-			data, err = tool.Fetch(dataURL)
-			if err != nil {
-				fmt.Fprintln(os.Stderr, err)
-				continue
-			}
+	recordList := []*lq.RecordGame{}
+	for i := 0; i < len(uuids); i += recordsDetailPageSize {
+		end := i + recordsDetailPageSize
+		if end > len(uuids) {
+			end = len(uuids)
 		}
-		detailRecords := lq.GameDetailRecords{}
-		if err := api.UnwrapMessage(data, &detailRecords); err != nil {
-			return err
+		respDetail, err := client.FetchGameRecordsDetail(ctx, &lq.ReqGameRecordsDetail{UuidList: uuids[i:end]})
+		if err != nil {
+			return nil, err
 		}
+		recordList = append(recordList, respDetail.RecordList...)
+	}
+	return recordList, nil
+}
 
-		type messageWithType struct {
-			Name string        `json:"name"`
-			Data proto.Message `json:"data"`
-		}
-		details := []messageWithType{}
-		for _, detailRecord := range detailRecords.GetRecords() {
-			name, data, err := api.UnwrapData(detailRecord)
-			if err != nil {
-				return err
-			}
+// 下载牌谱数据的 HTTP 客户端，各个下载任务共用
+var recordDataClient = &http.Client{Timeout: time.Minute}
 
-			name = name[1:] // 移除开头的 .
-			mt := proto.MessageType(name)
-			if mt == nil {
-				return fmt.Errorf("未找到 %s，请检查代码！", name)
-			}
-			messagePtr := reflect.New(mt.Elem())
-			if err := proto.Unmarshal(data, messagePtr.Interface().(proto.Message)); err != nil {
-				return err
-			}
+// 下载牌谱数据（ResGameRecord 的 DataUrl），并发安全
+func FetchRecordData(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", tool.UserAgent)
+	resp, err := recordDataClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
 
-			details = append(details, messageWithType{
-				Name: name[3:], // 移除开头的 lq.
-				Data: messagePtr.Interface().(proto.Message),
-			})
-		}
+// 获取并解析牌谱内容
+func fetchRecord(ctx context.Context, client *api.WebSocketClient, gameRecord *lq.RecordGame) (*Record, error) {
+	reqGameRecord := lq.ReqGameRecord{
+		GameUuid: gameRecord.Uuid,
+	}
+	respGameRecord, err := client.FetchGameRecord(ctx, &reqGameRecord)
+	if err != nil {
+		return nil, err
+	}
 
-		// 保存至本地（JSON 格式）
-		parseResult := struct {
-			Head    *lq.RecordGame    `json:"head"`
-			Details []messageWithType `json:"details"`
-		}{
-			Head:    gameRecord,
-			Details: details,
+	data := respGameRecord.Data
+	if len(data) == 0 {
+		dataURL := respGameRecord.DataUrl
+		if dataURL == "" {
+			return nil, fmt.Errorf("数据异常: dataURL 为空")
 		}
-		jsonData, err := json.MarshalIndent(&parseResult, "", "  ")
+		data, err = FetchRecordData(ctx, dataURL)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if err := ioutil.WriteFile(gameRecord.Uuid+".json", jsonData, 0644); err != nil {
-			return err
+	}
+	details, err := ParseGameDetailRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Head:    gameRecord,
+		Details: details,
+	}, nil
+}
+
+// 下载牌谱，每个牌谱保存为 uuid.json，可以用 ParseRecord 读取
+// 已下载的牌谱记录在索引文件中，再次下载时只下载新的牌谱
+// 部分牌谱下载失败时，其余牌谱仍会保存，下次下载时会重试失败的牌谱
+// ctx 取消后，未下载的牌谱会在下次下载时继续下载
+func Download(ctx context.Context, config *DownloadConfig) (numDownloaded int, err error) {
+	dir := config.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	index, err := loadRecordIndex(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	client, err := config.connect()
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	// 登录
+	if err := config.login(ctx, client); err != nil {
+		return 0, err
+	}
+	defer client.Logout(context.Background(), &lq.ReqLogout{})
+
+	// 获取牌谱列表
+	listName := config.listName()
+	var recordList []*lq.RecordGame
+	if config.Collected {
+		recordList, err = fetchCollectedRecordList(ctx, client, index, dir)
+	} else {
+		recordList, err = fetchRecordList(ctx, client, config.RecordType, index, dir, index.ListComplete[listName])
+	}
+	if err != nil {
+		return 0, err
+	}
+	for _, gameRecord := range recordList {
+		index.Pending[gameRecord.Uuid] = gameRecord
+	}
+	index.ListComplete[listName] = true
+
+	// 加上之前未下载完成的牌谱
+	recordList = recordList[:0]
+	for _, gameRecord := range index.Pending {
+		recordList = append(recordList, gameRecord)
+	}
+	sort.Slice(recordList, func(i, j int) bool {
+		return recordList[i].EndTime > recordList[j].EndTime
+	})
+	if err := index.save(dir); err != nil {
+		return 0, err
+	}
+
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	mu := sync.Mutex{}
+	numFailed := 0
+	var saveErr error
+	wg := sync.WaitGroup{}
+	gameRecords := make(chan *lq.RecordGame)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for gameRecord := range gameRecords {
+				err := downloadRecord(ctx, client, gameRecord, dir)
+
+				mu.Lock()
+				if err != nil {
+					numFailed++
+					fmt.Fprintln(os.Stderr, gameRecord.Uuid, err)
+				} else {
+					numDownloaded++
+					fmt.Printf("%d/%d %s\n", numDownloaded, len(recordList), gameRecord.Uuid)
+					delete(index.Pending, gameRecord.Uuid)
+					index.Downloaded[gameRecord.Uuid] = gameRecord.EndTime
+					if err := index.save(dir); err != nil {
+						saveErr = err
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+sendLoop:
+	for _, gameRecord := range recordList {
+		select {
+		case gameRecords <- gameRecord:
+		case <-ctx.Done():
+			break sendLoop
 		}
 	}
+	close(gameRecords)
+	wg.Wait()
 
-	return nil
+	if saveErr != nil {
+		return numDownloaded, saveErr
+	}
+	if err := ctx.Err(); err != nil {
+		return numDownloaded, err
+	}
+	if numFailed > 0 {
+		return numDownloaded, fmt.Errorf("%d 个牌谱下载失败，再次下载时会重试", numFailed)
+	}
+	return numDownloaded, nil
+}
+
+func downloadRecord(ctx context.Context, client *api.WebSocketClient, gameRecord *lq.RecordGame, dir string) error {
+	record, err := fetchRecord(ctx, client, gameRecord)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(recordFilePath(dir, gameRecord.Uuid), jsonData, 0644)
+}
+
+// 用用户名和密码登录，下载牌谱到当前目录
+func DownloadRecords(username string, password string, recordType uint32) error {
+	_, err := Download(context.Background(), &DownloadConfig{
+		Username:   username,
+		Password:   password,
+		RecordType: recordType,
+	})
+	return err
 }
@@ -0,0 +1,503 @@
+package majsoul
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou6"
+	"github.com/golang/protobuf/proto"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 牌谱中的一条记录，如 RecordNewRound RecordDiscardTile 等
+type RecordDetail struct {
+	Name string        `json:"name"` // 不含开头的 lq.
+	Data proto.Message `json:"data"`
+}
+
+func (d *RecordDetail) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Name string          `json:"name"`
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	mt := proto.MessageType("lq." + raw.Name)
+	if mt == nil {
+		return fmt.Errorf("未找到 %s，请检查代码！", raw.Name)
+	}
+	messagePtr := reflect.New(mt.Elem()).Interface().(proto.Message)
+	if err := json.Unmarshal(raw.Data, messagePtr); err != nil {
+		return err
+	}
+	d.Name = raw.Name
+	d.Data = messagePtr
+	return nil
+}
+
+// 雀魂牌谱，DownloadRecords 保存的 JSON 文件即为该格式
+type Record struct {
+	Head    *lq.RecordGame  `json:"head"`
+	Details []*RecordDetail `json:"details"`
+}
+
+func ParseRecord(data []byte) (*Record, error) {
+	record := &Record{}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("牌谱解析失败：%v", err)
+	}
+	if record.Head == nil || len(record.Details) == 0 {
+		return nil, fmt.Errorf("牌谱中没有对局数据")
+	}
+	return record, nil
+}
+
+// 解析 GameDetailRecords 的二进制数据，即 FetchGameRecord 返回的 data 或 data_url 的内容
+func ParseGameDetailRecords(data []byte) ([]*RecordDetail, error) {
+	detailRecords := lq.GameDetailRecords{}
+	if err := api.UnwrapMessage(data, &detailRecords); err != nil {
+		return nil, err
+	}
+
+	details := []*RecordDetail{}
+	for _, detailRecord := range detailRecords.GetRecords() {
+		name, data, err := api.UnwrapData(detailRecord)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(name, ".lq.") {
+			return nil, fmt.Errorf("数据异常：无法识别的记录 %q", name)
+		}
+
+		message, err := UnmarshalNamedMessage(name, data)
+		if err != nil {
+			return nil, err
+		}
+
+		details = append(details, &RecordDetail{
+			Name: name[4:], // 移除开头的 .lq.
+			Data: message,
+		})
+	}
+	return details, nil
+}
+
+// 解析 GameDetailRecords 的二进制数据，由于没有牌谱基本信息，Head 为 nil
+func ParseRawRecord(data []byte) (*Record, error) {
+	details, err := ParseGameDetailRecords(data)
+	if err != nil {
+		return nil, fmt.Errorf("牌谱解析失败：%v", err)
+	}
+	if len(details) == 0 {
+		return nil, fmt.Errorf("牌谱中没有对局数据")
+	}
+	return &Record{Details: details}, nil
+}
+
+//
+
+// 雀魂的牌，如 1m 0p(赤5饼) 7z(中)
+func tenhou6TileCode(majsoulTile string) (int, error) {
+	if len(majsoulTile) != 2 {
+		return -1, fmt.Errorf("错误的牌 %q", majsoulTile)
+	}
+	num := int(majsoulTile[0] - '0')
+	suit := strings.IndexByte("mpsz", majsoulTile[1])
+	if num < 0 || num > 9 || suit == -1 || suit == 3 && (num == 0 || num > 7) {
+		return -1, fmt.Errorf("错误的牌 %q", majsoulTile)
+	}
+	if num == 0 {
+		return tenhou6.TileCode(9*suit+4, true), nil
+	}
+	return tenhou6.TileCode(9*suit+num-1, false), nil
+}
+
+func tenhou6TileCodes(majsoulTiles []string) ([]int, error) {
+	codes := make([]int, len(majsoulTiles))
+	for i, majsoulTile := range majsoulTiles {
+		code, err := tenhou6TileCode(majsoulTile)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+func int32sToInts(a []int32) []int {
+	ints := make([]int, len(a))
+	for i, v := range a {
+		ints[i] = int(v)
+	}
+	return ints
+}
+
+// 鸣牌类型，与 RecordChiPengGang RecordAnGangAddGang 中的 type 对应
+const (
+	majsoulMeldTypeChi = iota
+	majsoulMeldTypePon
+	majsoulMeldTypeMinkanOrKakan
+	majsoulMeldTypeAnkan
+)
+
+// 途中流局的类型
+var liujuNames = map[uint32]string{
+	1: "九種九牌",
+	2: "四風連打",
+	3: "四槓散了",
+	4: "四家立直",
+	5: "三家和了",
+}
+
+type tenhou6Converter struct {
+	game  *tenhou6.Game
+	round *tenhou6.Round
+
+	// 庄家配牌的第 14 张牌，视作庄家的第一次摸牌
+	// 若庄家第一次舍牌为摸切，则以舍出的牌作为摸到的牌
+	dealerFirstDraw *tenhou6.Action
+
+	// 最近一次舍牌或加杠的玩家，用于判断放铳者
+	latestActionSeat int
+}
+
+func (c *tenhou6Converter) addAction(a *tenhou6.Action) {
+	c.round.Actions = append(c.round.Actions, a)
+	c.latestActionSeat = a.Who
+}
+
+// 翻开的杠宝牌
+func (c *tenhou6Converter) updateDoras(doras []string) error {
+	for _, dora := range doras[min(len(doras), len(c.round.DoraIndicators)):] {
+		code, err := tenhou6TileCode(dora)
+		if err != nil {
+			return err
+		}
+		c.round.DoraIndicators = append(c.round.DoraIndicators, code)
+	}
+	return nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (c *tenhou6Converter) newRound(msg *lq.RecordNewRound) error {
+	doras := msg.Doras
+	if len(doras) == 0 && msg.Dora != "" {
+		doras = []string{msg.Dora}
+	}
+	r := &tenhou6.Round{
+		RoundNumber:  int(4*msg.Chang + msg.Ju),
+		BenNumber:    int(msg.Ben),
+		RiichiSticks: int(msg.Liqibang),
+		Scores:       make([]int, 4), // 三麻时第四家为 0
+	}
+	copy(r.Scores, int32sToInts(msg.Scores))
+	c.round = r
+	c.dealerFirstDraw = nil
+	if err := c.updateDoras(doras); err != nil {
+		return err
+	}
+	for _, tiles := range [][]string{msg.Tiles0, msg.Tiles1, msg.Tiles2, msg.Tiles3} {
+		hand, err := tenhou6TileCodes(tiles)
+		if err != nil {
+			return err
+		}
+		r.Hands = append(r.Hands, hand)
+	}
+
+	dealer := int(msg.Ju)
+	if hand := r.Hands[dealer]; len(hand) == 14 {
+		r.Hands[dealer] = hand[:13]
+		c.dealerFirstDraw = &tenhou6.Action{Type: tenhou6.ActionTypeDraw, Who: dealer, Tile: hand[13]}
+		c.addAction(c.dealerFirstDraw)
+	}
+	return nil
+}
+
+func (c *tenhou6Converter) discard(msg *lq.RecordDiscardTile) error {
+	code, err := tenhou6TileCode(msg.Tile)
+	if err != nil {
+		return err
+	}
+	seat := int(msg.Seat)
+	if draw := c.dealerFirstDraw; draw != nil && msg.Moqie && draw.Tile != code {
+		// 配牌中的第 14 张牌不一定是最后一张
+		hand := c.round.Hands[seat]
+		for i, tile := range hand {
+			if tile == code {
+				hand[i] = draw.Tile
+				draw.Tile = code
+				break
+			}
+		}
+	}
+	c.dealerFirstDraw = nil
+	c.addAction(&tenhou6.Action{
+		Type:        tenhou6.ActionTypeDiscard,
+		Who:         seat,
+		Tile:        code,
+		IsTsumogiri: msg.Moqie,
+		IsRiichi:    msg.IsLiqi || msg.IsWliqi,
+	})
+	return c.updateDoras(msg.Doras)
+}
+
+func (c *tenhou6Converter) chiPonKan(msg *lq.RecordChiPengGang) error {
+	seat := int(msg.Seat)
+	a := &tenhou6.Action{Who: seat, From: -1}
+	switch len(msg.Tiles) {
+	case 3:
+		a.Type = tenhou6.ActionTypePon
+		if msg.Type == majsoulMeldTypeChi {
+			a.Type = tenhou6.ActionTypeChi
+		}
+	case 4:
+		a.Type = tenhou6.ActionTypeDaiminkan
+	default:
+		return fmt.Errorf("鸣牌数据有误 %v", msg.Tiles)
+	}
+	for i, tile := range msg.Tiles {
+		code, err := tenhou6TileCode(tile)
+		if err != nil {
+			return err
+		}
+		if i < len(msg.Froms) && int(msg.Froms[i]) != seat {
+			a.Tile = code
+			a.From = int(msg.Froms[i])
+		} else {
+			a.SelfTiles = append(a.SelfTiles, code)
+		}
+	}
+	if a.From == -1 {
+		return fmt.Errorf("鸣牌数据有误：未找到被鸣的牌 %v", msg.Tiles)
+	}
+	c.dealerFirstDraw = nil
+	c.addAction(a)
+	return nil
+}
+
+func (c *tenhou6Converter) selfKan(msg *lq.RecordAnGangAddGang) error {
+	seat := int(msg.Seat)
+	code, err := tenhou6TileCode(msg.Tiles)
+	if err != nil {
+		return err
+	}
+	c.dealerFirstDraw = nil
+	switch msg.Type {
+	case majsoulMeldTypeMinkanOrKakan:
+		a, err := c.round.NewKakan(seat, code)
+		if err != nil {
+			return err
+		}
+		c.addAction(a)
+	case majsoulMeldTypeAnkan:
+		tile34, _, _ := tenhou6.ParseTileCode(code)
+		normal := tenhou6.TileCode(tile34, false)
+		selfTiles := []int{normal, normal, normal}
+		if tile34 < 27 && tile34%9 == 4 {
+			// 杠5意味着一定有赤5
+			selfTiles[2] = tenhou6.TileCode(tile34, true)
+		}
+		c.addAction(&tenhou6.Action{Type: tenhou6.ActionTypeAnkan, Who: seat, Tile: normal, SelfTiles: selfTiles, From: seat})
+	default:
+		return fmt.Errorf("未知的杠类型 %d", msg.Type)
+	}
+	return c.updateDoras(msg.Doras)
+}
+
+func (c *tenhou6Converter) hule(msg *lq.RecordHule) error {
+	if err := c.updateDoras(msg.Doras); err != nil {
+		return err
+	}
+
+	result := &tenhou6.Result{Name: tenhou6.ResultNameAgari}
+	totalDeltas := int32sToInts(msg.DeltaScores)
+	for _, hule := range msg.Hules {
+		who := int(hule.Seat)
+		agari := &tenhou6.Agari{Who: who, From: who, Pao: who}
+		if !hule.Zimo {
+			agari.From = c.latestActionSeat
+		}
+
+		// 一炮多响时，各家的点数变化需要拆分，本场和立直棒算在第一个和牌者上
+		if len(msg.Hules) == 1 {
+			agari.Deltas = totalDeltas
+		} else {
+			agari.Deltas = make([]int, len(totalDeltas))
+			if who < len(totalDeltas) && agari.From < len(totalDeltas) {
+				agari.Deltas[who] += int(hule.PointRong)
+				agari.Deltas[agari.From] -= int(hule.PointRong)
+			}
+		}
+
+		title := tenhou6.ScoreTitle(int(hule.Fu), int(hule.Count), hule.Yiman)
+		switch {
+		case !hule.Zimo:
+			agari.Info = append(agari.Info, tenhou6.RonPointsInfo(title, int(hule.PointRong)))
+		case hule.Qinjia:
+			agari.Info = append(agari.Info, tenhou6.TsumoPointsInfo(title, int(hule.PointZimoXian), 0))
+		default:
+			agari.Info = append(agari.Info, tenhou6.TsumoPointsInfo(title, int(hule.PointZimoXian), int(hule.PointZimoQin)))
+		}
+		for _, fan := range hule.Fans {
+			if fan.Val > 0 {
+				agari.Info = append(agari.Info, tenhou6.YakuInfo(fan.Name, int(fan.Val), hule.Yiman))
+			}
+		}
+		result.Agaris = append(result.Agaris, agari)
+
+		if hule.Liqi && c.round.UraDoraIndicators == nil {
+			uraDoras, err := tenhou6TileCodes(hule.LiDoras)
+			if err != nil {
+				return err
+			}
+			c.round.UraDoraIndicators = uraDoras
+		}
+	}
+
+	if len(result.Agaris) > 1 {
+		first := result.Agaris[0]
+		for i := range first.Deltas {
+			first.Deltas[i] = totalDeltas[i]
+			for _, agari := range result.Agaris[1:] {
+				first.Deltas[i] -= agari.Deltas[i]
+			}
+		}
+	}
+
+	c.round.Result = result
+	return nil
+}
+
+func (c *tenhou6Converter) noTile(msg *lq.RecordNoTile) {
+	result := &tenhou6.Result{Name: "流局"}
+	numTenpai := 0
+	for _, player := range msg.Players {
+		if player.Tingpai {
+			numTenpai++
+		}
+	}
+	switch {
+	case msg.Liujumanguan:
+		result.Name = "流し満貫"
+	case numTenpai == len(msg.Players):
+		result.Name = "全員聴牌"
+	case numTenpai == 0:
+		result.Name = "全員不聴"
+	}
+	for _, score := range msg.Scores {
+		deltas := int32sToInts(score.DeltaScores)
+		if result.Deltas == nil {
+			result.Deltas = make([]int, len(deltas))
+		}
+		for i := 0; i < len(deltas) && i < len(result.Deltas); i++ {
+			result.Deltas[i] += deltas[i]
+		}
+	}
+	c.round.Result = result
+}
+
+func (c *tenhou6Converter) convert(detail *RecordDetail) (err error) {
+	if _, ok := detail.Data.(*lq.RecordNewRound); !ok && c.round == nil {
+		return fmt.Errorf("牌谱数据有误：%s 出现在 RecordNewRound 之前", detail.Name)
+	}
+
+	switch msg := detail.Data.(type) {
+	case *lq.RecordNewRound:
+		return c.newRound(msg)
+	case *lq.RecordDealTile:
+		code, err := tenhou6TileCode(msg.Tile)
+		if err != nil {
+			return err
+		}
+		c.dealerFirstDraw = nil
+		c.addAction(&tenhou6.Action{Type: tenhou6.ActionTypeDraw, Who: int(msg.Seat), Tile: code})
+		return c.updateDoras(msg.Doras)
+	case *lq.RecordDiscardTile:
+		return c.discard(msg)
+	case *lq.RecordChiPengGang:
+		return c.chiPonKan(msg)
+	case *lq.RecordAnGangAddGang:
+		return c.selfKan(msg)
+	case *lq.RecordBaBei:
+		c.dealerFirstDraw = nil
+		c.addAction(&tenhou6.Action{Type: tenhou6.ActionTypeNuki, Who: int(msg.Seat), Tile: tenhou6.TileCode(30, false)})
+		return c.updateDoras(msg.Doras)
+	case *lq.RecordHule:
+		err = c.hule(msg)
+	case *lq.RecordNoTile:
+		c.noTile(msg)
+	case *lq.RecordLiuJu:
+		name, ok := liujuNames[msg.Type]
+		if !ok {
+			name = "流局"
+		}
+		c.round.Result = &tenhou6.Result{Name: name}
+	default:
+		return nil
+	}
+
+	// 本局结束
+	c.game.Rounds = append(c.game.Rounds, c.round)
+	c.round = nil
+	return
+}
+
+// 雀魂的规则，如 "雀魂四人南喰赤"
+func (r *Record) tenhou6Rule(playerNumber int) tenhou6.Rule {
+	disp := "雀魂"
+	if playerNumber == 3 {
+		disp += "三人"
+	} else {
+		disp += "四人"
+	}
+	mode := r.Head.GetConfig().GetMode()
+	switch mode.GetMode() % 10 {
+	case 1:
+		disp += "東"
+	case 2:
+		disp += "南"
+	}
+	rule := tenhou6.Rule{Disp: disp + "喰", Aka: 1}
+	if detailRule := mode.GetDetailRule(); detailRule != nil && detailRule.DoraCount == 0 {
+		rule.Aka = 0
+	} else {
+		rule.Disp += "赤"
+	}
+	return rule
+}
+
+// 转换成 tenhou.net/6 格式
+func (r *Record) ToTenhou6() (*tenhou6.Game, error) {
+	c := &tenhou6Converter{game: &tenhou6.Game{}}
+	for _, detail := range r.Details {
+		if err := c.convert(detail); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.game.Rounds) == 0 {
+		return nil, fmt.Errorf("牌谱中没有完整的对局数据")
+	}
+
+	g := c.game
+	if r.Head != nil {
+		g.Title = []string{r.Head.Uuid, time.Unix(int64(r.Head.StartTime), 0).Format("2006/01/02 15:04")}
+	}
+	g.Names = make([]string, 4)
+	for _, account := range r.Head.GetAccounts() {
+		if account.Seat < 4 {
+			g.Names[account.Seat] = account.Nickname
+		}
+	}
+	g.Rule = r.tenhou6Rule(g.PlayerNumber())
+	return g, nil
+}
@@ -0,0 +1,121 @@
+package majsoul
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou6"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testRecord = `{
+  "head": {"uuid": "test", "start_time": 1577836800, "config": {"mode": {"mode": 2}},
+    "accounts": [{"seat": 0, "nickname": "A"}, {"seat": 1, "nickname": "B"}, {"seat": 2, "nickname": "C"}, {"seat": 3, "nickname": "D"}]},
+  "details": [
+    {"name": "RecordNewRound", "data": {"doras": ["1m"], "scores": [25000, 25000, 25000, 25000],
+      "tiles0": ["1m", "2m", "3m", "4m", "0m", "6m", "7m", "8m", "9m", "1p", "2p", "3p", "4p", "5z"],
+      "tiles1": ["1s", "2s", "3s", "4s", "5s", "6s", "7s", "8s", "9s", "1z", "2z", "3z", "4z"],
+      "tiles2": ["1m", "2m", "3m", "4m", "5m", "6m", "7m", "8m", "9m", "1z", "2z", "3z", "4z"],
+      "tiles3": ["1p", "2p", "3p", "4p", "5p", "6p", "7p", "8p", "9p", "5z", "5z", "6z", "6z"]}},
+    {"name": "RecordDiscardTile", "data": {"tile": "1p", "moqie": true}},
+    {"name": "RecordDealTile", "data": {"seat": 1, "tile": "5z"}},
+    {"name": "RecordDiscardTile", "data": {"seat": 1, "tile": "5z", "moqie": true}},
+    {"name": "RecordChiPengGang", "data": {"seat": 3, "type": 1, "tiles": ["5z", "5z", "5z"], "froms": [3, 3, 1]}},
+    {"name": "RecordDiscardTile", "data": {"seat": 3, "tile": "9p"}},
+    {"name": "RecordDealTile", "data": {"tile": "7z"}},
+    {"name": "RecordAnGangAddGang", "data": {"type": 3, "tiles": "5p", "doras": ["1m", "2m"]}},
+    {"name": "RecordDealTile", "data": {"tile": "9s"}},
+    {"name": "RecordDiscardTile", "data": {"tile": "9s", "moqie": true, "is_liqi": true}},
+    {"name": "RecordDealTile", "data": {"seat": 1, "tile": "3s"}},
+    {"name": "RecordDiscardTile", "data": {"seat": 1, "tile": "3s", "moqie": true}},
+    {"name": "RecordHule", "data": {"hules": [{"seat": 0, "hu_tile": "3s", "liqi": true, "li_doras": ["6s", "7s"], "count": 2, "fu": 40, "point_rong": 2600,
+      "fans": [{"name": "立直", "val": 1}, {"name": "宝牌", "val": 1}, {"name": "里宝牌", "val": 0}]}], "delta_scores": [2600, -2600, 0, 0]}}
+  ]
+}`
+
+func TestRecord_ToTenhou6(t *testing.T) {
+	assert := assert.New(t)
+
+	record, err := ParseRecord([]byte(testRecord))
+	if !assert.NoError(err) {
+		return
+	}
+	g, err := record.ToTenhou6()
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"A", "B", "C", "D"}, g.Names)
+	assert.Equal("雀魂四人南喰赤", g.Rule.Disp)
+	if !assert.Len(g.Rounds, 1) {
+		return
+	}
+
+	r := g.Rounds[0]
+	assert.Equal([]int{11, 12}, r.DoraIndicators)
+	assert.Equal([]int{36, 37}, r.UraDoraIndicators)
+	// 庄家第一次舍牌为摸切，配牌中的 1p 视作摸到的牌
+	assert.Equal([]int{11, 12, 13, 14, 51, 16, 17, 18, 19, 45, 22, 23, 24}, r.Hands[0])
+	assert.Equal(&tenhou6.Action{Type: tenhou6.ActionTypeDraw, Tile: 21}, r.Actions[0])
+	assert.Equal(&tenhou6.Action{Type: tenhou6.ActionTypePon, Who: 3, Tile: 45, SelfTiles: []int{45, 45}, From: 1}, r.Actions[4])
+	assert.Equal(&tenhou6.Action{Type: tenhou6.ActionTypeAnkan, Tile: 25, SelfTiles: []int{25, 25, 52}}, r.Actions[7])
+	assert.Equal(&tenhou6.Result{
+		Name: tenhou6.ResultNameAgari,
+		Agaris: []*tenhou6.Agari{{
+			Who:    0,
+			From:   1,
+			Pao:    0,
+			Deltas: []int{2600, -2600, 0, 0},
+			Info:   []string{"40符2飜2600点", "立直(1飜)", "宝牌(1飜)"},
+		}},
+	}, r.Result)
+
+	data, err := g.Marshal()
+	if !assert.NoError(err) {
+		return
+	}
+	parsedGame, err := tenhou6.Parse(data)
+	if assert.NoError(err) {
+		assert.Equal(g.Rounds, parsedGame.Rounds)
+	}
+}
+
+func TestParseRawRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	record, err := ParseRecord([]byte(testRecord))
+	if !assert.NoError(err) {
+		return
+	}
+
+	// 构造 data_url 返回的二进制数据
+	detailRecords := &lq.GameDetailRecords{}
+	for _, detail := range record.Details {
+		detailData, err := api.WrapMessage(".lq."+detail.Name, detail.Data)
+		if !assert.NoError(err) {
+			return
+		}
+		detailRecords.Records = append(detailRecords.Records, detailData)
+	}
+	data, err := api.WrapMessage("", detailRecords)
+	if !assert.NoError(err) {
+		return
+	}
+
+	rawRecord, err := ParseRawRecord(data)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Nil(rawRecord.Head)
+	assert.Equal(record.Details, rawRecord.Details)
+
+	g, err := record.ToTenhou6()
+	assert.NoError(err)
+	rawGame, err := rawRecord.ToTenhou6()
+	if assert.NoError(err) {
+		assert.Empty(rawGame.Title)
+		assert.Equal(g.Rounds, rawGame.Rounds)
+	}
+
+	_, err = ParseRawRecord([]byte("not a record"))
+	assert.Error(err)
+}
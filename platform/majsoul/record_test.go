@@ -1,8 +1,14 @@
 package majsoul
 
 import (
+	"context"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/api/apitest"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDownloadRecords(t *testing.T) {
@@ -20,3 +26,126 @@ func TestDownloadRecords(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDownload(t *testing.T) {
+	assert := assert.New(t)
+
+	server := apitest.NewServer()
+	defer server.Close()
+	server.HandleLogin(apitest.NewTestAccount())
+	store := apitest.NewRecordStore(25)
+	if !assert.NoError(server.HandleRecords(store)) {
+		return
+	}
+	records := store.Records()
+
+	dir, err := ioutil.TempDir("", "majsoul-records")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	config := &DownloadConfig{
+		Username:      "user",
+		Password:      "password",
+		Dir:           dir,
+		Concurrency:   3,
+		Endpoint:      server.WebSocketURL,
+		ClientVersion: "0.0.0.w",
+	}
+
+	// 登录失败
+	badConfig := *config
+	badConfig.Username = "nobody"
+	_, err = Download(context.Background(), &badConfig)
+	assert.Error(err)
+
+	// 首次下载全部牌谱，其中一个失败
+	store.SetFailed(records[20].Uuid, true)
+	n, err := Download(context.Background(), config)
+	assert.Error(err)
+	assert.Equal(24, n)
+	assert.Equal(3, server.NumCalls(".lq.Lobby.fetchGameRecordList"))
+	_, err = os.Stat(filepath.Join(dir, records[20].Uuid+".json"))
+	assert.True(os.IsNotExist(err))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, records[0].Uuid+".json"))
+	assert.NoError(err)
+	record, err := ParseRecord(data)
+	if assert.NoError(err) {
+		assert.Equal(records[0].Uuid, record.Head.Uuid)
+		assert.Equal("RecordNewRound", record.Details[0].Name)
+	}
+
+	// 增量下载：只获取第一页列表，并重试之前失败的牌谱
+	server.ResetCalls()
+	store.SetFailed(records[20].Uuid, false)
+	store.AddRecords(3)
+	records = store.Records()
+	n, err = Download(context.Background(), config)
+	assert.NoError(err)
+	assert.Equal(4, n)
+	assert.Equal(1, server.NumCalls(".lq.Lobby.fetchGameRecordList"))
+	assert.Equal(4, server.NumCalls(".lq.Lobby.fetchGameRecord"))
+
+	// 没有新牌谱，用 access token 登录
+	server.ResetCalls()
+	tokenConfig := *config
+	tokenConfig.Username, tokenConfig.Password = "", ""
+	tokenConfig.AccessToken = "token"
+	n, err = Download(context.Background(), &tokenConfig)
+	assert.NoError(err)
+	assert.Zero(n)
+	assert.Equal(1, server.NumCalls(".lq.Lobby.oauth2Login"))
+	assert.Zero(server.NumCalls(".lq.Lobby.login"))
+	assert.Zero(server.NumCalls(".lq.Lobby.fetchGameRecord"))
+
+	// 收藏的牌谱，已下载的不再下载
+	collectedDir, err := ioutil.TempDir("", "majsoul-collected")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(collectedDir)
+	store.SetCollected([]string{records[1].Uuid, records[5].Uuid, records[7].Uuid})
+	collectedConfig := *config
+	collectedConfig.Collected = true
+	collectedConfig.Dir = collectedDir
+	n, err = Download(context.Background(), &collectedConfig)
+	assert.NoError(err)
+	assert.Equal(3, n)
+	n, err = Download(context.Background(), &collectedConfig)
+	assert.NoError(err)
+	assert.Zero(n)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.Len(files, 28+1) // 含索引文件
+}
+
+func TestRecordIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "majsoul-index")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	index, err := loadRecordIndex(dir)
+	assert.NoError(err)
+	assert.Empty(index.Downloaded)
+
+	index.Downloaded["a"] = 1
+	index.Pending["b"] = &lq.RecordGame{Uuid: "b", EndTime: 2}
+	index.ListComplete["type-0"] = true
+	assert.NoError(index.save(dir))
+
+	index2, err := loadRecordIndex(dir)
+	assert.NoError(err)
+	assert.Equal(index, index2)
+
+	// 索引中有记录但文件不存在，视作未下载
+	assert.False(index2.isDownloaded(dir, "a"))
+	assert.NoError(ioutil.WriteFile(recordFilePath(dir, "a"), []byte("{}"), 0644))
+	assert.True(index2.isDownloaded(dir, "a"))
+
+}
@@ -0,0 +1,81 @@
+// 比较两个版本的雀魂 liqi.json，并根据新版本重新生成 liqi.proto 和 liqi_api.go
+//
+// 在仓库根目录下执行：
+//
+//	go run ./platform/majsoul/tool/cmd/liqi -new 新的liqi.json [-gen]
+//
+// 有不兼容的变化（影响助手解析对局或牌谱数据）时，退出码为 1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/tool"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var (
+	oldFilePath string
+	newFilePath string
+	gen         bool
+	protoDir    string
+	apiFilePath string
+)
+
+func init() {
+	flag.StringVar(&oldFilePath, "old", "platform/majsoul/proto/lq/liqi.json", "旧版本的 liqi.json")
+	flag.StringVar(&newFilePath, "new", "", "新版本的 liqi.json")
+	flag.BoolVar(&gen, "gen", false, "用新版本的 liqi.json 覆盖旧版本，并重新生成 liqi.proto 和 liqi_api.go")
+	flag.StringVar(&protoDir, "proto-dir", "platform/majsoul/proto/lq", "liqi.json 和 liqi.proto 所在目录")
+	flag.StringVar(&apiFilePath, "api", "platform/majsoul/api/liqi_api.go", "生成的 API 文件")
+}
+
+func run() (numBreaking int, err error) {
+	if newFilePath == "" {
+		return 0, fmt.Errorf("请用 -new 指定新版本的 liqi.json")
+	}
+	oldContent, err := ioutil.ReadFile(oldFilePath)
+	if err != nil {
+		return
+	}
+	newContent, err := ioutil.ReadFile(newFilePath)
+	if err != nil {
+		return
+	}
+
+	changes, err := tool.DiffLiqiJson(oldContent, newContent)
+	if err != nil {
+		return
+	}
+	numBreaking = tool.PrintLiqiChanges(os.Stdout, changes)
+
+	if !gen {
+		return
+	}
+	if err = ioutil.WriteFile(filepath.Join(protoDir, "liqi.json"), newContent, 0644); err != nil {
+		return
+	}
+	if err = tool.LiqiJsonToProto3(newContent, filepath.Join(protoDir, "liqi.proto")); err != nil {
+		return
+	}
+	if err = tool.GenLiqiAPI(newContent, apiFilePath); err != nil {
+		return
+	}
+	fmt.Printf("已生成 liqi.proto 和 %s，请在 %s 目录下执行 make 重新生成 liqi.pb.go\n", apiFilePath, protoDir)
+	return
+}
+
+func main() {
+	flag.Parse()
+
+	numBreaking, err := run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if numBreaking > 0 {
+		os.Exit(1)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"io/ioutil"
 )
 
+const UserAgent = "Mozilla/5.0 AppleWebKit/530.00 (KHTML, like Gecko) Chrome/75.0.3120.123 Safari/531.21"
+
 func newReqOpt() *grequests.RequestOptions {
 	return &grequests.RequestOptions{
-		UserAgent: "Mozilla/5.0 AppleWebKit/530.00 (KHTML, like Gecko) Chrome/75.0.3120.123 Safari/531.21",
+		UserAgent: UserAgent,
 	}
 }
 
@@ -24,6 +26,7 @@ func get(url string, userStruct interface{}) (err error) {
 	return resp.JSON(userStruct)
 }
 
+// 非并发安全（grequests 的限制），并发下载请使用共享的 http.Client
 func Fetch(url string) (content []byte, err error) {
 	resp, err := grequests.Get(url, newReqOpt())
 	if err != nil {
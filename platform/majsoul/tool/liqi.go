@@ -164,8 +164,12 @@ func (c *converter) parseEnums(enums map[string]interface{}) error {
 	for k, v := range enums {
 		pairs = append(pairs, kv{k, int(v.(float64))})
 	}
+	// 值相同时按名称排序，保证多次生成的结果相同
 	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].v < pairs[j].v
+		if pairs[i].v != pairs[j].v {
+			return pairs[i].v < pairs[j].v
+		}
+		return pairs[i].k < pairs[j].k
 	})
 	for _, pair := range pairs {
 		c.addLine(fmt.Sprintf("%s = %d;", pair.k, pair.v))
@@ -261,3 +265,55 @@ func LiqiJsonToProto3(liqiJsonContent []byte, protoFilePath string) (err error)
 	}
 	return ioutil.WriteFile(protoFilePath, content, 0644)
 }
+
+// 根据 liqi.json 中的 service 生成 api.WebSocketClient 的方法
+func liqiJsonToAPI(liqiJsonContent []byte) (apiContent []byte, err error) {
+	c := newConverter()
+	if _, err = c.LiqiJsonToProto3(liqiJsonContent); err != nil {
+		return
+	}
+
+	apiBB := bytes.Buffer{}
+	apiBB.WriteString(`// Code generated by tool/liqi.go. DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul/proto/lq"
+)
+`)
+	for _, service := range c.rpcServiceList {
+		name := service.name
+		for _, method := range service.methods {
+			format := `
+func (c *WebSocketClient) %s(ctx context.Context, req *lq.%s) (resp *lq.%s, err error) {
+	resp = &lq.%s{}
+	if err = c.call(ctx, ".lq.%s.%s", req, resp); err != nil {
+		return nil, err
+	}`
+			if _, ok := c.messageContainError[method.responseType]; ok {
+				format += `
+	if resp.Error != nil {
+		err = fmt.Errorf("majsoul error: %%s", resp.Error.String())
+	}`
+			}
+			format += `
+	return
+}
+`
+			apiBB.WriteString(fmt.Sprintf(format,
+				strings.Title(method.name), method.requestType, method.responseType,
+				method.responseType, name, method.name))
+		}
+	}
+	return apiBB.Bytes(), nil
+}
+
+func GenLiqiAPI(liqiJsonContent []byte, apiFilePath string) (err error) {
+	content, err := liqiJsonToAPI(liqiJsonContent)
+	if err != nil {
+		return
+	}
+	return ioutil.WriteFile(apiFilePath, content, 0644)
+}
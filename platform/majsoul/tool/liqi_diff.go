@@ -0,0 +1,530 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type liqiField struct {
+	name  string
+	id    int
+	type_ string
+	rule  string // 为空或 repeated 等
+}
+
+func (f *liqiField) typeString() string {
+	if f.rule != "" {
+		return f.rule + " " + f.type_
+	}
+	return f.type_
+}
+
+type liqiMessage struct {
+	name   string // 嵌套的 message 为 Parent.Child
+	fields map[string]*liqiField
+}
+
+// 字段的 id 和类型，用于识别重命名
+func (m *liqiMessage) signature() string {
+	fields := []string{}
+	for _, f := range m.fields {
+		fields = append(fields, fmt.Sprintf("%d:%s:%s", f.id, f.typeString(), f.name))
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ",")
+}
+
+type liqiMethod struct {
+	service      string
+	name         string
+	requestType  string
+	responseType string
+}
+
+func (m *liqiMethod) fullName() string {
+	return m.service + "." + m.name
+}
+
+// liqi.json 中与协议兼容性相关的内容
+type liqiSchema struct {
+	messages map[string]*liqiMessage
+	enums    map[string]map[string]int
+	methods  map[string]*liqiMethod // Service.method -> method
+}
+
+func parseLiqiSchema(liqiJsonContent []byte) (*liqiSchema, error) {
+	lq := liqi{}
+	if err := json.Unmarshal(liqiJsonContent, &lq); err != nil {
+		return nil, err
+	}
+	schema := &liqiSchema{
+		messages: map[string]*liqiMessage{},
+		enums:    map[string]map[string]int{},
+		methods:  map[string]*liqiMethod{},
+	}
+	for name, item := range lq.Nested.LQ.Nested {
+		if err := schema.addItem(name, item); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+func (s *liqiSchema) addItem(name string, item protoItem) error {
+	if methods, ok := item["methods"]; ok {
+		for methodName, rawMethod := range methods {
+			method, ok := rawMethod.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("解析 %s.%s 失败", name, methodName)
+			}
+			m := &liqiMethod{service: name, name: methodName}
+			m.requestType, _ = method["requestType"].(string)
+			m.responseType, _ = method["responseType"].(string)
+			s.methods[m.fullName()] = m
+		}
+	}
+
+	if values, ok := item["values"]; ok {
+		enum := map[string]int{}
+		for k, v := range values {
+			_v, ok := v.(float64)
+			if !ok {
+				return fmt.Errorf("解析 %s.%s 失败", name, k)
+			}
+			enum[k] = int(_v)
+		}
+		s.enums[name] = enum
+	}
+
+	if fields, ok := item["fields"]; ok {
+		message := &liqiMessage{name: name, fields: map[string]*liqiField{}}
+		for fieldName, rawField := range fields {
+			field, ok := rawField.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("解析 %s.%s 失败", name, fieldName)
+			}
+			f := &liqiField{name: fieldName}
+			id, _ := field["id"].(float64)
+			f.id = int(id)
+			f.type_, _ = field["type"].(string)
+			f.rule, _ = field["rule"].(string)
+			message.fields[fieldName] = f
+		}
+		s.messages[name] = message
+	}
+
+	if nestedItems, ok := item["nested"]; ok {
+		data, err := json.Marshal(nestedItems)
+		if err != nil {
+			return err
+		}
+		nested := map[string]protoItem{}
+		if err := json.Unmarshal(data, &nested); err != nil {
+			return err
+		}
+		for _name, _item := range nested {
+			if err := s.addItem(name+"."+_name, _item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 在 message scope 中查找字段类型对应的 message 名称，未找到（如 uint32）时返回空
+func (s *liqiSchema) resolveType(scope string, type_ string) string {
+	for {
+		name := type_
+		if scope != "" {
+			name = scope + "." + type_
+		}
+		if _, ok := s.messages[name]; ok {
+			return name
+		}
+		if _, ok := s.enums[name]; ok {
+			return name
+		}
+		if scope == "" {
+			return ""
+		}
+		if i := strings.LastIndexByte(scope, '.'); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+// 助手直接解析的响应和通知，见 majsoul_frame.go 和 record.go
+var liqiDependedMessages = map[string]bool{
+	"ResLogin":                  true,
+	"ResAuthGame":               true,
+	"ResEnterGame":              true,
+	"ResSyncGame":               true,
+	"ResGameRecordList":         true,
+	"ResGameRecord":             true,
+	"ResGameRecordsDetail":      true,
+	"NotifyPlayerLoadGameReady": true,
+}
+
+// 助手直接解析的类型：对局中的操作（ActionPrototype 及 Action*）、牌谱（GameDetailRecords 及 Record*）等
+func isLiqiRootDependedType(name string) bool {
+	topName := strings.SplitN(name, ".", 2)[0]
+	return topName == "ActionPrototype" || topName == "GameDetailRecords" ||
+		strings.HasPrefix(topName, "Action") || strings.HasPrefix(topName, "Record") ||
+		liqiDependedMessages[topName]
+}
+
+// 助手依赖的类型，包含这些类型的字段所引用的类型
+func (s *liqiSchema) dependedTypes() map[string]bool {
+	depended := map[string]bool{}
+	queue := []string{}
+	for name := range s.messages {
+		if isLiqiRootDependedType(name) {
+			depended[name] = true
+			queue = append(queue, name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, f := range s.messages[name].fields {
+			if t := s.resolveType(name, f.type_); t != "" && !depended[t] {
+				depended[t] = true
+				if _, ok := s.messages[t]; ok {
+					queue = append(queue, t)
+				}
+			}
+		}
+	}
+	return depended
+}
+
+const (
+	LiqiChangeAdded   = "新增"
+	LiqiChangeRemoved = "删除"
+	LiqiChangeRenamed = "重命名"
+	LiqiChangeChanged = "修改"
+)
+
+// liqi.json 的一处变化
+type LiqiChange struct {
+	Kind     string // 新增 删除 重命名 修改
+	Category string // message field enum method
+	Name     string // 如 ActionPrototype、ActionPrototype.step、Lobby.login
+	Detail   string
+
+	// 是否会影响助手解析对局或牌谱数据
+	Breaking bool
+}
+
+func (c *LiqiChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Kind, c.Category, c.Name)
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	if c.Breaking {
+		s = "[不兼容] " + s
+	}
+	return s
+}
+
+type liqiDiffer struct {
+	old, new *liqiSchema
+	depended map[string]bool
+	changes  []*LiqiChange
+}
+
+func (d *liqiDiffer) add(kind string, category string, name string, detail string, depended bool) {
+	d.changes = append(d.changes, &LiqiChange{
+		Kind:     kind,
+		Category: category,
+		Name:     name,
+		Detail:   detail,
+		Breaking: kind != LiqiChangeAdded && depended,
+	})
+}
+
+// 请求或响应是助手依赖的类型
+func (d *liqiDiffer) isMethodDepended(m *liqiMethod) bool {
+	return d.depended[m.requestType] || d.depended[m.responseType]
+}
+
+// 从 removed 和 added 中找出 key 相同且唯一的一对，视作重命名
+func matchRenames(removed []string, added []string, key func(isOld bool, name string) string) (renames map[string]string) {
+	count := func(isOld bool, names []string) map[string][]string {
+		m := map[string][]string{}
+		for _, name := range names {
+			k := key(isOld, name)
+			m[k] = append(m[k], name)
+		}
+		return m
+	}
+	removedKeys := count(true, removed)
+	addedKeys := count(false, added)
+	renames = map[string]string{}
+	for k, oldNames := range removedKeys {
+		if newNames := addedKeys[k]; len(oldNames) == 1 && len(newNames) == 1 {
+			renames[oldNames[0]] = newNames[0]
+		}
+	}
+	return
+}
+
+// 返回 old 和 new 中只在一方出现的 key，均已排序
+func diffKeys(oldKeys []string, newKeys []string) (removed []string, added []string) {
+	inOld := map[string]bool{}
+	for _, k := range oldKeys {
+		inOld[k] = true
+	}
+	inNew := map[string]bool{}
+	for _, k := range newKeys {
+		inNew[k] = true
+		if !inOld[k] {
+			added = append(added, k)
+		}
+	}
+	for _, k := range oldKeys {
+		if !inNew[k] {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	return
+}
+
+func (d *liqiDiffer) diffMessages() {
+	oldNames := []string{}
+	for name := range d.old.messages {
+		oldNames = append(oldNames, name)
+	}
+	newNames := []string{}
+	for name := range d.new.messages {
+		newNames = append(newNames, name)
+	}
+	removed, added := diffKeys(oldNames, newNames)
+
+	renames := matchRenames(removed, added, func(isOld bool, name string) string {
+		if isOld {
+			return d.old.messages[name].signature()
+		}
+		return d.new.messages[name].signature()
+	})
+	renamed := map[string]bool{}
+	for _, name := range removed {
+		if newName, ok := renames[name]; ok && d.old.messages[name].signature() != "" {
+			d.add(LiqiChangeRenamed, "message", name, name+" -> "+newName, d.depended[name])
+			renamed[newName] = true
+		} else {
+			d.add(LiqiChangeRemoved, "message", name, "", d.depended[name])
+		}
+	}
+	for _, name := range added {
+		if !renamed[name] {
+			d.add(LiqiChangeAdded, "message", name, "", d.depended[name])
+		}
+	}
+
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		if newMessage, ok := d.new.messages[name]; ok {
+			d.diffFields(d.old.messages[name], newMessage)
+		}
+	}
+}
+
+func (d *liqiDiffer) diffFields(oldMessage *liqiMessage, newMessage *liqiMessage) {
+	name := oldMessage.name
+	oldNames := []string{}
+	for fieldName := range oldMessage.fields {
+		oldNames = append(oldNames, fieldName)
+	}
+	newNames := []string{}
+	for fieldName := range newMessage.fields {
+		newNames = append(newNames, fieldName)
+	}
+	removed, added := diffKeys(oldNames, newNames)
+
+	// id 相同的字段视作重命名
+	renames := matchRenames(removed, added, func(isOld bool, fieldName string) string {
+		if isOld {
+			return fmt.Sprint(oldMessage.fields[fieldName].id)
+		}
+		return fmt.Sprint(newMessage.fields[fieldName].id)
+	})
+	renamed := map[string]bool{}
+	for _, fieldName := range removed {
+		oldField := oldMessage.fields[fieldName]
+		if newName, ok := renames[fieldName]; ok {
+			newField := newMessage.fields[newName]
+			detail := fmt.Sprintf("%s -> %s", fieldName, newName)
+			if oldField.typeString() != newField.typeString() {
+				detail += fmt.Sprintf("，类型 %s -> %s", oldField.typeString(), newField.typeString())
+			}
+			d.add(LiqiChangeRenamed, "field", name+"."+fieldName, detail, d.depended[name])
+			renamed[newName] = true
+		} else {
+			d.add(LiqiChangeRemoved, "field", name+"."+fieldName, fmt.Sprintf("%s = %d", oldField.typeString(), oldField.id), d.depended[name])
+		}
+	}
+	for _, fieldName := range added {
+		if !renamed[fieldName] {
+			newField := newMessage.fields[fieldName]
+			d.add(LiqiChangeAdded, "field", name+"."+fieldName, fmt.Sprintf("%s = %d", newField.typeString(), newField.id), d.depended[name])
+		}
+	}
+
+	sort.Strings(oldNames)
+	for _, fieldName := range oldNames {
+		newField, ok := newMessage.fields[fieldName]
+		if !ok {
+			continue
+		}
+		oldField := oldMessage.fields[fieldName]
+		details := []string{}
+		if oldField.typeString() != newField.typeString() {
+			details = append(details, fmt.Sprintf("类型 %s -> %s", oldField.typeString(), newField.typeString()))
+		}
+		if oldField.id != newField.id {
+			details = append(details, fmt.Sprintf("id %d -> %d", oldField.id, newField.id))
+		}
+		if len(details) > 0 {
+			d.add(LiqiChangeChanged, "field", name+"."+fieldName, strings.Join(details, "，"), d.depended[name])
+		}
+	}
+}
+
+func (d *liqiDiffer) diffEnums() {
+	oldNames := []string{}
+	for name := range d.old.enums {
+		oldNames = append(oldNames, name)
+	}
+	newNames := []string{}
+	for name := range d.new.enums {
+		newNames = append(newNames, name)
+	}
+	removed, added := diffKeys(oldNames, newNames)
+	for _, name := range removed {
+		d.add(LiqiChangeRemoved, "enum", name, "", d.depended[name])
+	}
+	for _, name := range added {
+		d.add(LiqiChangeAdded, "enum", name, "", d.depended[name])
+	}
+
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		newEnum, ok := d.new.enums[name]
+		if !ok {
+			continue
+		}
+		oldEnum := d.old.enums[name]
+		oldKeys := []string{}
+		for k := range oldEnum {
+			oldKeys = append(oldKeys, k)
+		}
+		newKeys := []string{}
+		for k := range newEnum {
+			newKeys = append(newKeys, k)
+		}
+		removedKeys, addedKeys := diffKeys(oldKeys, newKeys)
+		for _, k := range removedKeys {
+			d.add(LiqiChangeRemoved, "enum", name+"."+k, fmt.Sprint(oldEnum[k]), d.depended[name])
+		}
+		for _, k := range addedKeys {
+			d.add(LiqiChangeAdded, "enum", name+"."+k, fmt.Sprint(newEnum[k]), d.depended[name])
+		}
+		sort.Strings(oldKeys)
+		for _, k := range oldKeys {
+			if v, ok := newEnum[k]; ok && v != oldEnum[k] {
+				d.add(LiqiChangeChanged, "enum", name+"."+k, fmt.Sprintf("%d -> %d", oldEnum[k], v), d.depended[name])
+			}
+		}
+	}
+}
+
+func (d *liqiDiffer) diffMethods() {
+	oldNames := []string{}
+	for name := range d.old.methods {
+		oldNames = append(oldNames, name)
+	}
+	newNames := []string{}
+	for name := range d.new.methods {
+		newNames = append(newNames, name)
+	}
+	removed, added := diffKeys(oldNames, newNames)
+
+	// 同一 service 中请求和响应类型都相同的方法视作重命名
+	renames := matchRenames(removed, added, func(isOld bool, name string) string {
+		m := d.new.methods[name]
+		if isOld {
+			m = d.old.methods[name]
+		}
+		return m.service + " " + m.requestType + " " + m.responseType
+	})
+	renamed := map[string]bool{}
+	for _, name := range removed {
+		if newName, ok := renames[name]; ok {
+			d.add(LiqiChangeRenamed, "method", name, name+" -> "+newName, d.isMethodDepended(d.old.methods[name]))
+			renamed[newName] = true
+		} else {
+			d.add(LiqiChangeRemoved, "method", name, "", d.isMethodDepended(d.old.methods[name]))
+		}
+	}
+	for _, name := range added {
+		if !renamed[name] {
+			m := d.new.methods[name]
+			d.add(LiqiChangeAdded, "method", name, fmt.Sprintf("(%s) returns (%s)", m.requestType, m.responseType), false)
+		}
+	}
+
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		newMethod, ok := d.new.methods[name]
+		if !ok {
+			continue
+		}
+		oldMethod := d.old.methods[name]
+		if oldMethod.requestType != newMethod.requestType || oldMethod.responseType != newMethod.responseType {
+			d.add(LiqiChangeChanged, "method", name, fmt.Sprintf("(%s) returns (%s) -> (%s) returns (%s)",
+				oldMethod.requestType, oldMethod.responseType, newMethod.requestType, newMethod.responseType), d.isMethodDepended(oldMethod))
+		}
+	}
+}
+
+// 比较两个版本的 liqi.json，返回 message、字段、enum 和 RPC 方法的变化
+// 删除、重命名或修改了助手依赖的类型（ActionPrototype、Record*、GameDetailRecords 等）时，Breaking 为 true
+func DiffLiqiJson(oldLiqiJsonContent []byte, newLiqiJsonContent []byte) (changes []*LiqiChange, err error) {
+	oldSchema, err := parseLiqiSchema(oldLiqiJsonContent)
+	if err != nil {
+		return
+	}
+	newSchema, err := parseLiqiSchema(newLiqiJsonContent)
+	if err != nil {
+		return
+	}
+	d := &liqiDiffer{old: oldSchema, new: newSchema, depended: oldSchema.dependedTypes()}
+	d.diffMessages()
+	d.diffEnums()
+	d.diffMethods()
+	return d.changes, nil
+}
+
+// 输出 changes，返回不兼容的变化数
+func PrintLiqiChanges(w io.Writer, changes []*LiqiChange) (numBreaking int) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "liqi.json 没有变化")
+		return
+	}
+	for _, c := range changes {
+		fmt.Fprintln(w, c)
+		if c.Breaking {
+			numBreaking++
+		}
+	}
+	fmt.Fprintf(w, "共 %d 处变化，其中 %d 处不兼容\n", len(changes), numBreaking)
+	return
+}
@@ -0,0 +1,120 @@
+package tool
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const testOldLiqiJson = `{"nested": {"lq": {"nested": {
+	"Lobby": {"methods": {
+		"login": {"requestType": "ReqLogin", "responseType": "ResLogin"},
+		"fetchGameRecord": {"requestType": "ReqGameRecord", "responseType": "ResGameRecord"},
+		"fetchFriendList": {"requestType": "ReqCommon", "responseType": "ResFriendList"}
+	}},
+	"GamePlayerState": {"values": {"NULL": 0, "AUTH": 1}},
+	"ActionPrototype": {"fields": {
+		"step": {"type": "uint32", "id": 1},
+		"name": {"type": "string", "id": 2},
+		"data": {"type": "bytes", "id": 3}
+	}},
+	"RecordGame": {"fields": {
+		"uuid": {"type": "string", "id": 1},
+		"accounts": {"rule": "repeated", "type": "AccountInfo", "id": 2}
+	}, "nested": {"AccountInfo": {"fields": {
+		"account_id": {"type": "uint32", "id": 1},
+		"level": {"type": "AccountLevel", "id": 2}
+	}}}},
+	"AccountLevel": {"fields": {"id": {"type": "uint32", "id": 1}}},
+	"ResGameRecord": {"fields": {"head": {"type": "RecordGame", "id": 1}}},
+	"Friend": {"fields": {"account_id": {"type": "uint32", "id": 1}}},
+	"ReqCommon": {"fields": {}},
+	"OldName": {"fields": {"a": {"type": "uint32", "id": 1}, "b": {"type": "string", "id": 2}}}
+}}}}`
+
+const testNewLiqiJson = `{"nested": {"lq": {"nested": {
+	"Lobby": {"methods": {
+		"login": {"requestType": "ReqLogin", "responseType": "ResLogin"},
+		"fetchGameRecordV2": {"requestType": "ReqGameRecord", "responseType": "ResGameRecord"},
+		"fetchFriendList": {"requestType": "ReqCommon", "responseType": "ResFriendListV2"},
+		"fetchMailList": {"requestType": "ReqCommon", "responseType": "ResMailList"}
+	}},
+	"GamePlayerState": {"values": {"NULL": 0, "AUTH": 2, "READY": 3}},
+	"ActionPrototype": {"fields": {
+		"step": {"type": "uint32", "id": 1},
+		"action_name": {"type": "string", "id": 2},
+		"data": {"type": "bytes", "id": 3},
+		"timestamp": {"type": "uint32", "id": 4}
+	}},
+	"RecordGame": {"fields": {
+		"uuid": {"type": "string", "id": 1},
+		"accounts": {"rule": "repeated", "type": "AccountInfo", "id": 2}
+	}, "nested": {"AccountInfo": {"fields": {
+		"account_id": {"type": "uint32", "id": 1},
+		"level": {"type": "AccountLevel", "id": 2}
+	}}}},
+	"AccountLevel": {"fields": {"id": {"type": "string", "id": 1}}},
+	"ResGameRecord": {"fields": {"head": {"type": "RecordGame", "id": 1}}},
+	"Friend": {"fields": {"account_id": {"type": "uint64", "id": 1}}},
+	"ReqCommon": {"fields": {}},
+	"NewName": {"fields": {"a": {"type": "uint32", "id": 1}, "b": {"type": "string", "id": 2}}}
+}}}}`
+
+func TestDiffLiqiJson(t *testing.T) {
+	assert := assert.New(t)
+
+	changes, err := DiffLiqiJson([]byte(testOldLiqiJson), []byte(testNewLiqiJson))
+	if !assert.NoError(err) {
+		return
+	}
+	lines := []string{}
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	assert.Equal([]string{
+		"重命名 message OldName: OldName -> NewName",
+		// RecordGame.AccountInfo 引用了 AccountLevel
+		"[不兼容] 修改 field AccountLevel.id: 类型 uint32 -> string",
+		"[不兼容] 重命名 field ActionPrototype.name: name -> action_name",
+		"新增 field ActionPrototype.timestamp: uint32 = 4",
+		"修改 field Friend.account_id: 类型 uint32 -> uint64",
+		"新增 enum GamePlayerState.READY: 3",
+		"修改 enum GamePlayerState.AUTH: 1 -> 2",
+		"[不兼容] 重命名 method Lobby.fetchGameRecord: Lobby.fetchGameRecord -> Lobby.fetchGameRecordV2",
+		"新增 method Lobby.fetchMailList: (ReqCommon) returns (ResMailList)",
+		"修改 method Lobby.fetchFriendList: (ReqCommon) returns (ResFriendList) -> (ReqCommon) returns (ResFriendListV2)",
+	}, lines)
+
+	buf := bytes.Buffer{}
+	assert.Equal(3, PrintLiqiChanges(&buf, changes))
+	assert.Contains(buf.String(), "共 10 处变化，其中 3 处不兼容")
+
+	changes, err = DiffLiqiJson([]byte(testOldLiqiJson), []byte(testOldLiqiJson))
+	assert.NoError(err)
+	assert.Empty(changes)
+}
+
+// 生成的文件与仓库中的一致，且多次生成的结果相同
+func TestGenLiqiFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	content, err := ioutil.ReadFile("../proto/lq/liqi.json")
+	if !assert.NoError(err) {
+		return
+	}
+
+	protoContent, err := liqiJsonToProto3(content)
+	if assert.NoError(err) {
+		expected, err := ioutil.ReadFile("../proto/lq/liqi.proto")
+		assert.NoError(err)
+		assert.Equal(string(expected), string(protoContent))
+	}
+
+	apiContent, err := liqiJsonToAPI(content)
+	if assert.NoError(err) {
+		expected, err := ioutil.ReadFile("../api/liqi_api.go")
+		assert.NoError(err)
+		assert.Equal(string(expected), string(apiContent))
+	}
+}
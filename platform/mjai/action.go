@@ -0,0 +1,81 @@
+package mjai
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	TypeHello = "hello"
+	TypeJoin  = "join"
+	TypeNone  = "none"
+	TypeError = "error"
+)
+
+// AI 回复给服务端的消息
+// 与 Message 不同，未使用的字段不会输出
+type Action struct {
+	Type string `json:"type"`
+
+	// join
+	Name string `json:"name,omitempty"`
+	Room string `json:"room,omitempty"`
+
+	Actor     *int     `json:"actor,omitempty"`
+	Target    *int     `json:"target,omitempty"`
+	Pai       string   `json:"pai,omitempty"`
+	Consumed  []string `json:"consumed,omitempty"`
+	Tsumogiri *bool    `json:"tsumogiri,omitempty"`
+}
+
+func (a *Action) IsActor(seat int) bool {
+	return a.Actor != nil && *a.Actor == seat
+}
+
+func (a *Action) IsTarget(seat int) bool {
+	return a.Target != nil && *a.Target == seat
+}
+
+func NewNone() *Action {
+	return &Action{Type: TypeNone}
+}
+
+func NewJoin(name string, room string) *Action {
+	return &Action{Type: TypeJoin, Name: name, Room: room}
+}
+
+func NewDahai(actor int, pai string, tsumogiri bool) *Action {
+	return &Action{Type: TypeDahai, Actor: &actor, Pai: pai, Tsumogiri: &tsumogiri}
+}
+
+func NewReach(actor int) *Action {
+	return &Action{Type: TypeReach, Actor: &actor}
+}
+
+// 自摸时 target 与 actor 相同
+func NewHora(actor int, target int, pai string) *Action {
+	return &Action{Type: TypeHora, Actor: &actor, Target: &target, Pai: pai}
+}
+
+// 吃、碰、大明杠
+func NewCall(callType string, actor int, target int, pai string, consumed []string) *Action {
+	return &Action{Type: callType, Actor: &actor, Target: &target, Pai: pai, Consumed: consumed}
+}
+
+// 每条消息占一行写入
+type ActionWriter struct {
+	w io.Writer
+}
+
+func NewActionWriter(w io.Writer) *ActionWriter {
+	return &ActionWriter{w: w}
+}
+
+func (w *ActionWriter) Write(a *Action) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,300 @@
+package arena
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"sort"
+)
+
+// 听牌时的待牌（0-33），未听牌时为空（不检测国士无双）
+func waits(counts []int) (tiles []int) {
+	for tile := range counts {
+		if counts[tile] == 4 {
+			continue
+		}
+		counts[tile]++
+		if util.IsAgari(counts) {
+			tiles = append(tiles, tile)
+		}
+		counts[tile]--
+	}
+	return
+}
+
+func (g *game) isTenpaiAfterDiscard(who int, discardTile int) bool {
+	counts := g.seats[who].handCounts()
+	counts[tile34(discardTile)]--
+	return len(waits(counts)) > 0
+}
+
+// 和牌型（不考虑役和振听）
+func (g *game) isWinningTile(who int, tile int) bool {
+	counts := g.seats[who].handCounts()
+	counts[tile34(tile)]++
+	return util.IsAgari(counts)
+}
+
+// 舍牌振听或见逃
+func (g *game) isFuriten(who int) bool {
+	s := g.seats[who]
+	if s.isMissedRon {
+		return true
+	}
+	for _, tile := range waits(s.handCounts()) {
+		if containsTile34(s.discards, tile) {
+			return true
+		}
+	}
+	return false
+}
+
+var meldTypes = map[string]int{
+	mjai.TypeChi:       model.MeldTypeChi,
+	mjai.TypePon:       model.MeldTypePon,
+	mjai.TypeAnkan:     model.MeldTypeAnkan,
+	mjai.TypeDaiminkan: model.MeldTypeMinkan,
+	mjai.TypeKakan:     model.MeldTypeKakan,
+}
+
+// 计算和牌点数，没有和牌或者无役时返回 nil
+// 自摸时 winTile 已在手牌中
+func (g *game) agari(who int, winTile int, isTsumo bool) *util.PointResult {
+	s := g.seats[who]
+	hand := append([]int(nil), s.hand...)
+	if !isTsumo {
+		hand = append(hand, winTile)
+	}
+
+	counts := make([]int, 34)
+	numRedFives := make([]int, 3)
+	addTile := func(tile int) {
+		if isRedFive(tile) {
+			numRedFives[tile34(tile)/9]++
+		}
+	}
+	for _, tile := range hand {
+		counts[tile34(tile)]++
+		addTile(tile)
+	}
+	if !util.IsAgari(counts) {
+		return nil
+	}
+
+	melds := []model.Meld{}
+	for _, m := range s.melds {
+		modelMeld := model.Meld{MeldType: meldTypes[m.typ]}
+		tiles := append([]int(nil), m.tiles...)
+		if m.calledTile >= 0 {
+			tiles = append(tiles, m.calledTile)
+		}
+		for _, tile := range tiles {
+			modelMeld.Tiles = append(modelMeld.Tiles, tile34(tile))
+			modelMeld.ContainRedFive = modelMeld.ContainRedFive || isRedFive(tile)
+			addTile(tile)
+		}
+		for _, tile := range m.tiles {
+			modelMeld.SelfTiles = append(modelMeld.SelfTiles, tile34(tile))
+		}
+		sort.Ints(modelMeld.Tiles)
+		modelMeld.CalledTile = modelMeld.Tiles[0]
+		if m.calledTile >= 0 {
+			modelMeld.CalledTile = tile34(m.calledTile)
+		}
+		melds = append(melds, modelMeld)
+	}
+
+	doraIndicators := g.doraIndicators()
+	if s.isReached {
+		doraIndicators = append(append([]int(nil), doraIndicators...), g.uraDoraIndicators()...)
+	}
+	doraIndicators34 := []int{}
+	for _, tile := range doraIndicators {
+		doraIndicators34 = append(doraIndicators34, tile34(tile))
+	}
+
+	playerInfo := &model.PlayerInfo{
+		HandTiles34:   counts,
+		Melds:         melds,
+		DoraTiles:     model.DoraList(doraIndicators34, false),
+		NumRedFives:   numRedFives,
+		IsTsumo:       isTsumo,
+		WinTile:       tile34(winTile),
+		RoundWindTile: 27 + g.roundNumber/numPlayers,
+		SelfWindTile:  27 + (who-g.dealer+numPlayers)%numPlayers,
+		IsParent:      who == g.dealer,
+		IsRiichi:      s.isReached,
+	}
+	result := util.CalcPoint(playerInfo)
+	if result.Point == 0 {
+		return nil
+	}
+	return result
+}
+
+// 自摸和牌，返回庄家是否连庄
+func (g *game) tsumo(who int, winTile int) bool {
+	result := g.agari(who, winTile, true)
+	childPoint, parentPoint := result.TsumoPoints()
+	deltas := make([]int, numPlayers)
+	for i := range g.seats {
+		if i == who {
+			continue
+		}
+		pay := childPoint
+		if i == g.dealer {
+			pay = parentPoint
+		}
+		pay += 100 * g.benNumber
+		deltas[i] -= pay
+		deltas[who] += pay
+	}
+	deltas[who] += 1000 * g.riichiSticks
+	g.riichiSticks = 0
+	g.win(who, who, winTile, result, deltas)
+	return who == g.dealer
+}
+
+// 荣和，winners 按照放铳者之后的顺序排列，本场和供托归第一个和牌者
+// 返回庄家是否连庄
+func (g *game) ron(winners []int, from int, winTile int) (isRenchan bool) {
+	g.seats[from].stats.DealIns++
+	for i, who := range winners {
+		result := g.agari(who, winTile, false)
+		pay := result.Point
+		deltas := make([]int, numPlayers)
+		if i == 0 {
+			pay += 300 * g.benNumber
+			deltas[who] += 1000 * g.riichiSticks
+			g.riichiSticks = 0
+		}
+		deltas[from] -= pay
+		deltas[who] += pay
+		g.win(who, from, winTile, result, deltas)
+		isRenchan = isRenchan || who == g.dealer
+	}
+	return
+}
+
+func (g *game) win(who int, from int, winTile int, result *util.PointResult, deltas []int) {
+	s := g.seats[who]
+	s.stats.Wins++
+	s.stats.WinPoints += deltas[who]
+	g.addScores(deltas)
+	msg := &mjai.Message{
+		Type:       mjai.TypeHora,
+		Actor:      who,
+		Target:     from,
+		Pai:        pai(winTile),
+		HoraPoints: result.Point,
+		Deltas:     deltas,
+		Scores:     g.scores(),
+	}
+	if s.isReached {
+		msg.UraMarkers = pais(g.uraDoraIndicators())
+	}
+	g.broadcast(msg)
+}
+
+// 荒牌流局，返回庄家是否听牌（连庄）以及 true
+func (g *game) exhaustiveDraw() (isRenchan bool, isDraw bool) {
+	tenpais := make([]bool, numPlayers)
+	numTenpai := 0
+	for i, s := range g.seats {
+		s.stats.ExhaustiveDraws++
+		if len(waits(s.handCounts())) > 0 {
+			tenpais[i] = true
+			numTenpai++
+			s.stats.TenpaiAtDraw++
+		}
+	}
+	deltas := make([]int, numPlayers)
+	if numTenpai > 0 && numTenpai < numPlayers {
+		for i, tenpai := range tenpais {
+			if tenpai {
+				deltas[i] = 3000 / numTenpai
+			} else {
+				deltas[i] = -3000 / (numPlayers - numTenpai)
+			}
+		}
+	}
+	g.addScores(deltas)
+	g.broadcast(&mjai.Message{Type: mjai.TypeRyukyoku, Reason: "fanpai", Tenpais: tenpais, Deltas: deltas, Scores: g.scores()})
+	return tenpais[g.dealer], true
+}
+
+//
+
+func pai(tile int) string {
+	return mjai.TileString(tile34(tile), isRedFive(tile))
+}
+
+func pais(tiles []int) []string {
+	ps := []string{}
+	for _, tile := range tiles {
+		ps = append(ps, pai(tile))
+	}
+	return ps
+}
+
+func unknownPais(n int) []string {
+	ps := make([]string, n)
+	for i := range ps {
+		ps[i] = mjai.UnknownTile
+	}
+	return ps
+}
+
+// 从手牌中找出与 ps 对应的牌（赤5与普通的5视作不同的牌）
+func takeTiles(hand []int, ps []string) (tiles []int, ok bool) {
+	used := map[int]bool{}
+	for _, p := range ps {
+		found := false
+		for _, tile := range hand {
+			if !used[tile] && pai(tile) == p {
+				used[tile] = true
+				tiles = append(tiles, tile)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return tiles, true
+}
+
+func removeTile(hand []int, tile int) []int {
+	for i, t := range hand {
+		if t == tile {
+			return append(hand[:i:i], hand[i+1:]...)
+		}
+	}
+	return hand
+}
+
+func removeTiles(hand []int, tiles []int) []int {
+	for _, tile := range tiles {
+		hand = removeTile(hand, tile)
+	}
+	return hand
+}
+
+func isSameTile34(tiles []int) bool {
+	for _, tile := range tiles {
+		if tile34(tile) != tile34(tiles[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsTile34(tiles34 []int, tile int) bool {
+	for _, t := range tiles34 {
+		if t == tile {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,537 @@
+// Package arena 实现了离线的四人麻将对局（自我对战），用于比较不同策略的强弱
+// 对局按照 mjai 协议与玩家交互，裁定吃碰杠、立直、和牌和流局，并统计各家的成绩
+// 简化的规则：没有一发、海底、岭上、抢杠、途中流局、包牌和西入，杠宝牌在开杠后立即翻开，立直后不能暗杠
+package arena
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"sort"
+)
+
+// 对局中的玩家
+// 每条消息都会发给所有玩家，玩家需要对每条消息回复一个操作，无操作时回复 none
+type Player interface {
+	React(msg *mjai.Message) *mjai.Action
+}
+
+const (
+	numPlayers = 4
+	initPoint  = 25000
+	maxKans    = 4
+
+	Tonpuusen = 4 // 东风战的局数
+	Hanchan   = 8 // 半庄战的局数
+)
+
+// 副露
+type meld struct {
+	typ        string // mjai 的鸣牌类型
+	tiles      []int  // 自家的牌（编号）
+	calledTile int    // 被鸣的牌（编号），暗杠时为 -1
+}
+
+type seat struct {
+	player Player
+	score  int
+	stats  *PlayerStats
+
+	hand  []int // 手牌（编号），不含副露
+	melds []*meld
+	// 舍牌（0-33），含被鸣走的牌，用于判断振听
+	discards    []int
+	isReached   bool
+	isMissedRon bool // 见逃后到下次舍牌前为同巡振听，立直后为永久振听
+}
+
+type game struct {
+	seats [numPlayers]*seat
+	walls WallGenerator
+
+	roundNumber  int // 场数（东1为0，...，南4为7）
+	benNumber    int
+	riichiSticks int
+	dealer       int
+
+	wall      []int
+	drawIndex int // 下一张摸牌在牌山中的位置
+	numKans   int
+	numDoras  int // 已翻开的宝牌指示牌个数
+}
+
+// 进行一场 numRounds 局的对局（东风战为 Tonpuusen，半庄战为 Hanchan），players[0] 为起家
+func PlayGame(players [numPlayers]Player, walls WallGenerator, numRounds int) *GameResult {
+	g := &game{walls: walls}
+	names := []string{}
+	for i, p := range players {
+		g.seats[i] = &seat{player: p, score: initPoint, stats: &PlayerStats{}}
+		names = append(names, fmt.Sprintf("player%d", i))
+	}
+	g.sendAll(func(who int) *mjai.Message {
+		return &mjai.Message{Type: mjai.TypeStartGame, ID: &who, Names: names}
+	})
+
+	for g.roundNumber < numRounds {
+		isRenchan, isDraw := g.playRound()
+		g.broadcast(&mjai.Message{Type: mjai.TypeEndKyoku})
+		if g.isBusted() {
+			break
+		}
+		if isRenchan || isDraw {
+			g.benNumber++
+		} else {
+			g.benNumber = 0
+		}
+		if !isRenchan {
+			g.roundNumber++
+			g.dealer = g.roundNumber % numPlayers
+		}
+	}
+
+	result := &GameResult{}
+	for i, s := range g.seats {
+		result.Scores[i] = s.score
+		result.Stats[i] = *s.stats
+	}
+	// 同分时按起家顺序排名
+	order := []int{0, 1, 2, 3}
+	sort.SliceStable(order, func(i, j int) bool { return result.Scores[order[i]] > result.Scores[order[j]] })
+	// 剩余的供托归一位
+	result.Scores[order[0]] += 1000 * g.riichiSticks
+	for rank, who := range order {
+		result.Ranks[who] = rank + 1
+	}
+	g.broadcast(&mjai.Message{Type: mjai.TypeEndGame, Scores: result.Scores[:]})
+	return result
+}
+
+func (g *game) isBusted() bool {
+	for _, s := range g.seats {
+		if s.score < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *game) scores() []int {
+	scores := make([]int, numPlayers)
+	for i, s := range g.seats {
+		scores[i] = s.score
+	}
+	return scores
+}
+
+func (g *game) addScores(deltas []int) {
+	for i, s := range g.seats {
+		s.score += deltas[i]
+	}
+}
+
+// 给每个玩家发送各自的消息，返回各个玩家的操作
+func (g *game) sendAll(newMessage func(who int) *mjai.Message) (actions [numPlayers]*mjai.Action) {
+	for i, s := range g.seats {
+		if action := s.player.React(newMessage(i)); action != nil {
+			actions[i] = action
+		} else {
+			actions[i] = mjai.NewNone()
+		}
+	}
+	return
+}
+
+// 给所有玩家发送相同的消息
+func (g *game) broadcast(msg *mjai.Message) [numPlayers]*mjai.Action {
+	return g.sendAll(func(int) *mjai.Message { return msg })
+}
+
+//
+
+func (g *game) liveWallLeft() int {
+	return wallLiveEnd - g.numKans - g.drawIndex
+}
+
+func (g *game) doraIndicators() []int {
+	return g.wall[wallDoraStart : wallDoraStart+g.numDoras]
+}
+
+func (g *game) uraDoraIndicators() []int {
+	return g.wall[wallUraDoraStart : wallUraDoraStart+g.numDoras]
+}
+
+func (g *game) startRound() {
+	g.wall = g.walls.NextWall()
+	g.drawIndex = wallHandEnd
+	g.numKans = 0
+	g.numDoras = 1
+	for i := 0; i < numPlayers; i++ {
+		s := g.seats[(g.dealer+i)%numPlayers]
+		s.hand = append([]int(nil), g.wall[13*i:13*i+13]...)
+		s.melds = nil
+		s.discards = nil
+		s.isReached = false
+		s.isMissedRon = false
+		s.stats.Rounds++
+	}
+
+	scores := g.scores()
+	g.sendAll(func(who int) *mjai.Message {
+		tehais := make([][]string, numPlayers)
+		for i, s := range g.seats {
+			if i == who {
+				tehais[i] = pais(s.hand)
+			} else {
+				tehais[i] = unknownPais(len(s.hand))
+			}
+		}
+		return &mjai.Message{
+			Type:       mjai.TypeStartKyoku,
+			Bakaze:     mjai.TileString(27+g.roundNumber/numPlayers, false),
+			Kyoku:      g.roundNumber%numPlayers + 1,
+			Honba:      g.benNumber,
+			Kyotaku:    g.riichiSticks,
+			Oya:        g.dealer,
+			DoraMarker: pai(g.doraIndicators()[0]),
+			Scores:     scores,
+			Tehais:     tehais,
+		}
+	})
+}
+
+// 摸牌，返回摸到的牌和该玩家的操作
+func (g *game) draw(who int, isRinshan bool) (tile int, action *mjai.Action) {
+	if isRinshan {
+		tile = g.wall[wallRinshanStart+g.numKans-1]
+	} else {
+		tile = g.wall[g.drawIndex]
+		g.drawIndex++
+	}
+	s := g.seats[who]
+	s.hand = append(s.hand, tile)
+	actions := g.sendAll(func(i int) *mjai.Message {
+		msg := &mjai.Message{Type: mjai.TypeTsumo, Actor: who, Pai: mjai.UnknownTile}
+		if i == who {
+			msg.Pai = pai(tile)
+		}
+		return msg
+	})
+	return tile, actions[who]
+}
+
+// 翻开杠宝牌
+func (g *game) revealDora() {
+	g.numDoras++
+	indicators := g.doraIndicators()
+	g.broadcast(&mjai.Message{Type: mjai.TypeDora, DoraMarker: pai(indicators[len(indicators)-1])})
+}
+
+const (
+	selfActionDiscard = iota
+	selfActionTsumo
+	selfActionKan
+)
+
+// 进行一局，返回庄家是否连庄以及是否流局
+func (g *game) playRound() (isRenchan bool, isDraw bool) {
+	g.startRound()
+
+	turn := g.dealer
+	needDraw, isRinshan := true, false
+	var action *mjai.Action
+	var forbiddenTiles []int
+	for {
+		drawnTile := -1
+		if needDraw {
+			if !isRinshan && g.liveWallLeft() == 0 {
+				return g.exhaustiveDraw()
+			}
+			drawnTile, action = g.draw(turn, isRinshan)
+		}
+		needDraw, isRinshan = true, false
+
+		kind, discardTile, isReach := g.selfAction(turn, drawnTile, action, forbiddenTiles)
+		forbiddenTiles = nil
+		switch kind {
+		case selfActionTsumo:
+			return g.tsumo(turn, drawnTile), false
+		case selfActionKan:
+			isRinshan = true
+			continue
+		}
+
+		s := g.seats[turn]
+		s.hand = removeTile(s.hand, discardTile)
+		s.discards = append(s.discards, tile34(discardTile))
+		if !s.isReached {
+			s.isMissedRon = false
+		}
+		actions := g.broadcast(&mjai.Message{Type: mjai.TypeDahai, Actor: turn, Pai: pai(discardTile), Tsumogiri: discardTile == drawnTile})
+
+		// 荣和，立直宣言牌放铳时立直不成立
+		winners := []int{}
+		for i := 1; i < numPlayers; i++ {
+			who := (turn + i) % numPlayers
+			if !g.isWinningTile(who, discardTile) {
+				continue
+			}
+			if a := actions[who]; a.Type == mjai.TypeHora && a.IsActor(who) && !g.isFuriten(who) && g.agari(who, discardTile, false) != nil {
+				winners = append(winners, who)
+			} else {
+				g.seats[who].isMissedRon = true
+			}
+		}
+		if len(winners) > 0 {
+			return g.ron(winners, turn, discardTile), false
+		}
+
+		if isReach {
+			g.acceptReach(turn)
+		}
+
+		// 鸣牌，海底牌不能鸣
+		if g.liveWallLeft() > 0 {
+			if caller, callAction, consumed := g.findCaller(turn, discardTile, actions); caller >= 0 {
+				actions := g.call(caller, turn, discardTile, callAction, consumed)
+				turn = caller
+				if callAction.Type == mjai.TypeDaiminkan {
+					isRinshan = true
+					continue
+				}
+				needDraw = false
+				action = actions[caller]
+				forbiddenTiles = kuikaeTiles(callAction.Type, discardTile, consumed)
+				continue
+			}
+		}
+
+		turn = (turn + 1) % numPlayers
+	}
+}
+
+// 摸牌或鸣牌后的操作，直到和牌、开杠或舍牌为止
+// 非法的操作（包括必须舍牌时回复 none）会被替换成默认的舍牌
+func (g *game) selfAction(turn int, drawnTile int, action *mjai.Action, forbiddenTiles []int) (kind int, discardTile int, isReach bool) {
+	s := g.seats[turn]
+	for {
+		switch action.Type {
+		case mjai.TypeHora:
+			if drawnTile >= 0 && action.IsActor(turn) && g.agari(turn, drawnTile, true) != nil {
+				return selfActionTsumo, -1, false
+			}
+		case mjai.TypeReach:
+			if drawnTile >= 0 && !isReach && action.IsActor(turn) && g.canReach(turn) {
+				isReach = true
+				action = g.broadcast(&mjai.Message{Type: mjai.TypeReach, Actor: turn})[turn]
+				continue
+			}
+		case mjai.TypeAnkan, mjai.TypeKakan:
+			if drawnTile >= 0 && !isReach && !s.isReached && action.IsActor(turn) && g.numKans < maxKans && g.liveWallLeft() > 0 && g.selfKan(turn, action) {
+				return selfActionKan, -1, false
+			}
+		case mjai.TypeDahai:
+			if tile, ok := g.checkDiscard(turn, drawnTile, action, forbiddenTiles, isReach); ok {
+				return selfActionDiscard, tile, isReach
+			}
+		}
+		s.stats.InvalidActions++
+		return selfActionDiscard, g.defaultDiscard(turn, drawnTile, forbiddenTiles, isReach), isReach
+	}
+}
+
+func (g *game) checkDiscard(turn int, drawnTile int, action *mjai.Action, forbiddenTiles []int, isReach bool) (tile int, ok bool) {
+	s := g.seats[turn]
+	if !action.IsActor(turn) {
+		return -1, false
+	}
+	if drawnTile >= 0 && pai(drawnTile) == action.Pai {
+		tile = drawnTile
+	} else {
+		tiles, ok := takeTiles(s.hand, []string{action.Pai})
+		if !ok {
+			return -1, false
+		}
+		tile = tiles[0]
+	}
+	if s.isReached && tile != drawnTile {
+		return -1, false
+	}
+	if containsTile34(forbiddenTiles, tile34(tile)) {
+		return -1, false
+	}
+	if isReach && !g.isTenpaiAfterDiscard(turn, tile) {
+		return -1, false
+	}
+	return tile, true
+}
+
+// 默认的舍牌：优先摸切，不能食替，立直时保持听牌
+func (g *game) defaultDiscard(turn int, drawnTile int, forbiddenTiles []int, isReach bool) int {
+	s := g.seats[turn]
+	candidates := append([]int(nil), s.hand...)
+	if drawnTile >= 0 {
+		candidates = append([]int{drawnTile}, candidates...)
+	}
+	for _, tile := range candidates {
+		if !containsTile34(forbiddenTiles, tile34(tile)) && (!isReach || g.isTenpaiAfterDiscard(turn, tile)) {
+			return tile
+		}
+	}
+	return candidates[0]
+}
+
+func (g *game) canReach(turn int) bool {
+	s := g.seats[turn]
+	if s.isReached || s.score < 1000 || g.liveWallLeft() < numPlayers || !s.isMenzen() {
+		return false
+	}
+	for _, tile := range s.hand {
+		if g.isTenpaiAfterDiscard(turn, tile) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *game) acceptReach(turn int) {
+	s := g.seats[turn]
+	s.isReached = true
+	s.stats.Riichis++
+	g.riichiSticks++
+	deltas := make([]int, numPlayers)
+	deltas[turn] = -1000
+	g.addScores(deltas)
+	g.broadcast(&mjai.Message{Type: mjai.TypeReachAccepted, Actor: turn, Deltas: deltas, Scores: g.scores()})
+}
+
+// 暗杠或加杠，成功时翻开杠宝牌
+func (g *game) selfKan(turn int, action *mjai.Action) bool {
+	s := g.seats[turn]
+	msg := &mjai.Message{Type: action.Type, Actor: turn}
+	if action.Type == mjai.TypeAnkan {
+		tiles, ok := takeTiles(s.hand, action.Consumed)
+		if !ok || len(tiles) != 4 || !isSameTile34(tiles) {
+			return false
+		}
+		s.hand = removeTiles(s.hand, tiles)
+		s.melds = append(s.melds, &meld{typ: mjai.TypeAnkan, tiles: tiles, calledTile: -1})
+		msg.Consumed = pais(tiles)
+	} else {
+		tiles, ok := takeTiles(s.hand, []string{action.Pai})
+		if !ok {
+			return false
+		}
+		tile := tiles[0]
+		var pon *meld
+		for _, m := range s.melds {
+			if m.typ == mjai.TypePon && tile34(m.calledTile) == tile34(tile) {
+				pon = m
+			}
+		}
+		if pon == nil {
+			return false
+		}
+		msg.Pai = pai(tile)
+		msg.Consumed = pais(append(append([]int(nil), pon.tiles...), pon.calledTile))
+		s.hand = removeTile(s.hand, tile)
+		pon.typ = mjai.TypeKakan
+		pon.tiles = append(pon.tiles, tile)
+	}
+	g.numKans++
+	g.broadcast(msg)
+	g.revealDora()
+	return true
+}
+
+// 找出鸣牌的玩家，碰和大明杠优先于吃，没有时返回 -1
+func (g *game) findCaller(turn int, discardTile int, actions [numPlayers]*mjai.Action) (caller int, action *mjai.Action, consumed []int) {
+	for i := 1; i < numPlayers; i++ {
+		who := (turn + i) % numPlayers
+		if a := actions[who]; a.Type == mjai.TypePon || a.Type == mjai.TypeDaiminkan {
+			if consumed, ok := g.checkCall(who, turn, discardTile, a); ok {
+				return who, a, consumed
+			}
+		}
+	}
+	who := (turn + 1) % numPlayers
+	if a := actions[who]; a.Type == mjai.TypeChi {
+		if consumed, ok := g.checkCall(who, turn, discardTile, a); ok {
+			return who, a, consumed
+		}
+	}
+	return -1, nil, nil
+}
+
+func (g *game) checkCall(who int, target int, discardTile int, action *mjai.Action) (consumed []int, ok bool) {
+	s := g.seats[who]
+	if s.isReached || !action.IsActor(who) || !action.IsTarget(target) || action.Pai != pai(discardTile) {
+		return nil, false
+	}
+	consumed, ok = takeTiles(s.hand, action.Consumed)
+	if !ok {
+		return nil, false
+	}
+	tile := tile34(discardTile)
+	switch action.Type {
+	case mjai.TypePon:
+		return consumed, len(consumed) == 2 && isSameTile34(consumed) && tile34(consumed[0]) == tile
+	case mjai.TypeDaiminkan:
+		return consumed, len(consumed) == 3 && isSameTile34(consumed) && tile34(consumed[0]) == tile && g.numKans < maxKans
+	case mjai.TypeChi:
+		if len(consumed) != 2 || tile >= 27 {
+			return nil, false
+		}
+		tiles := []int{tile, tile34(consumed[0]), tile34(consumed[1])}
+		sort.Ints(tiles)
+		return consumed, tiles[0]/9 == tiles[2]/9 && tiles[1] == tiles[0]+1 && tiles[2] == tiles[1]+1
+	}
+	return nil, false
+}
+
+func (g *game) call(caller int, target int, discardTile int, action *mjai.Action, consumed []int) [numPlayers]*mjai.Action {
+	s := g.seats[caller]
+	if s.isMenzen() {
+		s.stats.CallRounds++
+	}
+	s.hand = removeTiles(s.hand, consumed)
+	s.melds = append(s.melds, &meld{typ: action.Type, tiles: consumed, calledTile: discardTile})
+	actions := g.broadcast(&mjai.Message{Type: action.Type, Actor: caller, Target: target, Pai: pai(discardTile), Consumed: pais(consumed)})
+	if action.Type == mjai.TypeDaiminkan {
+		g.numKans++
+		g.revealDora()
+	}
+	return actions
+}
+
+// 吃碰后不能切的牌（现物食替和筋食替）
+func kuikaeTiles(callType string, calledTile int, consumed []int) []int {
+	tile := tile34(calledTile)
+	tiles := []int{tile}
+	if callType == mjai.TypeChi {
+		t0, t1 := tile34(consumed[0]), tile34(consumed[1])
+		if t0 < tile && t1 < tile && tile%9 >= 3 {
+			tiles = append(tiles, tile-3)
+		} else if t0 > tile && t1 > tile && tile%9 <= 5 {
+			tiles = append(tiles, tile+3)
+		}
+	}
+	return tiles
+}
+
+//
+
+func (s *seat) isMenzen() bool {
+	for _, m := range s.melds {
+		if m.typ != mjai.TypeAnkan {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *seat) handCounts() []int {
+	counts := make([]int, 34)
+	for _, tile := range s.hand {
+		counts[tile34(tile)]++
+	}
+	return counts
+}
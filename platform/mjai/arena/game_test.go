@@ -0,0 +1,106 @@
+package arena
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/platform/mjai"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// 不鸣牌，自摸时切掉向听数最小的牌，能和牌时总是宣告和牌（由对局判断是否成立）
+type testPlayer struct {
+	seat int
+	hand []string
+}
+
+func (p *testPlayer) React(msg *mjai.Message) *mjai.Action {
+	switch msg.Type {
+	case mjai.TypeStartGame:
+		p.seat = *msg.ID
+	case mjai.TypeStartKyoku:
+		p.hand = append([]string(nil), msg.Tehais[p.seat]...)
+	case mjai.TypeTsumo:
+		if msg.Actor != p.seat {
+			break
+		}
+		p.hand = append(p.hand, msg.Pai)
+		tiles34, _, _ := mjai.ParseTiles(p.hand)
+		counts := make([]int, 34)
+		for _, tile := range tiles34 {
+			counts[tile]++
+		}
+		if util.IsAgari(counts) {
+			return mjai.NewHora(p.seat, p.seat, msg.Pai)
+		}
+		discardIndex, minShanten := len(p.hand)-1, 99
+		for i, tile := range tiles34 {
+			counts[tile]--
+			if shanten := util.CalculateShanten(counts); shanten < minShanten {
+				discardIndex, minShanten = i, shanten
+			}
+			counts[tile]++
+		}
+		return mjai.NewDahai(p.seat, p.hand[discardIndex], discardIndex == len(p.hand)-1)
+	case mjai.TypeDahai:
+		if msg.Actor != p.seat {
+			return mjai.NewHora(p.seat, msg.Actor, msg.Pai)
+		}
+		for i, pai := range p.hand {
+			if pai == msg.Pai {
+				p.hand = append(p.hand[:i], p.hand[i+1:]...)
+				break
+			}
+		}
+	}
+	return mjai.NewNone()
+}
+
+func playTestGame(newWallGenerator NewWallGenerator, seed int64) *GameResult {
+	players := [numPlayers]Player{}
+	for i := range players {
+		players[i] = &testPlayer{}
+	}
+	return PlayGame(players, newWallGenerator(seed), Hanchan)
+}
+
+func TestPlayGame(t *testing.T) {
+	assert := assert.New(t)
+
+	total := PlayerStats{}
+	for seed := int64(0); seed < 10; seed++ {
+		newWallGenerator := NewRandWallGenerator
+		if seed%2 == 1 {
+			newWallGenerator = NewTenhouWallGenerator
+		}
+		result := playTestGame(newWallGenerator, seed)
+		sum := 0
+		ranks := map[int]bool{}
+		for i, score := range result.Scores {
+			sum += score
+			ranks[result.Ranks[i]] = true
+			assert.Zero(result.Stats[i].InvalidActions)
+			assert.True(result.Stats[i].Rounds >= Tonpuusen)
+			total.add(result.Stats[i])
+		}
+		assert.Equal(4*initPoint, sum)
+		assert.Len(ranks, 4)
+		assert.Equal(result, playTestGame(newWallGenerator, seed))
+	}
+	assert.NotZero(total.Wins)
+	assert.NotZero(total.DealIns)
+	assert.NotZero(total.ExhaustiveDraws)
+}
+
+func TestSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Summary{}
+	s.Add(&GameResult{Ranks: [4]int{1, 2, 3, 4}, Scores: [4]int{40000, 30000, 20000, 10000}}, 0)
+	s.Add(&GameResult{Ranks: [4]int{1, 2, 3, 4}, Scores: [4]int{40000, 30000, 20000, 10000}}, 3)
+	mean, ci95 := s.AvgRank()
+	assert.Equal(2.5, mean)
+	assert.InDelta(2.94, ci95, 0.01)
+	assert.Equal(0.5, s.RankRate(1))
+	mean, _ = s.AvgScore()
+	assert.Equal(25000.0, mean)
+}
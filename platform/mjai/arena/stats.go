@@ -0,0 +1,136 @@
+package arena
+
+import "math"
+
+// 一场对局中某个玩家的统计数据
+type PlayerStats struct {
+	Rounds          int // 局数
+	Wins            int // 和牌次数
+	WinPoints       int // 和牌时的收入之和（含本场和供托）
+	DealIns         int // 放铳次数
+	Riichis         int // 立直次数
+	CallRounds      int // 副露的局数
+	ExhaustiveDraws int // 荒牌流局次数
+	TenpaiAtDraw    int // 荒牌流局时听牌的次数
+	InvalidActions  int // 非法操作次数
+}
+
+func (s *PlayerStats) add(other PlayerStats) {
+	s.Rounds += other.Rounds
+	s.Wins += other.Wins
+	s.WinPoints += other.WinPoints
+	s.DealIns += other.DealIns
+	s.Riichis += other.Riichis
+	s.CallRounds += other.CallRounds
+	s.ExhaustiveDraws += other.ExhaustiveDraws
+	s.TenpaiAtDraw += other.TenpaiAtDraw
+	s.InvalidActions += other.InvalidActions
+}
+
+// 一场对局的结果，下标为座位（0 为起家）
+type GameResult struct {
+	Scores [numPlayers]int
+	Ranks  [numPlayers]int // 1-4
+	Stats  [numPlayers]PlayerStats
+}
+
+// 均值及其 95% 置信区间的半径
+type meanStat struct {
+	n     int
+	sum   float64
+	sqSum float64
+}
+
+func (s *meanStat) add(x float64) {
+	s.n++
+	s.sum += x
+	s.sqSum += x * x
+}
+
+func (s *meanStat) mean() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return s.sum / float64(s.n)
+}
+
+func (s *meanStat) ci95() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	mean := s.mean()
+	variance := (s.sqSum - float64(s.n)*mean*mean) / float64(s.n-1)
+	if variance < 0 {
+		variance = 0
+	}
+	return 1.96 * math.Sqrt(variance/float64(s.n))
+}
+
+// 某个策略在多场对局中的汇总
+type Summary struct {
+	Name  string
+	Games int
+
+	RankCounts [numPlayers]int
+	Stats      PlayerStats
+
+	rank  meanStat
+	score meanStat
+}
+
+// 添加一场对局中 seat 号位的结果
+func (s *Summary) Add(result *GameResult, seat int) {
+	s.Games++
+	rank := result.Ranks[seat]
+	s.RankCounts[rank-1]++
+	s.Stats.add(result.Stats[seat])
+	s.rank.add(float64(rank))
+	s.score.add(float64(result.Scores[seat]))
+}
+
+// 平均顺位及其 95% 置信区间的半径
+func (s *Summary) AvgRank() (mean float64, ci95 float64) {
+	return s.rank.mean(), s.rank.ci95()
+}
+
+// 平均得点及其 95% 置信区间的半径
+func (s *Summary) AvgScore() (mean float64, ci95 float64) {
+	return s.score.mean(), s.score.ci95()
+}
+
+func rate(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func (s *Summary) RankRate(rank int) float64 {
+	return rate(s.RankCounts[rank-1], s.Games)
+}
+
+func (s *Summary) WinRate() float64 {
+	return rate(s.Stats.Wins, s.Stats.Rounds)
+}
+
+func (s *Summary) DealInRate() float64 {
+	return rate(s.Stats.DealIns, s.Stats.Rounds)
+}
+
+func (s *Summary) RiichiRate() float64 {
+	return rate(s.Stats.Riichis, s.Stats.Rounds)
+}
+
+func (s *Summary) CallRate() float64 {
+	return rate(s.Stats.CallRounds, s.Stats.Rounds)
+}
+
+// 荒牌流局时的听牌率
+func (s *Summary) TenpaiAtDrawRate() float64 {
+	return rate(s.Stats.TenpaiAtDraw, s.Stats.ExhaustiveDraws)
+}
+
+// 平均和牌收入
+func (s *Summary) AvgWinPoint() float64 {
+	return rate(s.Stats.WinPoints, s.Stats.Wins)
+}
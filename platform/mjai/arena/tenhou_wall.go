@@ -0,0 +1,37 @@
+package arena
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou"
+	"math/rand"
+)
+
+type tenhouWallGenerator struct {
+	g *tenhou.WallGenerator
+}
+
+// 使用天凤算法生成牌山，MT19937 的初始化数组由 seed 生成
+func NewTenhouWallGenerator(seed int64) WallGenerator {
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]uint32, 624)
+	for i := range key {
+		key[i] = rng.Uint32()
+	}
+	return &tenhouWallGenerator{g: tenhou.NewWallGeneratorByKey(key)}
+}
+
+func (g *tenhouWallGenerator) NextWall() []int {
+	return tenhouWallToArena(g.g.Next())
+}
+
+// 天凤的牌编号与这里的相同，只需要调整顺序
+func tenhouWallToArena(w *tenhou.Wall) []int {
+	tiles := make([]int, 0, numTiles)
+	for _, hand := range w.Haipai() {
+		tiles = append(tiles, hand...)
+	}
+	tiles = append(tiles, w.LiveTiles()...)
+	tiles = append(tiles, w.DoraIndicators()...)
+	tiles = append(tiles, w.UraDoraIndicators()...)
+	tiles = append(tiles, w.RinshanTiles()...)
+	return tiles
+}
@@ -0,0 +1,50 @@
+package arena
+
+import "math/rand"
+
+// 牌山生成器，每局开始时调用一次 NextWall
+// 牌的编号为 0-135，编号/4 即为 0-33 的牌，各色 5 中编号%4==0 的为赤5
+// 返回的牌山为这 136 张牌的排列，按下列顺序使用：
+// 0-51 为配牌，从庄家开始每人依次取 13 张
+// 52-121 为牌山，按顺序摸牌
+// 122-135 为王牌，其中 122-126 为宝牌指示牌，127-131 为对应的里宝牌指示牌，132-135 为岭上牌
+type WallGenerator interface {
+	NextWall() []int
+}
+
+// 根据种子创建牌山生成器，同样的种子应当生成同样的牌山序列
+type NewWallGenerator func(seed int64) WallGenerator
+
+const (
+	numTiles = 136
+
+	wallHandEnd      = 52
+	wallLiveEnd      = 122
+	wallDoraStart    = 122
+	wallUraDoraStart = 127
+	wallRinshanStart = 132
+)
+
+type randWallGenerator struct {
+	rng *rand.Rand
+}
+
+// 使用 math/rand 洗牌的牌山生成器
+func NewRandWallGenerator(seed int64) WallGenerator {
+	return &randWallGenerator{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (g *randWallGenerator) NextWall() []int {
+	return g.rng.Perm(numTiles)
+}
+
+//
+
+func tile34(id int) int {
+	return id / 4
+}
+
+func isRedFive(id int) bool {
+	tile := tile34(id)
+	return tile < 27 && tile%9 == 4 && id%4 == 0
+}
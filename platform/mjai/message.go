@@ -0,0 +1,134 @@
+// Package mjai 实现了 mjai 协议的消息格式
+// mjai 是开源麻将 AI 和模拟器常用的 JSON 协议，座位均为绝对座位（0 为起家）
+package mjai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	TypeStartGame     = "start_game"
+	TypeStartKyoku    = "start_kyoku"
+	TypeTsumo         = "tsumo"
+	TypeDahai         = "dahai"
+	TypeChi           = "chi"
+	TypePon           = "pon"
+	TypeDaiminkan     = "daiminkan"
+	TypeAnkan         = "ankan"
+	TypeKakan         = "kakan"
+	TypeNukidora      = "nukidora" // 三麻拔北（非标准扩展）
+	TypeReach         = "reach"
+	TypeReachAccepted = "reach_accepted"
+	TypeDora          = "dora"
+	TypeHora          = "hora"
+	TypeRyukyoku      = "ryukyoku"
+	TypeEndKyoku      = "end_kyoku"
+	TypeEndGame       = "end_game"
+)
+
+// mjai 消息，根据 Type 使用对应的字段
+type Message struct {
+	Type string `json:"type"`
+
+	// start_game 时为自家座位，观战或牌谱中可能没有
+	ID *int `json:"id,omitempty"`
+
+	Names []string `json:"names,omitempty"`
+
+	Actor  int `json:"actor"`
+	Target int `json:"target"`
+
+	Pai       string   `json:"pai,omitempty"`
+	Consumed  []string `json:"consumed,omitempty"`
+	Tsumogiri bool     `json:"tsumogiri,omitempty"`
+
+	// start_kyoku
+	Bakaze     string     `json:"bakaze,omitempty"`
+	Kyoku      int        `json:"kyoku,omitempty"`
+	Honba      int        `json:"honba"`
+	Kyotaku    int        `json:"kyotaku"`
+	Oya        int        `json:"oya"`
+	DoraMarker string     `json:"dora_marker,omitempty"`
+	Tehais     [][]string `json:"tehais,omitempty"`
+
+	// hora
+	UraMarkers []string `json:"ura_markers,omitempty"`
+	HoraPoints int      `json:"hora_points,omitempty"`
+	Fu         int      `json:"fu,omitempty"`
+	Fan        int      `json:"fan,omitempty"`
+
+	// ryukyoku
+	Reason  string `json:"reason,omitempty"`
+	Tenpais []bool `json:"tenpais,omitempty"`
+
+	Deltas []int `json:"deltas,omitempty"`
+	Scores []int `json:"scores,omitempty"`
+}
+
+// 场风 E S W N 转换成 0-3
+func (m *Message) BakazeIndex() int {
+	tile, _, err := ParseTile(m.Bakaze)
+	if err != nil || tile < 27 || tile > 30 {
+		return 0
+	}
+	return tile - 27
+}
+
+// 解析一行数据
+// 部分模拟器一次发送多条消息，以 JSON 数组的形式发送，这里也一并支持
+func Unmarshal(line []byte) ([]*Message, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+	if line[0] == '[' {
+		messages := []*Message{}
+		if err := json.Unmarshal(line, &messages); err != nil {
+			return nil, err
+		}
+		return messages, nil
+	}
+	msg := &Message{}
+	if err := json.Unmarshal(line, msg); err != nil {
+		return nil, err
+	}
+	return []*Message{msg}, nil
+}
+
+// 按行读取 mjai 消息
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+	pending []*Message
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &Reader{scanner: scanner}
+}
+
+// 读取下一条消息，读完时返回 io.EOF
+func (r *Reader) Read() (*Message, error) {
+	for len(r.pending) == 0 {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		r.line++
+		messages, err := Unmarshal(r.scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行解析失败：%v", r.line, err)
+		}
+		r.pending = messages
+	}
+	msg := r.pending[0]
+	r.pending = r.pending[1:]
+	return msg, nil
+}
@@ -0,0 +1,38 @@
+package mjai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReader_Read(t *testing.T) {
+	assert := assert.New(t)
+
+	const data = `{"type":"start_game","id":2,"names":["A","B","C","D"]}
+
+[{"type":"tsumo","actor":0,"pai":"?"},{"type":"dahai","actor":0,"pai":"5sr","tsumogiri":true}]
+{"type":"end_game"}
+`
+	reader := NewReader(strings.NewReader(data))
+	types := []string{}
+	for {
+		msg, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(err) {
+			return
+		}
+		types = append(types, msg.Type)
+		if msg.Type == TypeDahai {
+			assert.Equal("5sr", msg.Pai)
+			assert.True(msg.Tsumogiri)
+		}
+	}
+	assert.Equal([]string{TypeStartGame, TypeTsumo, TypeDahai, TypeEndGame}, types)
+
+	_, err := NewReader(strings.NewReader("{\n")).Read()
+	assert.Error(err)
+}
@@ -0,0 +1,61 @@
+package mjai
+
+import "fmt"
+
+// mjai 的牌表示
+// 1m-9m 万子，1p-9p 饼子，1s-9s 索子，5mr 5pr 5sr 为赤5
+// E S W N 东南西北，P F C 白发中，? 表示未知的牌（他家的手牌和摸牌）
+const UnknownTile = "?"
+
+var honorTiles = []string{"E", "S", "W", "N", "P", "F", "C"}
+
+var suits = "mps"
+
+// 0-33 的牌转换成 mjai 的牌
+func TileString(tile34 int, isRedFive bool) string {
+	if tile34 >= 27 {
+		return honorTiles[tile34-27]
+	}
+	s := fmt.Sprintf("%d%c", tile34%9+1, suits[tile34/9])
+	if isRedFive {
+		s += "r"
+	}
+	return s
+}
+
+// mjai 的牌转换成 0-33 的牌
+func ParseTile(s string) (tile34 int, isRedFive bool, err error) {
+	for i, honor := range honorTiles {
+		if s == honor {
+			return 27 + i, false, nil
+		}
+	}
+	if len(s) == 3 && s[2] == 'r' && s[0] == '5' {
+		s = s[:2]
+		isRedFive = true
+	}
+	if len(s) == 2 && s[0] >= '1' && s[0] <= '9' {
+		for i := range suits {
+			if s[1] == suits[i] {
+				return 9*i + int(s[0]-'1'), isRedFive, nil
+			}
+		}
+	}
+	return -1, false, fmt.Errorf("错误的牌 %q", s)
+}
+
+// 解析多张牌
+func ParseTiles(tiles []string) (tiles34 []int, numRedFives []int, err error) {
+	numRedFives = make([]int, 3)
+	for _, s := range tiles {
+		tile, isRedFive, er := ParseTile(s)
+		if er != nil {
+			return nil, nil, er
+		}
+		tiles34 = append(tiles34, tile)
+		if isRedFive {
+			numRedFives[tile/9]++
+		}
+	}
+	return
+}
@@ -0,0 +1,31 @@
+package mjai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseTile(t *testing.T) {
+	assert := assert.New(t)
+
+	for s, tile34 := range map[string]int{"1m": 0, "9m": 8, "1p": 9, "5p": 13, "9s": 26, "E": 27, "N": 30, "P": 31, "F": 32, "C": 33} {
+		tile, isRedFive, err := ParseTile(s)
+		if assert.NoError(err, s) {
+			assert.Equal(tile34, tile, s)
+			assert.False(isRedFive, s)
+			assert.Equal(s, TileString(tile, false))
+		}
+	}
+
+	tile, isRedFive, err := ParseTile("5mr")
+	if assert.NoError(err) {
+		assert.Equal(4, tile)
+		assert.True(isRedFive)
+		assert.Equal("5mr", TileString(tile, true))
+	}
+
+	for _, s := range []string{UnknownTile, "", "0m", "5z", "3mr", "5m5", "Z"} {
+		_, _, err := ParseTile(s)
+		assert.Error(err, s)
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"time"
 	"encoding/json"
 	"regexp"
+	"sync"
 )
 
 type MessageReceiver struct {
 	originMessageQueue  chan []byte
 	orderedMessageQueue chan []byte
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 func NewMessageReceiver() *MessageReceiver {
@@ -16,6 +20,7 @@ func NewMessageReceiver() *MessageReceiver {
 	mr := &MessageReceiver{
 		originMessageQueue:  make(chan []byte, maxQueueSize),
 		orderedMessageQueue: make(chan []byte, maxQueueSize),
+		done:                make(chan struct{}),
 	}
 	go mr.run()
 	return mr
@@ -35,9 +40,16 @@ func (mr *MessageReceiver) isSelfDraw(data []byte) bool {
 }
 
 func (mr *MessageReceiver) run() {
-	for data := range mr.originMessageQueue {
+	for {
+		var data []byte
+		select {
+		case data = <-mr.originMessageQueue:
+		case <-mr.done:
+			return
+		}
+
 		if !mr.isSelfDraw(data) {
-			mr.orderedMessageQueue <- data
+			mr.putOrdered(data)
 			continue
 		}
 
@@ -46,25 +58,48 @@ func (mr *MessageReceiver) run() {
 
 		// 未收到新数据
 		if len(mr.originMessageQueue) == 0 {
-			mr.orderedMessageQueue <- data
+			mr.putOrdered(data)
 			continue
 		}
 
 		// 在短时间内收到了新数据
 		// 因为摸牌后肯定要等待玩家操作，正常情况是不会马上有新数据的，所以这说明前端乱序发来了数据
 		// 把 data 重新塞回去，这样才是正确的顺序
-		mr.originMessageQueue <- data
+		mr.Put(data)
+	}
+}
+
+func (mr *MessageReceiver) putOrdered(data []byte) {
+	select {
+	case mr.orderedMessageQueue <- data:
+	case <-mr.done:
 	}
 }
 
+// 关闭后 Put 的数据会被丢弃
 func (mr *MessageReceiver) Put(data []byte) {
-	mr.originMessageQueue <- data
+	select {
+	case mr.originMessageQueue <- data:
+	case <-mr.done:
+	}
 }
 
+// 关闭后返回 nil
 func (mr *MessageReceiver) Get() []byte {
-	return <-mr.orderedMessageQueue
+	select {
+	case data := <-mr.orderedMessageQueue:
+		return data
+	case <-mr.done:
+		return nil
+	}
 }
 
 func (mr *MessageReceiver) IsEmpty() bool {
 	return len(mr.originMessageQueue) == 0 && len(mr.orderedMessageQueue) == 0
 }
+
+func (mr *MessageReceiver) Close() {
+	mr.closeOnce.Do(func() {
+		close(mr.done)
+	})
+}
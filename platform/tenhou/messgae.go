@@ -13,6 +13,12 @@ type message struct {
 	//J string `json:"j"`
 	//G string `json:"g"`
 
+	// 玩家列表 tag=UN，URL 编码
+	Name0 string `json:"-" xml:"n0,attr"`
+	Name1 string `json:"-" xml:"n1,attr"`
+	Name2 string `json:"-" xml:"n2,attr"`
+	Name3 string `json:"-" xml:"n3,attr"`
+
 	// round 开始 tag=INIT
 	Seed   string `json:"seed" xml:"seed,attr"` // 本局信息：场数，场棒数，立直棒数，骰子A减一，骰子B减一，宝牌指示牌 1,0,0,3,2,92
 	Ten    string `json:"ten" xml:"ten,attr"`   // 各家点数 280,230,240,250
@@ -46,36 +52,45 @@ type message struct {
 
 	// 和牌 tag=AGARI
 	// ba, hai, m, machi, ten, yaku, doraHai, who, fromWho, sc
-	//Ba string `json:"ba"` // 0,0
+	Ba string `json:"ba" xml:"ba,attr"` // 本场数,立直棒数 0,0
 	// `json:"hai"` // 和牌型 8,9,11,14,19,125,126,127
 	// `json:"m"` // 副露编号 13527,50794
 	//Machi string `json:"machi"` // (待ち) 自摸/荣和的牌 126
-	// `json:"ten"` // 符数,点数,这张牌的来源 30,7700,0
-	//Yaku        string `json:"yaku"`       // 役（编号，翻数） 18,1,20,1,34,2
-	//DoraTile    string `json:"doraHai"`    // 宝牌 123
-	//UraDoraTile string `json:"doraHaiUra"` // 里宝牌 77
+	// `json:"ten"` // 符数,点数,满贯等 30,7700,0
+	Yaku        string `json:"yaku" xml:"yaku,attr"`             // 役（编号，翻数） 18,1,20,1,34,2
+	Yakuman     string `json:"yakuman" xml:"yakuman,attr"`       // 役满（编号） 39
+	DoraTile    string `json:"doraHai" xml:"doraHai,attr"`       // 宝牌 123
+	UraDoraTile string `json:"doraHaiUra" xml:"doraHaiUra,attr"` // 里宝牌 77
 	// `json:"who"` // 和牌者
-	//FromWho string `json:"fromWho"` // 自摸/荣和牌的来源
-	//Score   string `json:"sc"`      // 各家增减分 260,-77,310,77,220,0,210,0
+	FromWho string `json:"fromWho" xml:"fromWho,attr"` // 自摸/荣和牌的来源
+	PaoWho  string `json:"paoWho" xml:"paoWho,attr"`   // 包牌者
+	Score   string `json:"sc" xml:"sc,attr"`           // 各家点数（百点）和增减 260,-77,310,77,220,0,210,0
+
+	// 流局 tag=RYUUKYOKU
+	// `json:"ba"`
+	// `json:"sc"`
+	// 途中流局的类型 yao9 reach4 ron3 kan4 kaze4 nm，荒牌流局时为空
+	Type string `json:"type" xml:"type,attr"`
 
 	// 游戏结束 tag=PROF
 
 	// 重连 tag=GO
 	// type, lobby, gpid
-	//Type  string `json:"type"`
+	// `json:"type"`
 	//Lobby string `json:"lobby"`
 	//GPID  string `json:"gpid"`
 
 	// 重连 tag=REINIT
-	// `json:"seed"`
+	// `json:"seed"` // 同 INIT，若有杠宝牌则依次附在末尾 1,0,0,3,2,92,39
 	// `json:"ten"`
 	// `json:"oya"`
-	// `json:"hai"`
-	//Meld1    string `json:"m1"` // 各家副露编号 17450
-	//Meld2    string `json:"m2"`
-	//Meld3    string `json:"m3"`
-	//Kawa0 string `json:"kawa0"` // 各家牌河 112,73,3,131,43,98,78,116
-	//Kawa1 string `json:"kawa1"`
-	//Kawa2 string `json:"kawa2"`
-	//Kawa3 string `json:"kawa3"`
+	// `json:"hai"` // 自家当前手牌（不含副露）
+	Meld0 string `json:"m0" xml:"-"` // 各家副露编号（含拔北） 17450,35914
+	Meld1 string `json:"m1" xml:"-"`
+	Meld2 string `json:"m2" xml:"-"`
+	Meld3 string `json:"m3" xml:"-"`
+	Kawa0 string `json:"kawa0" xml:"-"` // 各家牌河（不含被鸣走的牌），255 表示其后的牌为立直宣言牌 112,73,3,255,131,43
+	Kawa1 string `json:"kawa1" xml:"-"`
+	Kawa2 string `json:"kawa2" xml:"-"`
+	Kawa3 string `json:"kawa3" xml:"-"`
 }
@@ -0,0 +1,218 @@
+package tenhou
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+/*
+天凤的牌山生成算法，见 http://tenhou.net/stat/rand/
+牌谱中 <SHUFFLE seed="mt19937ar-sha512-n288-base64,..."> 的 seed 为 base64 编码的 624 个 32 位整数，用于初始化 MT19937
+每局开始时从 MT19937 取 288 个数，每 32 个数（128 字节）做一次 SHA-512，得到 144 个数 rnd
+用 rnd 对 0-135 洗牌得到牌山 yama，骰子为 rnd[135]%6 和 rnd[136]%6
+
+yama[135] 开始依次为配牌和牌山，yama[0]-yama[13] 为王牌：
+yama[5] 为宝牌指示牌，之后的杠宝牌指示牌依次为 yama[7] yama[9] yama[11] yama[13]
+yama[4] 为里宝牌指示牌，之后依次为 yama[6] yama[8] yama[10] yama[12]
+岭上牌依次为 yama[1] yama[0] yama[3] yama[2]
+*/
+
+const ShuffleSeedPrefix = "mt19937ar-sha512-n288-base64,"
+
+const (
+	numWallTiles   = 136
+	numDeadWall    = 14
+	numRandomBytes = 288 * 4
+)
+
+type WallGenerator struct {
+	mt *mt19937
+}
+
+// 根据牌谱中 SHUFFLE 的 seed 创建牌山生成器
+func NewWallGenerator(shuffleSeed string) (*WallGenerator, error) {
+	if !strings.HasPrefix(shuffleSeed, ShuffleSeedPrefix) {
+		return nil, fmt.Errorf("不支持的牌山种子：%s", shuffleSeed)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(shuffleSeed, ShuffleSeedPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("牌山种子解析失败：%v", err)
+	}
+	if len(data) == 0 || len(data)%4 != 0 {
+		return nil, fmt.Errorf("牌山种子长度错误：%d", len(data))
+	}
+	key := make([]uint32, len(data)/4)
+	for i := range key {
+		key[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	return NewWallGeneratorByKey(key), nil
+}
+
+// 直接用 MT19937 的初始化数组创建牌山生成器
+func NewWallGeneratorByKey(key []uint32) *WallGenerator {
+	return &WallGenerator{mt: newMT19937ByArray(key)}
+}
+
+// 生成下一局的牌山
+func (g *WallGenerator) Next() *Wall {
+	src := make([]byte, numRandomBytes)
+	for i := 0; i < len(src); i += 4 {
+		binary.LittleEndian.PutUint32(src[i:], g.mt.genrandUint32())
+	}
+	rnd := make([]uint32, 0, numRandomBytes/2/4)
+	for i := 0; i < len(src); i += 2 * sha512.Size {
+		sum := sha512.Sum512(src[i : i+2*sha512.Size])
+		for j := 0; j < len(sum); j += 4 {
+			rnd = append(rnd, binary.LittleEndian.Uint32(sum[j:]))
+		}
+	}
+
+	w := &Wall{}
+	for i := range w.Tiles {
+		w.Tiles[i] = i
+	}
+	for i := 0; i < numWallTiles-1; i++ {
+		j := i + int(rnd[i]%uint32(numWallTiles-i))
+		w.Tiles[i], w.Tiles[j] = w.Tiles[j], w.Tiles[i]
+	}
+	w.Dice = [2]int{int(rnd[135] % 6), int(rnd[136] % 6)}
+	return w
+}
+
+// 天凤的牌山，牌为 0-135 的天凤编号
+type Wall struct {
+	Tiles [numWallTiles]int
+
+	// 骰子减一，与 INIT 中 seed 的第 4、5 个数相同
+	Dice [2]int
+}
+
+// 配牌，下标 0 为庄家，之后依次为庄家的下家、对家、上家
+// 每人依次取 4 张，取三轮后每人再取 1 张
+func (w *Wall) Haipai() (hands [4][]int) {
+	index := numWallTiles - 1
+	for round := 0; round < 4; round++ {
+		n := 4
+		if round == 3 {
+			n = 1
+		}
+		for i := range hands {
+			for j := 0; j < n; j++ {
+				hands[i] = append(hands[i], w.Tiles[index])
+				index--
+			}
+		}
+	}
+	return
+}
+
+// 配牌后的牌山，按摸牌顺序排列
+// 开杠后岭上牌由牌山末尾补充，此时可摸的牌会相应减少
+func (w *Wall) LiveTiles() []int {
+	tiles := []int{}
+	for i := numWallTiles - 1 - 52; i >= numDeadWall; i-- {
+		tiles = append(tiles, w.Tiles[i])
+	}
+	return tiles
+}
+
+// 宝牌指示牌，第一张为开局时翻开的宝牌指示牌，之后依次为杠宝牌指示牌
+func (w *Wall) DoraIndicators() []int {
+	return []int{w.Tiles[5], w.Tiles[7], w.Tiles[9], w.Tiles[11], w.Tiles[13]}
+}
+
+// 与 DoraIndicators 对应的里宝牌指示牌
+func (w *Wall) UraDoraIndicators() []int {
+	return []int{w.Tiles[4], w.Tiles[6], w.Tiles[8], w.Tiles[10], w.Tiles[12]}
+}
+
+// 岭上牌，按摸牌顺序排列
+func (w *Wall) RinshanTiles() []int {
+	return []int{w.Tiles[1], w.Tiles[0], w.Tiles[3], w.Tiles[2]}
+}
+
+//
+
+// MT19937，见 http://www.math.sci.hiroshima-u.ac.jp/m-mat/MT/MT2002/emt19937ar.html
+const (
+	mtN         = 624
+	mtM         = 397
+	mtMatrixA   = 0x9908b0df
+	mtUpperMask = 0x80000000
+	mtLowerMask = 0x7fffffff
+)
+
+type mt19937 struct {
+	mt  [mtN]uint32
+	mti int
+}
+
+func newMT19937(seed uint32) *mt19937 {
+	r := &mt19937{}
+	r.mt[0] = seed
+	for i := 1; i < mtN; i++ {
+		r.mt[i] = 1812433253*(r.mt[i-1]^(r.mt[i-1]>>30)) + uint32(i)
+	}
+	r.mti = mtN
+	return r
+}
+
+func newMT19937ByArray(key []uint32) *mt19937 {
+	r := newMT19937(19650218)
+	i, j := 1, 0
+	k := mtN
+	if len(key) > k {
+		k = len(key)
+	}
+	for ; k > 0; k-- {
+		r.mt[i] = (r.mt[i] ^ ((r.mt[i-1] ^ (r.mt[i-1] >> 30)) * 1664525)) + key[j] + uint32(j)
+		i++
+		j++
+		if i >= mtN {
+			r.mt[0] = r.mt[mtN-1]
+			i = 1
+		}
+		if j >= len(key) {
+			j = 0
+		}
+	}
+	for k = mtN - 1; k > 0; k-- {
+		r.mt[i] = (r.mt[i] ^ ((r.mt[i-1] ^ (r.mt[i-1] >> 30)) * 1566083941)) - uint32(i)
+		i++
+		if i >= mtN {
+			r.mt[0] = r.mt[mtN-1]
+			i = 1
+		}
+	}
+	r.mt[0] = 0x80000000
+	return r
+}
+
+func (r *mt19937) genrandUint32() uint32 {
+	if r.mti >= mtN {
+		mag01 := [2]uint32{0, mtMatrixA}
+		kk := 0
+		for ; kk < mtN-mtM; kk++ {
+			y := (r.mt[kk] & mtUpperMask) | (r.mt[kk+1] & mtLowerMask)
+			r.mt[kk] = r.mt[kk+mtM] ^ (y >> 1) ^ mag01[y&1]
+		}
+		for ; kk < mtN-1; kk++ {
+			y := (r.mt[kk] & mtUpperMask) | (r.mt[kk+1] & mtLowerMask)
+			r.mt[kk] = r.mt[kk+(mtM-mtN)] ^ (y >> 1) ^ mag01[y&1]
+		}
+		y := (r.mt[mtN-1] & mtUpperMask) | (r.mt[0] & mtLowerMask)
+		r.mt[mtN-1] = r.mt[mtM-1] ^ (y >> 1) ^ mag01[y&1]
+		r.mti = 0
+	}
+
+	y := r.mt[r.mti]
+	r.mti++
+	y ^= y >> 11
+	y ^= (y << 7) & 0x9d2c5680
+	y ^= (y << 15) & 0xefc60000
+	y ^= y >> 18
+	return y
+}
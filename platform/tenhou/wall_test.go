@@ -0,0 +1,153 @@
+package tenhou
+
+import (
+	"testing"
+	"encoding/base64"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func TestMT19937(t *testing.T) {
+	assert := assert.New(t)
+
+	// mt19937ar.out
+	r := newMT19937ByArray([]uint32{0x123, 0x234, 0x345, 0x456})
+	expected := []uint32{1067595299, 955945823, 477289528, 4107218783, 4228976476}
+	for _, x := range expected {
+		assert.Equal(x, r.genrandUint32())
+	}
+}
+
+func TestWallGenerator(t *testing.T) {
+	assert := assert.New(t)
+
+	data := make([]byte, 4*624)
+	for i := 0; i < 624; i++ {
+		binary.LittleEndian.PutUint32(data[4*i:], uint32(i*2654435761))
+	}
+	seed := ShuffleSeedPrefix + base64.StdEncoding.EncodeToString(data)
+
+	g, err := NewWallGenerator(seed)
+	assert.NoError(err)
+	g2, _ := NewWallGenerator(seed)
+	for i := 0; i < 10; i++ {
+		w := g.Next()
+		assert.Equal(w, g2.Next())
+
+		used := map[int]bool{}
+		for _, hand := range w.Haipai() {
+			assert.Len(hand, 13)
+			for _, tile := range hand {
+				used[tile] = true
+			}
+		}
+		live := w.LiveTiles()
+		assert.Len(live, 70)
+		for _, tile := range live {
+			used[tile] = true
+		}
+		for _, tiles := range [][]int{w.DoraIndicators(), w.UraDoraIndicators(), w.RinshanTiles()} {
+			for _, tile := range tiles {
+				used[tile] = true
+			}
+		}
+		assert.Len(used, 136)
+		assert.True(w.Dice[0] >= 0 && w.Dice[0] < 6)
+		assert.True(w.Dice[1] >= 0 && w.Dice[1] < 6)
+	}
+
+	_, err = NewWallGenerator("mt19937ar-sha512-n288-base64,!!!")
+	assert.Error(err)
+	_, err = NewWallGenerator("")
+	assert.Error(err)
+}
+
+// 用天凤实际对局的牌谱（testdata/*.mjlog，可以是 gzip 压缩的）检验牌山生成算法：
+// 用 SHUFFLE 的 seed 生成每局的牌山，与 INIT 中记录的配牌 hai0-hai3、宝牌指示牌和骰子比较
+func TestWallGeneratorWithMjlog(t *testing.T) {
+	assert := assert.New(t)
+
+	files, err := filepath.Glob(filepath.Join("testdata", "*.mjlog"))
+	assert.NoError(err)
+	if len(files) == 0 {
+		t.Skip("testdata 中没有天凤牌谱")
+	}
+
+	parseTiles := func(s string) (tiles []int) {
+		for _, x := range strings.Split(s, ",") {
+			tile, err := strconv.Atoi(x)
+			assert.NoError(err)
+			tiles = append(tiles, tile)
+		}
+		return
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if !assert.NoError(err) {
+			continue
+		}
+		if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+			reader, err := gzip.NewReader(bytes.NewReader(data))
+			if !assert.NoError(err) {
+				continue
+			}
+			data, err = ioutil.ReadAll(reader)
+			if !assert.NoError(err) {
+				continue
+			}
+		}
+
+		record := Record{}
+		if !assert.NoError(xml.Unmarshal(data, &record), file) {
+			continue
+		}
+
+		var g *WallGenerator
+		numRounds := 0
+		for _, action := range record.Actions {
+			switch action.Tag {
+			case "SHUFFLE":
+				g, err = NewWallGenerator(action.Seed)
+				assert.NoError(err, file)
+			case "INIT":
+				if !assert.NotNil(g, file) {
+					break
+				}
+				if action.Hai3 == "" {
+					t.Logf("%s: 跳过三麻牌谱", file)
+					break
+				}
+				numRounds++
+				wall := g.Next()
+
+				seeds := parseTiles(action.Seed)
+				if !assert.Len(seeds, 6, file) {
+					break
+				}
+				assert.Equal(seeds[3], wall.Dice[0], "%s 第 %d 局的骰子", file, numRounds)
+				assert.Equal(seeds[4], wall.Dice[1], "%s 第 %d 局的骰子", file, numRounds)
+				assert.Equal(seeds[5], wall.DoraIndicators()[0], "%s 第 %d 局的宝牌指示牌", file, numRounds)
+
+				dealer, _ := strconv.Atoi(action.Dealer)
+				hands := wall.Haipai()
+				for seat, hai := range []string{action.Hai0, action.Hai1, action.Hai2, action.Hai3} {
+					hand := parseTiles(hai)
+					wallHand := append([]int(nil), hands[(seat-dealer+4)%4]...)
+					sort.Ints(hand)
+					sort.Ints(wallHand)
+					assert.Equal(hand, wallHand, "%s 第 %d 局 %d 号位的配牌", file, numRounds, seat)
+				}
+			}
+		}
+		assert.True(numRounds > 0, file)
+	}
+}
@@ -0,0 +1,150 @@
+// Package tenhou6 实现了 tenhou.net/6 牌谱格式（{"title":..., "log":[[...]]}）的读写
+// 该格式记录了各家的配牌、摸牌和舍牌，被很多复盘工具使用
+// 读取后，各家的摸牌和舍牌会还原成按时间顺序排列的操作（Action），便于与其他格式相互转换
+package tenhou6
+
+import "fmt"
+
+// 注意：以下座位均为绝对座位，0 为东1局的庄家
+// 牌均为 tenhou.net/6 的牌编号，见 TileCode
+
+type ActionType int
+
+const (
+	ActionTypeDraw      ActionType = iota // 摸牌
+	ActionTypeDiscard                     // 舍牌
+	ActionTypeChi                         // 吃
+	ActionTypePon                         // 碰
+	ActionTypeDaiminkan                   // 大明杠
+	ActionTypeAnkan                       // 暗杠
+	ActionTypeKakan                       // 加杠
+	ActionTypeNuki                        // 拔北
+)
+
+type Action struct {
+	Type ActionType
+	Who  int
+
+	// 摸牌、舍牌：摸到、舍出的牌
+	// 吃、碰、大明杠：被鸣的牌
+	// 暗杠：最后一张牌
+	// 加杠：加上的牌
+	// 拔北：北
+	Tile int
+
+	// 吃、碰、大明杠：手牌中组成副露的牌
+	// 暗杠：除 Tile 外的三张牌
+	// 加杠：原来的碰，按写入时的顺序排列（见 NewKakan）
+	SelfTiles []int
+
+	// 吃、碰、大明杠、加杠：被鸣牌的玩家，暗杠时为 Who
+	From int
+
+	// 舍牌：是否摸切、是否立直宣言
+	IsTsumogiri bool
+	IsRiichi    bool
+}
+
+// 是否为杠（杠后需要摸岭上牌、翻杠宝牌）
+func (a *Action) IsKan() bool {
+	return a.Type == ActionTypeDaiminkan || a.Type == ActionTypeAnkan || a.Type == ActionTypeKakan
+}
+
+// 一家和牌
+type Agari struct {
+	Who  int
+	From int // 自摸时为 Who
+	Pao  int // 包牌者，没有时为 Who
+
+	// 各家点数变化
+	Deltas []int
+
+	// 点数和役，如 "30符1000点" "立直(1飜)"
+	Info []string
+}
+
+// 一局的结果
+type Result struct {
+	// 和了 流局 流し満貫 九種九牌 四風連打 四槓散了 四家立直 三家和了 全員聴牌 全員不聴
+	Name string
+
+	// 非和牌时各家点数变化，可以为空
+	Deltas []int
+
+	// 和牌（一炮多响时有多个）
+	Agaris []*Agari
+}
+
+const ResultNameAgari = "和了"
+
+type Round struct {
+	// 场数（东1为0，东2为1，...，南1为4，...）
+	RoundNumber  int
+	BenNumber    int
+	RiichiSticks int
+
+	// 各家初始点数
+	Scores []int
+
+	// 宝牌指示牌（含杠宝牌，按翻开的顺序），里宝牌指示牌
+	DoraIndicators    []int
+	UraDoraIndicators []int
+
+	// 各家配牌（13 张），三麻时第四家为空
+	Hands [][]int
+
+	Actions []*Action
+
+	Result *Result
+}
+
+func (r *Round) PlayerNumber() int {
+	if len(r.Hands) < 4 || len(r.Hands[3]) == 0 {
+		return 3
+	}
+	return 4
+}
+
+func (r *Round) Dealer() int {
+	return r.RoundNumber % 4
+}
+
+// 根据之前的碰，生成加杠操作
+func (r *Round) NewKakan(who int, tile int) (*Action, error) {
+	for _, a := range r.Actions {
+		if a.Type == ActionTypePon && a.Who == who && sameTile(a.Tile, tile) {
+			return &Action{
+				Type:      ActionTypeKakan,
+				Who:       who,
+				Tile:      tile,
+				SelfTiles: ponTiles(r.PlayerNumber(), a),
+				From:      a.From,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("加杠数据有误：%d 没有碰过 %d", who, tile)
+}
+
+// 对局信息
+type Rule struct {
+	Disp  string `json:"disp"`            // 如 "般南喰赤"
+	Aka   int    `json:"aka"`             // 是否有赤宝牌
+	Aka51 int    `json:"aka51,omitempty"` // 各种赤5的数量
+	Aka52 int    `json:"aka52,omitempty"`
+	Aka53 int    `json:"aka53,omitempty"`
+}
+
+type Game struct {
+	Title []string
+	Names []string
+	Rule  Rule
+
+	Rounds []*Round
+}
+
+func (g *Game) PlayerNumber() int {
+	if len(g.Rounds) == 0 {
+		return 4
+	}
+	return g.Rounds[0].PlayerNumber()
+}
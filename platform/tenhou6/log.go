@@ -0,0 +1,559 @@
+package tenhou6
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 牌谱文件的 JSON 结构
+// log 中每一局依次为 [场数, 本场数, 立直棒数], [各家点数], [宝牌指示牌], [里宝牌指示牌],
+// 东家配牌, 东家摸牌, 东家舍牌, 南家配牌, 南家摸牌, 南家舍牌, ..., [结果]
+type rawLog struct {
+	Title []string        `json:"title"`
+	Name  []string        `json:"name"`
+	Rule  Rule            `json:"rule"`
+	Log   [][]interface{} `json:"log"`
+}
+
+const rawRoundLen = 4 + 3*4 + 1
+
+// 鸣牌字符串中表示鸣牌类型的字母
+const (
+	markerChi       = 'c'
+	markerPon       = 'p'
+	markerDaiminkan = 'm'
+	markerAnkan     = 'a'
+	markerKakan     = 'k'
+	markerNuki      = 'f'
+	markerRiichi    = 'r'
+)
+
+// 被鸣牌的玩家相对鸣牌者的位置 1=下家, 2=对家, 3=上家
+func relativeFrom(playerNumber int, who int, from int) int {
+	switch (from - who + playerNumber) % playerNumber {
+	case 1:
+		return 1
+	case playerNumber - 1:
+		return 3
+	default:
+		return 2
+	}
+}
+
+func absoluteFrom(playerNumber int, who int, relative int) int {
+	switch relative {
+	case 1:
+		return (who + 1) % playerNumber
+	case 3:
+		return (who + playerNumber - 1) % playerNumber
+	default:
+		return (who + 2) % playerNumber
+	}
+}
+
+// 碰、明杠、加杠时，字母在字符串中的位置（以牌为单位）表示被鸣牌的玩家
+// 上家 0，对家 1，下家 2（碰、加杠）或 3（明杠）
+func calledIndex(relative int, numSelfTiles int) int {
+	switch relative {
+	case 3:
+		return 0
+	case 2:
+		return 1
+	default:
+		return numSelfTiles
+	}
+}
+
+func relativeFromCalledIndex(idx int, numSelfTiles int) (int, error) {
+	switch idx {
+	case 0:
+		return 3, nil
+	case 1:
+		return 2, nil
+	case numSelfTiles:
+		return 1, nil
+	default:
+		return -1, fmt.Errorf("鸣牌位置错误 %d", idx)
+	}
+}
+
+func insertTile(tiles []int, idx int, tile int) []int {
+	newTiles := append([]int{}, tiles[:idx]...)
+	newTiles = append(newTiles, tile)
+	return append(newTiles, tiles[idx:]...)
+}
+
+// 碰的三张牌，按写入时的顺序排列
+func ponTiles(playerNumber int, pon *Action) []int {
+	idx := calledIndex(relativeFrom(playerNumber, pon.Who, pon.From), len(pon.SelfTiles))
+	return insertTile(pon.SelfTiles, idx, pon.Tile)
+}
+
+// 生成鸣牌字符串，marker 放在第 markerIndex 张牌之前
+func encodeMeld(marker byte, tiles []int, markerIndex int) string {
+	b := strings.Builder{}
+	for i, tile := range tiles {
+		if i == markerIndex {
+			b.WriteByte(marker)
+		}
+		b.WriteString(strconv.Itoa(tile))
+	}
+	return b.String()
+}
+
+// 解析鸣牌字符串，返回字母、字母在第几张牌之前、所有的牌
+func decodeMeld(s string) (marker byte, markerIndex int, tiles []int, err error) {
+	markerIndex = -1
+	for i := 0; i < len(s); {
+		if c := s[i]; c < '0' || c > '9' {
+			if markerIndex != -1 {
+				return 0, 0, nil, fmt.Errorf("鸣牌字符串错误 %s", s)
+			}
+			marker = c
+			markerIndex = len(tiles)
+			i++
+			continue
+		}
+		if i+2 > len(s) {
+			return 0, 0, nil, fmt.Errorf("鸣牌字符串错误 %s", s)
+		}
+		tile, err := strconv.Atoi(s[i : i+2])
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("鸣牌字符串错误 %s", s)
+		}
+		tiles = append(tiles, tile)
+		i += 2
+	}
+	if markerIndex == -1 || markerIndex >= len(tiles) {
+		return 0, 0, nil, fmt.Errorf("鸣牌字符串错误 %s", s)
+	}
+	return
+}
+
+func removeIndex(tiles []int, idx int) []int {
+	return append(append([]int{}, tiles[:idx]...), tiles[idx+1:]...)
+}
+
+// 解析摸牌：数字为摸牌，字符串为吃碰明杠
+func parseTake(playerNumber int, who int, raw interface{}) (*Action, error) {
+	switch v := raw.(type) {
+	case float64:
+		return &Action{Type: ActionTypeDraw, Who: who, Tile: int(v)}, nil
+	case string:
+		marker, idx, tiles, err := decodeMeld(v)
+		if err != nil {
+			return nil, err
+		}
+		a := &Action{Who: who, Tile: tiles[idx], SelfTiles: removeIndex(tiles, idx)}
+		switch {
+		case marker == markerChi && idx == 0 && len(tiles) == 3:
+			a.Type = ActionTypeChi
+			a.From = absoluteFrom(playerNumber, who, 3)
+			return a, nil
+		case marker == markerPon && len(tiles) == 3:
+			a.Type = ActionTypePon
+		case marker == markerDaiminkan && len(tiles) == 4:
+			a.Type = ActionTypeDaiminkan
+		default:
+			return nil, fmt.Errorf("无法解析摸牌数据 %s", v)
+		}
+		relative, err := relativeFromCalledIndex(idx, len(a.SelfTiles))
+		if err != nil {
+			return nil, err
+		}
+		a.From = absoluteFrom(playerNumber, who, relative)
+		return a, nil
+	default:
+		return nil, fmt.Errorf("无法解析摸牌数据 %v", raw)
+	}
+}
+
+// 解析舍牌：数字为舍牌（60 为摸切），字符串为立直宣言、暗杠、加杠、拔北
+// 大明杠后的 0 在调用前处理
+func parseDahai(playerNumber int, who int, raw interface{}, latestDrawTile int) (*Action, error) {
+	discard := func(tile int) *Action {
+		a := &Action{Type: ActionTypeDiscard, Who: who, Tile: tile}
+		if tile == tsumogiriTile {
+			a.Tile = latestDrawTile
+			a.IsTsumogiri = true
+		}
+		return a
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		return discard(int(v)), nil
+	case string:
+		if len(v) > 0 && v[0] == markerRiichi {
+			tile, err := strconv.Atoi(v[1:])
+			if err != nil {
+				return nil, fmt.Errorf("无法解析舍牌数据 %s", v)
+			}
+			a := discard(tile)
+			a.IsRiichi = true
+			return a, nil
+		}
+		marker, idx, tiles, err := decodeMeld(v)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case marker == markerAnkan && len(tiles) == 4 && idx == 3:
+			return &Action{Type: ActionTypeAnkan, Who: who, Tile: tiles[3], SelfTiles: tiles[:3], From: who}, nil
+		case marker == markerKakan && len(tiles) == 4:
+			relative, err := relativeFromCalledIndex(idx, 2)
+			if err != nil {
+				return nil, err
+			}
+			return &Action{
+				Type:      ActionTypeKakan,
+				Who:       who,
+				Tile:      tiles[idx],
+				SelfTiles: removeIndex(tiles, idx),
+				From:      absoluteFrom(playerNumber, who, relative),
+			}, nil
+		case marker == markerNuki && len(tiles) == 1 && idx == 0:
+			return &Action{Type: ActionTypeNuki, Who: who, Tile: tiles[0]}, nil
+		default:
+			return nil, fmt.Errorf("无法解析舍牌数据 %s", v)
+		}
+	default:
+		return nil, fmt.Errorf("无法解析舍牌数据 %v", raw)
+	}
+}
+
+func toInts(raw interface{}) ([]int, error) {
+	arr, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("数据格式错误 %v", raw)
+	}
+	ints := make([]int, len(arr))
+	for i, v := range arr {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("数据格式错误 %v", raw)
+		}
+		ints[i] = int(f)
+	}
+	return ints, nil
+}
+
+func parseResult(raw interface{}) (*Result, error) {
+	arr, ok := raw.([]interface{})
+	if !ok || len(arr) == 0 {
+		return nil, fmt.Errorf("结果格式错误 %v", raw)
+	}
+	name, ok := arr[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("结果格式错误 %v", raw)
+	}
+	result := &Result{Name: name}
+	if name != ResultNameAgari {
+		if len(arr) > 1 {
+			deltas, err := toInts(arr[1])
+			if err != nil {
+				return nil, err
+			}
+			result.Deltas = deltas
+		}
+		return result, nil
+	}
+
+	// 和了，之后依次为 [点数变化], [和牌者, 放铳者, 包牌者, 点数, 役...]
+	for i := 1; i+1 < len(arr); i += 2 {
+		deltas, err := toInts(arr[i])
+		if err != nil {
+			return nil, err
+		}
+		info, ok := arr[i+1].([]interface{})
+		if !ok || len(info) < 3 {
+			return nil, fmt.Errorf("和了数据格式错误 %v", arr[i+1])
+		}
+		whos, err := toInts(info[:3])
+		if err != nil {
+			return nil, err
+		}
+		agari := &Agari{Who: whos[0], From: whos[1], Pao: whos[2], Deltas: deltas}
+		for _, v := range info[3:] {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("和了数据格式错误 %v", arr[i+1])
+			}
+			agari.Info = append(agari.Info, s)
+		}
+		result.Agaris = append(result.Agaris, agari)
+	}
+	return result, nil
+}
+
+func parseRound(raw []interface{}) (*Round, error) {
+	if len(raw) != rawRoundLen {
+		return nil, fmt.Errorf("数据长度错误 %d", len(raw))
+	}
+
+	info, err := toInts(raw[0])
+	if err != nil || len(info) != 3 {
+		return nil, fmt.Errorf("本局信息错误 %v", raw[0])
+	}
+	r := &Round{RoundNumber: info[0], BenNumber: info[1], RiichiSticks: info[2]}
+	if r.Scores, err = toInts(raw[1]); err != nil {
+		return nil, err
+	}
+	if r.DoraIndicators, err = toInts(raw[2]); err != nil {
+		return nil, err
+	}
+	if r.UraDoraIndicators, err = toInts(raw[3]); err != nil {
+		return nil, err
+	}
+
+	takes := make([][]interface{}, 4)
+	dahais := make([][]interface{}, 4)
+	for i := 0; i < 4; i++ {
+		hand, err := toInts(raw[4+3*i])
+		if err != nil {
+			return nil, err
+		}
+		r.Hands = append(r.Hands, hand)
+		var ok bool
+		if takes[i], ok = raw[5+3*i].([]interface{}); !ok {
+			return nil, fmt.Errorf("摸牌数据错误 %v", raw[5+3*i])
+		}
+		if dahais[i], ok = raw[6+3*i].([]interface{}); !ok {
+			return nil, fmt.Errorf("舍牌数据错误 %v", raw[6+3*i])
+		}
+	}
+	if r.Result, err = parseResult(raw[rawRoundLen-1]); err != nil {
+		return nil, err
+	}
+
+	if err := r.restoreActions(takes, dahais); err != nil {
+		return nil, fmt.Errorf("%d 局 %d 本场：%v", r.RoundNumber, r.BenNumber, err)
+	}
+	return r, nil
+}
+
+// 牌谱中只有各家各自的摸牌和舍牌，这里还原出按时间顺序排列的操作
+func (r *Round) restoreActions(takes [][]interface{}, dahais [][]interface{}) error {
+	playerNumber := r.PlayerNumber()
+	takeIndexes := make([]int, 4)
+	dahaiIndexes := make([]int, 4)
+	latestDrawTiles := make([]int, 4)
+
+	// 查找鸣了 discard 这张牌的玩家，碰杠优先于吃
+	findCaller := func(discard *Action) (caller int) {
+		caller = -1
+		for who := 0; who < playerNumber; who++ {
+			if who == discard.Who || takeIndexes[who] >= len(takes[who]) {
+				continue
+			}
+			if _, ok := takes[who][takeIndexes[who]].(string); !ok {
+				continue
+			}
+			a, err := parseTake(playerNumber, who, takes[who][takeIndexes[who]])
+			if err != nil || a.From != discard.Who || !sameTile(a.Tile, discard.Tile) {
+				continue
+			}
+			if a.Type != ActionTypeChi {
+				return who
+			}
+			caller = who
+		}
+		return
+	}
+
+	who := r.Dealer()
+	for takeIndexes[who] < len(takes[who]) {
+		take, err := parseTake(playerNumber, who, takes[who][takeIndexes[who]])
+		if err != nil {
+			return err
+		}
+		takeIndexes[who]++
+		r.Actions = append(r.Actions, take)
+		if take.Type == ActionTypeDraw {
+			latestDrawTiles[who] = take.Tile
+		}
+
+		if dahaiIndexes[who] >= len(dahais[who]) {
+			// 自摸或流局
+			break
+		}
+		rawDahai := dahais[who][dahaiIndexes[who]]
+		dahaiIndexes[who]++
+
+		if take.Type == ActionTypeDaiminkan {
+			// 大明杠后没有舍牌，用 0 占位，接着摸岭上牌
+			if v, ok := rawDahai.(float64); !ok || v != 0 {
+				return fmt.Errorf("大明杠后的舍牌数据错误 %v", rawDahai)
+			}
+			continue
+		}
+
+		dahai, err := parseDahai(playerNumber, who, rawDahai, latestDrawTiles[who])
+		if err != nil {
+			return err
+		}
+		r.Actions = append(r.Actions, dahai)
+		if dahai.Type != ActionTypeDiscard {
+			// 暗杠、加杠、拔北后摸岭上牌
+			continue
+		}
+
+		if caller := findCaller(dahai); caller != -1 {
+			who = caller
+		} else {
+			who = (who + 1) % playerNumber
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		if takeIndexes[i] != len(takes[i]) || dahaiIndexes[i] != len(dahais[i]) {
+			return fmt.Errorf("无法还原操作顺序，%d 号位的摸牌或舍牌有剩余", i)
+		}
+	}
+	return nil
+}
+
+// 解析 tenhou.net/6 格式的牌谱
+func Parse(data []byte) (*Game, error) {
+	raw := rawLog{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	if err := d.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Log) == 0 {
+		return nil, fmt.Errorf("牌谱中没有对局数据")
+	}
+
+	g := &Game{
+		Title: raw.Title,
+		Names: raw.Name,
+		Rule:  raw.Rule,
+	}
+	for _, rawRound := range raw.Log {
+		r, err := parseRound(rawRound)
+		if err != nil {
+			return nil, err
+		}
+		g.Rounds = append(g.Rounds, r)
+	}
+	return g, nil
+}
+
+func (res *Result) toRaw() []interface{} {
+	raw := []interface{}{res.Name}
+	if res.Name != ResultNameAgari {
+		if res.Deltas != nil {
+			raw = append(raw, res.Deltas)
+		}
+		return raw
+	}
+	for _, agari := range res.Agaris {
+		info := []interface{}{agari.Who, agari.From, agari.Pao}
+		for _, s := range agari.Info {
+			info = append(info, s)
+		}
+		raw = append(raw, agari.Deltas, info)
+	}
+	return raw
+}
+
+func (r *Round) toRaw() ([]interface{}, error) {
+	playerNumber := r.PlayerNumber()
+	takes := make([][]interface{}, 4)
+	dahais := make([][]interface{}, 4)
+	latestDrawTiles := make([]int, 4)
+	for i := range takes {
+		takes[i] = []interface{}{}
+		dahais[i] = []interface{}{}
+	}
+
+	for _, a := range r.Actions {
+		who := a.Who
+		if who < 0 || who >= playerNumber {
+			return nil, fmt.Errorf("座位错误 %d", who)
+		}
+		relative := relativeFrom(playerNumber, who, a.From)
+		switch a.Type {
+		case ActionTypeDraw:
+			takes[who] = append(takes[who], a.Tile)
+			latestDrawTiles[who] = a.Tile
+		case ActionTypeDiscard:
+			tile := a.Tile
+			if a.IsTsumogiri {
+				tile = tsumogiriTile
+			}
+			if a.IsRiichi {
+				dahais[who] = append(dahais[who], string(markerRiichi)+strconv.Itoa(tile))
+			} else {
+				dahais[who] = append(dahais[who], tile)
+			}
+		case ActionTypeChi:
+			takes[who] = append(takes[who], encodeMeld(markerChi, insertTile(a.SelfTiles, 0, a.Tile), 0))
+		case ActionTypePon, ActionTypeDaiminkan:
+			marker := byte(markerPon)
+			if a.Type == ActionTypeDaiminkan {
+				marker = markerDaiminkan
+			}
+			idx := calledIndex(relative, len(a.SelfTiles))
+			takes[who] = append(takes[who], encodeMeld(marker, insertTile(a.SelfTiles, idx, a.Tile), idx))
+			if a.Type == ActionTypeDaiminkan {
+				dahais[who] = append(dahais[who], 0)
+			}
+		case ActionTypeAnkan:
+			dahais[who] = append(dahais[who], encodeMeld(markerAnkan, append(append([]int{}, a.SelfTiles...), a.Tile), 3))
+		case ActionTypeKakan:
+			idx := calledIndex(relative, 2)
+			dahais[who] = append(dahais[who], encodeMeld(markerKakan, insertTile(a.SelfTiles, idx, a.Tile), idx))
+		case ActionTypeNuki:
+			dahais[who] = append(dahais[who], encodeMeld(markerNuki, []int{a.Tile}, 0))
+		default:
+			return nil, fmt.Errorf("未知的操作 %d", a.Type)
+		}
+	}
+
+	uraDoraIndicators := r.UraDoraIndicators
+	if uraDoraIndicators == nil {
+		uraDoraIndicators = []int{}
+	}
+	raw := []interface{}{
+		[]int{r.RoundNumber, r.BenNumber, r.RiichiSticks},
+		r.Scores,
+		r.DoraIndicators,
+		uraDoraIndicators,
+	}
+	for i := 0; i < 4; i++ {
+		hand := []int{}
+		if i < len(r.Hands) {
+			hand = r.Hands[i]
+		}
+		raw = append(raw, hand, takes[i], dahais[i])
+	}
+	result := []interface{}{}
+	if r.Result != nil {
+		result = r.Result.toRaw()
+	}
+	return append(raw, result), nil
+}
+
+// 生成 tenhou.net/6 格式的牌谱
+func (g *Game) Marshal() ([]byte, error) {
+	raw := rawLog{
+		Title: g.Title,
+		Name:  g.Names,
+		Rule:  g.Rule,
+	}
+	if raw.Title == nil {
+		raw.Title = []string{"", ""}
+	}
+	for _, r := range g.Rounds {
+		rawRound, err := r.toRaw()
+		if err != nil {
+			return nil, err
+		}
+		raw.Log = append(raw.Log, rawRound)
+	}
+	return json.Marshal(&raw)
+}
@@ -0,0 +1,121 @@
+package tenhou6
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestGame() *Game {
+	r := &Round{
+		Scores:            []int{25000, 25000, 25000, 25000},
+		DoraIndicators:    []int{11, 12, 13, 14},
+		UraDoraIndicators: []int{47},
+		Hands: [][]int{
+			{11, 12, 13, 14, 16, 17, 18, 19, 31, 31, 31, 41, 41},
+			{51, 21, 22, 23, 24, 25, 26, 27, 28, 29, 32, 33, 34},
+			{15, 15, 22, 33, 34, 35, 36, 37, 38, 39, 42, 42, 43},
+			{23, 24, 31, 11, 12, 13, 16, 17, 18, 19, 45, 46, 47},
+		},
+	}
+	r.Actions = []*Action{
+		{Type: ActionTypeDraw, Who: 0, Tile: 11},
+		{Type: ActionTypeDiscard, Who: 0, Tile: 11, IsTsumogiri: true},
+		{Type: ActionTypeDraw, Who: 1, Tile: 21},
+		{Type: ActionTypeDiscard, Who: 1, Tile: 51},
+		{Type: ActionTypePon, Who: 2, Tile: 51, SelfTiles: []int{15, 15}, From: 1},
+		{Type: ActionTypeDiscard, Who: 2, Tile: 22},
+		{Type: ActionTypeChi, Who: 3, Tile: 22, SelfTiles: []int{23, 24}, From: 2},
+		{Type: ActionTypeDiscard, Who: 3, Tile: 31},
+		{Type: ActionTypeDaiminkan, Who: 0, Tile: 31, SelfTiles: []int{31, 31, 31}, From: 3},
+		{Type: ActionTypeDraw, Who: 0, Tile: 41},
+		{Type: ActionTypeAnkan, Who: 0, Tile: 41, SelfTiles: []int{41, 41, 41}, From: 0},
+		{Type: ActionTypeDraw, Who: 0, Tile: 42},
+		{Type: ActionTypeDiscard, Who: 0, Tile: 42, IsTsumogiri: true, IsRiichi: true},
+		{Type: ActionTypeDraw, Who: 1, Tile: 43},
+		{Type: ActionTypeDiscard, Who: 1, Tile: 43, IsTsumogiri: true},
+		{Type: ActionTypeDraw, Who: 2, Tile: 15},
+	}
+	kakan, _ := r.NewKakan(2, 15)
+	r.Actions = append(r.Actions, kakan,
+		&Action{Type: ActionTypeDraw, Who: 2, Tile: 44},
+		&Action{Type: ActionTypeDiscard, Who: 2, Tile: 44, IsTsumogiri: true},
+		&Action{Type: ActionTypeDraw, Who: 3, Tile: 45},
+		&Action{Type: ActionTypeDiscard, Who: 3, Tile: 45},
+		&Action{Type: ActionTypeDraw, Who: 0, Tile: 46},
+	)
+	r.Result = &Result{
+		Name: ResultNameAgari,
+		Agaris: []*Agari{{
+			Who:    0,
+			From:   0,
+			Pao:    0,
+			Deltas: []int{12000, -4000, -4000, -4000},
+			Info:   []string{TsumoPointsInfo(ScoreTitle(30, 5, false), 4000, 0), YakuInfo("立直", 1, false)},
+		}},
+	}
+
+	return &Game{
+		Title:  []string{"", ""},
+		Names:  []string{"A", "B", "C", "D"},
+		Rule:   Rule{Disp: "般南喰赤", Aka: 1},
+		Rounds: []*Round{r},
+	}
+}
+
+func TestGame_Marshal(t *testing.T) {
+	assert := assert.New(t)
+
+	g := newTestGame()
+	if kakan := g.Rounds[0].Actions[16]; assert.Equal(ActionTypeKakan, kakan.Type) {
+		assert.Equal([]int{51, 15, 15}, kakan.SelfTiles)
+		assert.Equal(1, kakan.From)
+	}
+
+	data, err := g.Marshal()
+	if !assert.NoError(err) {
+		return
+	}
+	for _, s := range []string{`"p511515"`, `"c222324"`, `"m31313131"`, `"414141a41"`, `"r60"`, `"k15511515"`, `"満貫4000点∀"`} {
+		assert.Contains(string(data), s)
+	}
+
+	parsedGame, err := Parse(data)
+	if assert.NoError(err) {
+		assert.Equal(g, parsedGame)
+	}
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	const data = `{"title":["",""],"name":["A","B","C",""],"rule":{"disp":"三般東喰赤","aka":1},"log":[[[4,1,0],[35000,35000,35000,0],[47],[],` +
+		`[11,19,21,22,23,24,25,26,27,31,32,33,44],[44,29],["f44",60],` +
+		`[12,18,21,22,23,24,25,26,27,31,32,33,41],[41],[41],` +
+		`[13,17,21,22,23,24,25,26,27,31,32,33,42],[42],[],` +
+		`[],[],[],` +
+		`["流局",[0,1500,-1500,0]]]]}`
+	g, err := Parse([]byte(data))
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(3, g.PlayerNumber())
+	r := g.Rounds[0]
+	assert.Equal(0, r.Dealer())
+	assert.Equal(&Result{Name: "流局", Deltas: []int{0, 1500, -1500, 0}}, r.Result)
+	assert.Equal([]*Action{
+		{Type: ActionTypeDraw, Who: 0, Tile: 44},
+		{Type: ActionTypeNuki, Who: 0, Tile: 44},
+		{Type: ActionTypeDraw, Who: 0, Tile: 29},
+		{Type: ActionTypeDiscard, Who: 0, Tile: 29, IsTsumogiri: true},
+		{Type: ActionTypeDraw, Who: 1, Tile: 41},
+		{Type: ActionTypeDiscard, Who: 1, Tile: 41},
+		{Type: ActionTypeDraw, Who: 2, Tile: 42},
+	}, r.Actions)
+
+	// 摸牌和舍牌对不上
+	_, err = Parse([]byte(`{"log":[[[0,0,0],[],[11],[],[],[11,12],[11],[],[],[],[],[],[],[],[],[],["流局"]]]}`))
+	assert.Error(err)
+
+	_, err = Parse([]byte(`{"log":[]}`))
+	assert.Error(err)
+}
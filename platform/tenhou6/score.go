@@ -0,0 +1,45 @@
+package tenhou6
+
+import "fmt"
+
+// 和了信息中的点数和役，各平台的牌谱转换时使用
+
+// 点数等级，如 "30符1飜" "満貫" "役満"
+func ScoreTitle(fu int, han int, isYakuman bool) string {
+	switch {
+	case isYakuman || han >= 13:
+		return "役満"
+	case han >= 11:
+		return "三倍満"
+	case han >= 8:
+		return "倍満"
+	case han >= 6:
+		return "跳満"
+	case han == 5 || han == 4 && fu >= 40 || han == 3 && fu >= 70:
+		return "満貫"
+	default:
+		return fmt.Sprintf("%d符%d飜", fu, han)
+	}
+}
+
+// 荣和的点数信息，如 "30符1飜1000点"
+func RonPointsInfo(title string, point int) string {
+	return fmt.Sprintf("%s%d点", title, point)
+}
+
+// 自摸的点数信息，如 "満貫2000-4000点"，庄家自摸时为 "満貫4000点∀"
+// dealerPay 为庄家支付的点数，庄家自摸时传入 0
+func TsumoPointsInfo(title string, nonDealerPay int, dealerPay int) string {
+	if dealerPay == 0 {
+		return fmt.Sprintf("%s%d点∀", title, nonDealerPay)
+	}
+	return fmt.Sprintf("%s%d-%d点", title, nonDealerPay, dealerPay)
+}
+
+// 役的信息，如 "立直(1飜)" "大三元(役満)"
+func YakuInfo(name string, han int, isYakuman bool) string {
+	if isYakuman {
+		return name + "(役満)"
+	}
+	return fmt.Sprintf("%s(%d飜)", name, han)
+}
@@ -0,0 +1,50 @@
+package tenhou6
+
+import "fmt"
+
+// tenhou.net/6 的牌编号
+// 11-19 万子，21-29 饼子，31-39 索子，41-47 东南西北白发中
+// 51 赤5万，52 赤5饼，53 赤5索
+const (
+	redFiveMan = 51
+	redFivePin = 52
+	redFiveSou = 53
+
+	// 舍牌中表示摸切
+	tsumogiriTile = 60
+)
+
+// 0-33 的牌转换成 tenhou.net/6 的牌编号
+func TileCode(tile34 int, isRedFive bool) int {
+	if isRedFive {
+		return redFiveMan + tile34/9
+	}
+	return 10*(tile34/9+1) + tile34%9 + 1
+}
+
+// tenhou.net/6 的牌编号转换成 0-33 的牌
+func ParseTileCode(code int) (tile34 int, isRedFive bool, err error) {
+	switch {
+	case code >= redFiveMan && code <= redFiveSou:
+		return 9*(code-redFiveMan) + 4, true, nil
+	case code >= 11 && code <= 39 && code%10 != 0, code >= 41 && code <= 47:
+		return 9*(code/10-1) + code%10 - 1, false, nil
+	default:
+		return -1, false, fmt.Errorf("错误的牌编号 %d", code)
+	}
+}
+
+func mustParseTileCode(code int) (tile34 int, isRedFive bool) {
+	tile34, isRedFive, err := ParseTileCode(code)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// 赤5和普通的5视作同一种牌
+func sameTile(code0, code1 int) bool {
+	t0, _ := mustParseTileCode(code0)
+	t1, _ := mustParseTileCode(code1)
+	return t0 == t1
+}
@@ -0,0 +1,3142 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.12.4
+// source: analysis.proto
+
+package analysis
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type AnalyzeHandRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 手牌 & 副露(暗杠用大写表示) + 要鸣的牌，格式同命令行，如 "24688m 34s # 6666P 234p + 3m"
+	Tiles string `protobuf:"bytes,1,opt,name=tiles,proto3" json:"tiles,omitempty"`
+	// 宝牌，如 "13m6p"
+	Dora string `protobuf:"bytes,2,opt,name=dora,proto3" json:"dora,omitempty"`
+	// 是否自摸
+	Tsumo bool `protobuf:"varint,3,opt,name=tsumo,proto3" json:"tsumo,omitempty"`
+}
+
+func (x *AnalyzeHandRequest) Reset() {
+	*x = AnalyzeHandRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnalyzeHandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeHandRequest) ProtoMessage() {}
+
+func (x *AnalyzeHandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeHandRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeHandRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnalyzeHandRequest) GetTiles() string {
+	if x != nil {
+		return x.Tiles
+	}
+	return ""
+}
+
+func (x *AnalyzeHandRequest) GetDora() string {
+	if x != nil {
+		return x.Dora
+	}
+	return ""
+}
+
+func (x *AnalyzeHandRequest) GetTsumo() bool {
+	if x != nil {
+		return x.Tsumo
+	}
+	return false
+}
+
+// 进张及其剩余枚数，key 为牌
+type Waits struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Waits map[int32]int32 `protobuf:"bytes,1,rep,name=waits,proto3" json:"waits,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *Waits) Reset() {
+	*x = Waits{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Waits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Waits) ProtoMessage() {}
+
+func (x *Waits) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Waits.ProtoReflect.Descriptor instead.
+func (*Waits) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Waits) GetWaits() map[int32]int32 {
+	if x != nil {
+		return x.Waits
+	}
+	return nil
+}
+
+// 3k+1 张手牌的分析结果
+type Hand13Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shanten int32 `protobuf:"varint,1,opt,name=shanten,proto3" json:"shanten,omitempty"`
+	// 进张（听牌时为和了牌）及其剩余枚数
+	Waits      map[int32]int32 `protobuf:"bytes,2,rep,name=waits,proto3" json:"waits,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	WaitsCount int32           `protobuf:"varint,3,opt,name=waits_count,json=waitsCount,proto3" json:"waits_count,omitempty"`
+	// 默听时能和的牌（听牌时才有）
+	DamaWaits map[int32]int32 `protobuf:"bytes,4,rep,name=dama_waits,json=damaWaits,proto3" json:"dama_waits,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// 向听前进后的平均进张数
+	AvgNextShantenWaitsCount float64 `protobuf:"fixed64,5,opt,name=avg_next_shanten_waits_count,json=avgNextShantenWaitsCount,proto3" json:"avg_next_shanten_waits_count,omitempty"`
+	// 综合了进张与向听前进后进张的评分
+	MixedWaitsScore float64 `protobuf:"fixed64,6,opt,name=mixed_waits_score,json=mixedWaitsScore,proto3" json:"mixed_waits_score,omitempty"`
+	// 改良：摸到 key 这张牌后切掉某张牌，进张变为 value
+	Improves             map[int32]*Waits `protobuf:"bytes,7,rep,name=improves,proto3" json:"improves,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ImproveWayCount      int32            `protobuf:"varint,8,opt,name=improve_way_count,json=improveWayCount,proto3" json:"improve_way_count,omitempty"`
+	AvgImproveWaitsCount float64          `protobuf:"fixed64,9,opt,name=avg_improve_waits_count,json=avgImproveWaitsCount,proto3" json:"avg_improve_waits_count,omitempty"`
+	// 和率（百分比）
+	AgariRate float64 `protobuf:"fixed64,10,opt,name=agari_rate,json=agariRate,proto3" json:"agari_rate,omitempty"`
+	// 振听率（百分比）
+	FuritenRate float64 `protobuf:"fixed64,11,opt,name=furiten_rate,json=furitenRate,proto3" json:"furiten_rate,omitempty"`
+	// 役种
+	YakuTypes []int32  `protobuf:"varint,12,rep,packed,name=yaku_types,json=yakuTypes,proto3" json:"yaku_types,omitempty"`
+	YakuNames []string `protobuf:"bytes,13,rep,name=yaku_names,json=yakuNames,proto3" json:"yaku_names,omitempty"`
+	PartWait  bool     `protobuf:"varint,14,opt,name=part_wait,json=partWait,proto3" json:"part_wait,omitempty"`
+	DoraCount int32    `protobuf:"varint,15,opt,name=dora_count,json=doraCount,proto3" json:"dora_count,omitempty"`
+	// 默听、立直的荣和点数，以及局收支
+	DamaPoint       float64 `protobuf:"fixed64,16,opt,name=dama_point,json=damaPoint,proto3" json:"dama_point,omitempty"`
+	RiichiPoint     float64 `protobuf:"fixed64,17,opt,name=riichi_point,json=riichiPoint,proto3" json:"riichi_point,omitempty"`
+	MixedRoundPoint float64 `protobuf:"fixed64,18,opt,name=mixed_round_point,json=mixedRoundPoint,proto3" json:"mixed_round_point,omitempty"`
+}
+
+func (x *Hand13Result) Reset() {
+	*x = Hand13Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hand13Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hand13Result) ProtoMessage() {}
+
+func (x *Hand13Result) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hand13Result.ProtoReflect.Descriptor instead.
+func (*Hand13Result) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Hand13Result) GetShanten() int32 {
+	if x != nil {
+		return x.Shanten
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetWaits() map[int32]int32 {
+	if x != nil {
+		return x.Waits
+	}
+	return nil
+}
+
+func (x *Hand13Result) GetWaitsCount() int32 {
+	if x != nil {
+		return x.WaitsCount
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetDamaWaits() map[int32]int32 {
+	if x != nil {
+		return x.DamaWaits
+	}
+	return nil
+}
+
+func (x *Hand13Result) GetAvgNextShantenWaitsCount() float64 {
+	if x != nil {
+		return x.AvgNextShantenWaitsCount
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetMixedWaitsScore() float64 {
+	if x != nil {
+		return x.MixedWaitsScore
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetImproves() map[int32]*Waits {
+	if x != nil {
+		return x.Improves
+	}
+	return nil
+}
+
+func (x *Hand13Result) GetImproveWayCount() int32 {
+	if x != nil {
+		return x.ImproveWayCount
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetAvgImproveWaitsCount() float64 {
+	if x != nil {
+		return x.AvgImproveWaitsCount
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetAgariRate() float64 {
+	if x != nil {
+		return x.AgariRate
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetFuritenRate() float64 {
+	if x != nil {
+		return x.FuritenRate
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetYakuTypes() []int32 {
+	if x != nil {
+		return x.YakuTypes
+	}
+	return nil
+}
+
+func (x *Hand13Result) GetYakuNames() []string {
+	if x != nil {
+		return x.YakuNames
+	}
+	return nil
+}
+
+func (x *Hand13Result) GetPartWait() bool {
+	if x != nil {
+		return x.PartWait
+	}
+	return false
+}
+
+func (x *Hand13Result) GetDoraCount() int32 {
+	if x != nil {
+		return x.DoraCount
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetDamaPoint() float64 {
+	if x != nil {
+		return x.DamaPoint
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetRiichiPoint() float64 {
+	if x != nil {
+		return x.RiichiPoint
+	}
+	return 0
+}
+
+func (x *Hand13Result) GetMixedRoundPoint() float64 {
+	if x != nil {
+		return x.MixedRoundPoint
+	}
+	return 0
+}
+
+// 3k+2 张手牌的一种切法
+type Hand14Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Discard     int32 `protobuf:"varint,1,opt,name=discard,proto3" json:"discard,omitempty"`
+	DiscardDora bool  `protobuf:"varint,2,opt,name=discard_dora,json=discardDora,proto3" json:"discard_dora,omitempty"`
+	// 鸣牌时用于副露的手牌，比如用 23m 吃了牌就是 [1,2]
+	OpenTiles []int32 `protobuf:"varint,3,rep,packed,name=open_tiles,json=openTiles,proto3" json:"open_tiles,omitempty"`
+	// 切牌的综合危险度（百分比），has_risk 为 false 时表示没有危险度表
+	HasRisk bool    `protobuf:"varint,4,opt,name=has_risk,json=hasRisk,proto3" json:"has_risk,omitempty"`
+	Risk    float64 `protobuf:"fixed64,5,opt,name=risk,proto3" json:"risk,omitempty"`
+	// 切牌后的手牌分析结果
+	Result13 *Hand13Result `protobuf:"bytes,6,opt,name=result13,proto3" json:"result13,omitempty"`
+}
+
+func (x *Hand14Result) Reset() {
+	*x = Hand14Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hand14Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hand14Result) ProtoMessage() {}
+
+func (x *Hand14Result) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hand14Result.ProtoReflect.Descriptor instead.
+func (*Hand14Result) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Hand14Result) GetDiscard() int32 {
+	if x != nil {
+		return x.Discard
+	}
+	return 0
+}
+
+func (x *Hand14Result) GetDiscardDora() bool {
+	if x != nil {
+		return x.DiscardDora
+	}
+	return false
+}
+
+func (x *Hand14Result) GetOpenTiles() []int32 {
+	if x != nil {
+		return x.OpenTiles
+	}
+	return nil
+}
+
+func (x *Hand14Result) GetHasRisk() bool {
+	if x != nil {
+		return x.HasRisk
+	}
+	return false
+}
+
+func (x *Hand14Result) GetRisk() float64 {
+	if x != nil {
+		return x.Risk
+	}
+	return 0
+}
+
+func (x *Hand14Result) GetResult13() *Hand13Result {
+	if x != nil {
+		return x.Result13
+	}
+	return nil
+}
+
+// 鸣他家舍牌的分析结果
+type MeldAnalysis struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target   int32 `protobuf:"varint,1,opt,name=target,proto3" json:"target,omitempty"`
+	RedFive  bool  `protobuf:"varint,2,opt,name=red_five,json=redFive,proto3" json:"red_five,omitempty"`
+	AllowChi bool  `protobuf:"varint,3,opt,name=allow_chi,json=allowChi,proto3" json:"allow_chi,omitempty"`
+	// 鸣牌后的向听数
+	Shanten int32 `protobuf:"varint,4,opt,name=shanten,proto3" json:"shanten,omitempty"`
+	// 鸣牌后的各种切法，按推荐顺序排列
+	Discards []*Hand14Result `protobuf:"bytes,5,rep,name=discards,proto3" json:"discards,omitempty"`
+	// 鸣牌后退向听的切法
+	BackwardDiscards []*Hand14Result `protobuf:"bytes,6,rep,name=backward_discards,json=backwardDiscards,proto3" json:"backward_discards,omitempty"`
+}
+
+func (x *MeldAnalysis) Reset() {
+	*x = MeldAnalysis{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MeldAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MeldAnalysis) ProtoMessage() {}
+
+func (x *MeldAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MeldAnalysis.ProtoReflect.Descriptor instead.
+func (*MeldAnalysis) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MeldAnalysis) GetTarget() int32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *MeldAnalysis) GetRedFive() bool {
+	if x != nil {
+		return x.RedFive
+	}
+	return false
+}
+
+func (x *MeldAnalysis) GetAllowChi() bool {
+	if x != nil {
+		return x.AllowChi
+	}
+	return false
+}
+
+func (x *MeldAnalysis) GetShanten() int32 {
+	if x != nil {
+		return x.Shanten
+	}
+	return 0
+}
+
+func (x *MeldAnalysis) GetDiscards() []*Hand14Result {
+	if x != nil {
+		return x.Discards
+	}
+	return nil
+}
+
+func (x *MeldAnalysis) GetBackwardDiscards() []*Hand14Result {
+	if x != nil {
+		return x.BackwardDiscards
+	}
+	return nil
+}
+
+// 手牌分析结果
+// 3k+1 张手牌时为 result13（有要鸣的牌时还有 meld），3k+2 张手牌时为 discards 和 backward_discards
+type HandAnalysis struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 手牌及副露，如 "24688m 34s # 6666P 234p"
+	Hand string `protobuf:"bytes,1,opt,name=hand,proto3" json:"hand,omitempty"`
+	// 当前的向听数，-1 表示已和牌
+	Shanten  int32         `protobuf:"varint,2,opt,name=shanten,proto3" json:"shanten,omitempty"`
+	Result13 *Hand13Result `protobuf:"bytes,3,opt,name=result13,proto3" json:"result13,omitempty"`
+	// 各种切法，按推荐顺序排列
+	Discards []*Hand14Result `protobuf:"bytes,4,rep,name=discards,proto3" json:"discards,omitempty"`
+	// 退向听的切法
+	BackwardDiscards []*Hand14Result `protobuf:"bytes,5,rep,name=backward_discards,json=backwardDiscards,proto3" json:"backward_discards,omitempty"`
+	// 无法鸣牌时为空
+	Meld *MeldAnalysis `protobuf:"bytes,6,opt,name=meld,proto3" json:"meld,omitempty"`
+}
+
+func (x *HandAnalysis) Reset() {
+	*x = HandAnalysis{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandAnalysis) ProtoMessage() {}
+
+func (x *HandAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandAnalysis.ProtoReflect.Descriptor instead.
+func (*HandAnalysis) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HandAnalysis) GetHand() string {
+	if x != nil {
+		return x.Hand
+	}
+	return ""
+}
+
+func (x *HandAnalysis) GetShanten() int32 {
+	if x != nil {
+		return x.Shanten
+	}
+	return 0
+}
+
+func (x *HandAnalysis) GetResult13() *Hand13Result {
+	if x != nil {
+		return x.Result13
+	}
+	return nil
+}
+
+func (x *HandAnalysis) GetDiscards() []*Hand14Result {
+	if x != nil {
+		return x.Discards
+	}
+	return nil
+}
+
+func (x *HandAnalysis) GetBackwardDiscards() []*Hand14Result {
+	if x != nil {
+		return x.BackwardDiscards
+	}
+	return nil
+}
+
+func (x *HandAnalysis) GetMeld() *MeldAnalysis {
+	if x != nil {
+		return x.Meld
+	}
+	return nil
+}
+
+type CalculatePointRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 和牌时的手牌（含和了牌）& 副露，格式同命令行，如 "234m 567p 11z # 6666P 234p"
+	Tiles string `protobuf:"bytes,1,opt,name=tiles,proto3" json:"tiles,omitempty"`
+	// 和了牌，如 "3m"，赤5 用 0 表示
+	WinTile string `protobuf:"bytes,2,opt,name=win_tile,json=winTile,proto3" json:"win_tile,omitempty"`
+	// 宝牌，如 "13m6p"
+	Dora    string `protobuf:"bytes,3,opt,name=dora,proto3" json:"dora,omitempty"`
+	Tsumo   bool   `protobuf:"varint,4,opt,name=tsumo,proto3" json:"tsumo,omitempty"`
+	Riichi  bool   `protobuf:"varint,5,opt,name=riichi,proto3" json:"riichi,omitempty"`
+	Daburii bool   `protobuf:"varint,6,opt,name=daburii,proto3" json:"daburii,omitempty"`
+	// 场风和自风（27-30），为 0 时视为东
+	RoundWind int32 `protobuf:"varint,7,opt,name=round_wind,json=roundWind,proto3" json:"round_wind,omitempty"`
+	SelfWind  int32 `protobuf:"varint,8,opt,name=self_wind,json=selfWind,proto3" json:"self_wind,omitempty"`
+}
+
+func (x *CalculatePointRequest) Reset() {
+	*x = CalculatePointRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CalculatePointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculatePointRequest) ProtoMessage() {}
+
+func (x *CalculatePointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculatePointRequest.ProtoReflect.Descriptor instead.
+func (*CalculatePointRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CalculatePointRequest) GetTiles() string {
+	if x != nil {
+		return x.Tiles
+	}
+	return ""
+}
+
+func (x *CalculatePointRequest) GetWinTile() string {
+	if x != nil {
+		return x.WinTile
+	}
+	return ""
+}
+
+func (x *CalculatePointRequest) GetDora() string {
+	if x != nil {
+		return x.Dora
+	}
+	return ""
+}
+
+func (x *CalculatePointRequest) GetTsumo() bool {
+	if x != nil {
+		return x.Tsumo
+	}
+	return false
+}
+
+func (x *CalculatePointRequest) GetRiichi() bool {
+	if x != nil {
+		return x.Riichi
+	}
+	return false
+}
+
+func (x *CalculatePointRequest) GetDaburii() bool {
+	if x != nil {
+		return x.Daburii
+	}
+	return false
+}
+
+func (x *CalculatePointRequest) GetRoundWind() int32 {
+	if x != nil {
+		return x.RoundWind
+	}
+	return 0
+}
+
+func (x *CalculatePointRequest) GetSelfWind() int32 {
+	if x != nil {
+		return x.SelfWind
+	}
+	return 0
+}
+
+type PointResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 荣和时为放铳者支付的点数，自摸时为三家支付的点数之和
+	// 无役时为 0
+	Point int32 `protobuf:"varint,1,opt,name=point,proto3" json:"point,omitempty"`
+	// 自摸时子家和亲家支付的点数（亲家自摸时 tsumo_parent_point 为 0）
+	TsumoChildPoint  int32 `protobuf:"varint,2,opt,name=tsumo_child_point,json=tsumoChildPoint,proto3" json:"tsumo_child_point,omitempty"`
+	TsumoParentPoint int32 `protobuf:"varint,3,opt,name=tsumo_parent_point,json=tsumoParentPoint,proto3" json:"tsumo_parent_point,omitempty"`
+	// 番数（含宝牌）和符数，役满时为 0
+	Han int32 `protobuf:"varint,4,opt,name=han,proto3" json:"han,omitempty"`
+	Fu  int32 `protobuf:"varint,5,opt,name=fu,proto3" json:"fu,omitempty"`
+	// 役满倍数，非役满时为 0
+	YakumanTimes int32    `protobuf:"varint,6,opt,name=yakuman_times,json=yakumanTimes,proto3" json:"yakuman_times,omitempty"`
+	YakuTypes    []int32  `protobuf:"varint,7,rep,packed,name=yaku_types,json=yakuTypes,proto3" json:"yaku_types,omitempty"`
+	YakuNames    []string `protobuf:"bytes,8,rep,name=yaku_names,json=yakuNames,proto3" json:"yaku_names,omitempty"`
+	DoraCount    int32    `protobuf:"varint,9,opt,name=dora_count,json=doraCount,proto3" json:"dora_count,omitempty"`
+}
+
+func (x *PointResult) Reset() {
+	*x = PointResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PointResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointResult) ProtoMessage() {}
+
+func (x *PointResult) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointResult.ProtoReflect.Descriptor instead.
+func (*PointResult) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PointResult) GetPoint() int32 {
+	if x != nil {
+		return x.Point
+	}
+	return 0
+}
+
+func (x *PointResult) GetTsumoChildPoint() int32 {
+	if x != nil {
+		return x.TsumoChildPoint
+	}
+	return 0
+}
+
+func (x *PointResult) GetTsumoParentPoint() int32 {
+	if x != nil {
+		return x.TsumoParentPoint
+	}
+	return 0
+}
+
+func (x *PointResult) GetHan() int32 {
+	if x != nil {
+		return x.Han
+	}
+	return 0
+}
+
+func (x *PointResult) GetFu() int32 {
+	if x != nil {
+		return x.Fu
+	}
+	return 0
+}
+
+func (x *PointResult) GetYakumanTimes() int32 {
+	if x != nil {
+		return x.YakumanTimes
+	}
+	return 0
+}
+
+func (x *PointResult) GetYakuTypes() []int32 {
+	if x != nil {
+		return x.YakuTypes
+	}
+	return nil
+}
+
+func (x *PointResult) GetYakuNames() []string {
+	if x != nil {
+		return x.YakuNames
+	}
+	return nil
+}
+
+func (x *PointResult) GetDoraCount() int32 {
+	if x != nil {
+		return x.DoraCount
+	}
+	return 0
+}
+
+// 副露，对应 model.Meld
+type Meld struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 0=吃, 1=碰, 2=暗杠, 3=大明杠, 4=加杠
+	Type int32 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	// 副露的牌
+	Tiles []int32 `protobuf:"varint,2,rep,packed,name=tiles,proto3" json:"tiles,omitempty"`
+	// 手牌中组成副露的牌
+	SelfTiles []int32 `protobuf:"varint,3,rep,packed,name=self_tiles,json=selfTiles,proto3" json:"self_tiles,omitempty"`
+	// 被鸣的牌
+	Called int32 `protobuf:"varint,4,opt,name=called,proto3" json:"called,omitempty"`
+	// 是否包含赤5
+	Red bool `protobuf:"varint,5,opt,name=red,proto3" json:"red,omitempty"`
+	// 赤5是否来自他家
+	RedFromOthers bool `protobuf:"varint,6,opt,name=red_from_others,json=redFromOthers,proto3" json:"red_from_others,omitempty"`
+}
+
+func (x *Meld) Reset() {
+	*x = Meld{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Meld) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Meld) ProtoMessage() {}
+
+func (x *Meld) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Meld.ProtoReflect.Descriptor instead.
+func (*Meld) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Meld) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *Meld) GetTiles() []int32 {
+	if x != nil {
+		return x.Tiles
+	}
+	return nil
+}
+
+func (x *Meld) GetSelfTiles() []int32 {
+	if x != nil {
+		return x.SelfTiles
+	}
+	return nil
+}
+
+func (x *Meld) GetCalled() int32 {
+	if x != nil {
+		return x.Called
+	}
+	return 0
+}
+
+func (x *Meld) GetRed() bool {
+	if x != nil {
+		return x.Red
+	}
+	return false
+}
+
+func (x *Meld) GetRedFromOthers() bool {
+	if x != nil {
+		return x.RedFromOthers
+	}
+	return false
+}
+
+// 重连时的玩家信息
+type PlayerSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Melds []*Meld `protobuf:"bytes,1,rep,name=melds,proto3" json:"melds,omitempty"`
+	// 舍牌，摸切的牌用 -1-tile 表示
+	Discards []int32 `protobuf:"varint,2,rep,packed,name=discards,proto3" json:"discards,omitempty"`
+	Riichi   bool    `protobuf:"varint,3,opt,name=riichi,proto3" json:"riichi,omitempty"`
+	// 立直宣言牌在 discards 中的下标
+	RiichiTileAt int32 `protobuf:"varint,4,opt,name=riichi_tile_at,json=riichiTileAt,proto3" json:"riichi_tile_at,omitempty"`
+	// 拔北数
+	Nuki int32 `protobuf:"varint,5,opt,name=nuki,proto3" json:"nuki,omitempty"`
+}
+
+func (x *PlayerSnapshot) Reset() {
+	*x = PlayerSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerSnapshot) ProtoMessage() {}
+
+func (x *PlayerSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerSnapshot.ProtoReflect.Descriptor instead.
+func (*PlayerSnapshot) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PlayerSnapshot) GetMelds() []*Meld {
+	if x != nil {
+		return x.Melds
+	}
+	return nil
+}
+
+func (x *PlayerSnapshot) GetDiscards() []int32 {
+	if x != nil {
+		return x.Discards
+	}
+	return nil
+}
+
+func (x *PlayerSnapshot) GetRiichi() bool {
+	if x != nil {
+		return x.Riichi
+	}
+	return false
+}
+
+func (x *PlayerSnapshot) GetRiichiTileAt() int32 {
+	if x != nil {
+		return x.RiichiTileAt
+	}
+	return 0
+}
+
+func (x *PlayerSnapshot) GetNuki() int32 {
+	if x != nil {
+		return x.Nuki
+	}
+	return 0
+}
+
+type GameStart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dealer int32 `protobuf:"varint,1,opt,name=dealer,proto3" json:"dealer,omitempty"`
+}
+
+func (x *GameStart) Reset() {
+	*x = GameStart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameStart) ProtoMessage() {}
+
+func (x *GameStart) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameStart.ProtoReflect.Descriptor instead.
+func (*GameStart) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GameStart) GetDealer() int32 {
+	if x != nil {
+		return x.Dealer
+	}
+	return 0
+}
+
+type RoundStart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 场数（如东1为0，东2为1，...，南1为4，...）
+	Round  int32 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Ben    int32 `protobuf:"varint,2,opt,name=ben,proto3" json:"ben,omitempty"`
+	Dealer int32 `protobuf:"varint,3,opt,name=dealer,proto3" json:"dealer,omitempty"`
+	// 人数，3 为三麻，4 为四麻，为 0 时视为四麻
+	PlayerNumber   int32   `protobuf:"varint,4,opt,name=player_number,json=playerNumber,proto3" json:"player_number,omitempty"`
+	DoraIndicators []int32 `protobuf:"varint,5,rep,packed,name=dora_indicators,json=doraIndicators,proto3" json:"dora_indicators,omitempty"`
+	// 自家手牌（不含副露）
+	Hand []int32 `protobuf:"varint,6,rep,packed,name=hand,proto3" json:"hand,omitempty"`
+	// 按照 mps 的顺序，自家赤5个数
+	RedFives []int32 `protobuf:"varint,7,rep,packed,name=red_fives,json=redFives,proto3" json:"red_fives,omitempty"`
+	// 重连时各家的牌局信息，依次为自家、下家、对家、上家，非重连时为空
+	Snapshot []*PlayerSnapshot `protobuf:"bytes,8,rep,name=snapshot,proto3" json:"snapshot,omitempty"`
+}
+
+func (x *RoundStart) Reset() {
+	*x = RoundStart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoundStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundStart) ProtoMessage() {}
+
+func (x *RoundStart) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundStart.ProtoReflect.Descriptor instead.
+func (*RoundStart) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RoundStart) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *RoundStart) GetBen() int32 {
+	if x != nil {
+		return x.Ben
+	}
+	return 0
+}
+
+func (x *RoundStart) GetDealer() int32 {
+	if x != nil {
+		return x.Dealer
+	}
+	return 0
+}
+
+func (x *RoundStart) GetPlayerNumber() int32 {
+	if x != nil {
+		return x.PlayerNumber
+	}
+	return 0
+}
+
+func (x *RoundStart) GetDoraIndicators() []int32 {
+	if x != nil {
+		return x.DoraIndicators
+	}
+	return nil
+}
+
+func (x *RoundStart) GetHand() []int32 {
+	if x != nil {
+		return x.Hand
+	}
+	return nil
+}
+
+func (x *RoundStart) GetRedFives() []int32 {
+	if x != nil {
+		return x.RedFives
+	}
+	return nil
+}
+
+func (x *RoundStart) GetSnapshot() []*PlayerSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type Draw struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tile    int32 `protobuf:"varint,1,opt,name=tile,proto3" json:"tile,omitempty"`
+	RedFive bool  `protobuf:"varint,2,opt,name=red_five,json=redFive,proto3" json:"red_five,omitempty"`
+}
+
+func (x *Draw) Reset() {
+	*x = Draw{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Draw) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Draw) ProtoMessage() {}
+
+func (x *Draw) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Draw.ProtoReflect.Descriptor instead.
+func (*Draw) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Draw) GetTile() int32 {
+	if x != nil {
+		return x.Tile
+	}
+	return 0
+}
+
+func (x *Draw) GetRedFive() bool {
+	if x != nil {
+		return x.RedFive
+	}
+	return false
+}
+
+type Discard struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Who       int32 `protobuf:"varint,1,opt,name=who,proto3" json:"who,omitempty"`
+	Tile      int32 `protobuf:"varint,2,opt,name=tile,proto3" json:"tile,omitempty"`
+	RedFive   bool  `protobuf:"varint,3,opt,name=red_five,json=redFive,proto3" json:"red_five,omitempty"`
+	Tsumogiri bool  `protobuf:"varint,4,opt,name=tsumogiri,proto3" json:"tsumogiri,omitempty"`
+	Riichi    bool  `protobuf:"varint,5,opt,name=riichi,proto3" json:"riichi,omitempty"`
+	// 自家能否鸣这张牌
+	CanBeMeld bool `protobuf:"varint,6,opt,name=can_be_meld,json=canBeMeld,proto3" json:"can_be_meld,omitempty"`
+}
+
+func (x *Discard) Reset() {
+	*x = Discard{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Discard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discard) ProtoMessage() {}
+
+func (x *Discard) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discard.ProtoReflect.Descriptor instead.
+func (*Discard) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Discard) GetWho() int32 {
+	if x != nil {
+		return x.Who
+	}
+	return 0
+}
+
+func (x *Discard) GetTile() int32 {
+	if x != nil {
+		return x.Tile
+	}
+	return 0
+}
+
+func (x *Discard) GetRedFive() bool {
+	if x != nil {
+		return x.RedFive
+	}
+	return false
+}
+
+func (x *Discard) GetTsumogiri() bool {
+	if x != nil {
+		return x.Tsumogiri
+	}
+	return false
+}
+
+func (x *Discard) GetRiichi() bool {
+	if x != nil {
+		return x.Riichi
+	}
+	return false
+}
+
+func (x *Discard) GetCanBeMeld() bool {
+	if x != nil {
+		return x.CanBeMeld
+	}
+	return false
+}
+
+type Call struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Who  int32 `protobuf:"varint,1,opt,name=who,proto3" json:"who,omitempty"`
+	Meld *Meld `protobuf:"bytes,2,opt,name=meld,proto3" json:"meld,omitempty"`
+}
+
+func (x *Call) Reset() {
+	*x = Call{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Call) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Call) ProtoMessage() {}
+
+func (x *Call) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Call.ProtoReflect.Descriptor instead.
+func (*Call) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Call) GetWho() int32 {
+	if x != nil {
+		return x.Who
+	}
+	return 0
+}
+
+func (x *Call) GetMeld() *Meld {
+	if x != nil {
+		return x.Meld
+	}
+	return nil
+}
+
+type Riichi struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Who int32 `protobuf:"varint,1,opt,name=who,proto3" json:"who,omitempty"`
+}
+
+func (x *Riichi) Reset() {
+	*x = Riichi{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Riichi) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Riichi) ProtoMessage() {}
+
+func (x *Riichi) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Riichi.ProtoReflect.Descriptor instead.
+func (*Riichi) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Riichi) GetWho() int32 {
+	if x != nil {
+		return x.Who
+	}
+	return 0
+}
+
+type NewDora struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Indicator int32 `protobuf:"varint,1,opt,name=indicator,proto3" json:"indicator,omitempty"`
+}
+
+func (x *NewDora) Reset() {
+	*x = NewDora{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewDora) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewDora) ProtoMessage() {}
+
+func (x *NewDora) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewDora.ProtoReflect.Descriptor instead.
+func (*NewDora) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NewDora) GetIndicator() int32 {
+	if x != nil {
+		return x.Indicator
+	}
+	return 0
+}
+
+type Nuki struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Who       int32 `protobuf:"varint,1,opt,name=who,proto3" json:"who,omitempty"`
+	Tsumogiri bool  `protobuf:"varint,2,opt,name=tsumogiri,proto3" json:"tsumogiri,omitempty"`
+}
+
+func (x *Nuki) Reset() {
+	*x = Nuki{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Nuki) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nuki) ProtoMessage() {}
+
+func (x *Nuki) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nuki.ProtoReflect.Descriptor instead.
+func (*Nuki) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Nuki) GetWho() int32 {
+	if x != nil {
+		return x.Who
+	}
+	return 0
+}
+
+func (x *Nuki) GetTsumogiri() bool {
+	if x != nil {
+		return x.Tsumogiri
+	}
+	return false
+}
+
+type Furiten struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Furiten) Reset() {
+	*x = Furiten{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Furiten) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Furiten) ProtoMessage() {}
+
+func (x *Furiten) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Furiten.ProtoReflect.Descriptor instead.
+func (*Furiten) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{18}
+}
+
+type Win struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Whos   []int32 `protobuf:"varint,1,rep,packed,name=whos,proto3" json:"whos,omitempty"`
+	Points []int32 `protobuf:"varint,2,rep,packed,name=points,proto3" json:"points,omitempty"`
+}
+
+func (x *Win) Reset() {
+	*x = Win{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Win) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Win) ProtoMessage() {}
+
+func (x *Win) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Win.ProtoReflect.Descriptor instead.
+func (*Win) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Win) GetWhos() []int32 {
+	if x != nil {
+		return x.Whos
+	}
+	return nil
+}
+
+func (x *Win) GetPoints() []int32 {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type DrawGame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type   int32   `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Whos   []int32 `protobuf:"varint,2,rep,packed,name=whos,proto3" json:"whos,omitempty"`
+	Points []int32 `protobuf:"varint,3,rep,packed,name=points,proto3" json:"points,omitempty"`
+}
+
+func (x *DrawGame) Reset() {
+	*x = DrawGame{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrawGame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawGame) ProtoMessage() {}
+
+func (x *DrawGame) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawGame.ProtoReflect.Descriptor instead.
+func (*DrawGame) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DrawGame) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *DrawGame) GetWhos() []int32 {
+	if x != nil {
+		return x.Whos
+	}
+	return nil
+}
+
+func (x *DrawGame) GetPoints() []int32 {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// 牌局事件，对应 event.Event
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*Event_GameStart
+	//	*Event_RoundStart
+	//	*Event_Draw
+	//	*Event_Discard
+	//	*Event_Call
+	//	*Event_Riichi
+	//	*Event_NewDora
+	//	*Event_Nuki
+	//	*Event_Furiten
+	//	*Event_Win
+	//	*Event_DrawGame
+	Event isEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{21}
+}
+
+func (m *Event) GetEvent() isEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *Event) GetGameStart() *GameStart {
+	if x, ok := x.GetEvent().(*Event_GameStart); ok {
+		return x.GameStart
+	}
+	return nil
+}
+
+func (x *Event) GetRoundStart() *RoundStart {
+	if x, ok := x.GetEvent().(*Event_RoundStart); ok {
+		return x.RoundStart
+	}
+	return nil
+}
+
+func (x *Event) GetDraw() *Draw {
+	if x, ok := x.GetEvent().(*Event_Draw); ok {
+		return x.Draw
+	}
+	return nil
+}
+
+func (x *Event) GetDiscard() *Discard {
+	if x, ok := x.GetEvent().(*Event_Discard); ok {
+		return x.Discard
+	}
+	return nil
+}
+
+func (x *Event) GetCall() *Call {
+	if x, ok := x.GetEvent().(*Event_Call); ok {
+		return x.Call
+	}
+	return nil
+}
+
+func (x *Event) GetRiichi() *Riichi {
+	if x, ok := x.GetEvent().(*Event_Riichi); ok {
+		return x.Riichi
+	}
+	return nil
+}
+
+func (x *Event) GetNewDora() *NewDora {
+	if x, ok := x.GetEvent().(*Event_NewDora); ok {
+		return x.NewDora
+	}
+	return nil
+}
+
+func (x *Event) GetNuki() *Nuki {
+	if x, ok := x.GetEvent().(*Event_Nuki); ok {
+		return x.Nuki
+	}
+	return nil
+}
+
+func (x *Event) GetFuriten() *Furiten {
+	if x, ok := x.GetEvent().(*Event_Furiten); ok {
+		return x.Furiten
+	}
+	return nil
+}
+
+func (x *Event) GetWin() *Win {
+	if x, ok := x.GetEvent().(*Event_Win); ok {
+		return x.Win
+	}
+	return nil
+}
+
+func (x *Event) GetDrawGame() *DrawGame {
+	if x, ok := x.GetEvent().(*Event_DrawGame); ok {
+		return x.DrawGame
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_GameStart struct {
+	GameStart *GameStart `protobuf:"bytes,1,opt,name=game_start,json=gameStart,proto3,oneof"`
+}
+
+type Event_RoundStart struct {
+	RoundStart *RoundStart `protobuf:"bytes,2,opt,name=round_start,json=roundStart,proto3,oneof"`
+}
+
+type Event_Draw struct {
+	Draw *Draw `protobuf:"bytes,3,opt,name=draw,proto3,oneof"`
+}
+
+type Event_Discard struct {
+	Discard *Discard `protobuf:"bytes,4,opt,name=discard,proto3,oneof"`
+}
+
+type Event_Call struct {
+	Call *Call `protobuf:"bytes,5,opt,name=call,proto3,oneof"`
+}
+
+type Event_Riichi struct {
+	Riichi *Riichi `protobuf:"bytes,6,opt,name=riichi,proto3,oneof"`
+}
+
+type Event_NewDora struct {
+	NewDora *NewDora `protobuf:"bytes,7,opt,name=new_dora,json=newDora,proto3,oneof"`
+}
+
+type Event_Nuki struct {
+	Nuki *Nuki `protobuf:"bytes,8,opt,name=nuki,proto3,oneof"`
+}
+
+type Event_Furiten struct {
+	Furiten *Furiten `protobuf:"bytes,9,opt,name=furiten,proto3,oneof"`
+}
+
+type Event_Win struct {
+	Win *Win `protobuf:"bytes,10,opt,name=win,proto3,oneof"`
+}
+
+type Event_DrawGame struct {
+	DrawGame *DrawGame `protobuf:"bytes,11,opt,name=draw_game,json=drawGame,proto3,oneof"`
+}
+
+func (*Event_GameStart) isEvent_Event() {}
+
+func (*Event_RoundStart) isEvent_Event() {}
+
+func (*Event_Draw) isEvent_Event() {}
+
+func (*Event_Discard) isEvent_Event() {}
+
+func (*Event_Call) isEvent_Event() {}
+
+func (*Event_Riichi) isEvent_Event() {}
+
+func (*Event_NewDora) isEvent_Event() {}
+
+func (*Event_Nuki) isEvent_Event() {}
+
+func (*Event_Furiten) isEvent_Event() {}
+
+func (*Event_Win) isEvent_Event() {}
+
+func (*Event_DrawGame) isEvent_Event() {}
+
+type EvaluateRiskRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 一局开始时的信息，需要包含 snapshot
+	Round *RoundStart `protobuf:"bytes,1,opt,name=round,proto3" json:"round,omitempty"`
+}
+
+func (x *EvaluateRiskRequest) Reset() {
+	*x = EvaluateRiskRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluateRiskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRiskRequest) ProtoMessage() {}
+
+func (x *EvaluateRiskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRiskRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRiskRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *EvaluateRiskRequest) GetRound() *RoundStart {
+	if x != nil {
+		return x.Round
+	}
+	return nil
+}
+
+// 他家的危险度信息
+type PlayerRisk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Who int32 `protobuf:"varint,1,opt,name=who,proto3" json:"who,omitempty"`
+	// 听牌率（百分比），立直时为 100
+	TenpaiRate      float64 `protobuf:"fixed64,2,opt,name=tenpai_rate,json=tenpaiRate,proto3" json:"tenpai_rate,omitempty"`
+	TsumogiriRiichi bool    `protobuf:"varint,3,opt,name=tsumogiri_riichi,json=tsumogiriRiichi,proto3" json:"tsumogiri_riichi,omitempty"`
+	// 对该玩家的安牌
+	SafeTiles []int32 `protobuf:"varint,4,rep,packed,name=safe_tiles,json=safeTiles,proto3" json:"safe_tiles,omitempty"`
+	// 各种牌的铳率（百分比），下标为牌
+	RiskTable []float64 `protobuf:"fixed64,5,rep,packed,name=risk_table,json=riskTable,proto3" json:"risk_table,omitempty"`
+	// 剩余无筋 123789 牌
+	LeftNoSujiTiles []int32 `protobuf:"varint,6,rep,packed,name=left_no_suji_tiles,json=leftNoSujiTiles,proto3" json:"left_no_suji_tiles,omitempty"`
+}
+
+func (x *PlayerRisk) Reset() {
+	*x = PlayerRisk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerRisk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerRisk) ProtoMessage() {}
+
+func (x *PlayerRisk) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerRisk.ProtoReflect.Descriptor instead.
+func (*PlayerRisk) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PlayerRisk) GetWho() int32 {
+	if x != nil {
+		return x.Who
+	}
+	return 0
+}
+
+func (x *PlayerRisk) GetTenpaiRate() float64 {
+	if x != nil {
+		return x.TenpaiRate
+	}
+	return 0
+}
+
+func (x *PlayerRisk) GetTsumogiriRiichi() bool {
+	if x != nil {
+		return x.TsumogiriRiichi
+	}
+	return false
+}
+
+func (x *PlayerRisk) GetSafeTiles() []int32 {
+	if x != nil {
+		return x.SafeTiles
+	}
+	return nil
+}
+
+func (x *PlayerRisk) GetRiskTable() []float64 {
+	if x != nil {
+		return x.RiskTable
+	}
+	return nil
+}
+
+func (x *PlayerRisk) GetLeftNoSujiTiles() []int32 {
+	if x != nil {
+		return x.LeftNoSujiTiles
+	}
+	return nil
+}
+
+type RiskEvaluation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 依次为下家、对家、上家（三麻时没有北家）
+	Players []*PlayerRisk `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	// 考虑了各家听牌率的综合危险度（百分比），下标为牌
+	MixedRiskTable []float64 `protobuf:"fixed64,2,rep,packed,name=mixed_risk_table,json=mixedRiskTable,proto3" json:"mixed_risk_table,omitempty"`
+}
+
+func (x *RiskEvaluation) Reset() {
+	*x = RiskEvaluation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RiskEvaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskEvaluation) ProtoMessage() {}
+
+func (x *RiskEvaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskEvaluation.ProtoReflect.Descriptor instead.
+func (*RiskEvaluation) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RiskEvaluation) GetPlayers() []*PlayerRisk {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *RiskEvaluation) GetMixedRiskTable() []float64 {
+	if x != nil {
+		return x.MixedRiskTable
+	}
+	return nil
+}
+
+type EventAnalysis struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 事件的序号，从 0 开始
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// 同 WebSocket 推送的类型：game_start round_start draw call risk round_end
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// 当前手牌的分析结果，对局开始前或 3k 张手牌时为空
+	Analysis *HandAnalysis   `protobuf:"bytes,3,opt,name=analysis,proto3" json:"analysis,omitempty"`
+	Risk     *RiskEvaluation `protobuf:"bytes,4,opt,name=risk,proto3" json:"risk,omitempty"`
+}
+
+func (x *EventAnalysis) Reset() {
+	*x = EventAnalysis{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_analysis_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventAnalysis) ProtoMessage() {}
+
+func (x *EventAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventAnalysis.ProtoReflect.Descriptor instead.
+func (*EventAnalysis) Descriptor() ([]byte, []int) {
+	return file_analysis_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *EventAnalysis) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *EventAnalysis) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EventAnalysis) GetAnalysis() *HandAnalysis {
+	if x != nil {
+		return x.Analysis
+	}
+	return nil
+}
+
+func (x *EventAnalysis) GetRisk() *RiskEvaluation {
+	if x != nil {
+		return x.Risk
+	}
+	return nil
+}
+
+var File_analysis_proto protoreflect.FileDescriptor
+
+var file_analysis_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x22, 0x54, 0x0a, 0x12, 0x41, 0x6e,
+	0x61, 0x6c, 0x79, 0x7a, 0x65, 0x48, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x72, 0x61, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x72, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x73,
+	0x75, 0x6d, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x74, 0x73, 0x75, 0x6d, 0x6f,
+	0x22, 0x73, 0x0a, 0x05, 0x57, 0x61, 0x69, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x77, 0x61, 0x69,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79,
+	0x73, 0x69, 0x73, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x73, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x77, 0x61, 0x69, 0x74, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x57,
+	0x61, 0x69, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc9, 0x07, 0x0a, 0x0c, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x33,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x68, 0x61, 0x6e, 0x74, 0x65,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x68, 0x61, 0x6e, 0x74, 0x65, 0x6e,
+	0x12, 0x37, 0x0a, 0x05, 0x77, 0x61, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31,
+	0x33, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x05, 0x77, 0x61, 0x69, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x61, 0x69,
+	0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x77, 0x61, 0x69, 0x74, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x0a, 0x64, 0x61,
+	0x6d, 0x61, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x33,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x44, 0x61, 0x6d, 0x61, 0x57, 0x61, 0x69, 0x74, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x64, 0x61, 0x6d, 0x61, 0x57, 0x61, 0x69, 0x74, 0x73,
+	0x12, 0x3e, 0x0a, 0x1c, 0x61, 0x76, 0x67, 0x5f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x73, 0x68, 0x61,
+	0x6e, 0x74, 0x65, 0x6e, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x18, 0x61, 0x76, 0x67, 0x4e, 0x65, 0x78, 0x74, 0x53,
+	0x68, 0x61, 0x6e, 0x74, 0x65, 0x6e, 0x57, 0x61, 0x69, 0x74, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x69, 0x78, 0x65, 0x64, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x73, 0x5f,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x69, 0x78,
+	0x65, 0x64, 0x57, 0x61, 0x69, 0x74, 0x73, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x40, 0x0a, 0x08,
+	0x69, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24,
+	0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x33,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x49, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x69, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x73, 0x12, 0x2a,
+	0x0a, 0x11, 0x69, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x5f, 0x77, 0x61, 0x79, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x69, 0x6d, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x57, 0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x17, 0x61, 0x76,
+	0x67, 0x5f, 0x69, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x73, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x61, 0x76, 0x67,
+	0x49, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x57, 0x61, 0x69, 0x74, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x67, 0x61, 0x72, 0x69, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x61, 0x67, 0x61, 0x72, 0x69, 0x52, 0x61, 0x74, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x66, 0x75, 0x72, 0x69, 0x74, 0x65, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x66, 0x75, 0x72, 0x69, 0x74, 0x65, 0x6e, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x79, 0x61, 0x6b, 0x75, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x79, 0x61, 0x6b, 0x75, 0x54, 0x79, 0x70,
+	0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x79, 0x61, 0x6b, 0x75, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x79, 0x61, 0x6b, 0x75, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x70, 0x61, 0x72, 0x74, 0x57, 0x61, 0x69, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x64, 0x6f, 0x72, 0x61, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x64, 0x6f, 0x72, 0x61, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x64, 0x61, 0x6d, 0x61, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x64, 0x61, 0x6d, 0x61, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0b, 0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x2a, 0x0a, 0x11, 0x6d, 0x69, 0x78, 0x65, 0x64, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x69, 0x78, 0x65,
+	0x64, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x38, 0x0a, 0x0a, 0x57,
+	0x61, 0x69, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e, 0x44, 0x61, 0x6d, 0x61, 0x57, 0x61, 0x69,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x4c, 0x0a, 0x0d, 0x49, 0x6d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x2e, 0x57, 0x61, 0x69, 0x74, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0xcd, 0x01, 0x0a, 0x0c, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x34, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x12, 0x21, 0x0a, 0x0c,
+	0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x6f, 0x72, 0x61, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x44, 0x6f, 0x72, 0x61, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x6e, 0x54, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x19,
+	0x0a, 0x08, 0x68, 0x61, 0x73, 0x5f, 0x72, 0x69, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x68, 0x61, 0x73, 0x52, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x69, 0x73,
+	0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x69, 0x73, 0x6b, 0x12, 0x32, 0x0a,
+	0x08, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x31, 0x33, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31,
+	0x33, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x08, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x31,
+	0x33, 0x22, 0xf1, 0x01, 0x0a, 0x0c, 0x4d, 0x65, 0x6c, 0x64, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65,
+	0x64, 0x5f, 0x66, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65,
+	0x64, 0x46, 0x69, 0x76, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x63,
+	0x68, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x43,
+	0x68, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x68, 0x61, 0x6e, 0x74, 0x65, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x68, 0x61, 0x6e, 0x74, 0x65, 0x6e, 0x12, 0x32, 0x0a, 0x08,
+	0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x34,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x08, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x73,
+	0x12, 0x43, 0x0a, 0x11, 0x62, 0x61, 0x63, 0x6b, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x69, 0x73,
+	0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x6e,
+	0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x34, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x10, 0x62, 0x61, 0x63, 0x6b, 0x77, 0x61, 0x72, 0x64, 0x44, 0x69, 0x73,
+	0x63, 0x61, 0x72, 0x64, 0x73, 0x22, 0x95, 0x02, 0x0a, 0x0c, 0x48, 0x61, 0x6e, 0x64, 0x41, 0x6e,
+	0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x68,
+	0x61, 0x6e, 0x74, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x68, 0x61,
+	0x6e, 0x74, 0x65, 0x6e, 0x12, 0x32, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x31, 0x33,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69,
+	0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x33, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x08,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x31, 0x33, 0x12, 0x32, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x63,
+	0x61, 0x72, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x6e, 0x61,
+	0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x34, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x08, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x73, 0x12, 0x43, 0x0a, 0x11,
+	0x62, 0x61, 0x63, 0x6b, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x31, 0x34, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x10, 0x62, 0x61, 0x63, 0x6b, 0x77, 0x61, 0x72, 0x64, 0x44, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64,
+	0x73, 0x12, 0x2a, 0x0a, 0x04, 0x6d, 0x65, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x4d, 0x65, 0x6c, 0x64, 0x41,
+	0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x04, 0x6d, 0x65, 0x6c, 0x64, 0x22, 0xe0, 0x01,
+	0x0a, 0x15, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x19, 0x0a,
+	0x08, 0x77, 0x69, 0x6e, 0x5f, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x77, 0x69, 0x6e, 0x54, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x72, 0x61,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x72, 0x61, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x73, 0x75, 0x6d, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x74, 0x73, 0x75,
+	0x6d, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61,
+	0x62, 0x75, 0x72, 0x69, 0x69, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x61, 0x62,
+	0x75, 0x72, 0x69, 0x69, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x77, 0x69,
+	0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x57,
+	0x69, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6c, 0x66, 0x5f, 0x77, 0x69, 0x6e, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x66, 0x57, 0x69, 0x6e, 0x64,
+	0x22, 0xa1, 0x02, 0x0a, 0x0b, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x5f,
+	0x63, 0x68, 0x69, 0x6c, 0x64, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0f, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x5f, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10,
+	0x74, 0x73, 0x75, 0x6d, 0x6f, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x68, 0x61, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x68,
+	0x61, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x66, 0x75, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02,
+	0x66, 0x75, 0x12, 0x23, 0x0a, 0x0d, 0x79, 0x61, 0x6b, 0x75, 0x6d, 0x61, 0x6e, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x79, 0x61, 0x6b, 0x75, 0x6d,
+	0x61, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x79, 0x61, 0x6b, 0x75, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x79, 0x61, 0x6b,
+	0x75, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x79, 0x61, 0x6b, 0x75, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x79, 0x61, 0x6b, 0x75,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x6f, 0x72, 0x61, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x64, 0x6f, 0x72, 0x61, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0xa1, 0x01, 0x0a, 0x04, 0x4d, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05,
+	0x52, 0x05, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x6c, 0x66, 0x5f,
+	0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65, 0x6c,
+	0x66, 0x54, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x72, 0x65, 0x64,
+	0x12, 0x26, 0x0a, 0x0f, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x6f, 0x74, 0x68,
+	0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x72, 0x65, 0x64, 0x46, 0x72,
+	0x6f, 0x6d, 0x4f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x22, 0xa4, 0x01, 0x0a, 0x0e, 0x50, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x24, 0x0a, 0x05, 0x6d,
+	0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x6e, 0x61,
+	0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x4d, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x6d, 0x65, 0x6c, 0x64,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x08, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72,
+	0x69, 0x69, 0x63, 0x68, 0x69, 0x12, 0x24, 0x0a, 0x0e, 0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x5f,
+	0x74, 0x69, 0x6c, 0x65, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72,
+	0x69, 0x69, 0x63, 0x68, 0x69, 0x54, 0x69, 0x6c, 0x65, 0x41, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x75, 0x6b, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6e, 0x75, 0x6b, 0x69, 0x22,
+	0x23, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x65,
+	0x61, 0x6c, 0x65, 0x72, 0x22, 0x81, 0x02, 0x0a, 0x0a, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x65, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x62, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x65, 0x61, 0x6c, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x65, 0x61,
+	0x6c, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x6f, 0x72, 0x61,
+	0x5f, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x05, 0x52, 0x0e, 0x64, 0x6f, 0x72, 0x61, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x04, 0x68, 0x61, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x76,
+	0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x64, 0x46, 0x69, 0x76,
+	0x65, 0x73, 0x12, 0x34, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x08,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x35, 0x0a, 0x04, 0x44, 0x72, 0x61, 0x77,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x74, 0x69, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x76, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x64, 0x46, 0x69, 0x76, 0x65, 0x22,
+	0xa0, 0x01, 0x0a, 0x07, 0x44, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x77,
+	0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x77, 0x68, 0x6f, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x69, 0x6c,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x64, 0x46, 0x69, 0x76, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x73, 0x75, 0x6d, 0x6f, 0x67, 0x69, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x67, 0x69, 0x72, 0x69, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x69,
+	0x69, 0x63, 0x68, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72, 0x69, 0x69, 0x63,
+	0x68, 0x69, 0x12, 0x1e, 0x0a, 0x0b, 0x63, 0x61, 0x6e, 0x5f, 0x62, 0x65, 0x5f, 0x6d, 0x65, 0x6c,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x42, 0x65, 0x4d, 0x65,
+	0x6c, 0x64, 0x22, 0x3c, 0x0a, 0x04, 0x43, 0x61, 0x6c, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x77, 0x68,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x77, 0x68, 0x6f, 0x12, 0x22, 0x0a, 0x04,
+	0x6d, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x6e, 0x61,
+	0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x4d, 0x65, 0x6c, 0x64, 0x52, 0x04, 0x6d, 0x65, 0x6c, 0x64,
+	0x22, 0x1a, 0x0a, 0x06, 0x52, 0x69, 0x69, 0x63, 0x68, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x77, 0x68,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x77, 0x68, 0x6f, 0x22, 0x27, 0x0a, 0x07,
+	0x4e, 0x65, 0x77, 0x44, 0x6f, 0x72, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x64, 0x69, 0x63,
+	0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6e, 0x64, 0x69,
+	0x63, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x36, 0x0a, 0x04, 0x4e, 0x75, 0x6b, 0x69, 0x12, 0x10, 0x0a,
+	0x03, 0x77, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x77, 0x68, 0x6f, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x67, 0x69, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x67, 0x69, 0x72, 0x69, 0x22, 0x09, 0x0a,
+	0x07, 0x46, 0x75, 0x72, 0x69, 0x74, 0x65, 0x6e, 0x22, 0x31, 0x0a, 0x03, 0x57, 0x69, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x77, 0x68, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x77,
+	0x68, 0x6f, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x4a, 0x0a, 0x08, 0x44,
+	0x72, 0x61, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x77,
+	0x68, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x77, 0x68, 0x6f, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x81, 0x04, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x34, 0x0a, 0x0a, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x48, 0x00, 0x52, 0x09, 0x67, 0x61,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x37, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61,
+	0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x12, 0x24, 0x0a, 0x04, 0x64, 0x72, 0x61, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x44, 0x72, 0x61, 0x77, 0x48, 0x00,
+	0x52, 0x04, 0x64, 0x72, 0x61, 0x77, 0x12, 0x2d, 0x0a, 0x07, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x48, 0x00, 0x52, 0x07, 0x64, 0x69,
+	0x73, 0x63, 0x61, 0x72, 0x64, 0x12, 0x24, 0x0a, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x43,
+	0x61, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x12, 0x2a, 0x0a, 0x06, 0x72,
+	0x69, 0x69, 0x63, 0x68, 0x69, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x6e,
+	0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x52, 0x69, 0x69, 0x63, 0x68, 0x69, 0x48, 0x00, 0x52,
+	0x06, 0x72, 0x69, 0x69, 0x63, 0x68, 0x69, 0x12, 0x2e, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x64,
+	0x6f, 0x72, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x6e, 0x61, 0x6c,
+	0x79, 0x73, 0x69, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x44, 0x6f, 0x72, 0x61, 0x48, 0x00, 0x52, 0x07,
+	0x6e, 0x65, 0x77, 0x44, 0x6f, 0x72, 0x61, 0x12, 0x24, 0x0a, 0x04, 0x6e, 0x75, 0x6b, 0x69, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x2e, 0x4e, 0x75, 0x6b, 0x69, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x75, 0x6b, 0x69, 0x12, 0x2d, 0x0a,
+	0x07, 0x66, 0x75, 0x72, 0x69, 0x74, 0x65, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x46, 0x75, 0x72, 0x69, 0x74, 0x65,
+	0x6e, 0x48, 0x00, 0x52, 0x07, 0x66, 0x75, 0x72, 0x69, 0x74, 0x65, 0x6e, 0x12, 0x21, 0x0a, 0x03,
+	0x77, 0x69, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x6e, 0x61, 0x6c,
+	0x79, 0x73, 0x69, 0x73, 0x2e, 0x57, 0x69, 0x6e, 0x48, 0x00, 0x52, 0x03, 0x77, 0x69, 0x6e, 0x12,
+	0x31, 0x0a, 0x09, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x44, 0x72,
+	0x61, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x48, 0x00, 0x52, 0x08, 0x64, 0x72, 0x61, 0x77, 0x47, 0x61,
+	0x6d, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x41, 0x0a, 0x13, 0x45,
+	0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x69, 0x73, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x52, 0x6f, 0x75,
+	0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0xd5,
+	0x01, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x69, 0x73, 0x6b, 0x12, 0x10, 0x0a,
+	0x03, 0x77, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x77, 0x68, 0x6f, 0x12,
+	0x1f, 0x0a, 0x0b, 0x74, 0x65, 0x6e, 0x70, 0x61, 0x69, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x65, 0x6e, 0x70, 0x61, 0x69, 0x52, 0x61, 0x74, 0x65,
+	0x12, 0x29, 0x0a, 0x10, 0x74, 0x73, 0x75, 0x6d, 0x6f, 0x67, 0x69, 0x72, 0x69, 0x5f, 0x72, 0x69,
+	0x69, 0x63, 0x68, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x74, 0x73, 0x75, 0x6d,
+	0x6f, 0x67, 0x69, 0x72, 0x69, 0x52, 0x69, 0x69, 0x63, 0x68, 0x69, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x61, 0x66, 0x65, 0x5f, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x09, 0x73, 0x61, 0x66, 0x65, 0x54, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x69,
+	0x73, 0x6b, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x01, 0x52, 0x09,
+	0x72, 0x69, 0x73, 0x6b, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x2b, 0x0a, 0x12, 0x6c, 0x65, 0x66,
+	0x74, 0x5f, 0x6e, 0x6f, 0x5f, 0x73, 0x75, 0x6a, 0x69, 0x5f, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0f, 0x6c, 0x65, 0x66, 0x74, 0x4e, 0x6f, 0x53, 0x75, 0x6a,
+	0x69, 0x54, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x6a, 0x0a, 0x0e, 0x52, 0x69, 0x73, 0x6b, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x6e, 0x61, 0x6c,
+	0x79, 0x73, 0x69, 0x73, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x69, 0x73, 0x6b, 0x52,
+	0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x69, 0x78, 0x65,
+	0x64, 0x5f, 0x72, 0x69, 0x73, 0x6b, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x01, 0x52, 0x0e, 0x6d, 0x69, 0x78, 0x65, 0x64, 0x52, 0x69, 0x73, 0x6b, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x22, 0x9b, 0x01, 0x0a, 0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x6e, 0x61, 0x6c,
+	0x79, 0x73, 0x69, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32,
+	0x0a, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64,
+	0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x12, 0x2c, 0x0a, 0x04, 0x72, 0x69, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x52, 0x69, 0x73, 0x6b,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x72, 0x69, 0x73, 0x6b,
+	0x32, 0xa1, 0x02, 0x0a, 0x08, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x12, 0x43, 0x0a,
+	0x0b, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x1c, 0x2e, 0x61,
+	0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x48,
+	0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x6e, 0x61,
+	0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x12, 0x48, 0x0a, 0x0e, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1f, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e,
+	0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x47, 0x0a, 0x0c,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x69, 0x73, 0x6b, 0x12, 0x1d, 0x2e, 0x61,
+	0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65,
+	0x52, 0x69, 0x73, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x61, 0x6e,
+	0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2e, 0x52, 0x69, 0x73, 0x6b, 0x45, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x0d, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69,
+	0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x28, 0x01, 0x30, 0x01, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x45, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x73, 0x43, 0x68, 0x65, 0x6e, 0x67, 0x2f,
+	0x6d, 0x61, 0x68, 0x6a, 0x6f, 0x6e, 0x67, 0x2d, 0x68, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x3b, 0x61, 0x6e,
+	0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_analysis_proto_rawDescOnce sync.Once
+	file_analysis_proto_rawDescData = file_analysis_proto_rawDesc
+)
+
+func file_analysis_proto_rawDescGZIP() []byte {
+	file_analysis_proto_rawDescOnce.Do(func() {
+		file_analysis_proto_rawDescData = protoimpl.X.CompressGZIP(file_analysis_proto_rawDescData)
+	})
+	return file_analysis_proto_rawDescData
+}
+
+var file_analysis_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_analysis_proto_goTypes = []interface{}{
+	(*AnalyzeHandRequest)(nil),    // 0: analysis.AnalyzeHandRequest
+	(*Waits)(nil),                 // 1: analysis.Waits
+	(*Hand13Result)(nil),          // 2: analysis.Hand13Result
+	(*Hand14Result)(nil),          // 3: analysis.Hand14Result
+	(*MeldAnalysis)(nil),          // 4: analysis.MeldAnalysis
+	(*HandAnalysis)(nil),          // 5: analysis.HandAnalysis
+	(*CalculatePointRequest)(nil), // 6: analysis.CalculatePointRequest
+	(*PointResult)(nil),           // 7: analysis.PointResult
+	(*Meld)(nil),                  // 8: analysis.Meld
+	(*PlayerSnapshot)(nil),        // 9: analysis.PlayerSnapshot
+	(*GameStart)(nil),             // 10: analysis.GameStart
+	(*RoundStart)(nil),            // 11: analysis.RoundStart
+	(*Draw)(nil),                  // 12: analysis.Draw
+	(*Discard)(nil),               // 13: analysis.Discard
+	(*Call)(nil),                  // 14: analysis.Call
+	(*Riichi)(nil),                // 15: analysis.Riichi
+	(*NewDora)(nil),               // 16: analysis.NewDora
+	(*Nuki)(nil),                  // 17: analysis.Nuki
+	(*Furiten)(nil),               // 18: analysis.Furiten
+	(*Win)(nil),                   // 19: analysis.Win
+	(*DrawGame)(nil),              // 20: analysis.DrawGame
+	(*Event)(nil),                 // 21: analysis.Event
+	(*EvaluateRiskRequest)(nil),   // 22: analysis.EvaluateRiskRequest
+	(*PlayerRisk)(nil),            // 23: analysis.PlayerRisk
+	(*RiskEvaluation)(nil),        // 24: analysis.RiskEvaluation
+	(*EventAnalysis)(nil),         // 25: analysis.EventAnalysis
+	nil,                           // 26: analysis.Waits.WaitsEntry
+	nil,                           // 27: analysis.Hand13Result.WaitsEntry
+	nil,                           // 28: analysis.Hand13Result.DamaWaitsEntry
+	nil,                           // 29: analysis.Hand13Result.ImprovesEntry
+}
+var file_analysis_proto_depIdxs = []int32{
+	26, // 0: analysis.Waits.waits:type_name -> analysis.Waits.WaitsEntry
+	27, // 1: analysis.Hand13Result.waits:type_name -> analysis.Hand13Result.WaitsEntry
+	28, // 2: analysis.Hand13Result.dama_waits:type_name -> analysis.Hand13Result.DamaWaitsEntry
+	29, // 3: analysis.Hand13Result.improves:type_name -> analysis.Hand13Result.ImprovesEntry
+	2,  // 4: analysis.Hand14Result.result13:type_name -> analysis.Hand13Result
+	3,  // 5: analysis.MeldAnalysis.discards:type_name -> analysis.Hand14Result
+	3,  // 6: analysis.MeldAnalysis.backward_discards:type_name -> analysis.Hand14Result
+	2,  // 7: analysis.HandAnalysis.result13:type_name -> analysis.Hand13Result
+	3,  // 8: analysis.HandAnalysis.discards:type_name -> analysis.Hand14Result
+	3,  // 9: analysis.HandAnalysis.backward_discards:type_name -> analysis.Hand14Result
+	4,  // 10: analysis.HandAnalysis.meld:type_name -> analysis.MeldAnalysis
+	8,  // 11: analysis.PlayerSnapshot.melds:type_name -> analysis.Meld
+	9,  // 12: analysis.RoundStart.snapshot:type_name -> analysis.PlayerSnapshot
+	8,  // 13: analysis.Call.meld:type_name -> analysis.Meld
+	10, // 14: analysis.Event.game_start:type_name -> analysis.GameStart
+	11, // 15: analysis.Event.round_start:type_name -> analysis.RoundStart
+	12, // 16: analysis.Event.draw:type_name -> analysis.Draw
+	13, // 17: analysis.Event.discard:type_name -> analysis.Discard
+	14, // 18: analysis.Event.call:type_name -> analysis.Call
+	15, // 19: analysis.Event.riichi:type_name -> analysis.Riichi
+	16, // 20: analysis.Event.new_dora:type_name -> analysis.NewDora
+	17, // 21: analysis.Event.nuki:type_name -> analysis.Nuki
+	18, // 22: analysis.Event.furiten:type_name -> analysis.Furiten
+	19, // 23: analysis.Event.win:type_name -> analysis.Win
+	20, // 24: analysis.Event.draw_game:type_name -> analysis.DrawGame
+	11, // 25: analysis.EvaluateRiskRequest.round:type_name -> analysis.RoundStart
+	23, // 26: analysis.RiskEvaluation.players:type_name -> analysis.PlayerRisk
+	5,  // 27: analysis.EventAnalysis.analysis:type_name -> analysis.HandAnalysis
+	24, // 28: analysis.EventAnalysis.risk:type_name -> analysis.RiskEvaluation
+	1,  // 29: analysis.Hand13Result.ImprovesEntry.value:type_name -> analysis.Waits
+	0,  // 30: analysis.Analysis.AnalyzeHand:input_type -> analysis.AnalyzeHandRequest
+	6,  // 31: analysis.Analysis.CalculatePoint:input_type -> analysis.CalculatePointRequest
+	22, // 32: analysis.Analysis.EvaluateRisk:input_type -> analysis.EvaluateRiskRequest
+	21, // 33: analysis.Analysis.AnalyzeEvents:input_type -> analysis.Event
+	5,  // 34: analysis.Analysis.AnalyzeHand:output_type -> analysis.HandAnalysis
+	7,  // 35: analysis.Analysis.CalculatePoint:output_type -> analysis.PointResult
+	24, // 36: analysis.Analysis.EvaluateRisk:output_type -> analysis.RiskEvaluation
+	25, // 37: analysis.Analysis.AnalyzeEvents:output_type -> analysis.EventAnalysis
+	34, // [34:38] is the sub-list for method output_type
+	30, // [30:34] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_analysis_proto_init() }
+func file_analysis_proto_init() {
+	if File_analysis_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_analysis_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnalyzeHandRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Waits); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Hand13Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Hand14Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MeldAnalysis); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandAnalysis); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CalculatePointRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PointResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Meld); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameStart); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoundStart); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Draw); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Discard); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Call); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Riichi); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewDora); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Nuki); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Furiten); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Win); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrawGame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluateRiskRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerRisk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RiskEvaluation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_analysis_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventAnalysis); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_analysis_proto_msgTypes[21].OneofWrappers = []interface{}{
+		(*Event_GameStart)(nil),
+		(*Event_RoundStart)(nil),
+		(*Event_Draw)(nil),
+		(*Event_Discard)(nil),
+		(*Event_Call)(nil),
+		(*Event_Riichi)(nil),
+		(*Event_NewDora)(nil),
+		(*Event_Nuki)(nil),
+		(*Event_Furiten)(nil),
+		(*Event_Win)(nil),
+		(*Event_DrawGame)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_analysis_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analysis_proto_goTypes,
+		DependencyIndexes: file_analysis_proto_depIdxs,
+		MessageInfos:      file_analysis_proto_msgTypes,
+	}.Build()
+	File_analysis_proto = out.File
+	file_analysis_proto_rawDesc = nil
+	file_analysis_proto_goTypes = nil
+	file_analysis_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// AnalysisClient is the client API for Analysis service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AnalysisClient interface {
+	// 何切分析：3k+1 张手牌分析进张，3k+2 张手牌分析各种切法，3k+1 张手牌 + 要鸣的牌时还分析鸣牌
+	AnalyzeHand(ctx context.Context, in *AnalyzeHandRequest, opts ...grpc.CallOption) (*HandAnalysis, error)
+	// 计算已和牌的手牌的点数
+	CalculatePoint(ctx context.Context, in *CalculatePointRequest, opts ...grpc.CallOption) (*PointResult, error)
+	// 根据牌局计算各家的危险度
+	EvaluateRisk(ctx context.Context, in *EvaluateRiskRequest, opts ...grpc.CallOption) (*RiskEvaluation, error)
+	// 依次发送牌局事件，每处理完一个事件返回一次分析结果
+	AnalyzeEvents(ctx context.Context, opts ...grpc.CallOption) (Analysis_AnalyzeEventsClient, error)
+}
+
+type analysisClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalysisClient(cc grpc.ClientConnInterface) AnalysisClient {
+	return &analysisClient{cc}
+}
+
+func (c *analysisClient) AnalyzeHand(ctx context.Context, in *AnalyzeHandRequest, opts ...grpc.CallOption) (*HandAnalysis, error) {
+	out := new(HandAnalysis)
+	err := c.cc.Invoke(ctx, "/analysis.Analysis/AnalyzeHand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analysisClient) CalculatePoint(ctx context.Context, in *CalculatePointRequest, opts ...grpc.CallOption) (*PointResult, error) {
+	out := new(PointResult)
+	err := c.cc.Invoke(ctx, "/analysis.Analysis/CalculatePoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analysisClient) EvaluateRisk(ctx context.Context, in *EvaluateRiskRequest, opts ...grpc.CallOption) (*RiskEvaluation, error) {
+	out := new(RiskEvaluation)
+	err := c.cc.Invoke(ctx, "/analysis.Analysis/EvaluateRisk", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analysisClient) AnalyzeEvents(ctx context.Context, opts ...grpc.CallOption) (Analysis_AnalyzeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Analysis_serviceDesc.Streams[0], "/analysis.Analysis/AnalyzeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &analysisAnalyzeEventsClient{stream}
+	return x, nil
+}
+
+type Analysis_AnalyzeEventsClient interface {
+	Send(*Event) error
+	Recv() (*EventAnalysis, error)
+	grpc.ClientStream
+}
+
+type analysisAnalyzeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *analysisAnalyzeEventsClient) Send(m *Event) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *analysisAnalyzeEventsClient) Recv() (*EventAnalysis, error) {
+	m := new(EventAnalysis)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AnalysisServer is the server API for Analysis service.
+type AnalysisServer interface {
+	// 何切分析：3k+1 张手牌分析进张，3k+2 张手牌分析各种切法，3k+1 张手牌 + 要鸣的牌时还分析鸣牌
+	AnalyzeHand(context.Context, *AnalyzeHandRequest) (*HandAnalysis, error)
+	// 计算已和牌的手牌的点数
+	CalculatePoint(context.Context, *CalculatePointRequest) (*PointResult, error)
+	// 根据牌局计算各家的危险度
+	EvaluateRisk(context.Context, *EvaluateRiskRequest) (*RiskEvaluation, error)
+	// 依次发送牌局事件，每处理完一个事件返回一次分析结果
+	AnalyzeEvents(Analysis_AnalyzeEventsServer) error
+}
+
+// UnimplementedAnalysisServer can be embedded to have forward compatible implementations.
+type UnimplementedAnalysisServer struct {
+}
+
+func (*UnimplementedAnalysisServer) AnalyzeHand(context.Context, *AnalyzeHandRequest) (*HandAnalysis, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeHand not implemented")
+}
+func (*UnimplementedAnalysisServer) CalculatePoint(context.Context, *CalculatePointRequest) (*PointResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculatePoint not implemented")
+}
+func (*UnimplementedAnalysisServer) EvaluateRisk(context.Context, *EvaluateRiskRequest) (*RiskEvaluation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateRisk not implemented")
+}
+func (*UnimplementedAnalysisServer) AnalyzeEvents(Analysis_AnalyzeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method AnalyzeEvents not implemented")
+}
+
+func RegisterAnalysisServer(s *grpc.Server, srv AnalysisServer) {
+	s.RegisterService(&_Analysis_serviceDesc, srv)
+}
+
+func _Analysis_AnalyzeHand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeHandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalysisServer).AnalyzeHand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/analysis.Analysis/AnalyzeHand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalysisServer).AnalyzeHand(ctx, req.(*AnalyzeHandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analysis_CalculatePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculatePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalysisServer).CalculatePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/analysis.Analysis/CalculatePoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalysisServer).CalculatePoint(ctx, req.(*CalculatePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analysis_EvaluateRisk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRiskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalysisServer).EvaluateRisk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/analysis.Analysis/EvaluateRisk",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalysisServer).EvaluateRisk(ctx, req.(*EvaluateRiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analysis_AnalyzeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AnalysisServer).AnalyzeEvents(&analysisAnalyzeEventsServer{stream})
+}
+
+type Analysis_AnalyzeEventsServer interface {
+	Send(*EventAnalysis) error
+	Recv() (*Event, error)
+	grpc.ServerStream
+}
+
+type analysisAnalyzeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *analysisAnalyzeEventsServer) Send(m *EventAnalysis) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *analysisAnalyzeEventsServer) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Analysis_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "analysis.Analysis",
+	HandlerType: (*AnalysisServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AnalyzeHand",
+			Handler:    _Analysis_AnalyzeHand_Handler,
+		},
+		{
+			MethodName: "CalculatePoint",
+			Handler:    _Analysis_CalculatePoint_Handler,
+		},
+		{
+			MethodName: "EvaluateRisk",
+			Handler:    _Analysis_EvaluateRisk_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AnalyzeEvents",
+			Handler:       _Analysis_AnalyzeEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "analysis.proto",
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+)
+
+// 通过 WebSocket 向浏览器悬浮窗、副屏等推送实时的分析结果
+// 每处理完一个牌局事件，就推送一次当前牌局及其分析结果（与 GET /api/v1/:platform/state 的内容相同）
+
+// 推送的类型
+const (
+	pushTypeGameStart  = "game_start"  // 游戏开始（就坐）
+	pushTypeRoundStart = "round_start" // 一局开始，含配牌的分析
+	pushTypeDraw       = "draw"        // 自家摸牌，含何切分析
+	pushTypeCall       = "call"        // 他家舍牌且自家可以鸣牌，含鸣牌分析
+	pushTypeRisk       = "risk"        // 舍牌、鸣牌、立直、新宝牌等，危险度有更新
+	pushTypeRoundEnd   = "round_end"   // 和牌或流局
+	pushTypeReview     = "review"      // 观看牌谱时，本局的舍牌推荐计算完成
+)
+
+// 订阅者处理不过来时，丢弃之后的消息
+const pushSubscriberBufferSize = 64
+
+type apiPushMessage struct {
+	Version  int    `json:"version"`
+	Session  string `json:"session"`
+	Platform string `json:"platform"`
+	Type     string `json:"type"`
+
+	// 触发这次推送的事件，review 时为 null
+	Event *event.Event `json:"event"`
+
+	State *apiGameState `json:"state"`
+}
+
+type pushSubscriber struct {
+	// 只接收该会话的消息，allSessions 为 true 时接收所有会话的消息
+	sessionID   string
+	allSessions bool
+
+	// 只接收该平台的消息，为空时不过滤
+	platform string
+
+	messages chan []byte
+}
+
+func (sub *pushSubscriber) match(sessionID string, platform string) bool {
+	return (sub.allSessions || sub.sessionID == sessionID) && (sub.platform == "" || sub.platform == platform)
+}
+
+// 管理所有的订阅者，并发安全
+type pushHub struct {
+	mu          sync.RWMutex
+	subscribers map[*pushSubscriber]struct{}
+}
+
+func newPushHub() *pushHub {
+	return &pushHub{subscribers: map[*pushSubscriber]struct{}{}}
+}
+
+func (h *pushHub) subscribe(sessionID string, allSessions bool, platform string) *pushSubscriber {
+	sub := &pushSubscriber{
+		sessionID:   sessionID,
+		allSessions: allSessions,
+		platform:    platform,
+		messages:    make(chan []byte, pushSubscriberBufferSize),
+	}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *pushHub) unsubscribe(sub *pushSubscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+}
+
+// 是否有订阅者，没有时不必生成推送的内容
+func (h *pushHub) hasSubscribers(sessionID string, platform string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers {
+		if sub.match(sessionID, platform) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *pushHub) publish(msg *apiPushMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers {
+		if !sub.match(msg.Session, msg.Platform) {
+			continue
+		}
+		select {
+		case sub.messages <- data:
+		default:
+		}
+	}
+	return nil
+}
+
+// 会话中某个平台的推送来源
+type analysisPusher struct {
+	hub       *pushHub
+	sessionID string
+	platform  string
+}
+
+func pushType(e *event.Event, state *apiGameState) string {
+	if e == nil {
+		return pushTypeReview
+	}
+	switch e.Type {
+	case event.TypeGameStart:
+		return pushTypeGameStart
+	case event.TypeRoundStart:
+		return pushTypeRoundStart
+	case event.TypeDraw:
+		return pushTypeDraw
+	case event.TypeDiscard:
+		if e.Discard.Who > 0 && state.Analysis != nil && state.Analysis.Meld != nil {
+			return pushTypeCall
+		}
+		return pushTypeRisk
+	case event.TypeWin, event.TypeDrawGame:
+		return pushTypeRoundEnd
+	default:
+		return pushTypeRisk
+	}
+}
+
+// 推送处理完事件 e 后的牌局，e 为 nil 时表示牌谱的舍牌推荐计算完成
+// 调用时需持有 mjSession.mu
+func (p *analysisPusher) push(d *roundData, e *event.Event) error {
+	if !p.hub.hasSubscribers(p.sessionID, p.platform) {
+		return nil
+	}
+
+	state, err := d.apiGameState(p.platform)
+	if err != nil {
+		return fmt.Errorf("推送失败：%v", err)
+	}
+	return p.hub.publish(&apiPushMessage{
+		Version:  analysisAPIVersion,
+		Session:  p.sessionID,
+		Platform: p.platform,
+		Type:     pushType(e, state),
+		Event:    e,
+		State:    state,
+	})
+}
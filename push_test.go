@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pushHub(t *testing.T) {
+	assert := assert.New(t)
+
+	hub := newPushHub()
+	subA := hub.subscribe("a", false, "")
+	subAll := hub.subscribe("", true, "majsoul")
+	assert.True(hub.hasSubscribers("a", "tenhou"))
+	assert.True(hub.hasSubscribers("b", "majsoul"))
+	assert.False(hub.hasSubscribers("b", "tenhou"))
+
+	assert.NoError(hub.publish(&apiPushMessage{Session: "a", Platform: "tenhou"}))
+	assert.Len(subA.messages, 1)
+	assert.Len(subAll.messages, 0)
+
+	assert.NoError(hub.publish(&apiPushMessage{Session: "b", Platform: "majsoul"}))
+	assert.Len(subA.messages, 1)
+	assert.Len(subAll.messages, 1)
+
+	// 订阅者处理不过来时丢弃消息，不会阻塞
+	for i := 0; i < 2*pushSubscriberBufferSize; i++ {
+		assert.NoError(hub.publish(&apiPushMessage{Session: "a", Platform: "tenhou"}))
+	}
+	assert.Len(subA.messages, pushSubscriberBufferSize)
+
+	hub.unsubscribe(subA)
+	assert.False(hub.hasSubscribers("a", "tenhou"))
+}
+
+func Test_mjHandler_apiPush(t *testing.T) {
+	assert := assert.New(t)
+
+	h := newMjHandler(nil)
+	e := echo.New()
+	e.GET(analysisAPIPrefix+"/push", h.apiPush)
+	server := httptest.NewServer(e)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + analysisAPIPrefix + "/push"
+
+	resp, err := http.Get(server.URL + analysisAPIPrefix + "/push?platform=unknown")
+	if assert.NoError(err) {
+		assert.Equal(http.StatusBadRequest, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	dial := func(query string) *websocket.Conn {
+		ws, _, err := websocket.DefaultDialer.Dial(wsURL+"?"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+	wsA := dial("session=a")
+	defer wsA.Close()
+	wsAll := dial("all=true&platform=tenhou")
+	defer wsAll.Close()
+	wsB := dial("session=b")
+	defer wsB.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(sessionIDHeader, "a")
+	s, err := h.getSession(e.NewContext(req, httptest.NewRecorder()))
+	if !assert.NoError(err) {
+		return
+	}
+	defer s.close()
+	s.mu.Lock()
+	for _, ev := range []*event.Event{
+		{Type: event.TypeRoundStart, RoundStart: &event.RoundStart{
+			Dealer:         3,
+			PlayerNumber:   4,
+			DoraIndicators: []int{0},
+			HandTiles:      []int{0, 1, 2, 12, 13, 14, 24, 25, 26, 27, 27, 2, 3},
+			NumRedFives:    []int{0, 0, 0},
+		}},
+		{Type: event.TypeDiscard, Discard: &event.Discard{Who: 3, Tile: 4, CanBeMeld: true}},
+		{Type: event.TypeDrawGame, DrawGame: &event.DrawGame{}},
+	} {
+		assert.NoError(s.tenhouRoundData.handleEvent(ev))
+	}
+	s.mu.Unlock()
+
+	readTypes := func(ws *websocket.Conn, n int) (types []string) {
+		for i := 0; i < n; i++ {
+			ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+			_, data, err := ws.ReadMessage()
+			if !assert.NoError(err) {
+				return
+			}
+			msg := apiPushMessage{}
+			if assert.NoError(json.Unmarshal(data, &msg)) {
+				assert.Equal(analysisAPIVersion, msg.Version)
+				assert.Equal("a", msg.Session)
+				assert.Equal("tenhou", msg.Platform)
+				assert.NotNil(msg.State)
+				types = append(types, msg.Type)
+			}
+		}
+		return
+	}
+	expected := []string{pushTypeRoundStart, pushTypeCall, pushTypeRoundEnd}
+	assert.Equal(expected, readTypes(wsA, len(expected)))
+	assert.Equal(expected, readTypes(wsAll, len(expected)))
+
+	// 其他会话收不到推送
+	wsB.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err = wsB.ReadMessage()
+	assert.Error(err)
+}
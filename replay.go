@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"io"
+	"os"
+	"strings"
+)
+
+// 回放牌局数据日志（log/gamedata-xxx.log），或每行一条天凤、雀魂 JSON 消息的文件
+// 消息按与实战相同的流程交给会话处理，可以选择回放哪一局游戏、从哪一局开始到哪一局结束，以及单步执行
+
+// 日志中的一条消息
+type gameDataLogMessage struct {
+	lineNumber int
+	message    string
+	isTenhou   bool
+	isLogin    bool
+
+	// 一局开始时的场数和本场数，其他消息的 isRoundStart 为 false
+	isRoundStart bool
+	roundNumber  int
+	benNumber    int
+}
+
+// 日志中的一局游戏
+type gameDataLogGame struct {
+	messages []*gameDataLogMessage
+
+	// 该局游戏之前最近的登录消息，单独回放该局游戏时需要先处理它
+	login *gameDataLogMessage
+}
+
+func (g *gameDataLogGame) rounds() (rounds []*gameDataLogMessage) {
+	for _, msg := range g.messages {
+		if msg.isRoundStart {
+			rounds = append(rounds, msg)
+		}
+	}
+	return
+}
+
+func (g *gameDataLogGame) String() string {
+	if len(g.messages) == 0 {
+		return ""
+	}
+	first, last := g.messages[0], g.messages[len(g.messages)-1]
+	platform := "雀魂"
+	if first.isTenhou {
+		platform = "天凤"
+	}
+	s := fmt.Sprintf("%s 第 %d-%d 行", platform, first.lineNumber, last.lineNumber)
+	if rounds := g.rounds(); len(rounds) > 0 {
+		firstRound, lastRound := rounds[0], rounds[len(rounds)-1]
+		s += fmt.Sprintf("，%s - %s，共 %d 局", roundName(firstRound.roundNumber, firstRound.benNumber), roundName(lastRound.roundNumber, lastRound.benNumber), len(rounds))
+	}
+	return s
+}
+
+// 旧版日志在每次启动服务时写入的分隔线
+const legacyServerStartMarker = "=============="
+
+// 读取日志，按游戏拆分
+// 每行可以是日志格式的 JSON（取其中 level 为 INFO 的 message），也可以直接是天凤或雀魂的消息
+func readGameDataLog(r io.Reader) ([]*gameDataLogGame, error) {
+	games := []*gameDataLogGame{}
+	game := &gameDataLogGame{}
+	hasRound := false
+	var login *gameDataLogMessage
+
+	newGame := func() {
+		if len(game.messages) > 0 {
+			games = append(games, game)
+		}
+		game = &gameDataLogGame{login: login}
+		hasRound = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxDataBodySize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		message := line
+		entry := gameDataLogEntry{}
+		if err := json.Unmarshal([]byte(line), &entry); err == nil && entry.Level != "" {
+			if strings.Contains(entry.Message, legacyServerStartMarker) {
+				newGame()
+				continue
+			}
+			if entry.Level != "INFO" {
+				if debugMode {
+					fmt.Printf("第 %d 行：%s %s\n", lineNumber, entry.Level, entry.Message)
+				}
+				continue
+			}
+			message = entry.Message
+		}
+
+		header := parseGameDataMessageHeader(message)
+		if header == nil {
+			// 如「服务启动」
+			continue
+		}
+		if header.isGameStart() && hasRound {
+			newGame()
+		}
+
+		msg := &gameDataLogMessage{
+			lineNumber: lineNumber,
+			message:    message,
+			isTenhou:   header.isTenhou(),
+			isLogin:    header.isLogin(),
+		}
+		msg.roundNumber, msg.benNumber, msg.isRoundStart = header.round()
+		game.messages = append(game.messages, msg)
+		if msg.isRoundStart {
+			hasRound = true
+		}
+		if msg.isLogin {
+			login = msg
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	newGame()
+	return games, nil
+}
+
+//
+
+// 回放的起止局
+type replayRound struct {
+	roundNumber  int
+	benNumber    int
+	hasBenNumber bool
+}
+
+// 格式为 东2局1本场，不指定本场数时表示该局的所有本场
+func parseReplayRound(s string) (*replayRound, error) {
+	if s == "" {
+		return nil, nil
+	}
+	roundNumber, benNumber, hasBenNumber, ok := parseRoundName(s)
+	if !ok {
+		return nil, fmt.Errorf("局的格式错误：%s（示例：东2局1本场、南3局）", s)
+	}
+	return &replayRound{roundNumber, benNumber, hasBenNumber}, nil
+}
+
+// 返回 -1 0 1，分别表示 msg 所在的局在 r 之前、与 r 相同、在 r 之后
+func (r *replayRound) compare(msg *gameDataLogMessage) int {
+	switch {
+	case msg.roundNumber < r.roundNumber:
+		return -1
+	case msg.roundNumber > r.roundNumber:
+		return 1
+	case !r.hasBenNumber || msg.benNumber == r.benNumber:
+		return 0
+	case msg.benNumber < r.benNumber:
+		return -1
+	default:
+		return 1
+	}
+}
+
+type gameDataReplayer struct {
+	session *mjSession
+
+	// 起止局，为 nil 表示不限
+	from *replayRound
+	to   *replayRound
+
+	// 最近处理的登录消息
+	lastLogin *gameDataLogMessage
+
+	// 单步执行时每处理一条消息后等待输入
+	step bool
+	// 单步执行时跳到下一局的开始
+	skipToNextRound bool
+	input           *bufio.Reader
+}
+
+func newGameDataReplayer(from *replayRound, to *replayRound, step bool, input io.Reader) *gameDataReplayer {
+	return &gameDataReplayer{
+		session: newMjSession("", nil),
+		from:    from,
+		to:      to,
+		step:    step,
+		input:   bufio.NewReader(input),
+	}
+}
+
+func (r *gameDataReplayer) setSkipOutput(skipOutput bool) {
+	r.session.tenhouRoundData.skipOutput = skipOutput
+	r.session.majsoulRoundData.skipOutput = skipOutput
+}
+
+func (r *gameDataReplayer) process(msg *gameDataLogMessage) {
+	if msg.isTenhou {
+		r.session.processTenhouMessage([]byte(msg.message))
+	} else {
+		r.session.processMajsoulMessage([]byte(msg.message))
+	}
+	if msg.isLogin {
+		r.lastLogin = msg
+	}
+}
+
+// 单步执行时等待输入，返回 false 表示退出回放
+func (r *gameDataReplayer) wait(msg *gameDataLogMessage) bool {
+	if !r.step {
+		return true
+	}
+	if r.skipToNextRound {
+		if !msg.isRoundStart {
+			return true
+		}
+		r.skipToNextRound = false
+	}
+
+	fmt.Printf("[第 %d 行] 回车 - 下一步，n - 下一局，c - 继续回放，q - 退出：", msg.lineNumber)
+	line, err := r.input.ReadString('\n')
+	if err != nil && line == "" {
+		// 没有更多输入了，回放剩余部分
+		fmt.Println()
+		r.step = false
+		return true
+	}
+	switch strings.TrimSpace(line) {
+	case "n":
+		r.skipToNextRound = true
+	case "c":
+		r.step = false
+	case "q":
+		return false
+	}
+	return true
+}
+
+// 回放一局游戏，返回 false 表示退出回放
+func (r *gameDataReplayer) replayGame(game *gameDataLogGame) bool {
+	started := r.from == nil
+	r.setSkipOutput(!started)
+	if game.login != nil && game.login != r.lastLogin {
+		r.setSkipOutput(true)
+		r.process(game.login)
+		r.setSkipOutput(!started)
+	}
+
+	for _, msg := range game.messages {
+		if msg.isRoundStart {
+			if r.to != nil && r.to.compare(msg) > 0 {
+				return true
+			}
+			if !started && r.from.compare(msg) >= 0 {
+				started = true
+				r.setSkipOutput(false)
+			}
+		}
+		r.process(msg)
+		if started && !r.wait(msg) {
+			return false
+		}
+	}
+	return true
+}
+
+// 回放牌局数据日志
+// gameIndex 为回放第几局游戏（从 1 开始，负数表示倒数第几局），为 0 时回放全部游戏
+// from 和 to 为开始和结束的局（如 东2局1本场），为空表示不限
+func replayGameDataLogFile(filePath string, gameIndex int, from string, to string, step bool) error {
+	fromRound, err := parseReplayRound(from)
+	if err != nil {
+		return err
+	}
+	toRound, err := parseReplayRound(to)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	games, err := readGameDataLog(f)
+	if err != nil {
+		return err
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("%s 中没有天凤或雀魂的消息", filePath)
+	}
+
+	fmt.Printf("共 %d 局游戏：\n", len(games))
+	for i, game := range games {
+		fmt.Printf("%d - %s\n", i+1, game)
+	}
+	if gameIndex < -len(games) || gameIndex > len(games) {
+		return fmt.Errorf("没有第 %d 局游戏", gameIndex)
+	}
+	if gameIndex < 0 {
+		gameIndex += len(games) + 1
+	}
+	if gameIndex > 0 {
+		games = games[gameIndex-1 : gameIndex]
+		color.HiGreen("回放第 %d 局游戏", gameIndex)
+	}
+	fmt.Println()
+
+	r := newGameDataReplayer(fromRound, toRound, step, os.Stdin)
+	if !debugMode {
+		defer func() {
+			if err := recover(); err != nil {
+				fmt.Println("内部错误：", err)
+			}
+		}()
+	}
+	for _, game := range games {
+		if !r.replayGame(game) {
+			break
+		}
+	}
+	return nil
+}
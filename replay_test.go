@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testGameDataLog = `{"time":"2019-07-15T20:13:49+08:00","level":"INFO","message":"============================================================================================"}
+{"time":"2019-07-15T20:13:49+08:00","level":"INFO","message":"服务启动"}
+{"time":"2019-07-15T20:13:50+08:00","level":"INFO","message":"{\"tag\":\"HELO\",\"uname\":\"%41\"}"}
+{"time":"2019-07-15T20:13:51+08:00","level":"INFO","message":"{\"tag\":\"GO\",\"type\":\"169\"}"}
+{"time":"2019-07-15T20:13:52+08:00","level":"INFO","message":"{\"tag\":\"INIT\",\"seed\":\"0,0,0,1,2,36\",\"ten\":\"250,250,250,250\",\"oya\":\"0\",\"hai\":\"0,4,8,12,16,21,37,40,44,72,76,80,108\"}"}
+{"time":"2019-07-15T20:13:53+08:00","level":"ERROR","message":"错误"}
+{"tag":"INIT","seed":"1,0,0,1,2,36","ten":"250,250,250,250","oya":"1","hai":"0,4,8,12,16,21,37,40,44,72,76,80,108"}
+{"tag":"INIT","seed":"1,1,0,1,2,36","ten":"250,250,250,250","oya":"1","hai":"0,4,8,12,16,21,37,40,44,72,76,80,108"}
+{"tag":"GO","type":"169"}
+{"tag":"INIT","seed":"0,0,0,1,2,36","ten":"250,250,250,250","oya":"0","hai":"0,4,8,12,16,21,37,40,44,72,76,80,108"}
+{"tag":"INIT","seed":"4,0,0,1,2,36","ten":"250,250,250,250","oya":"0","hai":"0,4,8,12,16,21,37,40,44,72,76,80,108"}
+`
+
+func Test_readGameDataLog(t *testing.T) {
+	assert := assert.New(t)
+
+	games, err := readGameDataLog(strings.NewReader(testGameDataLog))
+	if !assert.NoError(err) || !assert.Len(games, 2) {
+		return
+	}
+
+	assert.Nil(games[0].login)
+	assert.Len(games[0].messages, 5)
+	assert.Equal(3, games[0].messages[0].lineNumber)
+	assert.True(games[0].messages[0].isLogin)
+	assert.True(games[0].messages[0].isTenhou)
+	if rounds := games[0].rounds(); assert.Len(rounds, 3) {
+		assert.Equal(1, rounds[2].roundNumber)
+		assert.Equal(1, rounds[2].benNumber)
+	}
+	assert.Equal("天凤 第 3-8 行，东1局0本场 - 东2局1本场，共 3 局", games[0].String())
+
+	assert.True(games[1].login == games[0].messages[0])
+	assert.Len(games[1].rounds(), 2)
+}
+
+func Test_replayRound_compare(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := parseReplayRound("东5局")
+	assert.Error(err)
+
+	r, err := parseReplayRound("东2局1本场")
+	if assert.NoError(err) {
+		assert.Equal(-1, r.compare(&gameDataLogMessage{roundNumber: 1, benNumber: 0}))
+		assert.Equal(0, r.compare(&gameDataLogMessage{roundNumber: 1, benNumber: 1}))
+		assert.Equal(1, r.compare(&gameDataLogMessage{roundNumber: 1, benNumber: 2}))
+		assert.Equal(1, r.compare(&gameDataLogMessage{roundNumber: 4, benNumber: 0}))
+	}
+
+	r, err = parseReplayRound("东2")
+	if assert.NoError(err) {
+		assert.Equal(-1, r.compare(&gameDataLogMessage{roundNumber: 0, benNumber: 3}))
+		assert.Equal(0, r.compare(&gameDataLogMessage{roundNumber: 1, benNumber: 2}))
+	}
+}
+
+func Test_gameDataReplayer(t *testing.T) {
+	assert := assert.New(t)
+
+	games, err := readGameDataLog(strings.NewReader(testGameDataLog))
+	if !assert.NoError(err) {
+		return
+	}
+
+	// 回放第二局游戏的东场
+	r := newGameDataReplayer(nil, &replayRound{roundNumber: 3}, false, strings.NewReader(""))
+	assert.True(r.replayGame(games[1]))
+	assert.True(r.lastLogin == games[0].messages[0])
+	assert.Equal(0, r.session.tenhouRoundData.roundNumber)
+
+	// 从东2局开始
+	r = newGameDataReplayer(&replayRound{roundNumber: 1}, nil, false, strings.NewReader(""))
+	assert.True(r.replayGame(games[0]))
+	assert.Equal(1, r.session.tenhouRoundData.roundNumber)
+	assert.Equal(1, r.session.tenhouRoundData.benNumber)
+	assert.False(r.session.tenhouRoundData.skipOutput)
+
+	// 单步执行：跳到下一局后退出
+	r = newGameDataReplayer(nil, nil, true, strings.NewReader("n\nq\n"))
+	assert.False(r.replayGame(games[0]))
+	assert.Equal(0, r.session.tenhouRoundData.roundNumber)
+
+	// 输入结束后回放剩余部分
+	r = newGameDataReplayer(nil, nil, true, strings.NewReader("\n"))
+	assert.True(r.replayGame(games[0]))
+	assert.False(r.step)
+	assert.Equal(1, r.session.tenhouRoundData.benNumber)
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/majsoul"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou6"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/fatih/color"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// 可以复盘的牌谱：天凤牌谱（mjlog XML）、tenhou.net/6 牌谱（JSON）、雀魂牌谱（JSON 或 GameDetailRecords 二进制数据）
+type reviewRecord interface {
+	playerNumber() int
+
+	// seat: 0-起家 1-起家的下家 ...
+	playerName(seat int) string
+
+	// 以 selfSeat 的视角分析整场牌谱，返回分析结果
+	analysis(selfSeat int) *gameAnalysisCache
+
+	// 转换成 tenhou.net/6 格式
+	toTenhou6() (*tenhou6.Game, error)
+}
+
+// 根据文件内容判断牌谱格式并解析
+func parseReviewRecord(data []byte) (reviewRecord, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] == '<' || bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		// XML 或 gzip 压缩过的 XML
+		return parseTenhouRecord(data)
+	}
+	if trimmed[0] != '{' {
+		// 雀魂牌谱的 data_url 中的二进制数据
+		record, err := majsoul.ParseRawRecord(data)
+		if err != nil {
+			return nil, err
+		}
+		game, err := record.ToTenhou6()
+		if err != nil {
+			return nil, fmt.Errorf("牌谱转换失败：%v", err)
+		}
+		return &tenhou6Record{game}, nil
+	}
+
+	keys := map[string]json.RawMessage{}
+	if err := json.Unmarshal(trimmed, &keys); err != nil {
+		return nil, fmt.Errorf("牌谱解析失败：%v", err)
+	}
+	switch {
+	case keys["log"] != nil:
+		game, err := tenhou6.Parse(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("牌谱解析失败：%v", err)
+		}
+		return &tenhou6Record{game}, nil
+	case keys["head"] != nil && keys["details"] != nil:
+		record, err := majsoul.ParseRecord(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		game, err := record.ToTenhou6()
+		if err != nil {
+			return nil, fmt.Errorf("牌谱转换失败：%v", err)
+		}
+		return &tenhou6Record{game}, nil
+	default:
+		return nil, fmt.Errorf("无法识别的牌谱格式")
+	}
+}
+
+// 打印各局的舍牌推荐，以及整场的不一致统计
+func printRecordAnalysis(record reviewRecord, selfSeat int, analysisCache *gameAnalysisCache) {
+	color.HiGreen("%s 的牌谱分析", record.playerName(selfSeat))
+	fmt.Println()
+
+	for roundNumber, roundCaches := range analysisCache.wholeGameCache {
+		for benNumber, rc := range roundCaches {
+			if rc == nil {
+				continue
+			}
+			color.New(color.FgHiGreen).Printf("%s%d局 %d本场", util.MahjongZH[27+roundNumber/4], roundNumber%4+1, benNumber)
+			fmt.Println()
+			rc.print()
+		}
+	}
+
+	sumTotal, sumDiffAttack, sumDiffBoth := analysisCache.countDisagreements()
+	if sumTotal == 0 {
+		return
+	}
+	fmt.Printf("共 %d 巡，其中与攻め推奨不一致 %d 巡（%.1f%%），", sumTotal, sumDiffAttack, 100*float64(sumDiffAttack)/float64(sumTotal))
+	fmt.Printf("与攻め推奨、守り推奨均不一致 %d 巡（%.1f%%）\n", sumDiffBoth, 100*float64(sumDiffBoth)/float64(sumTotal))
+	fmt.Println()
+}
+
+// 根据昵称查找玩家的座位，找不到时返回 -1
+func findPlayerSeat(record reviewRecord, playerName string) int {
+	for seat := 0; seat < record.playerNumber(); seat++ {
+		if record.playerName(seat) == playerName {
+			return seat
+		}
+	}
+	return -1
+}
+
+// 复盘牌谱
+// filePath 为目录时，批量复盘目录下的所有牌谱并汇总结果
+// selfSeat 为 -1 时分析所有玩家；playerName 不为空时只分析该昵称的玩家
+// exportPath 不为空时，将牌谱转换成 tenhou.net/6 格式保存到该文件
+// whatIf 不为空时，用天凤牌谱的牌山推演 selfSeat 改切另一张牌之后的进展
+func reviewRecordFile(filePath string, selfSeat int, playerName string, exportPath string, whatIf string) error {
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	if fileInfo.IsDir() {
+		if exportPath != "" || whatIf != "" {
+			return fmt.Errorf("批量复盘时不支持 -export 和 -whatif")
+		}
+		return reviewRecordDir(filePath, selfSeat, playerName)
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	record, err := parseReviewRecord(data)
+	if err != nil {
+		return err
+	}
+
+	if playerName != "" {
+		if selfSeat = findPlayerSeat(record, playerName); selfSeat == -1 {
+			return fmt.Errorf("牌谱中没有名为 %s 的玩家", playerName)
+		}
+	}
+
+	if exportPath != "" {
+		game, err := record.toTenhou6()
+		if err != nil {
+			return fmt.Errorf("牌谱转换失败：%v", err)
+		}
+		jsonData, err := game.Marshal()
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(exportPath, jsonData, 0644); err != nil {
+			return err
+		}
+		fmt.Println("已保存 tenhou.net/6 格式的牌谱至", exportPath)
+		return nil
+	}
+
+	playerNumber := record.playerNumber()
+	if selfSeat >= playerNumber {
+		return fmt.Errorf("座位错误：%d 人对局没有 %d 号位", playerNumber, selfSeat)
+	}
+
+	if whatIf != "" {
+		tenhouRecord, ok := record.(*tenhouRecord)
+		if !ok {
+			return fmt.Errorf("推演仅支持天凤牌谱（mjlog XML）")
+		}
+		if selfSeat < 0 {
+			return fmt.Errorf("推演时需要用 -seat 指定座位")
+		}
+		spec, err := parseWhatIfSpec(whatIf)
+		if err != nil {
+			return err
+		}
+		return tenhouRecord.whatIf(selfSeat, spec)
+	}
+
+	seats := []int{selfSeat}
+	if selfSeat < 0 {
+		seats = seats[:0]
+		for seat := 0; seat < playerNumber; seat++ {
+			seats = append(seats, seat)
+		}
+	}
+
+	for _, seat := range seats {
+		fmt.Printf("正在分析 %s 的牌谱，请稍等...\n", record.playerName(seat))
+		analysisCache := record.analysis(seat)
+		printRecordAnalysis(record, seat, analysisCache)
+	}
+	return nil
+}
+
+// 批量复盘时某个玩家的汇总结果
+type reviewStats struct {
+	playerName string
+	numGames   int
+	total      int
+	diffAttack int
+	diffBoth   int
+}
+
+// 复盘多个牌谱，按玩家昵称汇总不一致统计
+// 无法解析的牌谱会跳过，返回跳过的牌谱数
+func reviewRecords(filePaths []string, selfSeat int, playerName string) (stats []*reviewStats, numSkipped int) {
+	statsMap := map[string]*reviewStats{}
+	for i, filePath := range filePaths {
+		fmt.Printf("[%d/%d] %s\n", i+1, len(filePaths), filepath.Base(filePath))
+
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			numSkipped++
+			continue
+		}
+		record, err := parseReviewRecord(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "跳过该牌谱：", err)
+			numSkipped++
+			continue
+		}
+
+		var seats []int
+		switch {
+		case playerName != "":
+			if seat := findPlayerSeat(record, playerName); seat != -1 {
+				seats = []int{seat}
+			}
+		case selfSeat >= 0:
+			if selfSeat < record.playerNumber() {
+				seats = []int{selfSeat}
+			}
+		default:
+			for seat := 0; seat < record.playerNumber(); seat++ {
+				seats = append(seats, seat)
+			}
+		}
+		if len(seats) == 0 {
+			fmt.Fprintln(os.Stderr, "跳过该牌谱：没有要分析的玩家")
+			numSkipped++
+			continue
+		}
+
+		for _, seat := range seats {
+			name := record.playerName(seat)
+			total, diffAttack, diffBoth := record.analysis(seat).countDisagreements()
+			fmt.Printf("  %s：共 %d 巡，与攻め推奨不一致 %d 巡，与攻め推奨、守り推奨均不一致 %d 巡\n", name, total, diffAttack, diffBoth)
+
+			s, ok := statsMap[name]
+			if !ok {
+				s = &reviewStats{playerName: name}
+				statsMap[name] = s
+				stats = append(stats, s)
+			}
+			s.numGames++
+			s.total += total
+			s.diffAttack += diffAttack
+			s.diffBoth += diffBoth
+		}
+	}
+
+	// 场数多的玩家在前
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].numGames > stats[j].numGames })
+	return
+}
+
+func printReviewStats(w io.Writer, stats []*reviewStats) {
+	percent := func(a, b int) string {
+		if b == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", 100*float64(a)/float64(b))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "玩家\t场数\t巡数\t与攻め推奨不一致\t与攻め、守り推奨均不一致")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d（%s）\t%d（%s）\n", s.playerName, s.numGames, s.total,
+			s.diffAttack, percent(s.diffAttack, s.total), s.diffBoth, percent(s.diffBoth, s.total))
+	}
+	tw.Flush()
+}
+
+// 批量复盘目录下的所有牌谱，如雀魂牌谱下载工具保存牌谱的目录
+func reviewRecordDir(dirPath string, selfSeat int, playerName string) error {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+	filePaths := []string{}
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || strings.HasPrefix(name, ".") || name == majsoul.RecordIndexFileName {
+			continue
+		}
+		filePaths = append(filePaths, filepath.Join(dirPath, name))
+	}
+	if len(filePaths) == 0 {
+		return fmt.Errorf("%s 中没有牌谱", dirPath)
+	}
+
+	stats, numSkipped := reviewRecords(filePaths, selfSeat, playerName)
+	fmt.Println()
+	if numSkipped > 0 {
+		fmt.Printf("共 %d 个牌谱，跳过 %d 个\n", len(filePaths), numSkipped)
+	} else {
+		fmt.Printf("共 %d 个牌谱\n", len(filePaths))
+	}
+	if len(stats) == 0 {
+		return fmt.Errorf("没有可以分析的牌谱")
+	}
+	printReviewStats(os.Stdout, stats)
+	return nil
+}
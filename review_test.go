@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reviewRecordDir(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "review")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	record, err := parseTenhouRecord([]byte(testTenhouRecord))
+	if !assert.NoError(err) {
+		return
+	}
+	g, _ := record.toTenhou6()
+	tenhou6Data, err := g.Marshal()
+	if !assert.NoError(err) {
+		return
+	}
+	files := map[string]string{
+		"a.xml":                "<mjloggm ver=\"2.3\"></mjloggm>", // 无法解析
+		"b.xml":                testTenhouRecord,
+		"c.json":               string(tenhou6Data),
+		"majsoul-records.json": "{}",
+	}
+	filePaths := []string{}
+	for name, content := range files {
+		assert.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		filePaths = append(filePaths, filepath.Join(dir, name))
+	}
+
+	stats, numSkipped := reviewRecords([]string{filepath.Join(dir, "a.xml"), filepath.Join(dir, "b.xml"), filepath.Join(dir, "c.json")}, -1, "")
+	assert.Equal(1, numSkipped)
+	if assert.Len(stats, 4) {
+		assert.Equal("A", stats[0].playerName)
+		assert.Equal(2, stats[0].numGames)
+		assert.Equal(4, stats[0].total)
+	}
+
+	stats, numSkipped = reviewRecords(filePaths, -1, "B")
+	assert.Equal(2, numSkipped)
+	if assert.Len(stats, 1) {
+		assert.Equal("B", stats[0].playerName)
+		assert.Equal(2, stats[0].numGames)
+	}
+
+	// 跳过索引文件
+	assert.NoError(reviewRecordFile(dir, 0, "", "", ""))
+	assert.Error(reviewRecordFile(dir, -1, "nobody", "", ""))
+	assert.Error(reviewRecordFile(filepath.Join(dir, "b.xml"), -1, "nobody", "", ""))
+	assert.NoError(reviewRecordFile(filepath.Join(dir, "b.xml"), -1, "C", "", ""))
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTP 接口的访问控制
+// 助手监听在本地端口上，用户访问的任意网页都能向其发送请求，伪造牌局数据或者发起大量分析，因此：
+// - 带 Origin 的请求（即浏览器中网页发起的请求）只允许来自雀魂、天凤等网页以及助手自身，抓包工具等不带 Origin 的请求不受此限制
+// - 除首页和网页版界面外，请求需要在请求头 X-Helper-Token 或 URL 参数 token 中提供令牌（每次安装随机生成，保存在 config.json 中）
+// - 限制请求体的大小，并限制分析接口的请求频率
+
+const (
+	apiTokenHeader = "X-Helper-Token"
+	apiTokenQuery  = "token"
+
+	// 分析接口的请求体上限
+	maxAnalysisBodySize = 64 << 10
+	// 牌局数据（WebSocket 消息、牌谱等）的请求体上限
+	maxDataBodySize = 16 << 20
+
+	// 分析接口每秒允许的请求数，以及允许的突发请求数
+	analysisRateLimit = 5
+	analysisRateBurst = 10
+)
+
+// 默认允许跨域访问的网页，*. 表示任意子域名
+// 可以在 config.json 的 allowed_origins 中修改
+var defaultAllowedOrigins = []string{
+	"https://game.maj-soul.com",
+	"https://game.maj-soul.net",
+	"https://game.mahjongsoul.com",
+	"https://mahjongsoul.game.yo-star.com",
+	"https://tenhou.net",
+	"https://*.tenhou.net",
+}
+
+func newAPIErrorJSON(c echo.Context, status int, code string, err error) error {
+	return c.JSON(status, newAPIErrorResponse(code, "", err))
+}
+
+type accessControl struct {
+	token          string
+	allowedOrigins []string
+}
+
+func newAccessControl(token string, allowedOrigins []string) *accessControl {
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = defaultAllowedOrigins
+	}
+	return &accessControl{
+		token:          token,
+		allowedOrigins: allowedOrigins,
+	}
+}
+
+// origin 是否与 pattern 匹配，pattern 的域名可以用 *. 开头表示任意子域名
+func matchOrigin(origin *url.URL, pattern string) bool {
+	p, err := url.Parse(pattern)
+	if err != nil || p.Scheme != origin.Scheme {
+		return false
+	}
+	if strings.HasPrefix(p.Host, "*.") {
+		return strings.HasSuffix(origin.Host, p.Host[1:])
+	}
+	return p.Host == origin.Host
+}
+
+// host 为请求的 Host，同源的请求（网页版界面）总是允许的
+func (ac *accessControl) isAllowedOrigin(origin string, host string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		// 包括沙箱中的网页发送的 "null"
+		return false
+	}
+	if u.Host == host {
+		return true
+	}
+	for _, pattern := range ac.allowedOrigins {
+		if matchOrigin(u, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// 拒绝来自其他网页的请求（含 CORS 预检请求和 WebSocket 握手），需要在 CORS 中间件之前使用
+func (ac *accessControl) checkOrigin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if origin := req.Header.Get(echo.HeaderOrigin); origin != "" && !ac.isAllowedOrigin(origin, req.Host) {
+			return newAPIErrorJSON(c, http.StatusForbidden, apiErrorForbiddenOrigin, fmt.Errorf("不允许来自 %s 的请求，可在 config.json 的 allowed_origins 中添加", origin))
+		}
+		return next(c)
+	}
+}
+
+func requestToken(c echo.Context) string {
+	if token := c.Request().Header.Get(apiTokenHeader); token != "" {
+		return token
+	}
+	return c.QueryParam(apiTokenQuery)
+}
+
+func (ac *accessControl) requireToken(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := requestToken(c)
+		if token == "" {
+			return newAPIErrorJSON(c, http.StatusUnauthorized, apiErrorUnauthorized, fmt.Errorf("缺少令牌，请在请求头 %s 或 URL 参数 %s 中提供助手启动时显示的令牌", apiTokenHeader, apiTokenQuery))
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(ac.token)) != 1 {
+			return newAPIErrorJSON(c, http.StatusUnauthorized, apiErrorUnauthorized, fmt.Errorf("令牌错误"))
+		}
+		return next(c)
+	}
+}
+
+// 限制请求体的大小，超过时返回 413
+func limitBodySize(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			err := fmt.Errorf("请求体超过了 %d 字节", limit)
+			if req.ContentLength > limit {
+				return newAPIErrorJSON(c, http.StatusRequestEntityTooLarge, apiErrorBodyTooLarge, err)
+			}
+			// 分块传输时没有 Content-Length，需要实际读取
+			data, er := ioutil.ReadAll(io.LimitReader(req.Body, limit+1))
+			if er != nil {
+				return c.String(http.StatusBadRequest, er.Error())
+			}
+			if int64(len(data)) > limit {
+				return newAPIErrorJSON(c, http.StatusRequestEntityTooLarge, apiErrorBodyTooLarge, err)
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(data))
+			return next(c)
+		}
+	}
+}
+
+// 令牌桶限流，按连接的客户端 IP 分别计算
+type rateLimiter struct {
+	mu sync.Mutex
+
+	// 每秒补充的令牌数
+	rate float64
+	// 桶的容量
+	burst float64
+
+	buckets map[string]*tokenBucket
+
+	// 当前时间，测试时可以替换
+	now func() time.Time
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// 是否允许该请求，不允许时返回需要等待的时间
+func (l *rateLimiter) allow(key string, now time.Time) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, exists := l.buckets[key]
+	if !exists {
+		// 清理已经补满的桶，避免占用过多内存
+		for k, _b := range l.buckets {
+			if now.Sub(_b.lastRefill).Seconds()*l.rate+_b.tokens >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		b = &tokenBucket{tokens: l.burst, lastRefill: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastRefill).Seconds()*l.rate)
+	b.lastRefill = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// 连接的客户端 IP
+// 服务只监听本机，不使用客户端可以随意设置的 X-Forwarded-For、X-Real-IP
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func (l *rateLimiter) limit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ok, retryAfter := l.allow(remoteIP(c.Request()), l.now())
+		if !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+			return newAPIErrorJSON(c, http.StatusTooManyRequests, apiErrorRateLimited, fmt.Errorf("请求过于频繁，请在 %d 秒后重试", seconds))
+		}
+		return next(c)
+	}
+}
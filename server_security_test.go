@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_accessControl_isAllowedOrigin(t *testing.T) {
+	assert := assert.New(t)
+
+	ac := newAccessControl("token", nil)
+	assert.True(ac.isAllowedOrigin("https://game.maj-soul.com", "localhost:12121"))
+	assert.True(ac.isAllowedOrigin("https://tenhou.net", "localhost:12121"))
+	assert.True(ac.isAllowedOrigin("https://www.tenhou.net", "localhost:12121"))
+	assert.True(ac.isAllowedOrigin("https://localhost:12121", "localhost:12121")) // 网页版界面
+
+	assert.False(ac.isAllowedOrigin("http://game.maj-soul.com", "localhost:12121"))
+	assert.False(ac.isAllowedOrigin("https://eviltenhou.net", "localhost:12121"))
+	assert.False(ac.isAllowedOrigin("https://game.maj-soul.com.evil.com", "localhost:12121"))
+	assert.False(ac.isAllowedOrigin("https://localhost:8080", "localhost:12121"))
+	assert.False(ac.isAllowedOrigin("null", "localhost:12121"))
+
+	ac = newAccessControl("token", []string{"https://example.com"})
+	assert.True(ac.isAllowedOrigin("https://example.com", "localhost:12121"))
+	assert.False(ac.isAllowedOrigin("https://game.maj-soul.com", "localhost:12121"))
+}
+
+func Test_rateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	l := newRateLimiter(2, 3)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		ok, _ := l.allow("a", now)
+		assert.True(ok)
+	}
+	ok, retryAfter := l.allow("a", now)
+	assert.False(ok)
+	assert.Equal(500*time.Millisecond, retryAfter)
+
+	// 其他客户端不受影响
+	ok, _ = l.allow("b", now)
+	assert.True(ok)
+
+	ok, _ = l.allow("a", now.Add(500*time.Millisecond))
+	assert.True(ok)
+	ok, _ = l.allow("a", now.Add(500*time.Millisecond))
+	assert.False(ok)
+}
+
+func Test_mjHandler_registerRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	const token = "0123456789abcdef"
+	h := newMjHandler(nil)
+	// 固定时间，使限流的结果与请求耗时无关
+	now := time.Now()
+	h.analysisLimiter.now = func() time.Time { return now }
+	e := echo.New()
+	h.registerRoutes(e, newAccessControl(token, nil), true)
+
+	do := func(method string, target string, body string, header map[string]string) (*httptest.ResponseRecorder, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		resp := apiErrorResponse{}
+		if rec.Code >= 400 && json.Unmarshal(rec.Body.Bytes(), &resp) == nil && resp.Error != nil {
+			return rec, resp.Error.Code
+		}
+		return rec, ""
+	}
+	const analysisBody = `{"tiles": "123m 456p 789s 11z 34m"}`
+	analysisURL := analysisAPIPrefix + "/analysis"
+
+	// 令牌
+	rec, code := do(http.MethodPost, analysisURL, analysisBody, nil)
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+	assert.Equal(apiErrorUnauthorized, code)
+	rec, code = do(http.MethodPost, analysisURL, analysisBody, map[string]string{apiTokenHeader: "wrong"})
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+	assert.Equal(apiErrorUnauthorized, code)
+	rec, _ = do(http.MethodPost, analysisURL, analysisBody, map[string]string{apiTokenHeader: token})
+	assert.Equal(http.StatusOK, rec.Code)
+	rec, _ = do(http.MethodPost, analysisURL+"?token="+token, analysisBody, nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	rec, _ = do(http.MethodPost, "/majsoul", "{}", nil)
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+
+	// 网页版界面不需要令牌
+	rec, _ = do(http.MethodGet, "/dashboard", "", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	// 来源
+	rec, code = do(http.MethodPost, "/majsoul", "{}", map[string]string{apiTokenHeader: token, echo.HeaderOrigin: "https://evil.example.com"})
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Equal(apiErrorForbiddenOrigin, code)
+	rec, _ = do(http.MethodOptions, "/majsoul", "", map[string]string{echo.HeaderOrigin: "https://evil.example.com", echo.HeaderAccessControlRequestMethod: http.MethodPost})
+	assert.Equal(http.StatusForbidden, rec.Code)
+	rec, _ = do(http.MethodOptions, "/majsoul", "", map[string]string{echo.HeaderOrigin: "https://game.maj-soul.com", echo.HeaderAccessControlRequestMethod: http.MethodPost})
+	assert.Equal(http.StatusNoContent, rec.Code)
+	assert.Equal("https://game.maj-soul.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	rec, _ = do(http.MethodPost, analysisURL, analysisBody, map[string]string{apiTokenHeader: token, echo.HeaderOrigin: "https://game.maj-soul.com"})
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("https://game.maj-soul.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	// 请求体大小
+	rec, code = do(http.MethodPost, analysisURL, `{"tiles": "`+strings.Repeat("1", maxAnalysisBodySize)+`"}`, map[string]string{apiTokenHeader: token})
+	assert.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(apiErrorBodyTooLarge, code)
+
+	// 请求频率
+	for i := 0; i < analysisRateBurst; i++ {
+		do(http.MethodPost, analysisURL, analysisBody, map[string]string{apiTokenHeader: token})
+	}
+	rec, code = do(http.MethodPost, analysisURL, analysisBody, map[string]string{apiTokenHeader: token})
+	assert.Equal(http.StatusTooManyRequests, rec.Code)
+	assert.Equal(apiErrorRateLimited, code)
+	assert.NotEmpty(rec.Header().Get("Retry-After"))
+	// 不能通过伪造请求头绕过限流
+	rec, _ = do(http.MethodPost, analysisURL, analysisBody, map[string]string{apiTokenHeader: token, echo.HeaderXForwardedFor: "203.0.113.1", echo.HeaderXRealIP: "203.0.113.2"})
+	assert.Equal(http.StatusTooManyRequests, rec.Code)
+}
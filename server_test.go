@@ -8,21 +8,22 @@ import (
 	"strings"
 	"io/ioutil"
 	"github.com/EndlessCheng/mahjong-helper/util/debug"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 )
 
-func Test_mjHandler_runAnalysisMajsoulMessageTask(t *testing.T) {
+func Test_mjSession_runAnalysisMajsoulMessageTask(t *testing.T) {
 	debugMode = true
 
 	logFile := "log/gamedata.log"
 	startLo := 33020
 	endLo := 33369
 
-	h := &mjHandler{
-		majsoulMessageQueue: make(chan []byte, 10000),
-		majsoulRoundData:    &majsoulRoundData{},
-		majsoulRecordMap:    map[string]*majsoulRecordBaseInfo{},
-	}
-	h.majsoulRoundData.roundData = newGame(h.majsoulRoundData)
+	session := newMjSession("", nil)
+	session.majsoulMessageQueue = make(chan []byte, 10000)
+	session.majsoulRoundData.selfSeat = 0
 
 	s := struct {
 		Level   string `json:"level"`
@@ -47,15 +48,166 @@ func Test_mjHandler_runAnalysisMajsoulMessageTask(t *testing.T) {
 			//t.Fatal(s.Message)
 			break
 		}
-		h.majsoulMessageQueue <- []byte([]byte(s.Message))
+		session.majsoulMessageQueue <- []byte([]byte(s.Message))
 	}
 
-	go h.runAnalysisMajsoulMessageTask()
+	go session.runAnalysisMajsoulMessageTask()
 
 	for {
-		if len(h.majsoulMessageQueue) == 0 {
+		if len(session.majsoulMessageQueue) == 0 {
 			break
 		}
 		time.Sleep(time.Second)
 	}
 }
+
+func Test_mjHandler_getSession(t *testing.T) {
+	assert := assert.New(t)
+
+	h := newMjHandler(nil)
+	e := echo.New()
+	newContext := func(sessionID string) echo.Context {
+		req := httptest.NewRequest(http.MethodPost, "/majsoul", nil)
+		if sessionID != "" {
+			req.Header.Set(sessionIDHeader, sessionID)
+		}
+		return e.NewContext(req, httptest.NewRecorder())
+	}
+
+	s0, err := h.getSession(newContext(""))
+	assert.NoError(err)
+	s1, err := h.getSession(newContext("tab1"))
+	assert.NoError(err)
+	s2, err := h.getSession(newContext("tab2"))
+	assert.NoError(err)
+	s1Again, err := h.getSession(newContext("tab1"))
+	assert.NoError(err)
+
+	assert.True(s1 == s1Again)
+	assert.True(s0 != s1 && s1 != s2)
+	assert.True(s1.analysisCaches != s2.analysisCaches)
+
+	// 不同会话的牌谱互不影响
+	s1.setMajsoulCurrentRecordUUID("record1")
+	s1.analysisCaches.set(newGameAnalysisCache(s1, "record1", 2))
+	assert.Equal("", s2.getMajsoulCurrentRecordUUID())
+	assert.Nil(s2.analysisCaches.current())
+	assert.True(s1.analysisCaches.current().isCurrentRecord())
+
+	// 会话过期
+	h.removeExpiredSessions(time.Hour)
+	assert.Len(h.sessions, 3)
+	h.removeExpiredSessions(0)
+	assert.Len(h.sessions, 0)
+	assert.False(s1.putMajsoulMessage([]byte("{}")))
+	assert.False(s1.putTenhouMessage([]byte("{}")))
+	assert.False(s1.analysisCaches.current().isCurrentRecord())
+}
+
+func Test_mjSession_close(t *testing.T) {
+	assert := assert.New(t)
+
+	// 没有在处理消息的会话，队列满后放入的消息被丢弃，不会阻塞
+	s := newMjSession("", nil)
+	for i := 0; i < 2*cap(s.majsoulMessageQueue); i++ {
+		assert.True(s.putMajsoulMessage([]byte("{}")))
+	}
+	assert.Len(s.majsoulMessageQueue, cap(s.majsoulMessageQueue))
+
+	// 关闭会话时，阻塞在放入天凤消息上的请求会返回
+	putDone := make(chan struct{})
+	go func() {
+		defer close(putDone)
+		for s.putTenhouMessage([]byte("{}")) {
+		}
+	}()
+	closeDone := make(chan struct{})
+	go func() {
+		defer close(closeDone)
+		s.close()
+	}()
+	for _, done := range []chan struct{}{putDone, closeDone} {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("关闭会话超时")
+		}
+	}
+	assert.False(s.putMajsoulMessage([]byte("{}")))
+}
+
+func Test_mjSession_processMajsoulMessage_recover(t *testing.T) {
+	assert := assert.New(t)
+
+	oldDebugMode := debugMode
+	debugMode = false
+	defer func() { debugMode = oldDebugMode }()
+
+	s := newMjSession("", nil)
+	defer s.close()
+
+	// 未载入牌谱时切换座位会 panic，只影响这一条消息
+	assert.NotPanics(func() { s.processMajsoulMessage([]byte(`{"change_seat_to":1}`)) })
+	assert.NotPanics(func() { s.processMajsoulMessage([]byte(`{"current_record_uuid":"record1","account_id":1}`)) })
+	assert.Equal(1, s.majsoulAccountID())
+}
+
+func Test_mjSession_onRecordClick_roundEnd(t *testing.T) {
+	assert := assert.New(t)
+
+	newRound := func(ju int) string {
+		return fmt.Sprintf(`{"name":"RecordNewRound","data":{"chang":0,"ju":%d,"ben":0,"md5":"md5","dora":"1z","left_tile_count":69,`+
+			`"tiles0":["1m","2m","3m","4p","5p","6p","7s","8s","9s","1z","1z","2z","2z","3z"],`+
+			`"tiles1":["1p","1p","1p","2p","2p","2p","3p","3p","3p","4m","4m","4m","5m"],`+
+			`"tiles2":["1s","1s","1s","2s","2s","2s","3s","3s","3s","4s","4s","4s","5s"],`+
+			`"tiles3":["6m","6m","6m","7m","7m","7m","8m","8m","8m","9m","9m","9m","5z"]}}`, ju)
+	}
+	newSession := func() *mjSession {
+		s := newMjSession("", nil)
+		recordActions := []*majsoulRecordAction{}
+		assert.NoError(json.Unmarshal([]byte(`[`+newRound(0)+`,{"name":"RecordNoTile","data":{}},`+newRound(1)+`]`), &recordActions))
+		actionsList, err := parseMajsoulRecordAction(recordActions)
+		assert.NoError(err)
+
+		s.setMajsoulCurrentRecordUUID("record1")
+		s.majsoulRoundData.accountID = 1
+		s.majsoulRoundData.newGame()
+		s.majsoulRoundData.gameMode = gameModeRecord
+		s.majsoulRoundData.selfSeat = 0
+		s.majsoulCurrentRecordActionsList = actionsList
+		analysisCache := newGameAnalysisCache(s, "record1", 0)
+		// 这里不测试舍牌推荐的计算
+		analysisCache.wholeGameCache[0][0] = &roundAnalysisCache{isStart: true}
+		analysisCache.wholeGameCache[1][0] = &roundAnalysisCache{isStart: true}
+		s.analysisCaches.set(analysisCache)
+		s._analysisMajsoulRoundData(actionsList[0][0].Action, "")
+		return s
+	}
+	roundNumber := func(s *mjSession) int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.majsoulRoundData.roundNumber
+	}
+
+	// 流局后等待一秒再分析下一局，等待时不阻塞其他消息
+	s := newSession()
+	defer s.close()
+	start := time.Now()
+	s.processMajsoulMessage([]byte(`{"record_click_action":"nextStep"}`))
+	assert.True(time.Since(start) < 500*time.Millisecond)
+	assert.Equal(0, roundNumber(s))
+	for deadline := time.Now().Add(3 * time.Second); roundNumber(s) != 1 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(1, roundNumber(s))
+
+	// 等待时点击，先分析下一局的起始信息
+	s2 := newSession()
+	defer s2.close()
+	s2.processMajsoulMessage([]byte(`{"record_click_action":"nextStep"}`))
+	s2.processMajsoulMessage([]byte(`{"record_click_action":"nextStep"}`))
+	assert.Equal(1, roundNumber(s2))
+	s2.mu.Lock()
+	assert.Nil(s2.majsoulRecordRoundStartTimer)
+	s2.mu.Unlock()
+}
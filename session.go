@@ -0,0 +1,729 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/debug"
+	"github.com/fatih/color"
+	"github.com/labstack/echo/v4"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 一个会话对应一个客户端（浏览器标签页），会话之间的对局、牌谱、观战数据互不影响
+// 会话 ID 由客户端提供，未提供时使用默认会话（兼容旧版脚本）
+type mjSession struct {
+	id  string
+	log echo.Logger
+
+	// 同一会话的消息串行处理
+	mu sync.Mutex
+
+	// 打一摸一分析器是否正在分析，使用原子操作
+	analysing int32
+
+	// 最后活跃时间（UnixNano），使用原子操作
+	lastActive int64
+
+	// 会话关闭后不再接收数据
+	closeMu sync.RWMutex
+	closed  bool
+
+	tenhouMessageReceiver *tenhou.MessageReceiver
+	tenhouRoundData       *tenhouRoundData
+
+	majsoulMessageQueue chan []byte
+	majsoulRoundData    *majsoulRoundData
+
+	// 抓包工具发送的原始数据由此转换成 JSON 数据
+	majsoulFrameMu        sync.Mutex
+	majsoulFrameConverter *majsoulFrameConverter
+
+	majsoulRecordMap                map[string]*majsoulRecordBaseInfo
+	majsoulCurrentRecordUUID        string
+	majsoulCurrentRecordUUIDMu      sync.RWMutex
+	majsoulCurrentRecordActionsList []majsoulRoundActions
+	majsoulCurrentRoundIndex        int
+	majsoulCurrentActionIndex       int
+
+	majsoulCurrentRoundActions majsoulRoundActions
+
+	// 等待和牌/流局动画播放完毕后，分析牌谱下一局的起始信息
+	majsoulRecordRoundStartTimer *time.Timer
+
+	// 牌谱各座位的分析缓存
+	analysisCaches *analysisCacheList
+
+	// 事件日志文件
+	eventLogFile *os.File
+
+	// 牌局数据日志
+	gameDataLog *gameDataLog
+}
+
+func newMjSession(id string, log echo.Logger) *mjSession {
+	s := &mjSession{
+		id:  id,
+		log: log,
+
+		tenhouMessageReceiver: tenhou.NewMessageReceiver(),
+		tenhouRoundData:       &tenhouRoundData{isRoundEnd: true},
+		majsoulMessageQueue:   make(chan []byte, 100),
+		majsoulRoundData:      &majsoulRoundData{selfSeat: -1, accountID: -1},
+		majsoulFrameConverter: newMajsoulFrameConverter(),
+		majsoulRecordMap:      map[string]*majsoulRecordBaseInfo{},
+		analysisCaches:        newAnalysisCacheList(),
+	}
+	s.tenhouRoundData.roundData = newGame(s.tenhouRoundData)
+	s.tenhouRoundData.analysisCaches = s.analysisCaches
+	s.majsoulRoundData.roundData = newGame(s.majsoulRoundData)
+	s.majsoulRoundData.analysisCaches = s.analysisCaches
+	s.touch()
+	return s
+}
+
+func (s *mjSession) start() {
+	go s.runAnalysisTenhouMessageTask()
+	go s.runAnalysisMajsoulMessageTask()
+}
+
+// 将该会话的牌局事件记录到 log/events-xxx.jsonl
+func (s *mjSession) enableEventLog() error {
+	file, writer, err := newEventLogFile(s.id)
+	if err != nil {
+		return err
+	}
+	s.eventLogFile = file
+	s.tenhouRoundData.eventWriter = writer
+	s.majsoulRoundData.eventWriter = writer
+	return nil
+}
+
+// 将该会话收到的天凤、雀魂消息记录到 log/gamedata-xxx.log
+func (s *mjSession) enableGameDataLog(conf *gameDataLogConfig) {
+	s.gameDataLog = newGameDataLog(logDir, s.id, conf)
+}
+
+// 将该会话的分析结果推送给订阅者
+func (s *mjSession) enablePush(hub *pushHub) {
+	s.tenhouRoundData.pusher = &analysisPusher{hub: hub, sessionID: s.id, platform: "tenhou"}
+	s.majsoulRoundData.pusher = &analysisPusher{hub: hub, sessionID: s.id, platform: "majsoul"}
+}
+
+// 牌谱的舍牌推荐计算完成后，推送给订阅者
+func (s *mjSession) pushMajsoulReview() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.majsoulRoundData; d.pusher != nil {
+		if err := d.pusher.push(d.roundData, nil); err != nil {
+			s.logError(err)
+		}
+	}
+}
+
+// 关闭会话，结束分析任务
+func (s *mjSession) close() {
+	// 先关闭天凤消息的接收，使阻塞在 Put 上的 putTenhouMessage 返回
+	s.tenhouMessageReceiver.Close()
+
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.majsoulMessageQueue)
+	s.setMajsoulCurrentRecordUUID("") // 结束正在进行的牌谱分析任务
+
+	if s.eventLogFile != nil {
+		// 等待正在处理的消息
+		s.mu.Lock()
+		s.eventLogFile.Close()
+		s.mu.Unlock()
+	}
+	if s.gameDataLog != nil {
+		s.gameDataLog.close()
+	}
+}
+
+func (s *mjSession) touch() {
+	atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+}
+
+func (s *mjSession) idleTime() time.Duration {
+	return time.Duration(time.Now().UnixNano() - atomic.LoadInt64(&s.lastActive))
+}
+
+func (s *mjSession) logError(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	if debugMode {
+		return
+	}
+	if s.gameDataLog != nil {
+		// 与消息记录在一起，便于回放时定位
+		s.gameDataLog.error(err)
+	}
+	if s.log != nil {
+		s.log.Error(err)
+	}
+}
+
+// 处理单条消息时发生 panic 只记录错误，不影响后续消息的处理
+func (s *mjSession) recoverMessagePanic() {
+	if err := recover(); err != nil {
+		s.logError(fmt.Errorf("内部错误：%v", err))
+	}
+}
+
+// 记录收到的消息，用于回放
+func (s *mjSession) logGameData(message string) {
+	if err := s.gameDataLog.info(message); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func (s *mjSession) putTenhouMessage(data []byte) bool {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		return false
+	}
+	s.tenhouMessageReceiver.Put(data)
+	return true
+}
+
+func (s *mjSession) putMajsoulMessage(data []byte) bool {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		return false
+	}
+	s.enqueueMajsoulMessage(data)
+	return true
+}
+
+// 放入雀魂消息队列，队列已满（消息处理不过来）时丢弃该消息，以免阻塞请求和关闭会话
+func (s *mjSession) enqueueMajsoulMessage(data []byte) {
+	select {
+	case s.majsoulMessageQueue <- data:
+	default:
+		s.logError(fmt.Errorf("雀魂消息队列已满，丢弃消息：%.100s", data))
+	}
+}
+
+// 下载抓包数据中牌谱数据的超时时间
+const majsoulRecordDownloadTimeout = 30 * time.Second
+
+// 一帧数据的转换结果
+type majsoulFrameResult struct {
+	messages   [][]byte
+	recordTask *majsoulGameRecordTask
+}
+
+// 转换雀魂 WebSocket 连接 connID 上的原始数据并放入消息队列，无法解析的数据会被跳过
+// 查看牌谱时若需要下载牌谱数据，则在锁外下载，这批数据的消息在下载完成后再放入队列
+func (s *mjSession) putMajsoulFrames(connID string, frames [][]byte) bool {
+	results, ok := s.convertMajsoulFrames(connID, frames)
+	if !ok {
+		return false
+	}
+	if len(results) == 0 {
+		return true
+	}
+
+	for _, result := range results {
+		if result.recordTask == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), majsoulRecordDownloadTimeout)
+		messages, err := result.recordTask.convert(ctx)
+		cancel()
+		if err != nil {
+			s.logError(err)
+		}
+		result.messages = append(result.messages, messages...)
+	}
+
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		return false
+	}
+	for _, result := range results {
+		for _, msg := range result.messages {
+			s.enqueueMajsoulMessage(msg)
+		}
+	}
+	return true
+}
+
+// 转换原始数据，无需下载牌谱数据时直接放入消息队列，否则返回转换结果
+func (s *mjSession) convertMajsoulFrames(connID string, frames [][]byte) (results []*majsoulFrameResult, ok bool) {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		return nil, false
+	}
+
+	// 请求与响应需要按顺序解析
+	s.majsoulFrameMu.Lock()
+	defer s.majsoulFrameMu.Unlock()
+	s.majsoulFrameConverter.defaultAccountID = s.majsoulAccountID()
+	needDownload := false
+	for _, frame := range frames {
+		messages, recordTask, err := s.majsoulFrameConverter.convert(connID, frame)
+		if err != nil {
+			s.logError(err)
+			continue
+		}
+		if recordTask != nil {
+			if recordTask.needDownload() {
+				needDownload = true
+			} else {
+				recordMessages, err := recordTask.convert(context.Background())
+				if err != nil {
+					s.logError(err)
+				}
+				messages = append(messages, recordMessages...)
+				recordTask = nil
+			}
+		}
+		results = append(results, &majsoulFrameResult{messages: messages, recordTask: recordTask})
+	}
+	if needDownload {
+		return results, true
+	}
+
+	for _, result := range results {
+		for _, msg := range result.messages {
+			s.enqueueMajsoulMessage(msg)
+		}
+	}
+	return nil, true
+}
+
+// 当前使用的雀魂账号 ID，-1 表示尚未获取到
+func (s *mjSession) majsoulAccountID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.majsoulRoundData.accountID
+}
+
+func (s *mjSession) getMajsoulCurrentRecordUUID() string {
+	s.majsoulCurrentRecordUUIDMu.RLock()
+	defer s.majsoulCurrentRecordUUIDMu.RUnlock()
+	return s.majsoulCurrentRecordUUID
+}
+
+func (s *mjSession) setMajsoulCurrentRecordUUID(majsoulRecordUUID string) {
+	s.majsoulCurrentRecordUUIDMu.Lock()
+	defer s.majsoulCurrentRecordUUIDMu.Unlock()
+	s.majsoulCurrentRecordUUID = majsoulRecordUUID
+}
+
+// 分析天凤 WebSocket 数据
+func (s *mjSession) runAnalysisTenhouMessageTask() {
+	for {
+		msg := s.tenhouMessageReceiver.Get()
+		if msg == nil {
+			// 会话已关闭
+			return
+		}
+		if s.gameDataLog != nil {
+			s.logGameData(string(msg))
+		}
+		s.processTenhouMessage(msg)
+	}
+}
+
+// 解析并处理一条天凤消息
+func (s *mjSession) processTenhouMessage(msg []byte) {
+	if !debugMode {
+		defer s.recoverMessagePanic()
+	}
+
+	d := tenhouMessage{}
+	if err := json.Unmarshal(msg, &d); err != nil {
+		s.logError(err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenhouRoundData.msg = &d
+	s.tenhouRoundData.originJSON = string(msg)
+	if err := s.tenhouRoundData.analysis(); err != nil {
+		s.logError(err)
+	}
+}
+
+// 分析雀魂 WebSocket 数据
+func (s *mjSession) runAnalysisMajsoulMessageTask() {
+	for msg := range s.majsoulMessageQueue {
+		originJSON := string(msg)
+		if s.gameDataLog != nil && debug.Lo == 0 {
+			s.logGameData(originJSON)
+		} else {
+			if len(originJSON) > 500 {
+				originJSON = originJSON[:500]
+			}
+			fmt.Println(originJSON)
+		}
+
+		s.processMajsoulMessage(msg)
+	}
+}
+
+// 解析并处理一条雀魂消息
+func (s *mjSession) processMajsoulMessage(msg []byte) {
+	if !debugMode {
+		defer s.recoverMessagePanic()
+	}
+
+	d := &majsoulMessage{}
+	if err := json.Unmarshal(msg, d); err != nil {
+		s.logError(err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handleMajsoulMessage(d, string(msg))
+}
+
+func (s *mjSession) handleMajsoulMessage(d *majsoulMessage, originJSON string) {
+	switch {
+	case len(d.Friends) > 0:
+		// 好友列表
+		fmt.Println(d.Friends)
+	case len(d.RecordBaseInfoList) > 0:
+		// 牌谱基本信息列表
+		for _, record := range d.RecordBaseInfoList {
+			s.majsoulRecordMap[record.UUID] = record
+		}
+		color.HiGreen("收到 %2d 个雀魂牌谱（已收集 %d 个），请在网页上点击「查看」", len(d.RecordBaseInfoList), len(s.majsoulRecordMap))
+	case d.SharedRecordBaseInfo != nil:
+		// 处理分享的牌谱基本信息
+		// FIXME: 观看自己的牌谱也会有 d.SharedRecordBaseInfo
+		record := d.SharedRecordBaseInfo
+		s.majsoulRecordMap[record.UUID] = record
+		if err := s._loadMajsoulRecordBaseInfo(record.UUID); err != nil {
+			s.logError(err)
+			break
+		}
+	case d.CurrentRecordUUID != "":
+		// 载入某个牌谱
+		s.analysisCaches.reset()
+		s.majsoulCurrentRecordActionsList = nil
+		s.stopMajsoulRecordRoundStartTimer()
+
+		if err := s._loadMajsoulRecordBaseInfo(d.CurrentRecordUUID); err != nil {
+			// 看的是分享的牌谱（先收到 CurrentRecordUUID 和 AccountID，然后收到 SharedRecordBaseInfo）
+			// 或者是比赛场的牌谱
+			// 记录主视角 ID（可能是 0）
+			s.majsoulRoundData.accountID = d.AccountID
+			break
+		}
+
+		// 看的是自己的牌谱
+		// 更新当前使用的账号
+		gameConf.addMajsoulAccountID(d.AccountID)
+		if s.majsoulRoundData.accountID != d.AccountID {
+			fmt.Println()
+			printAccountInfo(d.AccountID)
+			s.majsoulRoundData.accountID = d.AccountID
+		}
+	case len(d.RecordActions) > 0:
+		if s.majsoulCurrentRecordActionsList != nil {
+			// TODO: 网页发送更恰当的信息？
+			break
+		}
+
+		recordUUID := s.getMajsoulCurrentRecordUUID()
+		if recordUUID == "" {
+			s.logError(fmt.Errorf("错误：程序未收到所观看的雀魂牌谱的 UUID"))
+			break
+		}
+
+		baseInfo, ok := s.majsoulRecordMap[recordUUID]
+		if !ok {
+			s.logError(fmt.Errorf("错误：找不到雀魂牌谱 %s", recordUUID))
+			break
+		}
+
+		selfAccountID := s.majsoulRoundData.accountID
+		if selfAccountID == -1 {
+			s.logError(fmt.Errorf("错误：当前雀魂账号为空"))
+			break
+		}
+
+		s.majsoulRoundData.newGame()
+		s.majsoulRoundData.gameMode = gameModeRecord
+
+		// 获取并设置主视角初始座位
+		selfSeat, err := baseInfo.getSelfSeat(selfAccountID)
+		if err != nil {
+			s.logError(err)
+			break
+		}
+		s.majsoulRoundData.selfSeat = selfSeat
+
+		// 准备分析……
+		majsoulCurrentRecordActions, err := parseMajsoulRecordAction(d.RecordActions)
+		if err != nil {
+			s.logError(err)
+			break
+		}
+		s.majsoulCurrentRecordActionsList = majsoulCurrentRecordActions
+		s.majsoulCurrentRoundIndex = 0
+		s.majsoulCurrentActionIndex = 0
+
+		actions := s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex]
+
+		// 创建分析任务
+		analysisCache := newGameAnalysisCache(s, recordUUID, selfSeat)
+		analysisCache.considerOldYaku = s.majsoulRoundData.considerOldYaku
+		s.analysisCaches.set(analysisCache)
+		go analysisCache.runMajsoulRecordAnalysisTask(actions)
+
+		// 分析第一局的起始信息
+		data := actions[0].Action
+		s._analysisMajsoulRoundData(data, originJSON)
+	case d.RecordClickAction != "":
+		// 处理网页上的牌谱点击：上一局/跳到某局/下一局/上一巡/跳到某巡/下一巡/上一步/播放/暂停/下一步/点击桌面
+		// 暂不能分析他家手牌
+		s._onRecordClick(d.RecordClickAction, d.RecordClickActionIndex, d.FastRecordTo)
+	case d.LiveBaseInfo != nil:
+		// 观战
+		s.majsoulRoundData.accountID = 1 // TODO: 重构
+		s.majsoulRoundData.newGame()
+		s.majsoulRoundData.selfSeat = 0 // 观战进来后看的是东起的玩家
+		s.majsoulRoundData.gameMode = gameModeLive
+		clearConsole()
+		fmt.Printf("正在载入对战：%s", d.LiveBaseInfo.String())
+	case d.LiveFastAction != nil:
+		if err := s._loadLiveAction(d.LiveFastAction, true); err != nil {
+			s.logError(err)
+			break
+		}
+	case d.LiveAction != nil:
+		if err := s._loadLiveAction(d.LiveAction, false); err != nil {
+			s.logError(err)
+			break
+		}
+	case d.ChangeSeatTo != nil:
+		// 切换座位
+		changeSeatTo := *(d.ChangeSeatTo)
+		s.majsoulRoundData.selfSeat = changeSeatTo
+		if debugMode {
+			fmt.Println("座位已切换至", changeSeatTo)
+		}
+
+		var actions majsoulRoundActions
+		if s.majsoulRoundData.gameMode == gameModeLive { // 观战
+			actions = s.majsoulCurrentRoundActions
+		} else { // 牌谱
+			fullActions := s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex]
+			actions = fullActions[:s.majsoulCurrentActionIndex+1]
+			analysisCache := s.analysisCaches.get(changeSeatTo)
+			if analysisCache == nil {
+				analysisCache = newGameAnalysisCache(s, s.getMajsoulCurrentRecordUUID(), changeSeatTo)
+				analysisCache.considerOldYaku = s.majsoulRoundData.considerOldYaku
+			}
+			s.analysisCaches.set(analysisCache)
+			// 创建分析任务
+			go analysisCache.runMajsoulRecordAnalysisTask(fullActions)
+		}
+
+		s._fastLoadActions(actions)
+	case len(d.SyncGameActions) > 0:
+		s._fastLoadActions(d.SyncGameActions)
+	default:
+		// 其他：AI 分析
+		s._analysisMajsoulRoundData(d, originJSON)
+	}
+}
+
+func (s *mjSession) _loadMajsoulRecordBaseInfo(majsoulRecordUUID string) error {
+	baseInfo, ok := s.majsoulRecordMap[majsoulRecordUUID]
+	if !ok {
+		return fmt.Errorf("错误：找不到雀魂牌谱 %s", majsoulRecordUUID)
+	}
+
+	// 标记当前正在观看的牌谱
+	s.setMajsoulCurrentRecordUUID(majsoulRecordUUID)
+	clearConsole()
+	fmt.Printf("正在解析雀魂牌谱：%s", baseInfo.String())
+
+	// 标记古役模式
+	isGuyiMode := baseInfo.Config.isGuyiMode()
+	s.majsoulRoundData.considerOldYaku = isGuyiMode
+	if isGuyiMode {
+		fmt.Println()
+		color.HiGreen("古役模式已开启")
+	}
+
+	return nil
+}
+
+func (s *mjSession) _loadLiveAction(action *majsoulRecordAction, isFast bool) error {
+	if debugMode {
+		fmt.Println("[_loadLiveAction] 收到", action, isFast)
+	}
+
+	newActions, err := s.majsoulCurrentRoundActions.append(action)
+	if err != nil {
+		return err
+	}
+	s.majsoulCurrentRoundActions = newActions
+
+	s.majsoulRoundData.skipOutput = isFast
+	s._analysisMajsoulRoundData(action.Action, "")
+	return nil
+}
+
+func (s *mjSession) _analysisMajsoulRoundData(data *majsoulMessage, originJSON string) {
+	//if originJSON == "{}" {
+	//	return
+	//}
+	s.majsoulRoundData.msg = data
+	s.majsoulRoundData.originJSON = originJSON
+	if err := s.majsoulRoundData.analysis(); err != nil {
+		s.logError(err)
+	}
+}
+
+func (s *mjSession) _fastLoadActions(actions []*majsoulRecordAction) {
+	if len(actions) == 0 {
+		return
+	}
+	fastRecordEnd := util.MaxInt(0, len(actions)-3)
+	s.majsoulRoundData.skipOutput = true
+	// 留最后三个刷新，这样确保会刷新界面
+	for _, action := range actions[:fastRecordEnd] {
+		s._analysisMajsoulRoundData(action.Action, "")
+	}
+	s.majsoulRoundData.skipOutput = false
+	for _, action := range actions[fastRecordEnd:] {
+		s._analysisMajsoulRoundData(action.Action, "")
+	}
+}
+
+func (s *mjSession) _onRecordClick(clickAction string, clickActionIndex int, fastRecordTo int) {
+	if debugMode {
+		fmt.Println("[_onRecordClick] 收到", clickAction, clickActionIndex, fastRecordTo)
+	}
+
+	// 和牌/流局动画尚未播放完毕时就点击了，先分析下一局的起始信息
+	if s.stopMajsoulRecordRoundStartTimer() {
+		s._analysisMajsoulRecordRoundStart()
+	}
+
+	analysisCache := s.analysisCaches.current()
+
+	switch clickAction {
+	case "nextStep", "update":
+		newActionIndex := s.majsoulCurrentActionIndex + 1
+		if newActionIndex >= len(s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex]) {
+			return
+		}
+		s.majsoulCurrentActionIndex = newActionIndex
+	case "nextRound":
+		s.majsoulCurrentRoundIndex = (s.majsoulCurrentRoundIndex + 1) % len(s.majsoulCurrentRecordActionsList)
+		s.majsoulCurrentActionIndex = 0
+		go analysisCache.runMajsoulRecordAnalysisTask(s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex])
+	case "preRound":
+		s.majsoulCurrentRoundIndex = (s.majsoulCurrentRoundIndex - 1 + len(s.majsoulCurrentRecordActionsList)) % len(s.majsoulCurrentRecordActionsList)
+		s.majsoulCurrentActionIndex = 0
+		go analysisCache.runMajsoulRecordAnalysisTask(s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex])
+	case "jumpRound":
+		s.majsoulCurrentRoundIndex = clickActionIndex % len(s.majsoulCurrentRecordActionsList)
+		s.majsoulCurrentActionIndex = 0
+		go analysisCache.runMajsoulRecordAnalysisTask(s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex])
+	case "nextXun", "preXun", "jumpXun", "preStep", "jumpToLastRoundXun":
+		if clickAction == "jumpToLastRoundXun" {
+			s.majsoulCurrentRoundIndex = (s.majsoulCurrentRoundIndex - 1 + len(s.majsoulCurrentRecordActionsList)) % len(s.majsoulCurrentRecordActionsList)
+			go analysisCache.runMajsoulRecordAnalysisTask(s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex])
+		}
+
+		s.majsoulRoundData.skipOutput = true
+		currentRoundActions := s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex]
+		startActionIndex := 0
+		endActionIndex := fastRecordTo
+		if clickAction == "nextXun" {
+			startActionIndex = s.majsoulCurrentActionIndex + 1
+		}
+		if debugMode {
+			fmt.Printf("快速处理牌谱中的操作：局 %d 动作 %d-%d\n", s.majsoulCurrentRoundIndex, startActionIndex, endActionIndex)
+		}
+		for i, action := range currentRoundActions[startActionIndex : endActionIndex+1] {
+			if debugMode {
+				fmt.Printf("快速处理牌谱中的操作：局 %d 动作 %d\n", s.majsoulCurrentRoundIndex, startActionIndex+i)
+			}
+			s._analysisMajsoulRoundData(action.Action, "")
+		}
+		s.majsoulRoundData.skipOutput = false
+
+		s.majsoulCurrentActionIndex = endActionIndex + 1
+	default:
+		return
+	}
+
+	if debugMode {
+		fmt.Printf("处理牌谱中的操作：局 %d 动作 %d\n", s.majsoulCurrentRoundIndex, s.majsoulCurrentActionIndex)
+	}
+	action := s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex][s.majsoulCurrentActionIndex]
+	s._analysisMajsoulRoundData(action.Action, "")
+
+	if action.Name == "RecordHule" || action.Name == "RecordLiuJu" || action.Name == "RecordNoTile" {
+		// 播放和牌/流局动画，进入下一局或显示终局动画
+		s.majsoulCurrentRoundIndex++
+		s.majsoulCurrentActionIndex = 0
+		if s.majsoulCurrentRoundIndex == len(s.majsoulCurrentRecordActionsList) {
+			s.majsoulCurrentRoundIndex = 0
+			return
+		}
+
+		// 等待一秒后再分析下一局的起始信息，等待时不能持有 s.mu
+		var timer *time.Timer
+		timer = time.AfterFunc(time.Second, func() {
+			if !debugMode {
+				defer s.recoverMessagePanic()
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			// 已被点击处理、载入了其他牌谱或会话已关闭
+			if s.majsoulRecordRoundStartTimer != timer || s.getMajsoulCurrentRecordUUID() == "" {
+				return
+			}
+			s.majsoulRecordRoundStartTimer = nil
+			s._analysisMajsoulRecordRoundStart()
+		})
+		s.majsoulRecordRoundStartTimer = timer
+	}
+}
+
+// 停止等待分析牌谱下一局的起始信息，返回是否有尚未进行的分析
+// 调用前需持有 s.mu
+func (s *mjSession) stopMajsoulRecordRoundStartTimer() bool {
+	if s.majsoulRecordRoundStartTimer == nil {
+		return false
+	}
+	s.majsoulRecordRoundStartTimer.Stop()
+	s.majsoulRecordRoundStartTimer = nil
+	return true
+}
+
+// 分析牌谱当前局的起始信息，并开始该局的舍牌推荐计算
+// 调用前需持有 s.mu
+func (s *mjSession) _analysisMajsoulRecordRoundStart() {
+	actions := s.majsoulCurrentRecordActionsList[s.majsoulCurrentRoundIndex]
+	go s.analysisCaches.current().runMajsoulRecordAnalysisTask(actions)
+	data := actions[s.majsoulCurrentActionIndex].Action
+	s._analysisMajsoulRoundData(data, "")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+)
+
+// 在一局开始的基础上，根据快照恢复各家的副露和牌河，并修正牌山剩余量
+// 由于快照中没有各家舍牌的先后顺序，这里按照从庄家开始轮流舍牌的顺序来生成 globalDiscardTiles
+func (d *roundData) applySnapshot(snapshot *event.Snapshot) {
+	for who, ps := range snapshot.Players {
+		player := d.players[who]
+
+		for _, meld := range ps.Melds {
+			meld = meld.Copy()
+			player.melds = append(player.melds, meld)
+			if meld.MeldType != meldTypeAnkan {
+				player.isNaki = true
+			}
+			// 牌河中不含被鸣走的牌，所以副露中的牌都要从牌山中扣除
+			// 自家手牌中不含副露，同理
+			for _, tile := range meld.Tiles {
+				d.descLeftCounts(tile)
+			}
+			if who == 0 && meld.ContainRedFive {
+				d.numRedFives[meld.Tiles[0]/9]++
+			}
+		}
+
+		for i := 0; i < ps.NukiDoraNum; i++ {
+			d.descLeftCounts(30)
+		}
+		player.nukiDoraNum = ps.NukiDoraNum
+
+		for _, tile := range ps.DiscardTiles {
+			if tile < 0 {
+				tile = ^tile
+			}
+			d.descLeftCounts(tile)
+		}
+
+		if ps.ReachTileAt != -1 {
+			player.isReached = true
+			player.reachTileAt = ps.ReachTileAt
+		}
+	}
+
+	// 按巡目轮流还原全局舍牌
+	maxDiscardNum := 0
+	for _, ps := range snapshot.Players {
+		maxDiscardNum = util.MaxInt(maxDiscardNum, len(ps.DiscardTiles))
+	}
+	for turn := 0; turn < maxDiscardNum; turn++ {
+		for i := 0; i < len(d.players); i++ {
+			who := (d.dealer + i) % len(d.players)
+			ps := snapshot.Players[who]
+			if turn >= len(ps.DiscardTiles) {
+				continue
+			}
+			player := d.players[who]
+			tile := ps.DiscardTiles[turn]
+			d.globalDiscardTiles = append(d.globalDiscardTiles, tile)
+			player.discardTiles = append(player.discardTiles, tile)
+			player.latestDiscardAtGlobal = len(d.globalDiscardTiles) - 1
+			if turn == ps.ReachTileAt {
+				player.reachTileAtGlobal = len(d.globalDiscardTiles) - 1
+			}
+			// 标记外侧牌
+			if (ps.ReachTileAt == -1 || turn < ps.ReachTileAt) && turn < 5 {
+				if tile < 0 {
+					tile = ^tile
+				}
+				player.earlyOutsideTiles = append(player.earlyOutsideTiles, util.OutsideTiles(tile)...)
+			}
+		}
+	}
+
+	// 无法得知鸣牌后的舍牌是哪张，这里均视作最后一张舍牌，避免后续舍牌被误标记
+	for _, player := range d.players {
+		for range player.melds {
+			player.meldDiscardsAt = append(player.meldDiscardsAt, len(player.discardTiles)-1)
+			player.meldDiscardsAtGlobal = append(player.meldDiscardsAtGlobal, player.latestDiscardAtGlobal)
+		}
+	}
+}
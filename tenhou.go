@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
 	"sort"
 	"github.com/EndlessCheng/mahjong-helper/util"
 	"net/url"
@@ -210,17 +211,18 @@ type tenhouMessage struct {
 	//GPID  string `json:"gpid"`
 
 	// 重连 tag=REINIT
-	// `json:"seed"`
+	// `json:"seed"` // 同 INIT，若有杠宝牌则依次附在末尾 1,0,0,3,2,92,39
 	// `json:"ten"`
 	// `json:"oya"`
-	// `json:"hai"`
-	//Meld1    string `json:"m1"` // 各家副露编号 17450
-	//Meld2    string `json:"m2"`
-	//Meld3    string `json:"m3"`
-	//Kawa0 string `json:"kawa0"` // 各家牌河 112,73,3,131,43,98,78,116
-	//Kawa1 string `json:"kawa1"`
-	//Kawa2 string `json:"kawa2"`
-	//Kawa3 string `json:"kawa3"`
+	// `json:"hai"` // 自家当前手牌（不含副露）
+	Meld0 string `json:"m0" xml:"-"` // 各家副露编号（含拔北） 17450,35914
+	Meld1 string `json:"m1" xml:"-"`
+	Meld2 string `json:"m2" xml:"-"`
+	Meld3 string `json:"m3" xml:"-"`
+	Kawa0 string `json:"kawa0" xml:"-"` // 各家牌河（不含被鸣走的牌），255 表示其后的牌为立直宣言牌 112,73,3,255,131,43
+	Kawa1 string `json:"kawa1" xml:"-"`
+	Kawa2 string `json:"kawa2" xml:"-"`
+	Kawa3 string `json:"kawa3" xml:"-"`
 }
 
 //
@@ -232,6 +234,8 @@ type tenhouRoundData struct {
 	msg        *tenhouMessage
 
 	isRoundEnd bool // 某人和牌或流局。初始值为 true
+
+	username string // 当前登录的用户名
 }
 
 func (*tenhouRoundData) _tenhouTileToTile34(tenhouTile int) int {
@@ -409,6 +413,10 @@ func (d *tenhouRoundData) GetSelfSeat() int {
 	return -1
 }
 
+func (d *tenhouRoundData) ParseEvents() []*event.Event {
+	return parseMessageEvents(d)
+}
+
 func (d *tenhouRoundData) GetMessage() string {
 	return d.originJSON
 }
@@ -419,19 +427,20 @@ func (d *tenhouRoundData) SkipMessage() bool {
 }
 
 func (d *tenhouRoundData) IsLogin() bool {
-	// TODO: 重连时要填入 gameConf.currentActiveTenhouUsername
+	// TODO: 重连时要填入 username
 	return d.msg.Tag == "HELO"
 }
 
-func (d *tenhouRoundData) HandleLogin() {
+func (d *tenhouRoundData) HandleLogin() error {
 	username, err := url.QueryUnescape(d.msg.UserName)
 	if err != nil {
-		h.logError(err)
+		return err
 	}
-	if username != gameConf.currentActiveTenhouUsername {
+	if username != d.username {
 		color.HiGreen("%s 登录成功", username)
-		gameConf.currentActiveTenhouUsername = username
+		d.username = username
 	}
+	return nil
 }
 
 func (d *tenhouRoundData) IsInit() bool {
@@ -442,14 +451,15 @@ func (d *tenhouRoundData) ParseInit() (roundNumber int, benNumber int, dealer in
 	d.isRoundEnd = false
 
 	seedSplits := strings.Split(d.msg.Seed, ",")
-	if len(seedSplits) != 6 {
+	if len(seedSplits) != 6 && !(d.IsReconnect() && len(seedSplits) > 6) {
 		panic(fmt.Sprintln("seed 解析失败", d.msg.Seed))
 	}
 
 	roundNumber, _ = strconv.Atoi(seedSplits[0])
 	benNumber, _ = strconv.Atoi(seedSplits[1])
 	// TODO: 重构至 core。parser 不要修改任何东西
-	if roundNumber == 0 && benNumber == 0 {
+	// 重连时无法从之前的局得知是否为三麻，需要重新判断
+	if roundNumber == 0 && benNumber == 0 || d.IsReconnect() {
 		if util.InStrings("0", strings.Split(d.msg.Ten, ",")) {
 			d.playerNumber = 3
 		} else {
@@ -458,8 +468,10 @@ func (d *tenhouRoundData) ParseInit() (roundNumber int, benNumber int, dealer in
 	}
 
 	dealer, _ = strconv.Atoi(d.msg.Dealer)
-	doraIndicator, _ := d._parseTenhouTile(seedSplits[5])
-	doraIndicators = append(doraIndicators, doraIndicator)
+	for _, rawTile := range seedSplits[5:] {
+		doraIndicator, _ := d._parseTenhouTile(rawTile)
+		doraIndicators = append(doraIndicators, doraIndicator)
+	}
 	numRedFives = make([]int, 3)
 	tenhouTiles := strings.Split(d.msg.Hai, ",")
 	for _, tenhouTile := range tenhouTiles {
@@ -472,6 +484,54 @@ func (d *tenhouRoundData) ParseInit() (roundNumber int, benNumber int, dealer in
 	return
 }
 
+func (d *tenhouRoundData) IsReconnect() bool {
+	return d.msg.Tag == "REINIT"
+}
+
+// 天凤的牌河中，该值表示其后的牌为立直宣言牌
+const tenhouKawaReachMark = 255
+
+func (d *tenhouRoundData) ParseReconnect() *event.Snapshot {
+	snapshot := &event.Snapshot{}
+	rawMelds := []string{d.msg.Meld0, d.msg.Meld1, d.msg.Meld2, d.msg.Meld3}
+	rawKawas := []string{d.msg.Kawa0, d.msg.Kawa1, d.msg.Kawa2, d.msg.Kawa3}
+	for who := range snapshot.Players {
+		ps := &snapshot.Players[who]
+		ps.ReachTileAt = -1
+
+		if rawMelds[who] != "" {
+			for _, rawMeld := range strings.Split(rawMelds[who], ",") {
+				if d.isNukiOperator(rawMeld) {
+					ps.NukiDoraNum++
+					continue
+				}
+				ps.Melds = append(ps.Melds, d._parseMeld(rawMeld))
+			}
+		}
+
+		if rawKawas[who] != "" {
+			isReachTile := false
+			for _, rawTile := range strings.Split(rawKawas[who], ",") {
+				t, err := strconv.Atoi(rawTile)
+				if err != nil {
+					panic(err)
+				}
+				if t == tenhouKawaReachMark {
+					isReachTile = true
+					continue
+				}
+				if isReachTile {
+					ps.ReachTileAt = len(ps.DiscardTiles)
+					isReachTile = false
+				}
+				// 天凤的牌河数据中没有摸切信息，均视作手切
+				ps.DiscardTiles = append(ps.DiscardTiles, d._tenhouTileToTile34(t))
+			}
+		}
+	}
+	return snapshot
+}
+
 var _selfDrawReg = regexp.MustCompile("^T[0-9]{1,3}$")
 
 func isTenhouSelfDraw(tag string) bool {
@@ -527,7 +587,13 @@ func (d *tenhouRoundData) IsOpen() bool {
 
 func (d *tenhouRoundData) ParseOpen() (who int, meld *model.Meld, kanDoraIndicator int) {
 	who, _ = strconv.Atoi(d.msg.Who)
-	meldType, tenhouMeldTiles, tenhouCalledTile := d._parseTenhouMeld(d.msg.Meld)
+	meld = d._parseMeld(d.msg.Meld)
+	kanDoraIndicator = -1
+	return
+}
+
+func (d *tenhouRoundData) _parseMeld(data string) *model.Meld {
+	meldType, tenhouMeldTiles, tenhouCalledTile := d._parseTenhouMeld(data)
 	meldTiles := make([]int, len(tenhouMeldTiles))
 	for i, tenhouTile := range tenhouMeldTiles {
 		meldTiles[i] = d._tenhouTileToTile34(tenhouTile)
@@ -535,15 +601,13 @@ func (d *tenhouRoundData) ParseOpen() (who int, meld *model.Meld, kanDoraIndicat
 	sort.Ints(meldTiles)
 	calledTile := d._tenhouTileToTile34(tenhouCalledTile)
 	isCalledTileRedFive := d.isRedFive(tenhouCalledTile)
-	meld = &model.Meld{
+	return &model.Meld{
 		MeldType:          meldType,
 		Tiles:             meldTiles,
 		CalledTile:        calledTile,
 		ContainRedFive:    d.containRedFive(tenhouMeldTiles),
 		RedFiveFromOthers: isCalledTileRedFive && (meldType == model.MeldTypeChi || meldType == model.MeldTypePon || meldType == model.MeldTypeMinkan),
 	}
-	kanDoraIndicator = -1
-	return
 }
 
 func (d *tenhouRoundData) IsReach() bool {
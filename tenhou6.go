@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou6"
+	"github.com/EndlessCheng/mahjong-helper/util/event"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"sort"
+)
+
+// tenhou.net/6 格式的牌谱，雀魂牌谱也会先转换成该格式再分析
+type tenhou6Record struct {
+	*tenhou6.Game
+}
+
+func (r *tenhou6Record) playerNumber() int {
+	return r.PlayerNumber()
+}
+
+func (r *tenhou6Record) playerName(seat int) string {
+	if seat < len(r.Names) && r.Names[seat] != "" {
+		return r.Names[seat]
+	}
+	return fmt.Sprintf("%d 号位", seat)
+}
+
+func (r *tenhou6Record) toTenhou6() (*tenhou6.Game, error) {
+	return r.Game, nil
+}
+
+// 以 selfSeat 的视角分析整场牌谱，返回分析结果
+func (r *tenhou6Record) analysis(selfSeat int) *gameAnalysisCache {
+	analysisCache := newGameAnalysisCache(nil, "", selfSeat)
+	analysisCaches := newAnalysisCacheList()
+	analysisCaches.set(analysisCache)
+
+	d := newEventLogRoundData()
+	d.selfSeat = selfSeat
+	d.analysisCaches = analysisCaches
+	d.gameMode = gameModeRecordCache
+	d.skipOutput = true
+	for _, round := range r.Rounds {
+		var roundCache *roundAnalysisCache
+		if round.RoundNumber < len(analysisCache.wholeGameCache) && round.BenNumber < len(analysisCache.wholeGameCache[round.RoundNumber]) {
+			roundCache = &roundAnalysisCache{isStart: true}
+			analysisCache.wholeGameCache[round.RoundNumber][round.BenNumber] = roundCache
+		}
+		for _, e := range tenhou6RoundEvents(round, selfSeat) {
+			if err := d.analysisEvent(e); err != nil && debugMode {
+				fmt.Println(err)
+			}
+		}
+		if roundCache != nil {
+			roundCache.isEnd = true
+		}
+	}
+	return analysisCache
+}
+
+//
+
+func mustParseTenhou6Tile(code int) (tile34 int, isRedFive bool) {
+	tile34, isRedFive, err := tenhou6.ParseTileCode(code)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// 将 tenhou.net/6 的操作转换成副露
+func tenhou6ActionToMeld(a *tenhou6.Action) *model.Meld {
+	meld := &model.Meld{}
+	switch a.Type {
+	case tenhou6.ActionTypeChi:
+		meld.MeldType = meldTypeChi
+	case tenhou6.ActionTypePon:
+		meld.MeldType = meldTypePon
+	case tenhou6.ActionTypeDaiminkan:
+		meld.MeldType = meldTypeMinkan
+	case tenhou6.ActionTypeAnkan:
+		meld.MeldType = meldTypeAnkan
+	case tenhou6.ActionTypeKakan:
+		meld.MeldType = meldTypeKakan
+	}
+
+	calledTile, isCalledTileRedFive := mustParseTenhou6Tile(a.Tile)
+	meld.CalledTile = calledTile
+	meld.Tiles = append(meld.Tiles, calledTile)
+	meld.ContainRedFive = isCalledTileRedFive
+	for _, code := range a.SelfTiles {
+		tile, isRedFive := mustParseTenhou6Tile(code)
+		meld.SelfTiles = append(meld.SelfTiles, tile)
+		meld.Tiles = append(meld.Tiles, tile)
+		meld.ContainRedFive = meld.ContainRedFive || isRedFive
+	}
+	sort.Ints(meld.Tiles)
+	switch a.Type {
+	case tenhou6.ActionTypeChi, tenhou6.ActionTypePon, tenhou6.ActionTypeDaiminkan:
+		meld.RedFiveFromOthers = isCalledTileRedFive
+	case tenhou6.ActionTypeKakan:
+		// 加杠时 SelfTiles 为原来的碰
+		meld.SelfTiles = nil
+	}
+	return meld
+}
+
+// 将一局牌谱转换成以 selfSeat 为自家的事件
+// 他家的摸牌不产生事件
+func tenhou6RoundEvents(r *tenhou6.Round, selfSeat int) []*event.Event {
+	// 与雀魂相同，三麻时也按照四个座位计算相对位置
+	relativeWho := func(seat int) int {
+		return (seat - selfSeat + 4) % 4
+	}
+
+	hands := []int{}
+	numRedFives := make([]int, 3)
+	for _, code := range r.Hands[selfSeat] {
+		tile, isRedFive := mustParseTenhou6Tile(code)
+		hands = append(hands, tile)
+		if isRedFive {
+			numRedFives[tile/9]++
+		}
+	}
+	doraIndicator, _ := mustParseTenhou6Tile(r.DoraIndicators[0])
+	events := []*event.Event{event.NewRoundStart(&event.RoundStart{
+		RoundNumber:    r.RoundNumber,
+		BenNumber:      r.BenNumber,
+		Dealer:         relativeWho(r.Dealer()),
+		PlayerNumber:   r.PlayerNumber(),
+		DoraIndicators: []int{doraIndicator},
+		HandTiles:      hands,
+		NumRedFives:    numRedFives,
+	})}
+
+	numKans := 0
+	for _, a := range r.Actions {
+		who := relativeWho(a.Who)
+		switch a.Type {
+		case tenhou6.ActionTypeDraw:
+			if who == 0 {
+				tile, isRedFive := mustParseTenhou6Tile(a.Tile)
+				events = append(events, event.NewDraw(&event.Draw{Tile: tile, IsRedFive: isRedFive}))
+			}
+		case tenhou6.ActionTypeDiscard:
+			tile, isRedFive := mustParseTenhou6Tile(a.Tile)
+			events = append(events, event.NewDiscard(&event.Discard{
+				Who:         who,
+				Tile:        tile,
+				IsRedFive:   isRedFive,
+				IsTsumogiri: a.IsTsumogiri,
+				IsReach:     a.IsRiichi,
+			}))
+		case tenhou6.ActionTypeNuki:
+			events = append(events, event.NewNuki(&event.Nuki{Who: who}))
+		default:
+			events = append(events, event.NewCall(&event.Call{Who: who, Meld: tenhou6ActionToMeld(a)}))
+			if a.IsKan() {
+				numKans++
+				if numKans < len(r.DoraIndicators) {
+					kanDoraIndicator, _ := mustParseTenhou6Tile(r.DoraIndicators[numKans])
+					events = append(events, event.NewNewDora(&event.NewDora{Indicator: kanDoraIndicator}))
+				}
+			}
+		}
+	}
+
+	if res := r.Result; res != nil && res.Name == tenhou6.ResultNameAgari {
+		win := &event.Win{}
+		for _, agari := range res.Agaris {
+			win.Whos = append(win.Whos, relativeWho(agari.Who))
+			point := 0
+			if agari.Who < len(agari.Deltas) {
+				point = agari.Deltas[agari.Who]
+			}
+			win.Points = append(win.Points, point)
+		}
+		events = append(events, event.NewWin(win))
+	} else {
+		events = append(events, event.NewDrawGame(&event.DrawGame{}))
+	}
+	return events
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 解析天凤牌谱（mjlog XML）时使用的 DataParser
+// 牌谱中的座位是绝对座位，需要先用 tenhouRecordConverter 转换成以 selfSeat 为自家的消息
+type tenhouRecordRoundData struct {
+	*tenhouRoundData
+
+	selfSeat int // 0-起家 1-起家的下家 ...
+}
+
+func newTenhouRecordRoundData(selfSeat int, analysisCaches *analysisCacheList) *tenhouRecordRoundData {
+	d := &tenhouRecordRoundData{
+		tenhouRoundData: &tenhouRoundData{isRoundEnd: true},
+		selfSeat:        selfSeat,
+	}
+	d.roundData = newGame(d)
+	d.analysisCaches = analysisCaches
+	d.gameMode = gameModeRecordCache
+	d.skipOutput = true
+	return d
+}
+
+func (d *tenhouRecordRoundData) GetSelfSeat() int {
+	return d.selfSeat
+}
+
+//
+
+var (
+	_recordDrawReg    = regexp.MustCompile("^[T-W][0-9]{1,3}$")
+	_recordDiscardReg = regexp.MustCompile("^[D-G][0-9]{1,3}$")
+)
+
+// 将牌谱中的操作转换成以 selfSeat 为自家的天凤实时消息
+type tenhouRecordConverter struct {
+	selfSeat     int
+	playerNumber int
+
+	// 各家（绝对座位）最近一次摸到的牌，用于推断摸切
+	latestDrawTiles []int
+}
+
+func newTenhouRecordConverter(selfSeat int) *tenhouRecordConverter {
+	return &tenhouRecordConverter{
+		selfSeat:        selfSeat,
+		playerNumber:    4,
+		latestDrawTiles: []int{-1, -1, -1, -1},
+	}
+}
+
+// 绝对座位 -> 相对座位
+func (c *tenhouRecordConverter) relativeSeat(seat int) int {
+	return (seat - c.selfSeat + c.playerNumber) % c.playerNumber
+}
+
+func (c *tenhouRecordConverter) relativeWho(who string) string {
+	seat, err := strconv.Atoi(who)
+	if err != nil {
+		return who
+	}
+	return strconv.Itoa(c.relativeSeat(seat))
+}
+
+// 返回 nil 表示该操作无需分析
+func (c *tenhouRecordConverter) convert(a *tenhou.RecordAction) *tenhouMessage {
+	switch tag := a.Tag; {
+	case tag == "INIT":
+		hais := []string{a.Hai0, a.Hai1, a.Hai2, a.Hai3}
+		if a.Hai3 == "" {
+			c.playerNumber = 3
+		} else {
+			c.playerNumber = 4
+		}
+		for i := range c.latestDrawTiles {
+			c.latestDrawTiles[i] = -1
+		}
+
+		// 点数也要转成相对座位，三麻的第四个点数为 0
+		tens := strings.Split(a.Ten, ",")
+		if len(tens) >= c.playerNumber {
+			rotated := append([]string{}, tens[c.selfSeat:c.playerNumber]...)
+			rotated = append(rotated, tens[:c.selfSeat]...)
+			tens = append(rotated, tens[c.playerNumber:]...)
+		}
+		return &tenhouMessage{
+			Tag:    tag,
+			Seed:   a.Seed,
+			Ten:    strings.Join(tens, ","),
+			Dealer: c.relativeWho(a.Dealer),
+			Hai:    hais[c.selfSeat],
+		}
+	case _recordDrawReg.MatchString(tag):
+		seat := int(tag[0] - 'T')
+		tile, _ := strconv.Atoi(tag[1:])
+		c.latestDrawTiles[seat] = tile
+		if c.relativeSeat(seat) != 0 {
+			// 他家摸牌
+			return nil
+		}
+		return &tenhouMessage{Tag: "T" + tag[1:]}
+	case _recordDiscardReg.MatchString(tag):
+		seat := int(tag[0] - 'D')
+		tile, _ := strconv.Atoi(tag[1:])
+		// 牌谱中没有记录摸切，这里认为切出的牌和摸的牌相同就是摸切
+		isTsumogiri := tile == c.latestDrawTiles[seat]
+		c.latestDrawTiles[seat] = -1
+		prefix := 'D' + byte(c.relativeSeat(seat))
+		if prefix != 'D' && isTsumogiri {
+			prefix = util.Lower(prefix)
+		}
+		return &tenhouMessage{Tag: string(prefix) + tag[1:]}
+	case tag == "N":
+		return &tenhouMessage{Tag: tag, Who: c.relativeWho(a.Who), Meld: a.Meld}
+	case tag == "REACH":
+		return &tenhouMessage{Tag: tag, Who: c.relativeWho(a.Who), Step: a.Step}
+	case tag == "DORA":
+		return &tenhouMessage{Tag: tag, Hai: a.Hai}
+	case tag == "AGARI":
+		return &tenhouMessage{Tag: tag, Who: c.relativeWho(a.Who), Ten: a.Ten}
+	case tag == "RYUUKYOKU":
+		return &tenhouMessage{Tag: tag}
+	default:
+		// SHUFFLE GO UN TAIKYOKU BYE 等
+		return nil
+	}
+}
+
+//
+
+// 天凤牌谱
+type tenhouRecord struct {
+	playerNames []string
+
+	// GO 中的 type，记录了对局的规则
+	gameType int
+
+	// SHUFFLE 中的 seed，用于还原牌山
+	shuffleSeed string
+
+	// 每局的操作，以 INIT 开头
+	rounds [][]*tenhou.RecordAction
+}
+
+func (r *tenhouRecord) playerNumber() int {
+	if len(r.rounds) > 0 && r.rounds[0][0].Hai3 == "" {
+		return 3
+	}
+	return 4
+}
+
+func parseTenhouRecord(data []byte) (*tenhouRecord, error) {
+	// 从天凤下载的 .mjlog 文件是 gzip 压缩过的
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = ioutil.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	rawRecord := tenhou.Record{}
+	if err := xml.Unmarshal(data, &rawRecord); err != nil {
+		return nil, fmt.Errorf("牌谱解析失败：%v", err)
+	}
+
+	record := &tenhouRecord{}
+	for _, action := range rawRecord.Actions {
+		switch action.Tag {
+		case "SHUFFLE":
+			record.shuffleSeed = action.Seed
+		case "GO":
+			record.gameType, _ = strconv.Atoi(action.Type)
+		case "UN":
+			if record.playerNames != nil {
+				// 重连时也会有 UN
+				continue
+			}
+			for _, rawName := range []string{action.Name0, action.Name1, action.Name2, action.Name3} {
+				name, err := url.QueryUnescape(rawName)
+				if err != nil {
+					name = rawName
+				}
+				record.playerNames = append(record.playerNames, name)
+			}
+		case "INIT":
+			record.rounds = append(record.rounds, []*tenhou.RecordAction{action})
+		default:
+			if len(record.rounds) > 0 {
+				record.rounds[len(record.rounds)-1] = append(record.rounds[len(record.rounds)-1], action)
+			}
+		}
+	}
+	if len(record.rounds) == 0 {
+		return nil, fmt.Errorf("牌谱中没有对局数据")
+	}
+	return record, nil
+}
+
+func (r *tenhouRecord) playerName(seat int) string {
+	if seat < len(r.playerNames) && r.playerNames[seat] != "" {
+		return r.playerNames[seat]
+	}
+	return fmt.Sprintf("%d 号位", seat)
+}
+
+// 以 selfSeat 的视角分析整场牌谱，返回分析结果
+func (r *tenhouRecord) analysis(selfSeat int) *gameAnalysisCache {
+	analysisCache := newGameAnalysisCache(nil, "", selfSeat)
+	analysisCaches := newAnalysisCacheList()
+	analysisCaches.set(analysisCache)
+
+	d := newTenhouRecordRoundData(selfSeat, analysisCaches)
+	converter := newTenhouRecordConverter(selfSeat)
+	for _, actions := range r.rounds {
+		var roundCache *roundAnalysisCache
+		for _, action := range actions {
+			msg := converter.convert(action)
+			if msg == nil {
+				continue
+			}
+			if msg.Tag == "INIT" {
+				seedSplits := strings.Split(msg.Seed, ",")
+				roundNumber, _ := strconv.Atoi(seedSplits[0])
+				benNumber, _ := strconv.Atoi(seedSplits[1])
+				roundCache = nil
+				if roundNumber < len(analysisCache.wholeGameCache) && benNumber < len(analysisCache.wholeGameCache[roundNumber]) {
+					roundCache = &roundAnalysisCache{isStart: true}
+					analysisCache.wholeGameCache[roundNumber][benNumber] = roundCache
+				}
+			}
+			d.msg = msg
+			if err := d.analysis(); err != nil && debugMode {
+				fmt.Println(err)
+			}
+		}
+		if roundCache != nil {
+			roundCache.isEnd = true
+		}
+	}
+	return analysisCache
+}
+
+// 统计自家舍牌与 AI 推荐不一致的巡目数
+// diffAttack: 与攻め推奨不同的巡目数
+// diffBoth: 与攻め推奨、守り推奨都不同的巡目数
+func (rc *roundAnalysisCache) countDisagreements() (total int, diffAttack int, diffBoth int) {
+	for _, c := range rc.cache {
+		if c.selfDiscardTile == -1 || c.aiAttackDiscardTile == -1 {
+			continue
+		}
+		total++
+		if c.selfDiscardTile != c.aiAttackDiscardTile {
+			diffAttack++
+			if c.selfDiscardTile != c.aiDefenceDiscardTile {
+				diffBoth++
+			}
+		}
+	}
+	return
+}
+
+// 整场的不一致统计
+func (c *gameAnalysisCache) countDisagreements() (total int, diffAttack int, diffBoth int) {
+	for _, roundCaches := range c.wholeGameCache {
+		for _, rc := range roundCaches {
+			if rc == nil {
+				continue
+			}
+			t, da, db := rc.countDisagreements()
+			total += t
+			diffAttack += da
+			diffBoth += db
+		}
+	}
+	return
+}
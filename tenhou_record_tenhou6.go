@@ -0,0 +1,349 @@
+package main
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou6"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 天凤牌谱中役的编号
+var tenhouYakuNames = []string{
+	"門前清自摸和", "立直", "一発", "槍槓", "嶺上開花", "海底摸月", "河底撈魚", "平和", "断幺九", "一盃口",
+	"自風 東", "自風 南", "自風 西", "自風 北", "場風 東", "場風 南", "場風 西", "場風 北", "役牌 白", "役牌 發",
+	"役牌 中", "両立直", "七対子", "混全帯幺九", "一気通貫", "三色同順", "三色同刻", "三槓子", "対々和", "三暗刻",
+	"小三元", "混老頭", "二盃口", "純全帯幺九", "混一色", "清一色", "人和", "天和", "地和", "大三元",
+	"四暗刻", "四暗刻単騎", "字一色", "緑一色", "清老頭", "九蓮宝燈", "純正九蓮宝燈", "国士無双", "国士無双１３面", "大四喜",
+	"小四喜", "四槓子", "ドラ", "裏ドラ", "赤ドラ",
+}
+
+func tenhouYakuName(id int) string {
+	if id >= 0 && id < len(tenhouYakuNames) {
+		return tenhouYakuNames[id]
+	}
+	return fmt.Sprintf("役%d", id)
+}
+
+// 途中流局的类型
+var tenhouRyuukyokuNames = map[string]string{
+	"yao9":   "九種九牌",
+	"reach4": "四家立直",
+	"ron3":   "三家和了",
+	"kan4":   "四槓散了",
+	"kaze4":  "四風連打",
+	"nm":     "流し満貫",
+}
+
+// 逗号分隔的整数，空字符串返回 nil
+func parseTenhouInts(s string) []int {
+	if s == "" {
+		return nil
+	}
+	splits := strings.Split(s, ",")
+	ints := make([]int, len(splits))
+	for i, split := range splits {
+		ints[i], _ = strconv.Atoi(split)
+	}
+	return ints
+}
+
+// 天凤的牌（0-135）转换成 tenhou.net/6 的牌编号
+func tenhouTileToTenhou6(tenhouTile int) int {
+	return tenhou6.TileCode(tenhouTile/4, tenhouTile == redFiveMan || tenhouTile == redFivePin || tenhouTile == redFiveSou)
+}
+
+func tenhouTilesToTenhou6(tenhouTiles []int) []int {
+	codes := make([]int, len(tenhouTiles))
+	for i, tenhouTile := range tenhouTiles {
+		codes[i] = tenhouTileToTenhou6(tenhouTile)
+	}
+	return codes
+}
+
+// 天凤牌谱中的点数（百点）及增减转换成各家的点数变化
+func parseTenhouScoreDeltas(sc string) []int {
+	scores := parseTenhouInts(sc)
+	if len(scores) < 8 {
+		return nil
+	}
+	deltas := make([]int, 4)
+	for i := range deltas {
+		deltas[i] = 100 * scores[2*i+1]
+	}
+	return deltas
+}
+
+// 根据 GO 中的 type 生成规则，如 "鳳南喰赤"，三麻为 "三鳳南喰赤"
+func (r *tenhouRecord) tenhou6Rule() tenhou6.Rule {
+	t := r.gameType
+	disp := ""
+	if t&0x10 > 0 {
+		disp += "三"
+	}
+	switch t & 0xA0 {
+	case 0xA0:
+		disp += "鳳"
+	case 0x20:
+		disp += "特"
+	case 0x80:
+		disp += "上"
+	default:
+		disp += "般"
+	}
+	if t&0x08 > 0 {
+		disp += "南"
+	} else {
+		disp += "東"
+	}
+	if t&0x04 == 0 {
+		disp += "喰"
+	}
+	rule := tenhou6.Rule{}
+	if t&0x02 == 0 {
+		disp += "赤"
+		rule.Aka = 1
+	}
+	if t&0x40 > 0 {
+		disp += "速"
+	}
+	rule.Disp = disp
+	return rule
+}
+
+// 将一局牌谱转换成 tenhou.net/6 格式
+type tenhou6RoundConverter struct {
+	round        *tenhou6.Round
+	playerNumber int
+
+	// 各家最近一次摸到的牌，用于推断摸切
+	latestDrawTiles []int
+
+	// 已宣告立直，下一张舍牌为立直宣言牌
+	isReaching []bool
+}
+
+func (c *tenhou6RoundConverter) addAction(a *tenhou6.Action) {
+	c.round.Actions = append(c.round.Actions, a)
+}
+
+func (c *tenhou6RoundConverter) init(a *tenhou.RecordAction) {
+	seed := parseTenhouInts(a.Seed)
+	c.round = &tenhou6.Round{}
+	if len(seed) >= 6 {
+		c.round.RoundNumber, c.round.BenNumber, c.round.RiichiSticks = seed[0], seed[1], seed[2]
+		c.round.DoraIndicators = []int{tenhouTileToTenhou6(seed[5])}
+	}
+	for _, ten := range parseTenhouInts(a.Ten) {
+		c.round.Scores = append(c.round.Scores, 100*ten)
+	}
+	for _, hai := range []string{a.Hai0, a.Hai1, a.Hai2, a.Hai3} {
+		tiles := parseTenhouInts(hai)
+		sort.Ints(tiles)
+		c.round.Hands = append(c.round.Hands, tenhouTilesToTenhou6(tiles))
+	}
+	c.latestDrawTiles = []int{-1, -1, -1, -1}
+	c.isReaching = make([]bool, 4)
+}
+
+func (c *tenhou6RoundConverter) meld(a *tenhou.RecordAction) error {
+	who, _ := strconv.Atoi(a.Who)
+	bits, err := strconv.Atoi(a.Meld)
+	if err != nil {
+		return err
+	}
+	if bits&0x20 > 0 {
+		c.addAction(&tenhou6.Action{Type: tenhou6.ActionTypeNuki, Who: who, Tile: tenhou6.TileCode(30, false)})
+		return nil
+	}
+
+	meldType, tenhouMeldTiles, tenhouCalledTile := (&tenhouRoundData{})._parseTenhouMeld(a.Meld)
+	calledTile := tenhouTileToTenhou6(tenhouCalledTile)
+	if meldType == meldTypeKakan {
+		kakan, err := c.round.NewKakan(who, calledTile)
+		if err != nil {
+			return err
+		}
+		c.addAction(kakan)
+		return nil
+	}
+
+	action := &tenhou6.Action{
+		Who:  who,
+		Tile: calledTile,
+		// 低两位为被鸣牌的玩家相对鸣牌者的位置 1=下家, 2=对家, 3=上家
+		From: (who + bits&0x3) % c.playerNumber,
+	}
+	for _, tenhouTile := range tenhouMeldTiles {
+		if tenhouTile != tenhouCalledTile {
+			action.SelfTiles = append(action.SelfTiles, tenhouTileToTenhou6(tenhouTile))
+		}
+	}
+	switch meldType {
+	case meldTypeChi:
+		action.Type = tenhou6.ActionTypeChi
+	case meldTypePon:
+		action.Type = tenhou6.ActionTypePon
+	case meldTypeMinkan:
+		action.Type = tenhou6.ActionTypeDaiminkan
+	case meldTypeAnkan:
+		action.Type = tenhou6.ActionTypeAnkan
+		action.From = who
+	}
+	c.addAction(action)
+	return nil
+}
+
+func (c *tenhou6RoundConverter) agari(a *tenhou.RecordAction) {
+	r := c.round
+	if r.Result == nil {
+		r.Result = &tenhou6.Result{Name: tenhou6.ResultNameAgari}
+	}
+	who, _ := strconv.Atoi(a.Who)
+	fromWho, _ := strconv.Atoi(a.FromWho)
+	agari := &tenhou6.Agari{Who: who, From: fromWho, Pao: who, Deltas: parseTenhouScoreDeltas(a.Score)}
+	if a.PaoWho != "" {
+		agari.Pao, _ = strconv.Atoi(a.PaoWho)
+	}
+
+	// 符数,点数,满贯等
+	fu, point := 0, 0
+	if ten := parseTenhouInts(a.Ten); len(ten) >= 2 {
+		fu, point = ten[0], ten[1]
+	}
+	han := 0
+	var yakuInfo []string
+	yaku := parseTenhouInts(a.Yaku)
+	for i := 0; i+1 < len(yaku); i += 2 {
+		if yaku[i+1] > 0 {
+			han += yaku[i+1]
+			yakuInfo = append(yakuInfo, tenhou6.YakuInfo(tenhouYakuName(yaku[i]), yaku[i+1], false))
+		}
+	}
+	yakuman := parseTenhouInts(a.Yakuman)
+	for _, id := range yakuman {
+		yakuInfo = append(yakuInfo, tenhou6.YakuInfo(tenhouYakuName(id), 0, true))
+	}
+
+	title := tenhou6.ScoreTitle(fu, han, len(yakuman) > 0)
+	if who != fromWho {
+		agari.Info = append(agari.Info, tenhou6.RonPointsInfo(title, point))
+	} else {
+		// 自摸时根据各家的点数变化推算支付的点数
+		honba := 0
+		if ba := parseTenhouInts(a.Ba); len(ba) > 0 {
+			honba = ba[0]
+		}
+		pay := func(seat int) int {
+			if agari.Deltas == nil {
+				return 0
+			}
+			return -agari.Deltas[seat] - 100*honba
+		}
+		dealer := r.Dealer()
+		if who == dealer {
+			agari.Info = append(agari.Info, tenhou6.TsumoPointsInfo(title, pay((who+1)%c.playerNumber), 0))
+		} else {
+			nonDealer := (who + 1) % c.playerNumber
+			if nonDealer == dealer {
+				nonDealer = (nonDealer + 1) % c.playerNumber
+			}
+			agari.Info = append(agari.Info, tenhou6.TsumoPointsInfo(title, pay(nonDealer), pay(dealer)))
+		}
+	}
+	agari.Info = append(agari.Info, yakuInfo...)
+	r.Result.Agaris = append(r.Result.Agaris, agari)
+
+	if a.UraDoraTile != "" && r.UraDoraIndicators == nil {
+		r.UraDoraIndicators = tenhouTilesToTenhou6(parseTenhouInts(a.UraDoraTile))
+	}
+}
+
+func (c *tenhou6RoundConverter) ryuukyoku(a *tenhou.RecordAction) {
+	result := &tenhou6.Result{Deltas: parseTenhouScoreDeltas(a.Score)}
+	if name, ok := tenhouRyuukyokuNames[a.Type]; ok {
+		result.Name = name
+	} else {
+		// 荒牌流局，听牌者的手牌会公开
+		numTenpai := 0
+		for _, hai := range []string{a.Hai0, a.Hai1, a.Hai2, a.Hai3} {
+			if hai != "" {
+				numTenpai++
+			}
+		}
+		switch numTenpai {
+		case 0:
+			result.Name = "全員不聴"
+		case c.playerNumber:
+			result.Name = "全員聴牌"
+		default:
+			result.Name = "流局"
+		}
+	}
+	c.round.Result = result
+}
+
+func (c *tenhou6RoundConverter) convert(a *tenhou.RecordAction) error {
+	switch tag := a.Tag; {
+	case tag == "INIT":
+		c.init(a)
+	case _recordDrawReg.MatchString(tag):
+		seat := int(tag[0] - 'T')
+		tile, _ := strconv.Atoi(tag[1:])
+		c.latestDrawTiles[seat] = tile
+		c.addAction(&tenhou6.Action{Type: tenhou6.ActionTypeDraw, Who: seat, Tile: tenhouTileToTenhou6(tile)})
+	case _recordDiscardReg.MatchString(tag):
+		seat := int(tag[0] - 'D')
+		tile, _ := strconv.Atoi(tag[1:])
+		c.addAction(&tenhou6.Action{
+			Type:        tenhou6.ActionTypeDiscard,
+			Who:         seat,
+			Tile:        tenhouTileToTenhou6(tile),
+			IsTsumogiri: tile == c.latestDrawTiles[seat],
+			IsRiichi:    c.isReaching[seat],
+		})
+		c.latestDrawTiles[seat] = -1
+		c.isReaching[seat] = false
+	case tag == "N":
+		return c.meld(a)
+	case tag == "REACH":
+		if a.Step == "1" {
+			who, _ := strconv.Atoi(a.Who)
+			c.isReaching[who] = true
+		}
+	case tag == "DORA":
+		hai, _ := strconv.Atoi(a.Hai)
+		c.round.DoraIndicators = append(c.round.DoraIndicators, tenhouTileToTenhou6(hai))
+	case tag == "AGARI":
+		c.agari(a)
+	case tag == "RYUUKYOKU":
+		c.ryuukyoku(a)
+	}
+	return nil
+}
+
+// 转换成 tenhou.net/6 格式
+func (r *tenhouRecord) toTenhou6() (*tenhou6.Game, error) {
+	g := &tenhou6.Game{
+		Title: []string{"", ""},
+		Names: make([]string, 4),
+		Rule:  r.tenhou6Rule(),
+	}
+	copy(g.Names, r.playerNames)
+
+	c := &tenhou6RoundConverter{playerNumber: r.playerNumber()}
+	for _, actions := range r.rounds {
+		for _, action := range actions {
+			if err := c.convert(action); err != nil {
+				return nil, err
+			}
+		}
+		if c.round.Result == nil {
+			// 断线等原因导致牌谱不完整
+			c.round.Result = &tenhou6.Result{Name: "流局"}
+		}
+		g.Rounds = append(g.Rounds, c.round)
+	}
+	return g, nil
+}
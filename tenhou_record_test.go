@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou6"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTenhouRecord = `<mjloggm ver="2.3"><SHUFFLE seed="" ref=""/><GO type="169" lobby="0"/><UN n0="%41" n1="%42" n2="%43" n3="%44" dan="0,0,0,0" rate="1500.00,1500.00,1500.00,1500.00" sx="M,M,M,M"/><TAIKYOKU oya="0"/>` +
+	`<INIT seed="0,0,0,1,2,132" ten="250,250,250,250" oya="0" hai0="0,4,8,12,16,20,24,28,32,36,40,44,48" hai1="1,5,9,13,17,21,25,29,33,37,41,45,49" hai2="2,6,10,14,18,22,26,30,34,38,42,46,50" hai3="3,7,11,15,19,23,27,31,35,39,43,47,51"/>` +
+	`<T108/><D108/><U109/><E109/><V110/><F2/><W111/><G111/><T112/><D0/>` +
+	`<AGARI ba="0,0" hai="1,5,9,13,17,21,25,29,33,37,41,45,49,0" machi="0" ten="30,1000,0" yaku="8,1" doraHai="132" who="1" fromWho="0" sc="250,-10,250,10,250,0,250,0" owari="240,-26.0,260,4.0,250,-5.0,250,27.0"/></mjloggm>`
+
+func Test_tenhouRecordConverter(t *testing.T) {
+	assert := assert.New(t)
+
+	record, err := parseTenhouRecord([]byte(testTenhouRecord))
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"A", "B", "C", "D"}, record.playerNames)
+	assert.Equal(4, record.playerNumber())
+	assert.Len(record.rounds, 1)
+
+	c := newTenhouRecordConverter(1)
+	var tags []string
+	for _, action := range record.rounds[0] {
+		if msg := c.convert(action); msg != nil {
+			tags = append(tags, msg.Tag)
+			if msg.Tag == "INIT" {
+				assert.Equal("3", msg.Dealer)
+				assert.Equal(action.Hai1, msg.Hai)
+			}
+			if msg.Tag == "AGARI" {
+				assert.Equal("0", msg.Who)
+			}
+		}
+	}
+	// 与摸牌相同的舍牌视作摸切
+	assert.Equal([]string{"INIT", "g108", "T109", "D109", "E2", "f111", "G0", "AGARI"}, tags)
+
+	// .mjlog 是 gzip 压缩的
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.Write([]byte(testTenhouRecord))
+	w.Close()
+	record, err = parseTenhouRecord(buf.Bytes())
+	if assert.NoError(err) {
+		assert.Len(record.rounds, 1)
+	}
+
+	_, err = parseTenhouRecord([]byte(`<mjloggm ver="2.3"></mjloggm>`))
+	assert.Error(err)
+}
+
+func Test_tenhouRecord_analysis(t *testing.T) {
+	assert := assert.New(t)
+
+	record, err := parseTenhouRecord([]byte(testTenhouRecord))
+	if !assert.NoError(err) {
+		return
+	}
+	analysisCache := record.analysis(0)
+	rc := analysisCache.wholeGameCache[0][0]
+	if assert.NotNil(rc) {
+		assert.True(rc.isEnd)
+		if assert.Len(rc.cache, 2) {
+			assert.Equal(27, rc.cache[0].selfDiscardTile)
+			assert.Equal(0, rc.cache[1].selfDiscardTile)
+		}
+		total, _, _ := rc.countDisagreements()
+		assert.Equal(2, total)
+	}
+	printRecordAnalysis(record, 0, analysisCache)
+}
+
+func Test_tenhouRecord_toTenhou6(t *testing.T) {
+	assert := assert.New(t)
+
+	record, err := parseTenhouRecord([]byte(testTenhouRecord))
+	if !assert.NoError(err) {
+		return
+	}
+	g, err := record.toTenhou6()
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"A", "B", "C", "D"}, g.Names)
+	assert.Equal("鳳南喰赤", g.Rule.Disp)
+	if !assert.Len(g.Rounds, 1) {
+		return
+	}
+
+	r := g.Rounds[0]
+	assert.Equal([]int{25000, 25000, 25000, 25000}, r.Scores)
+	assert.Equal([]int{47}, r.DoraIndicators)
+	assert.Equal([]int{11, 12, 13, 14, 51, 16, 17, 18, 19, 21, 22, 23, 24}, r.Hands[0])
+	assert.Len(r.Actions, 10)
+	assert.Equal(&tenhou6.Action{Type: tenhou6.ActionTypeDiscard, Who: 0, Tile: 41, IsTsumogiri: true}, r.Actions[1])
+	assert.Equal(&tenhou6.Action{Type: tenhou6.ActionTypeDiscard, Who: 0, Tile: 11}, r.Actions[9])
+	assert.Equal(&tenhou6.Result{
+		Name: tenhou6.ResultNameAgari,
+		Agaris: []*tenhou6.Agari{{
+			Who:    1,
+			From:   0,
+			Pao:    1,
+			Deltas: []int{-1000, 1000, 0, 0},
+			Info:   []string{"30符1飜1000点", "断幺九(1飜)"},
+		}},
+	}, r.Result)
+
+	// tenhou.net/6 格式的牌谱分析结果与原牌谱相同
+	data, err := g.Marshal()
+	if !assert.NoError(err) {
+		return
+	}
+	parsedRecord, err := parseReviewRecord(data)
+	if !assert.NoError(err) {
+		return
+	}
+	for seat := 0; seat < 4; seat++ {
+		assert.Equal(record.analysis(seat).wholeGameCache, parsedRecord.analysis(seat).wholeGameCache)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"github.com/EndlessCheng/mahjong-helper/util"
 	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_parseTenhouMeld(t *testing.T) {
@@ -88,3 +89,63 @@ func TestReg(t *testing.T) {
 	d.msg.Tag = "E123123"
 	t.Log(d.IsDiscard() == false)
 }
+
+func Test_tenhouReinit(t *testing.T) {
+	assert := assert.New(t)
+
+	debugMode = true
+
+	d := &tenhouRoundData{isRoundEnd: true}
+	d.roundData = newGame(d)
+	d.msg = &tenhouMessage{
+		Tag:    "REINIT",
+		Seed:   "0,0,0,1,2,36",
+		Ten:    "250,250,250,250",
+		Dealer: "0",
+		Hai:    "0,4,8,12,16,21,37,40,44,72,76,80,108",
+		Meld2:  "43595",
+		Meld3:  "17511",
+		Kawa0:  "132",
+		Kawa1:  "120,124,255,128",
+	}
+	if err := d.analysis(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(4, d.playerNumber)
+	assert.Equal([]int{9}, d.doraIndicators)
+	assert.Equal(13, util.CountOfTiles34(d.counts))
+	assert.Equal([]int{1, 0, 0}, d.numRedFives)
+
+	assert.True(d.players[1].isReached)
+	assert.Equal(2, d.players[1].reachTileAt)
+	assert.Equal(3, d.players[1].reachTileAtGlobal)
+	assert.Equal([]int{30, 31, 32}, d.players[1].discardTiles)
+	assert.Equal([]int{33, 30, 31, 32}, d.globalDiscardTiles)
+
+	assert.True(d.players[2].isNaki)
+	assert.Len(d.players[2].melds, 1)
+	assert.Len(d.players[3].melds, 1)
+
+	assert.Equal(1, d.leftCounts[util.MustStrToTile34("2z")])
+	assert.Equal(3, d.leftCounts[util.MustStrToTile34("5z")])
+	assert.Equal(2, d.leftCounts[util.MustStrToTile34("1p")])
+	assert.Equal(3, d.leftCounts[util.MustStrToTile34("7m")])
+	assert.Equal(136-13-1-4-3-3, util.CountOfTiles34(d.leftCounts))
+}
+
+func Test_tenhouHandleLogin(t *testing.T) {
+	assert := assert.New(t)
+
+	d := &tenhouRoundData{isRoundEnd: true}
+	d.roundData = newGame(d)
+
+	d.msg = &tenhouMessage{Tag: "HELO", UserName: "%E5%92%8C"}
+	assert.NoError(d.analysis())
+	assert.Equal("和", d.username)
+
+	// 用户名解析失败时返回错误，由会话记录
+	d.msg = &tenhouMessage{Tag: "HELO", UserName: "%zz"}
+	assert.Error(d.analysis())
+	assert.Equal("和", d.username)
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou"
+	"github.com/EndlessCheng/mahjong-helper/util"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/fatih/color"
+)
+
+// 用天凤牌谱的牌山种子还原牌山，推演「如果当时切了另一张牌」之后的进展
+// 推演时假设他家的操作与实际对局相同，自家之后的舍牌由助手选择
+
+// 推演的起点：某局自家第 turn 次摸牌后改切 tile
+type whatIfSpec struct {
+	roundNumber int // 0=东1局，4=南1局，...
+	benNumber   int
+	turn        int
+	tile        int
+}
+
+// 格式为 局,巡目,牌，如 东2局1本场,6,3m
+func parseWhatIfSpec(s string) (*whatIfSpec, error) {
+	wrongSpecError := fmt.Errorf("推演参数格式错误：%s（示例：东2局1本场,6,3m）", s)
+
+	splits := strings.Split(s, ",")
+	if len(splits) != 3 {
+		return nil, wrongSpecError
+	}
+	roundNumber, benNumber, _, ok := parseRoundName(splits[0])
+	if !ok {
+		return nil, wrongSpecError
+	}
+	spec := &whatIfSpec{roundNumber: roundNumber, benNumber: benNumber}
+
+	turn, err := strconv.Atoi(strings.TrimSpace(splits[1]))
+	if err != nil || turn < 1 {
+		return nil, wrongSpecError
+	}
+	spec.turn = turn
+
+	tile, _, err := util.StrToTile34(splits[2])
+	if err != nil {
+		return nil, wrongSpecError
+	}
+	spec.tile = tile
+	return spec, nil
+}
+
+func (spec *whatIfSpec) roundName() string {
+	return roundName(spec.roundNumber, spec.benNumber)
+}
+
+// 逗号分隔的天凤牌编号
+func parseTenhouTileList(s string) []int {
+	tiles := []int{}
+	for _, split := range strings.Split(s, ",") {
+		if tile, err := strconv.Atoi(split); err == nil {
+			tiles = append(tiles, tile)
+		}
+	}
+	return tiles
+}
+
+func tenhouTilesToStr(tenhouTiles []int) string {
+	tiles34 := make([]int, len(tenhouTiles))
+	for i, tile := range tenhouTiles {
+		tiles34[i] = tile / 4
+	}
+	sort.Ints(tiles34)
+	return util.TilesToStr(tiles34)
+}
+
+// 用 SHUFFLE 的种子还原各局的牌山，并与牌谱中的配牌、骰子和宝牌指示牌核对
+func (r *tenhouRecord) walls() ([]*tenhou.Wall, error) {
+	if r.shuffleSeed == "" {
+		return nil, fmt.Errorf("牌谱中没有牌山种子（SHUFFLE）")
+	}
+	if r.playerNumber() != 4 {
+		return nil, fmt.Errorf("暂不支持还原三麻的牌山")
+	}
+	g, err := tenhou.NewWallGenerator(r.shuffleSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	walls := []*tenhou.Wall{}
+	for i, actions := range r.rounds {
+		wall := g.Next()
+		if err := checkTenhouWall(wall, actions[0]); err != nil {
+			return nil, fmt.Errorf("第 %d 局的牌山与牌谱不一致：%v", i+1, err)
+		}
+		walls = append(walls, wall)
+	}
+	return walls, nil
+}
+
+func checkTenhouWall(wall *tenhou.Wall, init *tenhou.RecordAction) error {
+	seeds := parseTenhouTileList(init.Seed)
+	if len(seeds) != 6 {
+		return fmt.Errorf("INIT 数据有误 %s", init.Seed)
+	}
+	if seeds[3] != wall.Dice[0] || seeds[4] != wall.Dice[1] {
+		return fmt.Errorf("骰子不一致")
+	}
+	if seeds[5] != wall.DoraIndicators()[0] {
+		return fmt.Errorf("宝牌指示牌不一致")
+	}
+
+	dealer, _ := strconv.Atoi(init.Dealer)
+	hands := wall.Haipai()
+	for seat, hai := range []string{init.Hai0, init.Hai1, init.Hai2, init.Hai3} {
+		hand := parseTenhouTileList(hai)
+		wallHand := append([]int(nil), hands[(seat-dealer+4)%4]...)
+		sort.Ints(hand)
+		sort.Ints(wallHand)
+		if fmt.Sprint(hand) != fmt.Sprint(wallHand) {
+			return fmt.Errorf("%d 号位的配牌不一致", seat)
+		}
+	}
+	return nil
+}
+
+//
+
+// 推演中的自家手牌
+type whatIfHand struct {
+	hand     []int // 天凤牌编号
+	melds    []model.Meld
+	discards []int // 0-33
+
+	turn int
+
+	roundWindTile  int
+	selfWindTile   int
+	isParent       bool
+	doraIndicators []int
+}
+
+func (h *whatIfHand) tiles34() []int {
+	counts := make([]int, 34)
+	for _, tile := range h.hand {
+		counts[tile/4]++
+	}
+	return counts
+}
+
+func (h *whatIfHand) removeTile34(tile34 int) bool {
+	index := -1
+	for i, tile := range h.hand {
+		// 优先切普通的 5
+		if tile/4 == tile34 && (index == -1 || (*tenhouRoundData)(nil).isRedFive(h.hand[index])) {
+			index = i
+		}
+	}
+	if index == -1 {
+		return false
+	}
+	h.hand = append(h.hand[:index], h.hand[index+1:]...)
+	return true
+}
+
+func (h *whatIfHand) removeTile(tenhouTile int) {
+	for i, tile := range h.hand {
+		if tile == tenhouTile {
+			h.hand = append(h.hand[:i], h.hand[i+1:]...)
+			return
+		}
+	}
+}
+
+// 和牌时的点数，无役时为 0
+func (h *whatIfHand) point(winTile int, isTsumo bool) int {
+	tiles34 := h.tiles34()
+	numRedFives := make([]int, 3)
+	for _, tile := range h.hand {
+		if (*tenhouRoundData)(nil).isRedFive(tile) {
+			numRedFives[tile/36]++
+		}
+	}
+	if !isTsumo {
+		tiles34[winTile/4]++
+		if (*tenhouRoundData)(nil).isRedFive(winTile) {
+			numRedFives[winTile/36]++
+		}
+	}
+	if !util.IsAgari(tiles34) {
+		return 0
+	}
+	doraIndicators34 := []int{}
+	for _, tile := range h.doraIndicators {
+		doraIndicators34 = append(doraIndicators34, tile/4)
+	}
+	playerInfo := model.NewSimplePlayerInfo(tiles34, h.melds)
+	playerInfo.NumRedFives = numRedFives
+	playerInfo.DoraTiles = model.DoraList(doraIndicators34, false)
+	playerInfo.IsTsumo = isTsumo
+	playerInfo.WinTile = winTile / 4
+	playerInfo.RoundWindTile = h.roundWindTile
+	playerInfo.SelfWindTile = h.selfWindTile
+	playerInfo.IsParent = h.isParent
+	return util.CalcPoint(playerInfo).Point
+}
+
+func (h *whatIfHand) isFuriten() bool {
+	tiles34 := h.tiles34()
+	for _, tile := range h.discards {
+		tiles34[tile]++
+		isAgari := util.IsAgari(tiles34)
+		tiles34[tile]--
+		if isAgari {
+			return true
+		}
+	}
+	return false
+}
+
+// 摸牌后自摸或者由助手选择舍牌，返回是否和牌
+func (h *whatIfHand) draw(tile int, note string) (isAgari bool) {
+	h.turn++
+	h.hand = append(h.hand, tile)
+	fmt.Printf("第%2d巡 摸 %s", h.turn, util.Mahjong[tile/4])
+	if util.IsAgari(h.tiles34()) {
+		if point := h.point(tile, true); point > 0 {
+			color.HiYellow(" 自摸和了 %d点%s", point, note)
+			return true
+		}
+		fmt.Print(" 和牌型但无役")
+	}
+
+	playerInfo := model.NewSimplePlayerInfo(h.tiles34(), h.melds)
+	playerInfo.DiscardTiles = h.discards
+	discardTile := simpleBestDiscardTile(playerInfo)
+	if discardTile == -1 {
+		// 不应该出现，摸切
+		discardTile = tile / 4
+	}
+	h.removeTile34(discardTile)
+	h.discards = append(h.discards, discardTile)
+	fmt.Printf(" 切 %s %s%s\n", util.Mahjong[discardTile], util.NumberToChineseShanten(util.CalculateShanten(h.tiles34())), note)
+	return false
+}
+
+// 他家舍牌时能否荣和（不检查同巡振听）
+func (h *whatIfHand) canRon(tile int) bool {
+	return !h.isFuriten() && h.point(tile, false) > 0
+}
+
+// 推演 selfSeat 在 spec 指定的巡目改切另一张牌之后的进展
+func (r *tenhouRecord) whatIf(selfSeat int, spec *whatIfSpec) error {
+	walls, err := r.walls()
+	if err != nil {
+		return err
+	}
+
+	roundIndex := -1
+	for i, actions := range r.rounds {
+		seeds := parseTenhouTileList(actions[0].Seed)
+		if seeds[0] == spec.roundNumber && seeds[1] == spec.benNumber {
+			roundIndex = i
+			break
+		}
+	}
+	if roundIndex == -1 {
+		return fmt.Errorf("牌谱中没有%s", spec.roundName())
+	}
+	wall := walls[roundIndex]
+	actions := r.rounds[roundIndex]
+
+	init := actions[0]
+	dealer, _ := strconv.Atoi(init.Dealer)
+	h := &whatIfHand{
+		hand:           parseTenhouTileList([]string{init.Hai0, init.Hai1, init.Hai2, init.Hai3}[selfSeat]),
+		roundWindTile:  27 + spec.roundNumber/4,
+		selfWindTile:   27 + (selfSeat-dealer+4)%4,
+		isParent:       selfSeat == dealer,
+		doraIndicators: wall.DoraIndicators()[:1],
+	}
+
+	color.HiGreen("%s %s 的推演", spec.roundName(), r.playerName(selfSeat))
+	fmt.Printf("宝牌指示牌 %s，里宝牌指示牌 %s\n", util.Mahjong[wall.DoraIndicators()[0]/4], util.Mahjong[wall.UraDoraIndicators()[0]/4])
+	fmt.Println("假设他家的操作与实际对局相同，之后的舍牌由助手选择")
+
+	isBranched := false
+	isSelfJustDrew := false
+	lastCallSeat := -1
+	numLiveDraws, numKans := 0, 0
+	nextDrawer := dealer
+	var meldParser *tenhouRoundData
+
+loop:
+	for _, a := range actions[1:] {
+		switch tag := a.Tag; {
+		case _recordDrawReg.MatchString(tag):
+			seat := int(tag[0] - 'T')
+			tile, _ := strconv.Atoi(tag[1:])
+			// 鸣牌后紧接着的摸牌是岭上牌
+			if seat == lastCallSeat {
+				numKans++
+			} else {
+				numLiveDraws++
+			}
+			lastCallSeat = -1
+			nextDrawer = (seat + 1) % 4
+			if seat != selfSeat {
+				continue
+			}
+			if isBranched {
+				if h.draw(tile, "") {
+					return nil
+				}
+				continue
+			}
+			h.turn++
+			h.hand = append(h.hand, tile)
+			isSelfJustDrew = true
+		case _recordDiscardReg.MatchString(tag):
+			seat := int(tag[0] - 'D')
+			tile, _ := strconv.Atoi(tag[1:])
+			lastCallSeat = -1
+			nextDrawer = (seat + 1) % 4
+			if seat != selfSeat {
+				if isBranched && h.canRon(tile) {
+					color.HiYellow("可以荣和 %s 打出的 %s，%d点", r.playerName(seat), util.Mahjong[tile/4], h.point(tile, false))
+					return nil
+				}
+				continue
+			}
+			if isBranched {
+				continue
+			}
+			if h.turn == spec.turn && isSelfJustDrew {
+				if tile/4 == spec.tile {
+					return fmt.Errorf("第%d巡实际切的就是 %s", spec.turn, util.Mahjong[spec.tile])
+				}
+				fmt.Printf("第%2d巡 手牌 %s，实际切 %s，改切 %s\n", h.turn, tenhouTilesToStr(h.hand), util.Mahjong[tile/4], util.Mahjong[spec.tile])
+				if !h.removeTile34(spec.tile) {
+					return fmt.Errorf("第%d巡的手牌中没有 %s", spec.turn, util.Mahjong[spec.tile])
+				}
+				h.discards = append(h.discards, spec.tile)
+				isBranched = true
+				continue
+			}
+			h.removeTile(tile)
+			h.discards = append(h.discards, tile/4)
+			isSelfJustDrew = false
+		case tag == "N":
+			seat, _ := strconv.Atoi(a.Who)
+			lastCallSeat = seat
+			if seat != selfSeat {
+				continue
+			}
+			if isBranched {
+				fmt.Println("实际对局中自家在之后鸣牌，推演到此为止")
+				return nil
+			}
+			meldType, tenhouMeldTiles, _ := meldParser._parseTenhouMeld(a.Meld)
+			for _, tile := range tenhouMeldTiles {
+				h.removeTile(tile)
+			}
+			meld := meldParser._parseMeld(a.Meld)
+			if meldType == meldTypeKakan {
+				for i, m := range h.melds {
+					if m.MeldType == meldTypePon && m.Tiles[0] == meld.Tiles[0] {
+						h.melds = append(h.melds[:i], h.melds[i+1:]...)
+						break
+					}
+				}
+			}
+			h.melds = append(h.melds, *meld)
+		case tag == "DORA":
+			// 不影响摸牌顺序
+		case tag == "AGARI" || tag == "RYUUKYOKU":
+			break loop
+		}
+	}
+	if !isBranched {
+		return fmt.Errorf("没有找到%s第%d巡自家的舍牌", spec.roundName(), spec.turn)
+	}
+
+	// 实际对局已结束，继续从还原的牌山摸牌（假设之后无人鸣牌）
+	liveTiles := wall.LiveTiles()
+	liveTiles = liveTiles[numLiveDraws : len(liveTiles)-numKans]
+	fmt.Printf("实际对局在此结束，之后的牌山：%s\n", tenhouTilesToStr(liveTiles))
+	for i, tile := range liveTiles {
+		if (nextDrawer+i)%4 != selfSeat {
+			continue
+		}
+		if h.draw(tile, "（牌山）") {
+			return nil
+		}
+	}
+	fmt.Printf("荒牌流局，%s\n", util.NumberToChineseShanten(util.CalculateShanten(h.tiles34())))
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"github.com/EndlessCheng/mahjong-helper/platform/tenhou"
+	"github.com/stretchr/testify/assert"
+)
+
+// 用天凤的牌山算法生成一局：各家依次摸切 numTurns 巡后流局
+func newTestWallTenhouRecord(numTurns int) string {
+	seedBytes := make([]byte, 4*624)
+	for i := range seedBytes {
+		seedBytes[i] = byte(i * 7)
+	}
+	seed := tenhou.ShuffleSeedPrefix + base64.StdEncoding.EncodeToString(seedBytes)
+	g, _ := tenhou.NewWallGenerator(seed)
+	wall := g.Next()
+
+	joinTiles := func(tiles []int) string {
+		return strings.Trim(strings.Join(strings.Fields(fmt.Sprint(tiles)), ","), "[]")
+	}
+	hands := wall.Haipai()
+	record := fmt.Sprintf(`<mjloggm ver="2.3"><SHUFFLE seed="%s" ref=""/><GO type="169" lobby="0"/><UN n0="%%41" n1="%%42" n2="%%43" n3="%%44"/><TAIKYOKU oya="0"/>`, seed)
+	record += fmt.Sprintf(`<INIT seed="0,0,0,%d,%d,%d" ten="250,250,250,250" oya="0" hai0="%s" hai1="%s" hai2="%s" hai3="%s"/>`,
+		wall.Dice[0], wall.Dice[1], wall.DoraIndicators()[0], joinTiles(hands[0]), joinTiles(hands[1]), joinTiles(hands[2]), joinTiles(hands[3]))
+	for i, tile := range wall.LiveTiles()[:4*numTurns] {
+		record += fmt.Sprintf(`<%c%d/><%c%d/>`, 'T'+i%4, tile, 'D'+i%4, tile)
+	}
+	return record + `<RYUUKYOKU ba="0,0" sc="250,0,250,0,250,0,250,0"/></mjloggm>`
+}
+
+func Test_parseWhatIfSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	spec, err := parseWhatIfSpec("东2局1本场,6,3m")
+	assert.NoError(err)
+	assert.Equal(&whatIfSpec{roundNumber: 1, benNumber: 1, turn: 6, tile: 2}, spec)
+	assert.Equal("东2局1本场", spec.roundName())
+
+	spec, err = parseWhatIfSpec("南4,10,7z")
+	assert.NoError(err)
+	assert.Equal(&whatIfSpec{roundNumber: 7, turn: 10, tile: 33}, spec)
+
+	for _, s := range []string{"", "东5局,1,1m", "东1局,0,1m", "东1局,1,8z", "东1局,1"} {
+		_, err = parseWhatIfSpec(s)
+		assert.Error(err, s)
+	}
+}
+
+func Test_tenhouRecord_whatIf(t *testing.T) {
+	assert := assert.New(t)
+
+	record, err := parseTenhouRecord([]byte(newTestWallTenhouRecord(10)))
+	if !assert.NoError(err) {
+		return
+	}
+	walls, err := record.walls()
+	assert.NoError(err)
+	assert.Len(walls, 1)
+
+	// 第 2 巡改切配牌中的一张牌
+	hand := parseTenhouTileList(record.rounds[0][0].Hai0)
+	tile := hand[0] / 4
+	drawTile := walls[0].LiveTiles()[4]
+	if tile == drawTile/4 {
+		tile = hand[1] / 4
+	}
+	spec := &whatIfSpec{turn: 2, tile: tile}
+	assert.NoError(record.whatIf(0, spec))
+
+	// 实际切的牌
+	spec.tile = drawTile / 4
+	assert.Error(record.whatIf(0, spec))
+
+	// 牌谱中没有的局
+	assert.Error(record.whatIf(0, &whatIfSpec{roundNumber: 1, turn: 2, tile: tile}))
+
+	// 配牌被改动过
+	record.rounds[0][0].Hai0 = strings.Replace(record.rounds[0][0].Hai0, fmt.Sprint(hand[0]), fmt.Sprint(walls[0].LiveTiles()[69]), 1)
+	_, err = record.walls()
+	assert.Error(err)
+}
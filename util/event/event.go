@@ -0,0 +1,201 @@
+// Package event 定义了与平台无关的牌局事件
+// 天凤、雀魂等平台的数据均可转换成事件流，事件流可以序列化为 JSON Lines 格式的日志，用于存档、回放、比较和离线分析
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+)
+
+// 注意：以下 who 均为相对自家的位置 0=自家, 1=下家, 2=对家, 3=上家
+// 牌均为 0-33
+
+type Type string
+
+const (
+	TypeGameStart  Type = "gs" // 游戏开始（就坐）
+	TypeRoundStart Type = "rs" // 一局开始/重连
+	TypeDraw       Type = "dr" // 自家摸牌
+	TypeDiscard    Type = "di" // 舍牌
+	TypeCall       Type = "ca" // 鸣牌（含暗杠、加杠）
+	TypeRiichi     Type = "ri" // 立直宣言
+	TypeNewDora    Type = "do" // 杠宝牌
+	TypeNuki       Type = "nu" // 拔北
+	TypeFuriten    Type = "fu" // 振听
+	TypeWin        Type = "wi" // 和牌
+	TypeDrawGame   Type = "dg" // 流局
+)
+
+// 游戏开始，分配座位
+type GameStart struct {
+	// 第一局的庄家
+	Dealer int `json:"dealer"`
+}
+
+// 一局开始/重连
+type RoundStart struct {
+	// 场数（如东1为0，东2为1，...，南1为4，...），对于三麻来说南1也是4
+	RoundNumber int `json:"round"`
+	// 本场数
+	BenNumber int `json:"ben"`
+	// 庄家
+	Dealer int `json:"dealer"`
+	// 人数，未知时为 0
+	PlayerNumber int `json:"players,omitempty"`
+
+	DoraIndicators []int `json:"doras"`
+	HandTiles      []int `json:"hand"`
+	// 按照 mps 的顺序，赤5个数
+	NumRedFives []int `json:"reds"`
+
+	// 重连时的牌局快照
+	Snapshot *Snapshot `json:"snapshot,omitempty"`
+}
+
+// 重连时各家的牌局信息
+type PlayerSnapshot struct {
+	// 副露（含暗杠、加杠），不含拔北
+	Melds []*model.Meld `json:"melds,omitempty"`
+
+	// 牌河中的舍牌（不含被鸣走的牌），负数表示摸切
+	DiscardTiles []int `json:"discards,omitempty"`
+
+	// 立直宣言牌在 DiscardTiles 中的下标，未立直时为 -1
+	ReachTileAt int `json:"reach"`
+
+	// 拔北宝牌数
+	NukiDoraNum int `json:"nuki,omitempty"`
+}
+
+// 重连时的牌局快照
+// 自家手牌、宝牌指示牌见 RoundStart，这里只包含其余信息
+type Snapshot struct {
+	Players [4]PlayerSnapshot `json:"players"`
+}
+
+// 自家摸牌
+type Draw struct {
+	Tile      int  `json:"tile"`
+	IsRedFive bool `json:"red,omitempty"`
+}
+
+// 舍牌
+type Discard struct {
+	Who       int  `json:"who"`
+	Tile      int  `json:"tile"`
+	IsRedFive bool `json:"red,omitempty"`
+	// 是否为摸切（who=0 时无意义）
+	IsTsumogiri bool `json:"tsumogiri,omitempty"`
+	// 是否为立直宣言牌
+	IsReach bool `json:"reach,omitempty"`
+	// 是否可以鸣牌（who=0 时无意义）
+	CanBeMeld bool `json:"meld,omitempty"`
+}
+
+// 鸣牌（含暗杠、加杠）
+type Call struct {
+	Who  int         `json:"who"`
+	Meld *model.Meld `json:"meld"`
+}
+
+// 立直宣言
+type Riichi struct {
+	Who int `json:"who"`
+}
+
+// 杠宝牌
+type NewDora struct {
+	Indicator int `json:"indicator"`
+}
+
+// 拔北
+type Nuki struct {
+	Who         int  `json:"who"`
+	IsTsumogiri bool `json:"tsumogiri,omitempty"`
+}
+
+// 和牌
+type Win struct {
+	Whos   []int `json:"whos"`
+	Points []int `json:"points"`
+}
+
+// 流局
+type DrawGame struct {
+	Type   int   `json:"type"`
+	Whos   []int `json:"whos,omitempty"`
+	Points []int `json:"points,omitempty"`
+}
+
+// 牌局事件，根据 Type 使用对应的字段
+type Event struct {
+	Type Type `json:"t"`
+
+	GameStart  *GameStart  `json:"gs,omitempty"`
+	RoundStart *RoundStart `json:"rs,omitempty"`
+	Draw       *Draw       `json:"dr,omitempty"`
+	Discard    *Discard    `json:"di,omitempty"`
+	Call       *Call       `json:"ca,omitempty"`
+	Riichi     *Riichi     `json:"ri,omitempty"`
+	NewDora    *NewDora    `json:"do,omitempty"`
+	Nuki       *Nuki       `json:"nu,omitempty"`
+	Win        *Win        `json:"wi,omitempty"`
+	DrawGame   *DrawGame   `json:"dg,omitempty"`
+}
+
+func NewGameStart(e *GameStart) *Event   { return &Event{Type: TypeGameStart, GameStart: e} }
+func NewRoundStart(e *RoundStart) *Event { return &Event{Type: TypeRoundStart, RoundStart: e} }
+func NewDraw(e *Draw) *Event             { return &Event{Type: TypeDraw, Draw: e} }
+func NewDiscard(e *Discard) *Event       { return &Event{Type: TypeDiscard, Discard: e} }
+func NewCall(e *Call) *Event             { return &Event{Type: TypeCall, Call: e} }
+func NewRiichi(e *Riichi) *Event         { return &Event{Type: TypeRiichi, Riichi: e} }
+func NewNewDora(e *NewDora) *Event       { return &Event{Type: TypeNewDora, NewDora: e} }
+func NewNuki(e *Nuki) *Event             { return &Event{Type: TypeNuki, Nuki: e} }
+func NewFuriten() *Event                 { return &Event{Type: TypeFuriten} }
+func NewWin(e *Win) *Event               { return &Event{Type: TypeWin, Win: e} }
+func NewDrawGame(e *DrawGame) *Event     { return &Event{Type: TypeDrawGame, DrawGame: e} }
+
+// 检查 Type 与对应字段是否一致
+func (e *Event) Validate() error {
+	var ok bool
+	switch e.Type {
+	case TypeGameStart:
+		ok = e.GameStart != nil
+	case TypeRoundStart:
+		ok = e.RoundStart != nil
+	case TypeDraw:
+		ok = e.Draw != nil
+	case TypeDiscard:
+		ok = e.Discard != nil
+	case TypeCall:
+		ok = e.Call != nil && e.Call.Meld != nil
+	case TypeRiichi:
+		ok = e.Riichi != nil
+	case TypeNewDora:
+		ok = e.NewDora != nil
+	case TypeNuki:
+		ok = e.Nuki != nil
+	case TypeFuriten:
+		ok = true
+	case TypeWin:
+		ok = e.Win != nil
+	case TypeDrawGame:
+		ok = e.DrawGame != nil
+	default:
+		return fmt.Errorf("未知的事件类型 %q", e.Type)
+	}
+	if !ok {
+		return fmt.Errorf("事件 %q 缺少数据", e.Type)
+	}
+	return nil
+}
+
+// 以 JSON 的形式输出事件，便于定位问题
+func (e *Event) String() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Sprintf("%s %v", e.Type, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// 将事件以 JSON Lines 格式写入，每行一个事件
+// 可以被多个协程同时使用
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) Write(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.w.Write(data)
+	return err
+}
+
+// 读取 JSON Lines 格式的事件日志，忽略空行
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	// 牌局快照可能较长
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &Reader{scanner: scanner}
+}
+
+// 读取下一个事件，读完时返回 io.EOF
+func (r *Reader) Read() (*Event, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		e := &Event{}
+		if err := json.Unmarshal(line, e); err != nil {
+			return nil, fmt.Errorf("第 %d 行解析失败：%v", r.line, err)
+		}
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("第 %d 行：%v", r.line, err)
+		}
+		return e, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// 读取全部事件
+func ReadAll(r io.Reader) (events []*Event, err error) {
+	reader := NewReader(r)
+	for {
+		e, err := reader.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+}
@@ -0,0 +1,61 @@
+package event
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterReader(t *testing.T) {
+	assert := assert.New(t)
+
+	events := []*Event{
+		NewRoundStart(&RoundStart{RoundNumber: 4, BenNumber: 1, Dealer: 2, PlayerNumber: 4, DoraIndicators: []int{9}, HandTiles: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, NumRedFives: []int{1, 0, 0}}),
+		NewDraw(&Draw{Tile: 0}),
+		NewDiscard(&Discard{Who: 0, Tile: 4, IsRedFive: true}),
+		NewRiichi(&Riichi{Who: 1}),
+		NewDiscard(&Discard{Who: 1, Tile: 27, IsTsumogiri: true, CanBeMeld: true}),
+		NewCall(&Call{Who: 2, Meld: &model.Meld{MeldType: model.MeldTypePon, Tiles: []int{27, 27, 27}, CalledTile: 27}}),
+		NewNewDora(&NewDora{Indicator: 0}),
+		NewNuki(&Nuki{Who: 3}),
+		NewFuriten(),
+		NewWin(&Win{Whos: []int{2}, Points: []int{8000}}),
+		NewDrawGame(&DrawGame{}),
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	for _, e := range events {
+		assert.NoError(e.Validate())
+		assert.NoError(w.Write(e))
+	}
+	assert.Equal(len(events), strings.Count(buf.String(), "\n"))
+	t.Log(buf.String())
+
+	readEvents, err := ReadAll(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(events, readEvents)
+
+	// 忽略空行
+	r := NewReader(strings.NewReader("\n" + `{"t":"fu"}` + "\n\n"))
+	e, err := r.Read()
+	assert.NoError(err)
+	assert.Equal(TypeFuriten, e.Type)
+	_, err = r.Read()
+	assert.Equal(io.EOF, err)
+}
+
+func TestReaderError(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ReadAll(strings.NewReader(`{"t":"xx"}`))
+	assert.Error(err)
+	_, err = ReadAll(strings.NewReader(`{"t":"di"}`))
+	assert.Error(err)
+	_, err = ReadAll(strings.NewReader(`{"t":"dr","dr":{"tile":1}}` + "\n" + `not json`))
+	assert.Error(err)
+}
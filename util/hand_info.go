@@ -11,6 +11,11 @@ type _handInfo struct {
 	allKotsuTiles        []int
 }
 
+// 是否考虑古役
+func (hi *_handInfo) isOldYakuEnabled() bool {
+	return considerOldYaku || hi.ConsiderOldYaku
+}
+
 // 未排序。用于算一通、三色
 func (hi *_handInfo) getAllShuntsuFirstTiles() []int {
 	shuntsuFirstTiles := append([]int{}, hi.divideResult.ShuntsuFirstTiles...)
@@ -0,0 +1,381 @@
+package util
+
+import (
+	"fmt"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"math"
+)
+
+// 杠的分析结果
+type KanAnalysisResult struct {
+	// 杠的类型（暗杠、大明杠、加杠）
+	MeldType int
+
+	// 杠的牌
+	KanTile int
+
+	// 不杠时的手牌分析结果
+	// 暗杠、加杠时为不杠而切出最优舍牌后的结果；大明杠时为当前手牌的结果
+	Result13 *Hand13AnalysisResult
+
+	// 杠后（摸岭上牌前）的手牌分析结果
+	KanResult13 *Hand13AnalysisResult
+
+	// 杠后听牌是否发生变化（仅在杠前听牌时有意义）
+	IsWaitsChanged bool
+
+	// 规则上是否允许杠
+	Allowed bool
+
+	// 不允许杠的原因
+	NotAllowedReason string
+
+	// 杠后听牌时，岭上牌为和牌的概率（百分比）
+	RinshanAgariRate float64
+
+	// 新翻开的杠宝牌指示牌给自家带来的宝牌增加数的期望值
+	KanDoraExpectation float64
+
+	// 新翻开的杠宝牌指示牌给一名他家带来的宝牌增加数的期望值（按手牌 13 张估算）
+	OtherKanDoraExpectation float64
+
+	// 立直的他家人数，杠宝牌会提高他们的打点
+	NumReachedOpponents int
+
+	// 不杠时的局收支
+	Point float64
+
+	// 杠后的局收支（考虑了岭上开花、杠宝牌，以及杠宝牌给立直者带来的打点）
+	KanPoint float64
+}
+
+// 杠后局收支的变化
+func (r *KanAnalysisResult) PointDelta() float64 {
+	return r.KanPoint - r.Point
+}
+
+// 杠后向听数的变化，正数表示向听倒退
+func (r *KanAnalysisResult) ShantenDelta() int {
+	return r.KanResult13.Shanten - r.Result13.Shanten
+}
+
+// 综合判断是否推荐杠
+func (r *KanAnalysisResult) IsRecommended() bool {
+	if !r.Allowed {
+		return false
+	}
+	if r.ShantenDelta() != 0 {
+		return r.ShantenDelta() < 0
+	}
+	if r.Result13.Shanten == 0 && r.IsWaitsChanged && r.KanResult13.Waits.AllCount() < r.Result13.Waits.AllCount() {
+		return false
+	}
+	// 杠后进张数基本不变时看局收支
+	if r.Result13.Shanten > 0 && r.KanResult13.Waits.AllCount() < r.Result13.Waits.AllCount()*3/4 {
+		return false
+	}
+	// 有人立直时，杠后未听牌只会增加放铳的损失
+	if r.NumReachedOpponents > 0 && r.KanResult13.Shanten > 0 {
+		return false
+	}
+	return r.PointDelta() >= 0
+}
+
+// 调试用
+func (r *KanAnalysisResult) String() string {
+	kanName := [...]string{"吃", "碰", "暗杠", "大明杠", "加杠"}[r.MeldType]
+	s := fmt.Sprintf("%s %s: %s => %s", kanName, MahjongZH[r.KanTile], NumberToChineseShanten(r.Result13.Shanten), NumberToChineseShanten(r.KanResult13.Shanten))
+	s += fmt.Sprintf(" [局收支%d => %d]", int(math.Round(r.Point)), int(math.Round(r.KanPoint)))
+	if !r.Allowed {
+		s += "[" + r.NotAllowedReason + "]"
+	}
+	return s
+}
+
+const (
+	// 岭上开花时打点的粗略倍率（自摸 + 岭上开花一番）
+	rinshanPointMulti = 1.6
+
+	// 估算他家宝牌期望时的手牌数
+	otherHandTilesCount = 13
+
+	// 立直者的宝牌每增加一枚，自家局收支的损失
+	// 立直者的打点约增加一番（约 3000~4000 点），按其和了且由自家放铳或被自摸的比例估算
+	reachedOpponentKanDoraLoss = 2000
+)
+
+// 新杠宝牌指示牌从剩余牌中随机翻出，手牌和副露中宝牌个数增加 k 枚的概率为 probs[k]，此时新的宝牌为 doraTiles[k]
+func calcKanDoraDistribution(playerInfo *model.PlayerInfo, isSannin bool) (probs [5]float64, doraTiles [5]int) {
+	leftTiles34 := playerInfo.LeftTiles34
+	total := CountOfTiles34(leftTiles34)
+	if total == 0 {
+		return
+	}
+	tiles34 := make([]int, 34)
+	copy(tiles34, playerInfo.HandTiles34)
+	for _, meld := range playerInfo.Melds {
+		for _, tile := range meld.Tiles {
+			tiles34[tile]++
+		}
+	}
+	for indicator, left := range leftTiles34 {
+		if left == 0 {
+			continue
+		}
+		dora := model.DoraTile(indicator, isSannin)
+		k := tiles34[dora]
+		probs[k] += float64(left) / float64(total)
+		doraTiles[k] = dora
+	}
+	return
+}
+
+// 新杠宝牌指示牌从剩余牌中随机翻出，计算手牌和副露中宝牌个数增加的期望值
+func calcKanDoraExpectation(playerInfo *model.PlayerInfo, isSannin bool) (expectation float64) {
+	probs, _ := calcKanDoraDistribution(playerInfo, isSannin)
+	for k, p := range probs {
+		expectation += float64(k) * p
+	}
+	return
+}
+
+// 新杠宝牌指示牌给他家带来的宝牌期望，视他家手牌为从剩余牌中随机抽取的 13 张
+func calcOtherKanDoraExpectation(leftTiles34 []int, isSannin bool) float64 {
+	total := CountOfTiles34(leftTiles34)
+	if total <= 1 {
+		return 0
+	}
+	expectation := 0.0
+	for indicator, left := range leftTiles34 {
+		if left == 0 {
+			continue
+		}
+		dora := model.DoraTile(indicator, isSannin)
+		doraLeft := leftTiles34[dora]
+		if dora == indicator {
+			doraLeft--
+		}
+		expectation += float64(left) / float64(total) * float64(doraLeft) / float64(total-1)
+	}
+	return expectation * otherHandTilesCount
+}
+
+// 杠后（翻开杠宝牌后）手牌分析结果为 kanResult13 时的局收支
+// 杠后听牌时：先摸岭上牌，摸到和牌则岭上开花，否则按杠后的和率计算
+// 杠后未听牌时：即为杠后的局收支
+func (r *KanAnalysisResult) kanRoundPoint(kanResult13 *Hand13AnalysisResult) float64 {
+	if kanResult13.Shanten > 0 {
+		return kanResult13.MixedRoundPoint
+	}
+
+	point := kanResult13.DamaPoint
+	if kanResult13.RiichiPoint > 0 {
+		point = kanResult13.RiichiPoint
+	}
+	rinshanRate := r.RinshanAgariRate / 100
+	agariRate := rinshanRate + (1-rinshanRate)*kanResult13.AvgAgariRate/100
+	avgPoint := 0.0
+	if agariRate > 0 {
+		avgPoint = (rinshanRate*point*rinshanPointMulti + (1-rinshanRate)*kanResult13.AvgAgariRate/100*point) / agariRate
+	}
+	return agariRate*(avgPoint+1500) - 1500
+}
+
+// 杠后的局收支
+// 按新杠宝牌使自家宝牌增加的枚数分别计算打点（即增加的番数带来的打点），再按概率加权
+// 有人立直时，减去杠宝牌提高立直者打点而带来的损失
+func (r *KanAnalysisResult) calcKanPoint(kanPlayerInfo *model.PlayerInfo, isSannin bool) {
+	r.Point = r.Result13.MixedRoundPoint
+
+	probs, doraTiles := calcKanDoraDistribution(kanPlayerInfo, isSannin)
+	r.KanPoint = r.kanRoundPoint(r.KanResult13)
+	if totalProb := probs[0] + probs[1] + probs[2] + probs[3] + probs[4]; totalProb > 0 {
+		r.KanPoint *= probs[0] / totalProb
+		for k := 1; k < len(probs); k++ {
+			if probs[k] == 0 {
+				continue
+			}
+			doraPlayerInfo := copyPlayerInfo(kanPlayerInfo)
+			doraPlayerInfo.DoraTiles = append(append([]int{}, kanPlayerInfo.DoraTiles...), doraTiles[k])
+			r.KanPoint += probs[k] / totalProb * r.kanRoundPoint(CalculateShantenWithImproves13(doraPlayerInfo))
+		}
+	}
+
+	r.KanPoint -= float64(r.NumReachedOpponents) * r.OtherKanDoraExpectation * reachedOpponentKanDoraLoss
+}
+
+func (r *KanAnalysisResult) fill(playerInfo *model.PlayerInfo, kanPlayerInfo *model.PlayerInfo, isSannin bool) {
+	r.KanResult13 = CalculateShantenWithImproves13(kanPlayerInfo)
+	if r.Result13.Shanten == 0 {
+		r.IsWaitsChanged = !r.Result13.Waits.Equals(r.KanResult13.Waits)
+	}
+	if r.KanResult13.Shanten == 0 {
+		if leftCount := CountOfTiles34(playerInfo.LeftTiles34); leftCount > 0 {
+			r.RinshanAgariRate = float64(r.KanResult13.Waits.AllCount()) / float64(leftCount) * 100
+		}
+	}
+	r.KanDoraExpectation = calcKanDoraExpectation(kanPlayerInfo, isSannin)
+	r.OtherKanDoraExpectation = calcOtherKanDoraExpectation(playerInfo.LeftTiles34, isSannin)
+	r.calcKanPoint(kanPlayerInfo, isSannin)
+}
+
+func countKan(melds []model.Meld) (cnt int) {
+	for _, meld := range melds {
+		if meld.IsKan() {
+			cnt++
+		}
+	}
+	return
+}
+
+func copyPlayerInfo(playerInfo *model.PlayerInfo) *model.PlayerInfo {
+	pi := *playerInfo
+	pi.HandTiles34 = append([]int{}, playerInfo.HandTiles34...)
+	pi.Melds = append([]model.Meld{}, playerInfo.Melds...)
+	pi.NumRedFives = append([]int{}, playerInfo.NumRedFives...)
+	pi.LeftTiles34 = append([]int{}, playerInfo.LeftTiles34...)
+	return &pi
+}
+
+// 摸牌后（3k+2 张手牌），计算所有能暗杠、加杠的牌的分析结果
+// drawTile: 刚摸到的牌，未知时传入 -1（立直后只能杠刚摸到的牌）
+// isSannin: 是否为三麻
+// numReachedOpponents: 立直的他家人数
+func CalculateSelfKan(playerInfo *model.PlayerInfo, drawTile int, isSannin bool, numReachedOpponents int) (results []*KanAnalysisResult) {
+	if len(playerInfo.LeftTiles34) == 0 {
+		playerInfo.FillLeftTiles34()
+	}
+
+	tiles34 := playerInfo.HandTiles34
+	if CountOfTiles34(tiles34)%3 != 2 {
+		return
+	}
+
+	kanTiles := []int{}
+	for tile, c := range tiles34 {
+		if c == 4 {
+			kanTiles = append(kanTiles, tile)
+		}
+	}
+	for _, meld := range playerInfo.Melds {
+		if meld.MeldType == model.MeldTypePon && tiles34[meld.Tiles[0]] > 0 {
+			kanTiles = append(kanTiles, meld.Tiles[0])
+		}
+	}
+	if len(kanTiles) == 0 {
+		return
+	}
+
+	// 不杠的情况：切出最优舍牌
+	var result13 *Hand13AnalysisResult
+	if playerInfo.IsRiichi && drawTile != -1 {
+		// 立直后只能摸切
+		tiles34[drawTile]--
+		result13 = CalculateShantenWithImproves13(playerInfo)
+		tiles34[drawTile]++
+	} else {
+		_, results14, incShantenResults14 := CalculateShantenWithImproves14(playerInfo)
+		if len(results14) > 0 {
+			result13 = results14[0].Result13
+		} else if len(incShantenResults14) > 0 {
+			result13 = incShantenResults14[0].Result13
+		} else {
+			return
+		}
+	}
+
+	numKan := countKan(playerInfo.Melds)
+	for _, kanTile := range kanTiles {
+		kanPlayerInfo := copyPlayerInfo(playerInfo)
+		r := &KanAnalysisResult{
+			KanTile:             kanTile,
+			Result13:            result13,
+			Allowed:             true,
+			NumReachedOpponents: numReachedOpponents,
+		}
+		if tiles34[kanTile] == 4 {
+			// 暗杠
+			r.MeldType = model.MeldTypeAnkan
+			kanPlayerInfo.HandTiles34[kanTile] = 0
+			kanPlayerInfo.Melds = append(kanPlayerInfo.Melds, model.Meld{
+				MeldType:       model.MeldTypeAnkan,
+				Tiles:          []int{kanTile, kanTile, kanTile, kanTile},
+				SelfTiles:      []int{kanTile, kanTile, kanTile, kanTile},
+				CalledTile:     kanTile,
+				ContainRedFive: kanTile < 27 && kanTile%9 == 4 && playerInfo.NumRedFives[kanTile/9] > 0,
+			})
+		} else {
+			// 加杠
+			r.MeldType = model.MeldTypeKakan
+			kanPlayerInfo.HandTiles34[kanTile]--
+			for i, meld := range kanPlayerInfo.Melds {
+				if meld.MeldType == model.MeldTypePon && meld.Tiles[0] == kanTile {
+					meld.MeldType = model.MeldTypeKakan
+					meld.Tiles = []int{kanTile, kanTile, kanTile, kanTile}
+					kanPlayerInfo.Melds[i] = meld
+					break
+				}
+			}
+		}
+		r.fill(playerInfo, kanPlayerInfo, isSannin)
+
+		switch {
+		case numKan >= 4:
+			r.Allowed = false
+			r.NotAllowedReason = "已有四杠"
+		case playerInfo.IsRiichi && drawTile != -1 && kanTile != drawTile:
+			r.Allowed = false
+			r.NotAllowedReason = "立直后不能送杠"
+		case playerInfo.IsRiichi && r.IsWaitsChanged:
+			r.Allowed = false
+			r.NotAllowedReason = "立直后暗杠不能改变听牌"
+		case playerInfo.IsRiichi && r.Result13.Shanten != r.KanResult13.Shanten:
+			r.Allowed = false
+			r.NotAllowedReason = "立直后暗杠不能改变听牌"
+		}
+		results = append(results, r)
+	}
+	return
+}
+
+// 他家舍牌时（3k+1 张手牌），计算大明杠的分析结果
+// 不能大明杠时返回 nil
+// numReachedOpponents: 立直的他家人数
+func CalculateDaiminkan(playerInfo *model.PlayerInfo, calledTile int, isRedFive bool, isSannin bool, numReachedOpponents int) *KanAnalysisResult {
+	if len(playerInfo.LeftTiles34) == 0 {
+		playerInfo.FillLeftTiles34()
+	}
+
+	if playerInfo.HandTiles34[calledTile] != 3 {
+		return nil
+	}
+
+	r := &KanAnalysisResult{
+		MeldType:            model.MeldTypeMinkan,
+		KanTile:             calledTile,
+		Result13:            CalculateShantenWithImproves13(playerInfo),
+		Allowed:             true,
+		NumReachedOpponents: numReachedOpponents,
+	}
+
+	kanPlayerInfo := copyPlayerInfo(playerInfo)
+	kanPlayerInfo.AddMeld(model.Meld{
+		MeldType:          model.MeldTypeMinkan,
+		Tiles:             []int{calledTile, calledTile, calledTile, calledTile},
+		SelfTiles:         []int{calledTile, calledTile, calledTile},
+		CalledTile:        calledTile,
+		ContainRedFive:    calledTile < 27 && calledTile%9 == 4 && (isRedFive || playerInfo.NumRedFives[calledTile/9] > 0),
+		RedFiveFromOthers: isRedFive,
+	})
+	r.fill(playerInfo, kanPlayerInfo, isSannin)
+
+	switch {
+	case playerInfo.IsRiichi:
+		r.Allowed = false
+		r.NotAllowedReason = "立直后不能大明杠"
+	case countKan(playerInfo.Melds) >= 4:
+		r.Allowed = false
+		r.NotAllowedReason = "已有四杠"
+	}
+	return r
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"testing"
+	"github.com/EndlessCheng/mahjong-helper/util/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateSelfKan(t *testing.T) {
+	assert := assert.New(t)
+
+	// 立直后暗杠，不改变听牌
+	pi := model.NewSimplePlayerInfo(MustStrToTiles34("1111m 234p 567s 1177z"), nil)
+	pi.IsRiichi = true
+	results := CalculateSelfKan(pi, MustStrToTile34("1m"), false, 0)
+	if assert.Len(results, 1) {
+		r := results[0]
+		t.Log(r)
+		assert.Equal(model.MeldTypeAnkan, r.MeldType)
+		assert.False(r.IsWaitsChanged)
+		assert.True(r.Allowed)
+		assert.Equal(0, r.KanResult13.Shanten)
+		assert.True(r.RinshanAgariRate > 0)
+		assert.True(r.KanDoraExpectation > 0)
+	}
+
+	// 立直后暗杠，改变听牌
+	pi = model.NewSimplePlayerInfo(MustStrToTiles34("11112345678999m"), nil)
+	pi.IsRiichi = true
+	results = CalculateSelfKan(pi, MustStrToTile34("1m"), false, 0)
+	if assert.Len(results, 1) {
+		r := results[0]
+		t.Log(r)
+		assert.True(r.IsWaitsChanged)
+		assert.False(r.Allowed)
+	}
+
+	// 加杠
+	melds := []model.Meld{{
+		MeldType:   model.MeldTypePon,
+		Tiles:      MustStrToTiles("555z"),
+		SelfTiles:  MustStrToTiles("55z"),
+		CalledTile: MustStrToTile34("5z"),
+	}}
+	pi = model.NewSimplePlayerInfo(MustStrToTiles34("234m 567p 789s 15z"), melds)
+	results = CalculateSelfKan(pi, -1, false, 0)
+	if assert.Len(results, 1) {
+		r := results[0]
+		t.Log(r)
+		assert.Equal(model.MeldTypeKakan, r.MeldType)
+		assert.True(r.Allowed)
+		assert.Equal(0, r.KanResult13.Shanten)
+	}
+
+	// 默听时加杠：无人立直时推荐，有人立直时杠宝牌会提高立直者的打点，不推荐
+	melds = []model.Meld{{
+		MeldType:   model.MeldTypePon,
+		Tiles:      MustStrToTiles("777z"),
+		SelfTiles:  MustStrToTiles("77z"),
+		CalledTile: MustStrToTile34("7z"),
+	}}
+	pi = model.NewSimplePlayerInfo(MustStrToTiles34("234m 567p 46s 55p 7z"), melds)
+	results = CalculateSelfKan(pi, -1, false, 0)
+	if assert.Len(results, 1) {
+		assert.True(results[0].IsRecommended())
+	}
+	results = CalculateSelfKan(pi, -1, false, 1)
+	if assert.Len(results, 1) {
+		r := results[0]
+		t.Log(r)
+		assert.Equal(1, r.NumReachedOpponents)
+		assert.False(r.IsRecommended())
+	}
+}
+
+func TestCalculateDaiminkan(t *testing.T) {
+	assert := assert.New(t)
+
+	pi := model.NewSimplePlayerInfo(MustStrToTiles34("111m 234p 567s 789s 1z"), nil)
+	r := CalculateDaiminkan(pi, MustStrToTile34("1m"), false, false, 0)
+	if assert.NotNil(r) {
+		t.Log(r)
+		assert.Equal(model.MeldTypeMinkan, r.MeldType)
+		assert.True(r.Allowed)
+		assert.False(r.IsWaitsChanged)
+		assert.True(r.OtherKanDoraExpectation > 0)
+	}
+
+	pi.IsRiichi = true
+	r = CalculateDaiminkan(pi, MustStrToTile34("1m"), false, false, 0)
+	if assert.NotNil(r) {
+		assert.False(r.Allowed)
+	}
+
+	assert.Nil(CalculateDaiminkan(pi, MustStrToTile34("2p"), false, false, 0))
+}
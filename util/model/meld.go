@@ -9,19 +9,27 @@ const (
 )
 
 type Meld struct {
-	MeldType int // 鸣牌类型（吃、碰、暗杠、大明杠、加杠）
+	MeldType int `json:"type"` // 鸣牌类型（吃、碰、暗杠、大明杠、加杠）
 
 	// Tiles == sort(SelfTiles + CalledTile)
-	Tiles      []int // 副露的牌
-	SelfTiles  []int // 手牌中组成副露的牌（用于鸣牌分析）
-	CalledTile int   // 被鸣的牌
+	Tiles      []int `json:"tiles"`          // 副露的牌
+	SelfTiles  []int `json:"self,omitempty"` // 手牌中组成副露的牌（用于鸣牌分析）
+	CalledTile int   `json:"called"`         // 被鸣的牌
 
 	// TODO: 重构 ContainRedFive RedFiveFromOthers
-	ContainRedFive    bool // 是否包含赤5
-	RedFiveFromOthers bool // 赤5是否来自他家（用于获取宝牌数）
+	ContainRedFive    bool `json:"red,omitempty"`     // 是否包含赤5
+	RedFiveFromOthers bool `json:"redFrom,omitempty"` // 赤5是否来自他家（用于获取宝牌数）
 }
 
 // 是否为杠子
 func (m *Meld) IsKan() bool {
 	return m.MeldType == MeldTypeAnkan || m.MeldType == MeldTypeMinkan || m.MeldType == MeldTypeKakan
 }
+
+// 深拷贝
+func (m *Meld) Copy() *Meld {
+	newMeld := *m
+	newMeld.Tiles = append([]int(nil), m.Tiles...)
+	newMeld.SelfTiles = append([]int(nil), m.SelfTiles...)
+	return &newMeld
+}
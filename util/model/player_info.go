@@ -25,6 +25,8 @@ type PlayerInfo struct {
 	//AvgUraDora float64 // 平均里宝牌个数，用于计算立直时的打点
 
 	NukiDoraNum int // 拔北宝牌数
+
+	ConsiderOldYaku bool // 是否考虑古役（如雀魂的古役模式）
 }
 
 func NewSimplePlayerInfo(tiles34 []int, melds []Meld) *PlayerInfo {
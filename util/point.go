@@ -86,6 +86,26 @@ type PointResult struct {
 	agariRate    float64 // 无役时的和率为 0
 }
 
+// 自摸时的子家支付点数和亲家支付点数（亲家自摸时 parentPoint 为 0）
+func (r *PointResult) TsumoPoints() (childPoint int, parentPoint int) {
+	return CalcPointTsumo(r.han, r.fu, r.yakumanTimes, r.isParent)
+}
+
+// 番数（含宝牌）和符数，役满时均为 0
+func (r *PointResult) HanFu() (han int, fu int) {
+	return r.han, r.fu
+}
+
+// 役满倍数，非役满时为 0
+func (r *PointResult) YakumanTimes() int {
+	return r.yakumanTimes
+}
+
+// 役种，无役时为空
+func (r *PointResult) YakuTypes() []int {
+	return append([]int{}, r.yakuTypes...)
+}
+
 // 已和牌，计算自摸或荣和时的点数（不考虑里宝、一发等情况）
 // 无役时返回的点数为 0（和率也为 0）
 // 调用前请设置 IsTsumo WinTile
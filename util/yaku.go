@@ -381,7 +381,7 @@ func findNormalYaku(hi *_handInfo, isNaki bool) (yakuTypes []int) {
 		}
 	}
 
-	if considerOldYaku {
+	if hi.isOldYakuEnabled() {
 		if !isNaki {
 			yakuHanMap = OldYakuHanMap
 		} else {
@@ -413,7 +413,7 @@ func findYakuTypes(hi *_handInfo, isNaki bool) (yakuTypes []int) {
 	hi.allShuntsuFirstTiles = hi.getAllShuntsuFirstTiles()
 	hi.allKotsuTiles = hi.getAllKotsuTiles()
 
-	if considerOldYaku {
+	if hi.isOldYakuEnabled() {
 		sort.Ints(hi.allShuntsuFirstTiles)
 		sort.Ints(hi.allKotsuTiles)
 	}
@@ -5,6 +5,7 @@ import (
 	"sort"
 )
 
+// 全局的古役设置（命令行参数 -old），也可以通过 PlayerInfo.ConsiderOldYaku 为单个玩家开启
 var considerOldYaku bool
 
 func SetConsiderOldYaku(b bool) {
@@ -165,11 +166,10 @@ func YakuTypesToStr(yakuTypes []int) string {
 		}
 	}
 
-	if considerOldYaku {
-		for _, t := range yakuTypes {
-			if name, ok := OldYakuNameMap[t]; ok {
-				names = append(names, name)
-			}
+	// 古役只会在开启古役时被找出，这里无需再判断
+	for _, t := range yakuTypes {
+		if name, ok := OldYakuNameMap[t]; ok {
+			names = append(names, name)
 		}
 	}
 
@@ -288,17 +288,15 @@ func CalcYakuHan(yakuTypes []int, isNaki bool) (cntHan int) {
 		}
 	}
 
-	if considerOldYaku {
-		if !isNaki {
-			yakuHanMap = OldYakuHanMap
-		} else {
-			yakuHanMap = OldNakiYakuHanMap
-		}
-
-		for _, yakuType := range yakuTypes {
-			if han, ok := yakuHanMap[yakuType]; ok {
-				cntHan += han
-			}
+	// 古役只会在开启古役时被找出，这里无需再判断
+	if !isNaki {
+		yakuHanMap = OldYakuHanMap
+	} else {
+		yakuHanMap = OldNakiYakuHanMap
+	}
+	for _, yakuType := range yakuTypes {
+		if han, ok := yakuHanMap[yakuType]; ok {
+			cntHan += han
 		}
 	}
 
@@ -357,7 +355,7 @@ func CalcYakumanTimes(yakuTypes []int, isNaki bool) (times int) {
 		}
 	}
 
-	if considerOldYaku && !isNaki {
+	if !isNaki {
 		for _, yakuman := range yakuTypes {
 			if t, ok := OldYakumanTimesMap[yakuman]; ok {
 				times += t
@@ -137,3 +137,30 @@ func Benchmark_findYakuTypes(b *testing.B) {
 		}
 	}
 }
+
+func Test_findYakuTypes_playerConsiderOldYaku(t *testing.T) {
+	oldConsiderOldYaku := considerOldYaku
+	considerOldYaku = false
+	defer func() { considerOldYaku = oldConsiderOldYaku }()
+
+	assert := assert.New(t)
+
+	findSanrenkou := func(considerOldYaku bool) bool {
+		pi := &model.PlayerInfo{
+			HandTiles34:     MustStrToTiles34("222333444p 11m 789s"),
+			WinTile:         MustStrToTile34("9s"),
+			RoundWindTile:   27,
+			SelfWindTile:    27,
+			ConsiderOldYaku: considerOldYaku,
+		}
+		for _, result := range DivideTiles34(pi.HandTiles34) {
+			yakuTypes := findYakuTypes(&_handInfo{PlayerInfo: pi, divideResult: result}, false)
+			if InInts(YakuSanrenkou, yakuTypes) {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(findSanrenkou(true))
+	assert.False(findSanrenkou(false))
+}
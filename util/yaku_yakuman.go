@@ -214,7 +214,7 @@ func findYakumanTypes(hi *_handInfo, isNaki bool) (yakumanTypes []int) {
 		}
 	}
 
-	if considerOldYaku && !isNaki {
+	if hi.isOldYakuEnabled() && !isNaki {
 		for yakuman := range OldYakumanTimesMap {
 			if checker, ok := oldYakumanCheckerMap[yakuman]; ok {
 				if checker(hi) {
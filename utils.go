@@ -5,6 +5,10 @@ import (
 	"os"
 	"github.com/fatih/color"
 	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+	"github.com/EndlessCheng/mahjong-helper/util"
 )
 
 func errorExit(args ...interface{}) {
@@ -16,6 +20,38 @@ func errorExit(args ...interface{}) {
 
 //
 
+var _roundNameReg = regexp.MustCompile(`^([东東南西北])([1-4])局?(?:(\d+)本场)?$`)
+
+// 解析局名，如 东2局1本场、南3局、西1
+// roundNumber 为场数（东1局为 0，南1局为 4），未指定本场数时 hasBenNumber 为 false
+func parseRoundName(s string) (roundNumber int, benNumber int, hasBenNumber bool, ok bool) {
+	matches := _roundNameReg.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return
+	}
+	switch matches[1] {
+	case "南":
+		roundNumber = 4
+	case "西":
+		roundNumber = 8
+	case "北":
+		roundNumber = 12
+	}
+	n, _ := strconv.Atoi(matches[2])
+	roundNumber += n - 1
+	if matches[3] != "" {
+		benNumber, _ = strconv.Atoi(matches[3])
+		hasBenNumber = true
+	}
+	return roundNumber, benNumber, hasBenNumber, true
+}
+
+func roundName(roundNumber int, benNumber int) string {
+	return fmt.Sprintf("%s%d局%d本场", util.MahjongZH[27+roundNumber/4], roundNumber%4+1, benNumber)
+}
+
+//
+
 // 进张数优劣
 func getWaitsCountColor(shanten int, waitsCount float64) color.Attribute {
 	_getWaitsCountColor := func(fixedWaitsCount float64) color.Attribute {